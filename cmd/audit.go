@@ -0,0 +1,73 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/internal/audit"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/spf13/cobra"
+)
+
+func auditCommand() *cobra.Command {
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Signing audit log commands",
+		Long:  "",
+	}
+	auditCmd.AddCommand(auditVerifyCommand())
+	return auditCmd
+}
+
+func auditVerifyCommand() *cobra.Command {
+	var allowPruned bool
+	verifyCmd := &cobra.Command{
+		Use:   "verify [file]",
+		Short: "Verifies the hash chain of the signing audit log, including the rotated files",
+		Long: "Verifies the hash chain of the signing audit log. The file defaults to audit.file in the config file. " +
+			"Verification fails if older rotated files have been removed, unless --allow-pruned is set",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			var filename string
+			if len(args) > 0 {
+				filename = args[0]
+			} else {
+				initConfig()
+				if err := config.ReadConfig("ffsigner", cfgFile); err != nil {
+					return i18n.WrapError(ctx, err, i18n.MsgConfigFailed)
+				}
+				filename = audit.ReadConfig(signerconfig.AuditConfig).File
+			}
+
+			result, err := audit.Verify(ctx, filename, allowPruned)
+			if err != nil {
+				return err
+			}
+			b, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(b))
+			return nil
+		},
+	}
+	verifyCmd.Flags().BoolVar(&allowPruned, "allow-pruned", false, "accept a log that starts after the first entry, because older rotated files were removed by audit.maxFiles")
+	return verifyCmd
+}
@@ -0,0 +1,106 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/firefly-signer/internal/audit"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestAuditLog(t *testing.T) string {
+	auditFile := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := audit.NewLogger(context.Background(), &audit.Config{File: auditFile})
+	assert.NoError(t, err)
+	defer l.Close()
+	err = l.Record(context.Background(), &audit.Entry{
+		Type: audit.EntryTypeMessage,
+		From: ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248"),
+	})
+	assert.NoError(t, err)
+	return auditFile
+}
+
+func TestAuditVerifyFileArg(t *testing.T) {
+	rootCmd.SetArgs([]string{"audit", "verify", writeTestAuditLog(t)})
+	defer rootCmd.SetArgs([]string{})
+
+	err := rootCmd.Execute()
+	assert.NoError(t, err)
+}
+
+func TestAuditVerifyFromConfig(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "ffsigner.yaml")
+	err := os.WriteFile(configFile, []byte(fmt.Sprintf("audit:\n  file: %s\n", writeTestAuditLog(t))), 0600)
+	assert.NoError(t, err)
+
+	rootCmd.SetArgs([]string{"-f", configFile, "audit", "verify"})
+	defer rootCmd.SetArgs([]string{})
+
+	err = rootCmd.Execute()
+	assert.NoError(t, err)
+}
+
+func TestAuditVerifyBadConfig(t *testing.T) {
+	rootCmd.SetArgs([]string{"-f", "../test/bad-config.ffsigner.yaml", "audit", "verify"})
+	defer rootCmd.SetArgs([]string{})
+
+	err := rootCmd.Execute()
+	assert.Regexp(t, "FF00101", err)
+}
+
+func TestAuditVerifyTampered(t *testing.T) {
+	auditFile := writeTestAuditLog(t)
+	err := os.WriteFile(auditFile, []byte(`{"seq":0,"type":"message","hash":"0x00"}`+"\n"), 0600)
+	assert.NoError(t, err)
+
+	rootCmd.SetArgs([]string{"audit", "verify", auditFile})
+	defer rootCmd.SetArgs([]string{})
+
+	err = rootCmd.Execute()
+	assert.Regexp(t, "FF22136", err)
+}
+
+func TestAuditVerifyPruned(t *testing.T) {
+	auditFile := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := audit.NewLogger(context.Background(), &audit.Config{File: auditFile, MaxSize: 100, MaxFiles: 1})
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		err = l.Record(context.Background(), &audit.Entry{
+			Type: audit.EntryTypeMessage,
+			From: ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248"),
+		})
+		assert.NoError(t, err)
+	}
+	l.Close()
+
+	verifyCmd := auditVerifyCommand()
+	verifyCmd.SetArgs([]string{auditFile})
+	err = verifyCmd.Execute()
+	assert.Regexp(t, "FF22203", err)
+
+	verifyCmd = auditVerifyCommand()
+	verifyCmd.SetArgs([]string{auditFile, "--allow-pruned"})
+	err = verifyCmd.Execute()
+	assert.NoError(t, err)
+}
@@ -28,8 +28,6 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly-signer/internal/rpcserver"
 	"github.com/hyperledger/firefly-signer/internal/signerconfig"
-	"github.com/hyperledger/firefly-signer/internal/signermsgs"
-	"github.com/hyperledger/firefly-signer/pkg/fswallet"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -51,6 +49,8 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "f", "", "config file")
 	rootCmd.AddCommand(versionCommand())
 	rootCmd.AddCommand(configCommand())
+	rootCmd.AddCommand(auditCommand())
+	rootCmd.AddCommand(hdwalletCommand())
 }
 
 func Execute() error {
@@ -89,15 +89,12 @@ func run() error {
 		cancelCtx()
 	}()
 
-	if !config.GetBool(signerconfig.FileWalletEnabled) {
-		return i18n.NewError(ctx, signermsgs.MsgNoWalletEnabled)
-	}
-	fileWallet, err := fswallet.NewFilesystemWallet(ctx, fswallet.ReadConfig(signerconfig.FileWalletConfig))
+	wallet, err := newWallet(ctx)
 	if err != nil {
 		return err
 	}
 
-	server, err := rpcserver.NewServer(ctx, fileWallet)
+	server, err := rpcserver.NewServer(ctx, wallet)
 	if err != nil {
 		return err
 	}
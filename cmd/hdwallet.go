@@ -0,0 +1,163 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/hdwallet"
+	"github.com/hyperledger/firefly-signer/pkg/keystorev3"
+	"github.com/spf13/cobra"
+)
+
+// randRead is the source of entropy for a generated mnemonic
+var randRead = rand.Read
+
+func hdwalletCommand() *cobra.Command {
+	hdwalletCmd := &cobra.Command{
+		Use:   "hdwallet",
+		Short: "HD wallet seed file commands",
+		Long:  "",
+	}
+	hdwalletCmd.AddCommand(hdwalletCreateCommand())
+	return hdwalletCmd
+}
+
+type hdwalletCreateResult struct {
+	SeedFile string `json:"seedFile"`
+	Mnemonic string `json:"mnemonic,omitempty"`
+}
+
+func hdwalletCreateCommand() *cobra.Command {
+	var passwordFile, mnemonicFile, passphraseFile, extendedKeyFile string
+	createCmd := &cobra.Command{
+		Use:   "create [seedFile]",
+		Short: "Creates an encrypted seed file for an HD wallet",
+		Long: "Creates a Keystore V3 seed file for an HD wallet, from a BIP-39 mnemonic or a BIP-32 extended private key. " +
+			"When neither is supplied, a new 24 word mnemonic is generated and printed - back it up before using the wallet. An existing seed file is never overwritten",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			if mnemonicFile != "" && extendedKeyFile != "" {
+				return i18n.NewError(ctx, signermsgs.MsgHDWalletSecretConflict)
+			}
+			password, err := readTrimmedFile(passwordFile)
+			if err != nil {
+				return err
+			}
+
+			result := &hdwalletCreateResult{SeedFile: args[0]}
+			var secret []byte
+			switch {
+			case extendedKeyFile != "":
+				encoded, err := readTrimmedFile(extendedKeyFile)
+				if err != nil {
+					return err
+				}
+				key, err := hdwallet.ParseExtendedKey(ctx, encoded)
+				if err != nil {
+					return err
+				}
+				secret = key.Serialize()
+			default:
+				mnemonic := ""
+				if mnemonicFile != "" {
+					if mnemonic, err = readTrimmedFile(mnemonicFile); err != nil {
+						return err
+					}
+				} else {
+					entropy := make([]byte, 32)
+					if _, err := randRead(entropy); err != nil {
+						return i18n.WrapError(ctx, err, signermsgs.MsgHDWalletEntropyFailed)
+					}
+					if mnemonic, err = hdwallet.NewMnemonic(ctx, entropy); err != nil {
+						return err
+					}
+					result.Mnemonic = mnemonic
+				}
+				passphrase := ""
+				if passphraseFile != "" {
+					if passphrase, err = readPassphraseFile(passphraseFile); err != nil {
+						return err
+					}
+				}
+				if secret, err = hdwallet.MnemonicToSeed(ctx, mnemonic, passphrase); err != nil {
+					return err
+				}
+			}
+
+			kv3 := keystorev3.NewWalletFileCustomBytesStandard(password, secret)
+			if err := writeNewFile(ctx, args[0], kv3.JSON()); err != nil {
+				return err
+			}
+			b, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(b))
+			return nil
+		},
+	}
+	createCmd.Flags().StringVar(&passwordFile, "password-file", "", "file containing the password to encrypt the seed file (required)")
+	createCmd.Flags().StringVar(&mnemonicFile, "mnemonic-file", "", "file containing an existing BIP-39 mnemonic")
+	createCmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "file containing the optional BIP-39 passphrase for the mnemonic")
+	createCmd.Flags().StringVar(&extendedKeyFile, "extended-key-file", "", "file containing an existing BIP-32 extended private key (xprv)")
+	_ = createCmd.MarkFlagRequired("password-file")
+	return createCmd
+}
+
+// writeNewFile fails rather than truncating an existing file, as that might be the only copy of a seed
+func writeNewFile(ctx context.Context, filename string, data []byte) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return i18n.NewError(ctx, signermsgs.MsgSeedFileExists, filename)
+		}
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(filename)
+	}
+	return err
+}
+
+func readTrimmedFile(filename string) (string, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// readPassphraseFile only strips a single trailing newline, as leading and trailing spaces
+// are part of a BIP-39 passphrase
+func readPassphraseFile(filename string) (string, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	passphrase := strings.TrimSuffix(string(b), "\n")
+	return strings.TrimSuffix(passphrase, "\r"), nil
+}
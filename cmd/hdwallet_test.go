@@ -0,0 +1,180 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/hdwallet"
+	"github.com/hyperledger/firefly-signer/pkg/keystorev3"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	filename := filepath.Join(dir, name)
+	err := os.WriteFile(filename, []byte(content), 0600)
+	assert.NoError(t, err)
+	return filename
+}
+
+func runHDWalletCreate(args ...string) error {
+	createCmd := hdwalletCreateCommand()
+	createCmd.SetArgs(args)
+	return createCmd.Execute()
+}
+
+func TestHDWalletCreateFromMnemonic(t *testing.T) {
+	dir := t.TempDir()
+	seedFile := filepath.Join(dir, "seed.json")
+	passwordFile := writeTestFile(t, dir, "seed.pwd", "correcthorsebatterystaple\n")
+	mnemonicFile := writeTestFile(t, dir, "mnemonic.txt", "test test test test test test test test test test test junk\n")
+
+	err := runHDWalletCreate(seedFile, "--password-file", passwordFile, "--mnemonic-file", mnemonicFile)
+	assert.NoError(t, err)
+
+	testWalletConfig(t, fmt.Sprintf(`
+fileWallet:
+  enabled: false
+wallets:
+- name: hd
+  type: hdWallet
+  hdWallet:
+    seedFile: %s
+    passwordFile: %s
+    accounts:
+      count: 2
+`, seedFile, passwordFile))
+	ctx := context.Background()
+	wallet, err := newWallet(ctx)
+	assert.NoError(t, err)
+	_, ok := wallet.(hdwallet.Wallet)
+	assert.True(t, ok)
+
+	err = wallet.Initialize(ctx)
+	assert.NoError(t, err)
+	accounts, err := wallet.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{
+		ethtypes.MustNewAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		ethtypes.MustNewAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+	}, accounts)
+}
+
+func TestHDWalletCreateGenerateMnemonic(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := writeTestFile(t, dir, "seed.pwd", "correcthorsebatterystaple")
+	passphraseFile := writeTestFile(t, dir, "passphrase.txt", "TREZOR")
+
+	err := runHDWalletCreate(filepath.Join(dir, "seed.json"), "--password-file", passwordFile, "--passphrase-file", passphraseFile)
+	assert.NoError(t, err)
+}
+
+func TestHDWalletCreatePassphraseWhitespace(t *testing.T) {
+	dir := t.TempDir()
+	seedFile := filepath.Join(dir, "seed.json")
+	passwordFile := writeTestFile(t, dir, "seed.pwd", "correcthorsebatterystaple")
+	mnemonic := "test test test test test test test test test test test junk"
+	mnemonicFile := writeTestFile(t, dir, "mnemonic.txt", mnemonic)
+	passphraseFile := writeTestFile(t, dir, "passphrase.txt", " TREZOR \n\n")
+
+	err := runHDWalletCreate(seedFile, "--password-file", passwordFile, "--mnemonic-file", mnemonicFile, "--passphrase-file", passphraseFile)
+	assert.NoError(t, err)
+
+	// Only the last newline is stripped from the passphrase
+	expected, err := hdwallet.MnemonicToSeed(context.Background(), mnemonic, " TREZOR \n")
+	assert.NoError(t, err)
+	b, err := os.ReadFile(seedFile)
+	assert.NoError(t, err)
+	kv3, err := keystorev3.ReadWalletFile(b, []byte("correcthorsebatterystaple"))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, kv3.PrivateKey())
+}
+
+func TestHDWalletCreateEntropyFail(t *testing.T) {
+	defer func() { randRead = rand.Read }()
+	randRead = func([]byte) (int, error) { return 0, fmt.Errorf("pop") }
+
+	dir := t.TempDir()
+	passwordFile := writeTestFile(t, dir, "seed.pwd", "correcthorsebatterystaple")
+	err := runHDWalletCreate(filepath.Join(dir, "seed.json"), "--password-file", passwordFile)
+	assert.Regexp(t, "FF22199.*pop", err)
+}
+
+func TestHDWalletCreateFromExtendedKey(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := writeTestFile(t, dir, "seed.pwd", "correcthorsebatterystaple")
+	xprvFile := writeTestFile(t, dir, "xprv.txt", "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi")
+
+	err := runHDWalletCreate(filepath.Join(dir, "seed.json"), "--password-file", passwordFile, "--extended-key-file", xprvFile)
+	assert.NoError(t, err)
+}
+
+func TestHDWalletCreateExistingSeedFile(t *testing.T) {
+	dir := t.TempDir()
+	seedFile := writeTestFile(t, dir, "seed.json", "existing seed")
+	passwordFile := writeTestFile(t, dir, "seed.pwd", "correcthorsebatterystaple")
+	mnemonicFile := writeTestFile(t, dir, "mnemonic.txt", "test test test test test test test test test test test junk")
+
+	err := runHDWalletCreate(seedFile, "--password-file", passwordFile, "--mnemonic-file", mnemonicFile)
+	assert.Regexp(t, "FF22201", err)
+
+	b, err := os.ReadFile(seedFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "existing seed", string(b))
+}
+
+func TestHDWalletCreateErrors(t *testing.T) {
+	dir := t.TempDir()
+	seedFile := filepath.Join(dir, "seed.json")
+	passwordFile := writeTestFile(t, dir, "seed.pwd", "correcthorsebatterystaple")
+	missingFile := filepath.Join(dir, "missing")
+	badFile := writeTestFile(t, dir, "bad.txt", "bad")
+
+	err := runHDWalletCreate(seedFile)
+	assert.Regexp(t, "password-file", err)
+
+	err = runHDWalletCreate(seedFile, "--password-file", passwordFile, "--mnemonic-file", badFile, "--extended-key-file", badFile)
+	assert.Regexp(t, "FF22175", err)
+
+	err = runHDWalletCreate(seedFile, "--password-file", missingFile)
+	assert.Error(t, err)
+
+	err = runHDWalletCreate(seedFile, "--password-file", passwordFile, "--mnemonic-file", missingFile)
+	assert.Error(t, err)
+
+	err = runHDWalletCreate(seedFile, "--password-file", passwordFile, "--passphrase-file", missingFile)
+	assert.Error(t, err)
+
+	err = runHDWalletCreate(seedFile, "--password-file", passwordFile, "--mnemonic-file", badFile)
+	assert.Regexp(t, "FF22167", err)
+
+	err = runHDWalletCreate(seedFile, "--password-file", passwordFile, "--extended-key-file", missingFile)
+	assert.Error(t, err)
+
+	err = runHDWalletCreate(seedFile, "--password-file", passwordFile, "--extended-key-file", badFile)
+	assert.Regexp(t, "FF22169", err)
+
+	xprvFile := writeTestFile(t, dir, "xprv.txt", "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi")
+	err = runHDWalletCreate(filepath.Join(missingFile, "seed.json"), "--password-file", passwordFile, "--extended-key-file", xprvFile)
+	assert.Error(t, err)
+}
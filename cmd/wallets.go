@@ -0,0 +1,157 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/internal/metrics"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/compositewallet"
+	"github.com/hyperledger/firefly-signer/pkg/devwallet"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/fswallet"
+	"github.com/hyperledger/firefly-signer/pkg/hdwallet"
+	"github.com/hyperledger/firefly-signer/pkg/hsmwallet"
+	"github.com/hyperledger/firefly-signer/pkg/kmswallet"
+)
+
+const (
+	fileWalletName = "fileWallet"
+	devWalletName  = "devWallet"
+)
+
+// newWallet creates the wallet from the fileWallet and devWallet sections, and the wallets array.
+// When more than one wallet is configured, they are combined into a composite wallet.
+func newWallet(ctx context.Context) (ethsigner.Wallet, error) {
+	var walletMetrics *accountCounts
+	if config.GetBool(signerconfig.MetricsEnabled) {
+		walletMetrics = &accountCounts{Metrics: metrics.Get(), counts: make(map[string]int)}
+	}
+
+	children := make([]*compositewallet.Child, 0)
+	if config.GetBool(signerconfig.FileWalletEnabled) {
+		fileWallet, err := newFileWallet(ctx, fileWalletName, signerconfig.FileWalletConfig, walletMetrics)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, &compositewallet.Child{
+			Name:     fileWalletName,
+			Priority: config.GetInt(signerconfig.FileWalletPriority),
+			Wallet:   fileWallet,
+		})
+	}
+	if config.GetBool(signerconfig.DevWalletEnabled) {
+		devWallet, err := devwallet.NewDevWallet(ctx, devwallet.ReadConfig(signerconfig.DevWalletConfig))
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, &compositewallet.Child{
+			Name:     devWalletName,
+			Priority: config.GetInt(signerconfig.DevWalletPriority),
+			Wallet:   devWallet,
+		})
+	}
+
+	walletCount := signerconfig.WalletsConfig.ArraySize()
+	for i := 0; i < walletCount; i++ {
+		walletConf := signerconfig.WalletsConfig.ArrayEntry(i)
+		name := walletConf.GetString(signerconfig.WalletConfName)
+		if err := fftypes.ValidateFFNameField(ctx, name, signerconfig.WalletConfName); err != nil {
+			return nil, i18n.WrapError(ctx, err, signermsgs.MsgInvalidWalletName, i)
+		}
+		var wallet ethsigner.Wallet
+		var err error
+		switch walletType := walletConf.GetString(signerconfig.WalletConfType); walletType {
+		case signerconfig.WalletTypeFileWallet:
+			wallet, err = newFileWallet(ctx, name, walletConf.SubSection(signerconfig.WalletConfFileWallet), walletMetrics)
+		case signerconfig.WalletTypeHDWallet:
+			wallet, err = hdwallet.NewHDWallet(ctx, hdwallet.ReadConfig(walletConf.SubSection(signerconfig.WalletConfHDWallet)))
+		case signerconfig.WalletTypeDevWallet:
+			wallet, err = devwallet.NewDevWallet(ctx, devwallet.ReadConfig(walletConf.SubSection(signerconfig.WalletConfDevWallet)))
+		case signerconfig.WalletTypeHSMWallet:
+			wallet, err = hsmwallet.NewHSMWallet(ctx, hsmwallet.ReadConfig(walletConf.SubSection(signerconfig.WalletConfHSMWallet)))
+		case signerconfig.WalletTypeKMSWallet:
+			wallet, err = newKMSWallet(ctx, walletConf.SubSection(signerconfig.WalletConfKMSWallet))
+		default:
+			err = i18n.NewError(ctx, signermsgs.MsgInvalidWalletType, walletType, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, &compositewallet.Child{
+			Name:     name,
+			Priority: walletConf.GetInt(signerconfig.WalletConfPriority),
+			Wallet:   wallet,
+		})
+	}
+
+	switch len(children) {
+	case 0:
+		return nil, i18n.NewError(ctx, signermsgs.MsgNoWalletEnabled)
+	case 1:
+		return children[0].Wallet, nil
+	default:
+		return compositewallet.NewCompositeWallet(ctx, children)
+	}
+}
+
+func newFileWallet(ctx context.Context, name string, section config.Section, walletMetrics *accountCounts) (ethsigner.Wallet, error) {
+	walletConf := fswallet.ReadConfig(section)
+	if walletMetrics != nil {
+		walletConf.Metrics = &fileWalletMetrics{accountCounts: walletMetrics, name: name}
+	}
+	return fswallet.NewFilesystemWallet(ctx, walletConf)
+}
+
+func newKMSWallet(ctx context.Context, section config.Section) (ethsigner.Wallet, error) {
+	httpClient, err := ffresty.New(ctx, section)
+	if err != nil {
+		return nil, err
+	}
+	return kmswallet.NewKMSWallet(ctx, kmswallet.ReadConfig(section), httpClient)
+}
+
+// accountCounts reports the total of the accounts loaded by each filesystem wallet, as the
+// wallet accounts metric is for the whole signer
+type accountCounts struct {
+	fswallet.Metrics
+	mux    sync.Mutex
+	counts map[string]int
+}
+
+type fileWalletMetrics struct {
+	*accountCounts
+	name string
+}
+
+func (m *fileWalletMetrics) AccountsLoaded(ctx context.Context, count int) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.counts[m.name] = count
+	total := 0
+	for _, c := range m.counts {
+		total += c
+	}
+	m.Metrics.AccountsLoaded(ctx, total)
+}
@@ -0,0 +1,306 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/pkg/compositewallet"
+	"github.com/hyperledger/firefly-signer/pkg/devwallet"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/fswallet"
+	"github.com/hyperledger/firefly-signer/pkg/hsmwallet"
+	"github.com/hyperledger/firefly-signer/pkg/kmswallet"
+	"github.com/stretchr/testify/assert"
+)
+
+const testFileWalletConf = `
+    path: "../test/keystore_toml"
+    disableListener: true
+    filenames:
+      primaryExt: ".toml"
+    metadata:
+      format: auto
+      keyFileProperty: '{{ index .signing "key-file" }}'
+      passwordFileProperty: '{{ index .signing "password-file" }}'
+`
+
+func testWalletConfig(t *testing.T, yaml string) {
+	configFile := filepath.Join(t.TempDir(), "ffsigner.yaml")
+	err := os.WriteFile(configFile, []byte(yaml), 0600)
+	assert.NoError(t, err)
+	signerconfig.Reset()
+	err = config.ReadConfig("ffsigner", configFile)
+	assert.NoError(t, err)
+}
+
+func TestNewWalletSingleFileWallet(t *testing.T) {
+	testWalletConfig(t, `
+fileWallet:`+testFileWalletConf+`
+metrics:
+  enabled: true
+`)
+	wallet, err := newWallet(context.Background())
+	assert.NoError(t, err)
+	_, ok := wallet.(fswallet.Wallet)
+	assert.True(t, ok)
+}
+
+func TestNewWalletComposite(t *testing.T) {
+	testWalletConfig(t, `
+fileWallet:
+  enabled: false
+wallets:
+- name: wallet1
+  type: fileWallet
+  fileWallet:`+testFileWalletConf+`
+- name: wallet2
+  priority: 10
+  fileWallet:`+testFileWalletConf+`
+metrics:
+  enabled: true
+`)
+	ctx := context.Background()
+	wallet, err := newWallet(ctx)
+	assert.NoError(t, err)
+	cw, ok := wallet.(compositewallet.Wallet)
+	assert.True(t, ok)
+
+	err = cw.Initialize(ctx)
+	assert.NoError(t, err)
+	defer cw.Close()
+
+	accounts, err := cw.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 3)
+	owner, err := cw.GetAccountWallet(ctx, *ethtypes.MustNewAddress("0x1f185718734552d08278aa70f804580bab5fd2b4"))
+	assert.NoError(t, err)
+	assert.Equal(t, "wallet2", owner)
+}
+
+func TestNewWalletCompositeDuplicateName(t *testing.T) {
+	testWalletConfig(t, `
+fileWallet:`+testFileWalletConf+`
+wallets:
+- name: fileWallet
+  fileWallet:`+testFileWalletConf+`
+`)
+	_, err := newWallet(context.Background())
+	assert.Regexp(t, "FF22164.*fileWallet", err)
+}
+
+func TestNewWalletBadName(t *testing.T) {
+	testWalletConfig(t, `
+wallets:
+- name: "!bad"
+`)
+	_, err := newWallet(context.Background())
+	assert.Regexp(t, "FF22163", err)
+}
+
+func TestNewWalletBadType(t *testing.T) {
+	testWalletConfig(t, `
+wallets:
+- name: wallet1
+  type: wrong
+`)
+	_, err := newWallet(context.Background())
+	assert.Regexp(t, "FF22166.*wrong.*wallet1", err)
+}
+
+func TestNewWalletBadFileWallet(t *testing.T) {
+	testWalletConfig(t, `
+fileWallet:
+  enabled: false
+wallets:
+- name: wallet1
+  fileWallet:
+    metadata:
+      keyFileProperty: '{{ !!! }}'
+`)
+	_, err := newWallet(context.Background())
+	assert.Regexp(t, "FF22016", err)
+}
+
+func TestNewWalletBadHDWallet(t *testing.T) {
+	testWalletConfig(t, `
+wallets:
+- name: wallet1
+  type: hdWallet
+  hdWallet:
+    accounts:
+      count: 0
+`)
+	_, err := newWallet(context.Background())
+	assert.Regexp(t, "FF22174", err)
+}
+
+func TestNewWalletHSMWallet(t *testing.T) {
+	testWalletConfig(t, `
+fileWallet:
+  enabled: false
+wallets:
+- name: hsm1
+  type: hsmWallet
+  hsmWallet:
+    tokenLabel: signer
+    keys:
+      ids: ["0a0b"]
+`)
+	wallet, err := newWallet(context.Background())
+	assert.NoError(t, err)
+	_, ok := wallet.(hsmwallet.Wallet)
+	assert.True(t, ok)
+}
+
+func TestNewWalletBadHSMWallet(t *testing.T) {
+	testWalletConfig(t, `
+wallets:
+- name: wallet1
+  type: hsmWallet
+  hsmWallet:
+    keys:
+      ids: [not hex]
+`)
+	_, err := newWallet(context.Background())
+	assert.Regexp(t, "FF22184", err)
+}
+
+func TestNewWalletKMSWallet(t *testing.T) {
+	testWalletConfig(t, `
+fileWallet:
+  enabled: false
+wallets:
+- name: kms1
+  type: kmsWallet
+  kmsWallet:
+    region: us-east-1
+    keys:
+      aliasPrefix: signer-
+`)
+	wallet, err := newWallet(context.Background())
+	assert.NoError(t, err)
+	_, ok := wallet.(kmswallet.Wallet)
+	assert.True(t, ok)
+}
+
+func TestNewWalletBadKMSWallet(t *testing.T) {
+	testWalletConfig(t, `
+wallets:
+- name: wallet1
+  type: kmsWallet
+  kmsWallet:
+    keys:
+      ids: [key1]
+`)
+	_, err := newWallet(context.Background())
+	assert.Regexp(t, "FF22191.*region", err)
+}
+
+func TestNewWalletBadKMSWalletHTTPConfig(t *testing.T) {
+	testWalletConfig(t, `
+wallets:
+- name: wallet1
+  type: kmsWallet
+  kmsWallet:
+    region: us-east-1
+    tls:
+      enabled: true
+      caFile: /does/not/exist
+`)
+	_, err := newWallet(context.Background())
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestNewWalletDevWallet(t *testing.T) {
+	testWalletConfig(t, `
+fileWallet:
+  enabled: false
+devWallet:
+  enabled: true
+  accounts: 2
+`)
+	ctx := context.Background()
+	wallet, err := newWallet(ctx)
+	assert.NoError(t, err)
+	_, ok := wallet.(devwallet.Wallet)
+	assert.True(t, ok)
+
+	err = wallet.Initialize(ctx)
+	assert.NoError(t, err)
+	accounts, err := wallet.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{
+		ethtypes.MustNewAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		ethtypes.MustNewAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+	}, accounts)
+}
+
+func TestNewWalletDevWalletComposite(t *testing.T) {
+	testWalletConfig(t, `
+fileWallet:`+testFileWalletConf+`
+devWallet:
+  enabled: true
+  priority: 10
+wallets:
+- name: dev2
+  type: devWallet
+  devWallet:
+    seed: another seed
+    accounts: 1
+`)
+	ctx := context.Background()
+	wallet, err := newWallet(ctx)
+	assert.NoError(t, err)
+	cw, ok := wallet.(compositewallet.Wallet)
+	assert.True(t, ok)
+
+	err = cw.Initialize(ctx)
+	assert.NoError(t, err)
+	defer cw.Close()
+
+	accounts, err := cw.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 14)
+	owner, err := cw.GetAccountWallet(ctx, *ethtypes.MustNewAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"))
+	assert.NoError(t, err)
+	assert.Equal(t, "devWallet", owner)
+}
+
+func TestNewWalletBadDevWallet(t *testing.T) {
+	testWalletConfig(t, `
+devWallet:
+  enabled: true
+  balance: lots
+`)
+	_, err := newWallet(context.Background())
+	assert.Regexp(t, "FF22176", err)
+}
+
+func TestNewWalletNone(t *testing.T) {
+	testWalletConfig(t, `
+fileWallet:
+  enabled: false
+`)
+	_, err := newWallet(context.Background())
+	assert.Regexp(t, "FF22017", err)
+}
@@ -0,0 +1,287 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+)
+
+type EntryType string
+
+const (
+	EntryTypeTransaction EntryType = "transaction"
+	EntryTypeMessage     EntryType = "message"   // EIP-191
+	EntryTypeTypedData   EntryType = "typedData" // EIP-712
+)
+
+// GenesisHash is the previous hash of the first entry in the log
+var GenesisHash = ethtypes.HexBytes0xPrefix(make([]byte, sha256.Size))
+
+// Entry is a line in the audit log, recording one signature made by the wallet.
+//
+// The hash is the SHA-256 of the JSON serialization of the entry without the hash, which includes
+// the hash of the previous entry. So modifying, removing, inserting or re-ordering entries breaks the chain.
+type Entry struct {
+	Seq           uint64                    `json:"seq"`
+	Timestamp     *fftypes.FFTime           `json:"timestamp"`
+	Type          EntryType                 `json:"type"`
+	Identity      string                    `json:"identity,omitempty"`
+	Chain         string                    `json:"chain,omitempty"`
+	ChainID       int64                     `json:"chainId"`
+	From          *ethtypes.Address0xHex    `json:"from"`
+	TxHash        ethtypes.HexBytes0xPrefix `json:"txHash,omitempty"`
+	MessageHash   ethtypes.HexBytes0xPrefix `json:"messageHash,omitempty"`
+	TypedDataHash ethtypes.HexBytes0xPrefix `json:"typedDataHash,omitempty"`
+	To            *ethtypes.Address0xHex    `json:"to,omitempty"`
+	Value         *ethtypes.HexInteger      `json:"value,omitempty"`
+	Selector      ethtypes.HexBytes0xPrefix `json:"selector,omitempty"`
+	PrevHash      ethtypes.HexBytes0xPrefix `json:"prevHash"`
+	Hash          ethtypes.HexBytes0xPrefix `json:"hash,omitempty"`
+}
+
+// Logger appends hash-chained entries to a JSONL file, rotating it when it reaches the maximum size.
+// The chain continues across the rotated files.
+type Logger struct {
+	mux      sync.Mutex
+	filename string
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+	nextSeq  uint64
+	prevHash ethtypes.HexBytes0xPrefix
+}
+
+// NewLogger opens the audit log, and continues the chain from the last entry written
+func NewLogger(ctx context.Context, conf *Config) (*Logger, error) {
+	if conf.File == "" {
+		return nil, i18n.NewError(ctx, signermsgs.MsgAuditLogFileRequired)
+	}
+	l := &Logger{
+		filename: conf.File,
+		maxSize:  conf.MaxSize,
+		maxFiles: conf.MaxFiles,
+		prevHash: GenesisHash,
+	}
+
+	// The current file is empty after a rotation, so we might need to look at the last rotated file
+	files, err := logFiles(l.filename)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, signermsgs.MsgAuditLogOpenFailed, l.filename)
+	}
+	for i := len(files) - 1; i >= 0; i-- {
+		last, err := lastEntry(ctx, files[i])
+		if err != nil {
+			return nil, err
+		}
+		if last != nil {
+			l.nextSeq = last.Seq + 1
+			l.prevHash = last.Hash
+			break
+		}
+	}
+
+	if err := l.open(); err != nil {
+		return nil, i18n.WrapError(ctx, err, signermsgs.MsgAuditLogOpenFailed, l.filename)
+	}
+	log.L(ctx).Infof("Audit log '%s' continuing from sequence %d with previous hash %s", l.filename, l.nextSeq, l.prevHash)
+	return l, nil
+}
+
+func (l *Logger) open() (err error) {
+	l.file, err = os.OpenFile(l.filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	stat, err := l.file.Stat()
+	if err != nil {
+		return err
+	}
+	l.size = stat.Size()
+	return nil
+}
+
+// Record fills in the sequence, timestamp and hashes of the entry, and appends it to the log.
+// Does nothing if the logger is nil, as the audit log is disabled.
+func (l *Logger) Record(ctx context.Context, entry *Entry) error {
+	if l == nil {
+		return nil
+	}
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	entry.Seq = l.nextSeq
+	entry.Timestamp = fftypes.Now()
+	entry.PrevHash = l.prevHash
+	entry.Hash = entry.computeHash()
+	line, _ := json.Marshal(entry)
+	line = append(line, '\n')
+
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return i18n.WrapError(ctx, err, signermsgs.MsgAuditLogWriteFailed, l.filename)
+		}
+	}
+	if _, err := l.file.Write(line); err != nil {
+		return i18n.WrapError(ctx, err, signermsgs.MsgAuditLogWriteFailed, l.filename)
+	}
+	l.size += int64(len(line))
+	l.nextSeq++
+	l.prevHash = entry.Hash
+	return nil
+}
+
+// rotate renames the current file to .1, after renaming each existing rotated file to the next
+// number up, and removing any beyond the maximum number of files
+func (l *Logger) rotate() error {
+	_ = l.file.Close()
+	rotated, err := rotatedFileNumbers(l.filename)
+	if err != nil {
+		return err
+	}
+	for i := len(rotated) - 1; i >= 0; i-- {
+		n := rotated[i]
+		if l.maxFiles > 0 && n >= l.maxFiles {
+			err = os.Remove(rotatedFilename(l.filename, n))
+		} else {
+			err = os.Rename(rotatedFilename(l.filename, n), rotatedFilename(l.filename, n+1))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(l.filename, rotatedFilename(l.filename, 1)); err != nil {
+		return err
+	}
+	return l.open()
+}
+
+// Close is safe to call on a nil logger
+func (l *Logger) Close() {
+	if l == nil {
+		return
+	}
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	_ = l.file.Close()
+}
+
+func (e *Entry) computeHash() ethtypes.HexBytes0xPrefix {
+	unhashed := *e
+	unhashed.Hash = nil
+	b, _ := json.Marshal(&unhashed)
+	hash := sha256.Sum256(b)
+	return hash[:]
+}
+
+func rotatedFilename(filename string, n int) string {
+	return fmt.Sprintf("%s.%d", filename, n)
+}
+
+// rotatedFileNumbers returns the numbers of the rotated files that exist, from newest (1) to oldest
+func rotatedFileNumbers(filename string) ([]int, error) {
+	dirEntries, err := os.ReadDir(filepath.Dir(filename))
+	if err != nil {
+		return nil, err
+	}
+	prefix := filepath.Base(filename) + "."
+	var numbers []int
+	for _, de := range dirEntries {
+		if !de.IsDir() && strings.HasPrefix(de.Name(), prefix) {
+			if n, err := strconv.Atoi(strings.TrimPrefix(de.Name(), prefix)); err == nil && n > 0 {
+				numbers = append(numbers, n)
+			}
+		}
+	}
+	sort.Ints(numbers)
+	return numbers, nil
+}
+
+// logFiles returns the rotated files from oldest to newest, followed by the current file if it exists
+func logFiles(filename string) ([]string, error) {
+	rotated, err := rotatedFileNumbers(filename)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(rotated)+1)
+	for i := len(rotated) - 1; i >= 0; i-- {
+		files = append(files, rotatedFilename(filename, rotated[i]))
+	}
+	if _, err := os.Stat(filename); err == nil {
+		files = append(files, filename)
+	}
+	return files, nil
+}
+
+// readEntries calls the function for each entry in the file, with its line number
+func readEntries(ctx context.Context, filename string, fn func(lineNumber int, entry *Entry) error) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return i18n.WrapError(ctx, err, signermsgs.MsgAuditLogOpenFailed, filename)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return i18n.WrapError(ctx, err, signermsgs.MsgAuditLogInvalidEntry, lineNumber, filename)
+		}
+		if !bytes.Equal(entry.Hash, entry.computeHash()) {
+			return i18n.NewError(ctx, signermsgs.MsgAuditLogHashMismatch, entry.Seq, lineNumber, filename)
+		}
+		if err := fn(lineNumber, &entry); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return i18n.WrapError(ctx, err, signermsgs.MsgAuditLogOpenFailed, filename)
+	}
+	return nil
+}
+
+// lastEntry returns nil if the file is empty
+func lastEntry(ctx context.Context, filename string) (last *Entry, err error) {
+	err = readEntries(ctx, filename, func(_ int, entry *Entry) error {
+		last = entry
+		return nil
+	})
+	return last, err
+}
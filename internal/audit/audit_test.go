@@ -0,0 +1,336 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLogger(t *testing.T, maxSize int64, maxFiles int) (*Logger, string) {
+	filename := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := NewLogger(context.Background(), &Config{File: filename, MaxSize: maxSize, MaxFiles: maxFiles})
+	assert.NoError(t, err)
+	t.Cleanup(l.Close)
+	return l, filename
+}
+
+func recordTestEntries(t *testing.T, l *Logger, count int) {
+	for i := 0; i < count; i++ {
+		err := l.Record(context.Background(), &Entry{
+			Type:     EntryTypeTransaction,
+			Identity: "team1",
+			ChainID:  12345,
+			From:     ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248"),
+			TxHash:   ethtypes.MustNewHexBytes0xPrefix("0x1f2e3d4c5b6a79881f2e3d4c5b6a79881f2e3d4c5b6a79881f2e3d4c5b6a7988"),
+			To:       ethtypes.MustNewAddress("0x497eedc4299dea2f2a364be10025d0ad0f702de3"),
+			Value:    ethtypes.NewHexInteger64(int64(i)),
+			Selector: ethtypes.MustNewHexBytes0xPrefix("0xa9059cbb"),
+		})
+		assert.NoError(t, err)
+	}
+}
+
+func readTestLines(t *testing.T, filename string) []string {
+	b, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(b)), "\n")
+}
+
+func writeTestLines(t *testing.T, filename string, lines []string) {
+	err := os.WriteFile(filename, []byte(strings.Join(lines, "\n")+"\n"), 0600)
+	assert.NoError(t, err)
+}
+
+func TestInitReadConfig(t *testing.T) {
+	config.RootConfigReset()
+	section := config.RootSection("audit")
+	InitConfig(section)
+	section.Set(ConfigFile, "audit.jsonl")
+	conf := ReadConfig(section)
+	assert.Equal(t, "audit.jsonl", conf.File)
+	assert.Equal(t, int64(100*1024*1024), conf.MaxSize)
+	assert.Equal(t, 0, conf.MaxFiles)
+}
+
+func TestRecordAndVerifyOK(t *testing.T) {
+	l, filename := newTestLogger(t, 0, 0)
+	recordTestEntries(t, l, 3)
+
+	lines := readTestLines(t, filename)
+	assert.Len(t, lines, 3)
+	var first Entry
+	err := json.Unmarshal([]byte(lines[0]), &first)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), first.Seq)
+	assert.Equal(t, GenesisHash, first.PrevHash)
+	assert.Equal(t, "team1", first.Identity)
+	assert.Equal(t, "0xa9059cbb", first.Selector.String())
+	assert.NotNil(t, first.Timestamp)
+	assert.Len(t, first.Hash, 32)
+
+	result, err := Verify(context.Background(), filename, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Entries)
+	assert.Equal(t, uint64(0), result.FirstSeq)
+	assert.Equal(t, uint64(2), result.LastSeq)
+	assert.Equal(t, l.prevHash, result.LastHash)
+}
+
+func TestRecordNilLogger(t *testing.T) {
+	var l *Logger
+	err := l.Record(context.Background(), &Entry{})
+	assert.NoError(t, err)
+	l.Close()
+}
+
+func TestRecordContinuesAfterRestart(t *testing.T) {
+	l, filename := newTestLogger(t, 0, 0)
+	recordTestEntries(t, l, 2)
+	l.Close()
+
+	l, err := NewLogger(context.Background(), &Config{File: filename})
+	assert.NoError(t, err)
+	defer l.Close()
+	assert.Equal(t, uint64(2), l.nextSeq)
+	recordTestEntries(t, l, 2)
+
+	result, err := Verify(context.Background(), filename, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.Entries)
+	assert.Equal(t, uint64(3), result.LastSeq)
+}
+
+func TestRotateChainContinues(t *testing.T) {
+	// Small enough that every entry rotates the file
+	l, filename := newTestLogger(t, 100, 0)
+	recordTestEntries(t, l, 4)
+
+	files, err := logFiles(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filename + ".3", filename + ".2", filename + ".1", filename}, files)
+
+	result, err := Verify(context.Background(), filename, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.Entries)
+	assert.Equal(t, uint64(0), result.FirstSeq)
+	assert.Equal(t, uint64(3), result.LastSeq)
+
+	// After a restart with an empty current file, the chain continues from the last rotated file
+	l.Close()
+	err = os.Remove(filename)
+	assert.NoError(t, err)
+	l, err = NewLogger(context.Background(), &Config{File: filename, MaxSize: 100})
+	assert.NoError(t, err)
+	defer l.Close()
+	assert.Equal(t, uint64(3), l.nextSeq)
+}
+
+func TestRotateMaxFiles(t *testing.T) {
+	l, filename := newTestLogger(t, 100, 2)
+	recordTestEntries(t, l, 5)
+
+	files, err := logFiles(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filename + ".2", filename + ".1", filename}, files)
+
+	_, err = Verify(context.Background(), filename, false)
+	assert.Regexp(t, "FF22203.*entry 2", err)
+
+	result, err := Verify(context.Background(), filename, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Entries)
+	assert.Equal(t, uint64(2), result.FirstSeq)
+	assert.Equal(t, uint64(4), result.LastSeq)
+}
+
+func TestVerifyPrunedNotFirstLine(t *testing.T) {
+	// Two entries in each file
+	l, filename := newTestLogger(t, 1500, 2)
+	recordTestEntries(t, l, 8)
+
+	// Removing the first entry of the oldest rotated file is not pruning
+	oldest := filename + ".2"
+	lines := readTestLines(t, oldest)
+	assert.Greater(t, len(lines), 1)
+	writeTestLines(t, oldest, append([]string{""}, lines[1:]...))
+
+	_, err := Verify(context.Background(), filename, true)
+	assert.Regexp(t, "FF22137.*line 2", err)
+}
+
+func TestVerifyTruncatedCurrentFile(t *testing.T) {
+	l, filename := newTestLogger(t, 0, 0)
+	recordTestEntries(t, l, 3)
+
+	// With no rotated files, the log must start from the genesis entry
+	lines := readTestLines(t, filename)
+	writeTestLines(t, filename, lines[1:])
+
+	_, err := Verify(context.Background(), filename, true)
+	assert.Regexp(t, "FF22137.*entry 1 on line 1", err)
+}
+
+func TestRotateFail(t *testing.T) {
+	l, filename := newTestLogger(t, 100, 0)
+	recordTestEntries(t, l, 1)
+	err := os.Remove(filename)
+	assert.NoError(t, err)
+
+	err = l.Record(context.Background(), &Entry{Type: EntryTypeMessage})
+	assert.Regexp(t, "FF22134", err)
+}
+
+func TestRecordWriteFail(t *testing.T) {
+	l, _ := newTestLogger(t, 0, 0)
+	l.Close()
+
+	err := l.Record(context.Background(), &Entry{Type: EntryTypeMessage})
+	assert.Regexp(t, "FF22134", err)
+	assert.Equal(t, uint64(0), l.nextSeq)
+}
+
+func TestNewLoggerMissingFile(t *testing.T) {
+	_, err := NewLogger(context.Background(), &Config{})
+	assert.Regexp(t, "FF22132", err)
+}
+
+func TestNewLoggerBadDir(t *testing.T) {
+	_, err := NewLogger(context.Background(), &Config{File: filepath.Join(t.TempDir(), "missing", "audit.jsonl")})
+	assert.Regexp(t, "FF22133", err)
+}
+
+func TestNewLoggerOpenFail(t *testing.T) {
+	// A directory cannot be opened for writing
+	dir := filepath.Join(t.TempDir(), "audit.jsonl")
+	err := os.Mkdir(dir, 0700)
+	assert.NoError(t, err)
+	_, err = NewLogger(context.Background(), &Config{File: dir})
+	assert.Regexp(t, "FF22133", err)
+}
+
+func TestNewLoggerModifiedLastEntry(t *testing.T) {
+	l, filename := newTestLogger(t, 0, 0)
+	recordTestEntries(t, l, 2)
+	l.Close()
+
+	lines := readTestLines(t, filename)
+	lines[1] = strings.Replace(lines[1], `"identity":"team1"`, `"identity":"team2"`, 1)
+	writeTestLines(t, filename, lines)
+
+	_, err := NewLogger(context.Background(), &Config{File: filename})
+	assert.Regexp(t, "FF22136", err)
+}
+
+func TestVerifyModifiedEntry(t *testing.T) {
+	l, filename := newTestLogger(t, 0, 0)
+	recordTestEntries(t, l, 3)
+
+	lines := readTestLines(t, filename)
+	lines[1] = strings.Replace(lines[1], `"value":"0x1"`, `"value":"0x1000"`, 1)
+	writeTestLines(t, filename, lines)
+
+	_, err := Verify(context.Background(), filename, false)
+	assert.Regexp(t, "FF22136.*entry 1 on line 2", err)
+}
+
+func TestVerifyRemovedEntry(t *testing.T) {
+	l, filename := newTestLogger(t, 0, 0)
+	recordTestEntries(t, l, 3)
+
+	lines := readTestLines(t, filename)
+	writeTestLines(t, filename, []string{lines[0], lines[2]})
+
+	_, err := Verify(context.Background(), filename, false)
+	assert.Regexp(t, "FF22137.*entry 2 on line 2", err)
+}
+
+func TestVerifyReorderedEntries(t *testing.T) {
+	l, filename := newTestLogger(t, 0, 0)
+	recordTestEntries(t, l, 3)
+
+	lines := readTestLines(t, filename)
+	writeTestLines(t, filename, []string{lines[0], lines[2], lines[1]})
+
+	_, err := Verify(context.Background(), filename, false)
+	assert.Regexp(t, "FF22137", err)
+}
+
+func TestVerifyRemovedFirstEntry(t *testing.T) {
+	l, filename := newTestLogger(t, 0, 0)
+	recordTestEntries(t, l, 2)
+
+	// Re-chained from a non-genesis hash, pretending to be the first entry
+	var entry Entry
+	err := json.Unmarshal([]byte(readTestLines(t, filename)[1]), &entry)
+	assert.NoError(t, err)
+	entry.Seq = 0
+	entry.Hash = entry.computeHash()
+	b, _ := json.Marshal(&entry)
+	writeTestLines(t, filename, []string{string(b)})
+
+	_, err = Verify(context.Background(), filename, false)
+	assert.Regexp(t, "FF22137", err)
+}
+
+func TestVerifyInvalidEntry(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.jsonl")
+	writeTestLines(t, filename, []string{"!json"})
+
+	_, err := Verify(context.Background(), filename, false)
+	assert.Regexp(t, "FF22135.*line 1", err)
+}
+
+func TestVerifyNoFilename(t *testing.T) {
+	_, err := Verify(context.Background(), "", false)
+	assert.Regexp(t, "FF22132", err)
+}
+
+func TestVerifyNoFiles(t *testing.T) {
+	_, err := Verify(context.Background(), filepath.Join(t.TempDir(), "audit.jsonl"), false)
+	assert.Regexp(t, "FF22138", err)
+}
+
+func TestVerifyBadDir(t *testing.T) {
+	_, err := Verify(context.Background(), filepath.Join(t.TempDir(), "missing", "audit.jsonl"), false)
+	assert.Regexp(t, "FF22133", err)
+}
+
+func TestVerifyEmptyFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.jsonl")
+	err := os.WriteFile(filename, []byte{}, 0600)
+	assert.NoError(t, err)
+
+	_, err = Verify(context.Background(), filename, false)
+	assert.Regexp(t, "FF22138", err)
+}
+
+func TestVerifyLineTooLong(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.jsonl")
+	writeTestLines(t, filename, []string{strings.Repeat("x", 2*1024*1024)})
+
+	_, err := Verify(context.Background(), filename, false)
+	assert.Regexp(t, "FF22133", err)
+}
@@ -0,0 +1,50 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+)
+
+const (
+	// ConfigFile the path of the current audit log file
+	ConfigFile = "file"
+	// ConfigMaxSize the size at which the current file is rotated
+	ConfigMaxSize = "maxSize"
+	// ConfigMaxFiles the number of rotated files to keep, or zero to keep all of them
+	ConfigMaxFiles = "maxFiles"
+)
+
+type Config struct {
+	File     string
+	MaxSize  int64
+	MaxFiles int
+}
+
+func InitConfig(section config.Section) {
+	section.AddKnownKey(ConfigFile)
+	section.AddKnownKey(ConfigMaxSize, "100Mb")
+	section.AddKnownKey(ConfigMaxFiles, 0)
+}
+
+func ReadConfig(section config.Section) *Config {
+	return &Config{
+		File:     section.GetString(ConfigFile),
+		MaxSize:  section.GetByteSize(ConfigMaxSize),
+		MaxFiles: section.GetInt(ConfigMaxFiles),
+	}
+}
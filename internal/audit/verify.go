@@ -0,0 +1,90 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+)
+
+// VerifyResult summarizes a log with an unbroken chain
+type VerifyResult struct {
+	Files    []string                  `json:"files"`
+	Entries  int                       `json:"entries"`
+	FirstSeq uint64                    `json:"firstSeq"`
+	LastSeq  uint64                    `json:"lastSeq"`
+	LastHash ethtypes.HexBytes0xPrefix `json:"lastHash"`
+}
+
+// Verify checks the hash of every entry in the rotated files and the current file, and that each
+// entry follows the one before it.
+//
+// If rotated files have been removed because of the maximum number of files, the chain starts
+// from the first entry of the oldest rotated file remaining (FirstSeq is non-zero). This is only
+// accepted when allowPruned is set, and a chain starting anywhere else is always broken.
+func Verify(ctx context.Context, filename string, allowPruned bool) (*VerifyResult, error) {
+	if filename == "" {
+		return nil, i18n.NewError(ctx, signermsgs.MsgAuditLogFileRequired)
+	}
+	files, err := logFiles(filename)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, signermsgs.MsgAuditLogOpenFailed, filename)
+	}
+	if len(files) == 0 {
+		return nil, i18n.NewError(ctx, signermsgs.MsgAuditLogNoEntries, filename)
+	}
+
+	result := &VerifyResult{Files: files}
+	var prev *Entry
+	for _, file := range files {
+		err := readEntries(ctx, file, func(lineNumber int, entry *Entry) error {
+			var follows bool
+			switch {
+			case prev != nil:
+				follows = entry.Seq == prev.Seq+1 && bytes.Equal(entry.PrevHash, prev.Hash)
+			case entry.Seq == 0:
+				follows = bytes.Equal(entry.PrevHash, GenesisHash)
+			case file == files[0] && file != filename && lineNumber == 1:
+				// The start of the retained files, after older rotated files were removed
+				if !allowPruned {
+					return i18n.NewError(ctx, signermsgs.MsgAuditLogPruned, entry.Seq, file)
+				}
+				follows = true
+				result.FirstSeq = entry.Seq
+			}
+			if !follows {
+				return i18n.NewError(ctx, signermsgs.MsgAuditLogChainBroken, entry.Seq, lineNumber, file)
+			}
+			result.Entries++
+			prev = entry
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if prev == nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgAuditLogNoEntries, filename)
+	}
+	result.LastSeq = prev.Seq
+	result.LastHash = prev.Hash
+	return result, nil
+}
@@ -0,0 +1,270 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/metric"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	metricsComponentName = "ffsigner"
+	metricsSubsystem     = "signer"
+	httpMetricsSubsystem = "jsonrpc_http"
+)
+
+const (
+	metricRPCRequests            = "rpc_requests_total"
+	metricRPCRequestDuration     = "rpc_request_duration_seconds"
+	metricSignings               = "signing_total"
+	metricBackendRequests        = "backend_requests_total"
+	metricBackendRequestDuration = "backend_request_duration_seconds"
+	metricWalletCacheHits        = "wallet_cache_hits_total"
+	metricWalletCacheMisses      = "wallet_cache_misses_total"
+	metricWalletDecryptDuration  = "wallet_decrypt_duration_seconds"
+	metricWalletDecryptFailures  = "wallet_decrypt_failures_total"
+	metricWalletAccounts         = "wallet_accounts"
+)
+
+const (
+	labelChain   = "chain"
+	labelMethod  = "method"
+	labelStatus  = "status"
+	labelCode    = "code"
+	labelAddress = "address"
+	labelType    = "type"
+
+	statusSuccess   = "success"
+	statusError     = "error"
+	codeUnavailable = "unavailable"
+	methodOther     = "other"
+)
+
+// The type label of the signing metrics
+const (
+	SigningTypeLegacy    = "legacy"
+	SigningTypeEIP1559   = "eip1559"
+	SigningTypeMessage   = "eip191"
+	SigningTypeTypedData = "eip712"
+)
+
+// Method names come from the caller, so only the methods the server handles and the standard
+// Ethereum JSON/RPC methods have their own label value - every other method is counted as "other",
+// so callers cannot create an unbounded number of series
+var methodLabels = map[string]bool{
+	// Handled by the server
+	"eth_accounts":            true,
+	"personal_accounts":       true,
+	"eth_sendTransaction":     true,
+	"eth_signTransaction":     true,
+	"eth_sendRawTransaction":  true,
+	"personal_sign":           true,
+	"eth_sign":                true,
+	"eth_signTypedData":       true,
+	"eth_signTypedData_v4":    true,
+	"eth_subscribe":           true,
+	"eth_unsubscribe":         true,
+	"ffsigner_getApproval":    true,
+	"ffsigner_getTransaction": true,
+	// Standard methods passed through to the backend
+	"web3_clientVersion":                      true,
+	"web3_sha3":                               true,
+	"net_version":                             true,
+	"net_listening":                           true,
+	"net_peerCount":                           true,
+	"eth_protocolVersion":                     true,
+	"eth_syncing":                             true,
+	"eth_coinbase":                            true,
+	"eth_chainId":                             true,
+	"eth_mining":                              true,
+	"eth_hashrate":                            true,
+	"eth_gasPrice":                            true,
+	"eth_maxPriorityFeePerGas":                true,
+	"eth_feeHistory":                          true,
+	"eth_blobBaseFee":                         true,
+	"eth_blockNumber":                         true,
+	"eth_getBalance":                          true,
+	"eth_getStorageAt":                        true,
+	"eth_getTransactionCount":                 true,
+	"eth_getBlockTransactionCountByHash":      true,
+	"eth_getBlockTransactionCountByNumber":    true,
+	"eth_getUncleCountByBlockHash":            true,
+	"eth_getUncleCountByBlockNumber":          true,
+	"eth_getCode":                             true,
+	"eth_call":                                true,
+	"eth_estimateGas":                         true,
+	"eth_createAccessList":                    true,
+	"eth_getBlockByHash":                      true,
+	"eth_getBlockByNumber":                    true,
+	"eth_getBlockReceipts":                    true,
+	"eth_getTransactionByHash":                true,
+	"eth_getTransactionByBlockHashAndIndex":   true,
+	"eth_getTransactionByBlockNumberAndIndex": true,
+	"eth_getTransactionReceipt":               true,
+	"eth_getUncleByBlockHashAndIndex":         true,
+	"eth_getUncleByBlockNumberAndIndex":       true,
+	"eth_newFilter":                           true,
+	"eth_newBlockFilter":                      true,
+	"eth_newPendingTransactionFilter":         true,
+	"eth_uninstallFilter":                     true,
+	"eth_getFilterChanges":                    true,
+	"eth_getFilterLogs":                       true,
+	"eth_getLogs":                             true,
+	"eth_getProof":                            true,
+}
+
+// Manager records the metrics for the process, in a single registry served by the metrics server.
+// The functions that record JSON/RPC and signing metrics can be called on a nil Manager when metrics are disabled.
+type Manager struct {
+	registry metric.MetricsRegistry
+	mm       metric.MetricsManager
+}
+
+var (
+	manager     *Manager
+	managerOnce sync.Once
+)
+
+// Get returns the metrics manager for the process, which is created on first use.
+// All servers and wallets in the process share the same metrics.
+func Get() *Manager {
+	managerOnce.Do(func() {
+		manager = newManager(context.Background())
+	})
+	return manager
+}
+
+func newManager(ctx context.Context) *Manager {
+	registry := metric.NewPrometheusMetricsRegistry(metricsComponentName)
+	// Neither can fail with the fixed subsystem names we use
+	mm, _ := registry.NewMetricsManagerForSubsystem(ctx, metricsSubsystem)
+	_ = registry.NewHTTPMetricsInstrumentationsForSubsystem(ctx, httpMetricsSubsystem, true, prometheus.DefBuckets, nil)
+
+	mm.NewCounterMetricWithLabels(ctx, metricRPCRequests, "Number of JSON/RPC requests processed", []string{labelChain, labelMethod, labelStatus}, false)
+	mm.NewHistogramMetricWithLabels(ctx, metricRPCRequestDuration, "Duration of JSON/RPC requests, including any signing and backend requests", prometheus.DefBuckets, []string{labelChain, labelMethod}, false)
+	mm.NewCounterMetricWithLabels(ctx, metricSignings, "Number of signing operations by the wallet", []string{labelAddress, labelType, labelStatus}, false)
+	mm.NewCounterMetricWithLabels(ctx, metricBackendRequests, "Number of requests sent to the backend, by JSON/RPC error code (0 for success) or 'unavailable' if the backend could not be reached", []string{labelChain, labelMethod, labelCode}, false)
+	mm.NewHistogramMetricWithLabels(ctx, metricBackendRequestDuration, "Duration of requests sent to the backend", prometheus.DefBuckets, []string{labelChain, labelMethod}, false)
+	mm.NewCounterMetric(ctx, metricWalletCacheHits, "Number of signing key lookups served from the wallet cache", false)
+	mm.NewCounterMetric(ctx, metricWalletCacheMisses, "Number of signing key lookups that required the key to be loaded and decrypted", false)
+	mm.NewHistogramMetric(ctx, metricWalletDecryptDuration, "Duration of loading and decrypting a signing key", prometheus.ExponentialBuckets(0.01, 2, 10), false)
+	mm.NewCounterMetricWithLabels(ctx, metricWalletDecryptFailures, "Number of signing keys that failed to load or decrypt", []string{labelAddress}, false)
+	mm.NewGaugeMetric(ctx, metricWalletAccounts, "Number of accounts loaded in the wallet", false)
+
+	return &Manager{
+		registry: registry,
+		mm:       mm,
+	}
+}
+
+// HTTPHandler serves the metrics in the Prometheus text format
+func (m *Manager) HTTPHandler() http.Handler {
+	h, _ := m.registry.HTTPHandler(context.Background(), promhttp.HandlerOpts{})
+	return h
+}
+
+// HTTPMiddleware records the count, duration and size of HTTP requests to the JSON/RPC server
+func (m *Manager) HTTPMiddleware() func(http.Handler) http.Handler {
+	h, _ := m.registry.GetHTTPMetricsInstrumentationsMiddlewareForSubsystem(context.Background(), httpMetricsSubsystem)
+	return h
+}
+
+func methodLabel(method string) string {
+	if methodLabels[method] {
+		return method
+	}
+	return methodOther
+}
+
+func statusLabel(err error) string {
+	if err != nil {
+		return statusError
+	}
+	return statusSuccess
+}
+
+// RPCRequestCompleted records a JSON/RPC request processed by the server
+func (m *Manager) RPCRequestCompleted(ctx context.Context, chain, method string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	method = methodLabel(method)
+	m.mm.IncCounterMetricWithLabels(ctx, metricRPCRequests, map[string]string{labelChain: chain, labelMethod: method, labelStatus: statusLabel(err)}, nil)
+	m.mm.ObserveHistogramMetricWithLabels(ctx, metricRPCRequestDuration, duration.Seconds(), map[string]string{labelChain: chain, labelMethod: method}, nil)
+}
+
+// SigningCompleted records a transaction, message or typed data signed by the wallet (or that failed)
+func (m *Manager) SigningCompleted(ctx context.Context, address, signingType string, err error) {
+	if m == nil {
+		return
+	}
+	m.mm.IncCounterMetricWithLabels(ctx, metricSignings, map[string]string{labelAddress: address, labelType: signingType, labelStatus: statusLabel(err)}, nil)
+}
+
+// BackendMetrics returns the metrics for the RPC client(s) of a chain
+func (m *Manager) BackendMetrics(chain string) rpcbackend.RPCClientMetrics {
+	if m == nil {
+		return nil
+	}
+	return &backendMetrics{m: m, chain: chain}
+}
+
+type backendMetrics struct {
+	m     *Manager
+	chain string
+}
+
+func (bm *backendMetrics) RequestCompleted(ctx context.Context, method string, duration time.Duration, errorCode rpcbackend.RPCCode, unavailable bool) {
+	method = methodLabel(method)
+	code := strconv.FormatInt(int64(errorCode), 10)
+	if unavailable {
+		code = codeUnavailable
+	}
+	bm.m.mm.IncCounterMetricWithLabels(ctx, metricBackendRequests, map[string]string{labelChain: bm.chain, labelMethod: method, labelCode: code}, nil)
+	bm.m.mm.ObserveHistogramMetricWithLabels(ctx, metricBackendRequestDuration, duration.Seconds(), map[string]string{labelChain: bm.chain, labelMethod: method}, nil)
+}
+
+// SignerCacheHit implements fswallet.Metrics
+func (m *Manager) SignerCacheHit(ctx context.Context) {
+	m.mm.IncCounterMetric(ctx, metricWalletCacheHits, nil)
+}
+
+// SignerCacheMiss implements fswallet.Metrics
+func (m *Manager) SignerCacheMiss(ctx context.Context) {
+	m.mm.IncCounterMetric(ctx, metricWalletCacheMisses, nil)
+}
+
+// KeyDecrypted implements fswallet.Metrics
+func (m *Manager) KeyDecrypted(ctx context.Context, address string, duration time.Duration, err error) {
+	m.mm.ObserveHistogramMetric(ctx, metricWalletDecryptDuration, duration.Seconds(), nil)
+	if err != nil {
+		m.mm.IncCounterMetricWithLabels(ctx, metricWalletDecryptFailures, map[string]string{labelAddress: address}, nil)
+	}
+}
+
+// AccountsLoaded implements fswallet.Metrics
+func (m *Manager) AccountsLoaded(ctx context.Context, count int) {
+	m.mm.SetGaugeMetric(ctx, metricWalletAccounts, float64(count), nil)
+}
@@ -0,0 +1,123 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/stretchr/testify/assert"
+)
+
+func scrapeTestMetrics(t *testing.T, m *Manager) string {
+	res := httptest.NewRecorder()
+	m.HTTPHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	return res.Body.String()
+}
+
+func TestGetSingleton(t *testing.T) {
+	assert.Same(t, Get(), Get())
+}
+
+func TestRPCAndSigningMetrics(t *testing.T) {
+	ctx := context.Background()
+	m := newManager(ctx)
+
+	m.RPCRequestCompleted(ctx, "default", "eth_sendTransaction", 10*time.Millisecond, nil)
+	m.RPCRequestCompleted(ctx, "default", "eth_sendTransaction", 10*time.Millisecond, fmt.Errorf("pop"))
+	m.RPCRequestCompleted(ctx, "default", "not a method!", 10*time.Millisecond, nil)
+	// Any method name a caller makes up shares the same label value
+	m.RPCRequestCompleted(ctx, "default", "made_up_method_1", 10*time.Millisecond, nil)
+	m.RPCRequestCompleted(ctx, "default", "made_up_method_2", 10*time.Millisecond, nil)
+	m.SigningCompleted(ctx, "0xfb075bb99f2aa4c49955bf703509a227d7a12248", SigningTypeEIP1559, nil)
+	m.SigningCompleted(ctx, "0xfb075bb99f2aa4c49955bf703509a227d7a12248", SigningTypeMessage, fmt.Errorf("pop"))
+
+	out := scrapeTestMetrics(t, m)
+	assert.Contains(t, out, `ff_signer_rpc_requests_total{chain="default",component="ffsigner",method="eth_sendTransaction",status="success"} 1`)
+	assert.Contains(t, out, `ff_signer_rpc_requests_total{chain="default",component="ffsigner",method="eth_sendTransaction",status="error"} 1`)
+	assert.Contains(t, out, `ff_signer_rpc_requests_total{chain="default",component="ffsigner",method="other",status="success"} 3`)
+	assert.Contains(t, out, `ff_signer_rpc_request_duration_seconds_count{chain="default",component="ffsigner",method="eth_sendTransaction"} 2`)
+	assert.NotContains(t, out, "made_up_method")
+	assert.Contains(t, out, `ff_signer_signing_total{address="0xfb075bb99f2aa4c49955bf703509a227d7a12248",component="ffsigner",status="success",type="eip1559"} 1`)
+	assert.Contains(t, out, `ff_signer_signing_total{address="0xfb075bb99f2aa4c49955bf703509a227d7a12248",component="ffsigner",status="error",type="eip191"} 1`)
+}
+
+func TestBackendMetrics(t *testing.T) {
+	ctx := context.Background()
+	m := newManager(ctx)
+
+	bm := m.BackendMetrics("chain1")
+	bm.RequestCompleted(ctx, "eth_getBalance", 5*time.Millisecond, 0, false)
+	bm.RequestCompleted(ctx, "eth_sendRawTransaction", 5*time.Millisecond, -32000, false)
+	bm.RequestCompleted(ctx, "eth_sendRawTransaction", 5*time.Millisecond, rpcbackend.RPCCodeInternalError, true)
+	bm.RequestCompleted(ctx, "debug_traceTransaction", 5*time.Millisecond, 0, false)
+
+	out := scrapeTestMetrics(t, m)
+	assert.Contains(t, out, `ff_signer_backend_requests_total{chain="chain1",code="0",component="ffsigner",method="eth_getBalance"} 1`)
+	assert.Contains(t, out, `ff_signer_backend_requests_total{chain="chain1",code="-32000",component="ffsigner",method="eth_sendRawTransaction"} 1`)
+	assert.Contains(t, out, `ff_signer_backend_requests_total{chain="chain1",code="unavailable",component="ffsigner",method="eth_sendRawTransaction"} 1`)
+	assert.Contains(t, out, `ff_signer_backend_request_duration_seconds_count{chain="chain1",component="ffsigner",method="eth_sendRawTransaction"} 2`)
+	assert.Contains(t, out, `ff_signer_backend_requests_total{chain="chain1",code="0",component="ffsigner",method="other"} 1`)
+}
+
+func TestWalletMetrics(t *testing.T) {
+	ctx := context.Background()
+	m := newManager(ctx)
+
+	m.SignerCacheHit(ctx)
+	m.SignerCacheMiss(ctx)
+	m.SignerCacheMiss(ctx)
+	m.KeyDecrypted(ctx, "0xfb075bb99f2aa4c49955bf703509a227d7a12248", time.Second, nil)
+	m.KeyDecrypted(ctx, "0x497eedc4299dea2f2a364be10025d0ad0f702de3", time.Second, fmt.Errorf("pop"))
+	m.AccountsLoaded(ctx, 5)
+
+	out := scrapeTestMetrics(t, m)
+	assert.Contains(t, out, `ff_signer_wallet_cache_hits_total{component="ffsigner"} 1`)
+	assert.Contains(t, out, `ff_signer_wallet_cache_misses_total{component="ffsigner"} 2`)
+	assert.Contains(t, out, `ff_signer_wallet_decrypt_duration_seconds_count{component="ffsigner"} 2`)
+	assert.Contains(t, out, `ff_signer_wallet_decrypt_failures_total{address="0x497eedc4299dea2f2a364be10025d0ad0f702de3",component="ffsigner"} 1`)
+	assert.Contains(t, out, `ff_signer_wallet_accounts{component="ffsigner"} 5`)
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	m := newManager(context.Background())
+
+	// The middleware labels the requests with the matched route
+	r := mux.NewRouter()
+	r.Use(m.HTTPMiddleware())
+	r.Path("/").Methods(http.MethodPost).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+
+	out := scrapeTestMetrics(t, m)
+	assert.Contains(t, out, `ff_jsonrpc_http_requests_total`)
+}
+
+func TestNilManager(t *testing.T) {
+	var m *Manager
+	m.RPCRequestCompleted(context.Background(), "default", "eth_chainId", time.Second, nil)
+	m.SigningCompleted(context.Background(), "0xfb075bb99f2aa4c49955bf703509a227d7a12248", SigningTypeLegacy, nil)
+	assert.Nil(t, m.BackendMetrics("default"))
+}
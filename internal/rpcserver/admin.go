@@ -0,0 +1,357 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/httpserver"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+)
+
+const (
+	adminAPIPath            = "/api/v1"
+	adminAPITitle           = "FireFly Signer Admin API"
+	adminAPIVersion         = "1.0"
+	adminAPITagWallet       = "Wallet"
+	adminAPITagChains       = "Chains"
+	adminAPITagApprovals    = "Approvals"
+	adminAPITagTransactions = "Transactions"
+)
+
+// AdminAccount is an account in the wallet, returned by the admin API
+type AdminAccount struct {
+	Address  *ethtypes.Address0xHex `ffstruct:"AdminAccount" json:"address"`
+	Cached   *bool                  `ffstruct:"AdminAccount" json:"cached,omitempty"`
+	Metadata map[string]interface{} `ffstruct:"AdminAccount" json:"metadata,omitempty"`
+}
+
+func (s *rpcServer) adminRoutes() []*ffapi.Route {
+	addressParam := []*ffapi.PathParam{
+		{Name: "address", Description: signermsgs.APIParamsAddress},
+	}
+	approvalParam := []*ffapi.PathParam{
+		{Name: "id", Description: signermsgs.APIParamsApprovalID},
+	}
+	txHashParam := []*ffapi.PathParam{
+		{Name: "hash", Description: signermsgs.APIParamsTxHash},
+	}
+	return []*ffapi.Route{
+		{
+			Name:            "getAccounts",
+			Path:            "accounts",
+			Method:          http.MethodGet,
+			Description:     signermsgs.APIEndpointsGetAccounts,
+			Tag:             adminAPITagWallet,
+			JSONOutputValue: func() interface{} { return []*AdminAccount{} },
+			JSONOutputCodes: []int{http.StatusOK},
+			JSONHandler: func(r *ffapi.APIRequest) (output interface{}, err error) {
+				return s.getAdminAccounts(r.Req.Context())
+			},
+		},
+		{
+			Name:            "getAccount",
+			Path:            "accounts/{address}",
+			Method:          http.MethodGet,
+			PathParams:      addressParam,
+			Description:     signermsgs.APIEndpointsGetAccount,
+			Tag:             adminAPITagWallet,
+			JSONOutputValue: func() interface{} { return &AdminAccount{} },
+			JSONOutputCodes: []int{http.StatusOK},
+			JSONHandler: func(r *ffapi.APIRequest) (output interface{}, err error) {
+				addr, err := s.findAdminAccount(r.Req.Context(), r.PP["address"])
+				if err != nil {
+					return nil, err
+				}
+				return s.getAdminAccount(r.Req.Context(), *addr)
+			},
+		},
+		{
+			Name:            "deleteAccountCache",
+			Path:            "accounts/{address}/cache",
+			Method:          http.MethodDelete,
+			PathParams:      addressParam,
+			Description:     signermsgs.APIEndpointsDeleteAccountCache,
+			Tag:             adminAPITagWallet,
+			JSONOutputValue: func() interface{} { return &AdminAccount{} },
+			JSONOutputCodes: []int{http.StatusOK},
+			JSONHandler: func(r *ffapi.APIRequest) (output interface{}, err error) {
+				return s.evictAdminAccount(r.Req.Context(), r.PP["address"])
+			},
+		},
+		{
+			Name:            "postRefresh",
+			Path:            "refresh",
+			Method:          http.MethodPost,
+			Description:     signermsgs.APIEndpointsPostRefresh,
+			Tag:             adminAPITagWallet,
+			JSONOutputValue: func() interface{} { return []*AdminAccount{} },
+			JSONOutputCodes: []int{http.StatusOK},
+			JSONHandler: func(r *ffapi.APIRequest) (output interface{}, err error) {
+				if err := s.wallet.Refresh(r.Req.Context()); err != nil {
+					return nil, err
+				}
+				return s.getAdminAccounts(r.Req.Context())
+			},
+		},
+		{
+			Name:   "getApprovals",
+			Path:   "approvals",
+			Method: http.MethodGet,
+			QueryParams: []*ffapi.QueryParam{
+				{Name: "status", Description: signermsgs.APIParamsApprovalStatus},
+			},
+			Description:     signermsgs.APIEndpointsGetApprovals,
+			Tag:             adminAPITagApprovals,
+			JSONOutputValue: func() interface{} { return []*Approval{} },
+			JSONOutputCodes: []int{http.StatusOK},
+			JSONHandler: func(r *ffapi.APIRequest) (output interface{}, err error) {
+				if s.approvals == nil {
+					return nil, i18n.NewError(r.Req.Context(), signermsgs.MsgApprovalsNotEnabled)
+				}
+				return s.approvals.list(ApprovalStatus(r.QP["status"])), nil
+			},
+		},
+		{
+			Name:            "getApproval",
+			Path:            "approvals/{id}",
+			Method:          http.MethodGet,
+			PathParams:      approvalParam,
+			Description:     signermsgs.APIEndpointsGetApproval,
+			Tag:             adminAPITagApprovals,
+			JSONOutputValue: func() interface{} { return &Approval{} },
+			JSONOutputCodes: []int{http.StatusOK},
+			JSONHandler: func(r *ffapi.APIRequest) (output interface{}, err error) {
+				if s.approvals == nil {
+					return nil, i18n.NewError(r.Req.Context(), signermsgs.MsgApprovalsNotEnabled)
+				}
+				return s.approvals.get(r.Req.Context(), r.PP["id"])
+			},
+		},
+		{
+			Name:            "postApprove",
+			Path:            "approvals/{id}/approve",
+			Method:          http.MethodPost,
+			PathParams:      approvalParam,
+			Description:     signermsgs.APIEndpointsPostApprove,
+			Tag:             adminAPITagApprovals,
+			JSONInputValue:  func() interface{} { return &ApprovalDecision{} },
+			JSONOutputValue: func() interface{} { return &Approval{} },
+			JSONOutputCodes: []int{http.StatusOK},
+			JSONHandler: func(r *ffapi.APIRequest) (output interface{}, err error) {
+				return s.decideApproval(r, true)
+			},
+		},
+		{
+			Name:            "postReject",
+			Path:            "approvals/{id}/reject",
+			Method:          http.MethodPost,
+			PathParams:      approvalParam,
+			Description:     signermsgs.APIEndpointsPostReject,
+			Tag:             adminAPITagApprovals,
+			JSONInputValue:  func() interface{} { return &ApprovalDecision{} },
+			JSONOutputValue: func() interface{} { return &Approval{} },
+			JSONOutputCodes: []int{http.StatusOK},
+			JSONHandler: func(r *ffapi.APIRequest) (output interface{}, err error) {
+				return s.decideApproval(r, false)
+			},
+		},
+		{
+			Name:   "getTransactions",
+			Path:   "transactions",
+			Method: http.MethodGet,
+			QueryParams: []*ffapi.QueryParam{
+				{Name: "status", Description: signermsgs.APIParamsTxStatus},
+				{Name: "from", Description: signermsgs.APIParamsTxFrom},
+			},
+			Description:     signermsgs.APIEndpointsGetTransactions,
+			Tag:             adminAPITagTransactions,
+			JSONOutputValue: func() interface{} { return []*TrackedTransaction{} },
+			JSONOutputCodes: []int{http.StatusOK},
+			JSONHandler: func(r *ffapi.APIRequest) (output interface{}, err error) {
+				if s.tracker == nil {
+					return nil, i18n.NewError(r.Req.Context(), signermsgs.MsgTrackingNotEnabled)
+				}
+				return s.tracker.list(r.Req.Context(), r.QP["status"], r.QP["from"])
+			},
+		},
+		{
+			Name:            "getTransaction",
+			Path:            "transactions/{hash}",
+			Method:          http.MethodGet,
+			PathParams:      txHashParam,
+			Description:     signermsgs.APIEndpointsGetTransaction,
+			Tag:             adminAPITagTransactions,
+			JSONOutputValue: func() interface{} { return &TrackedTransaction{} },
+			JSONOutputCodes: []int{http.StatusOK},
+			JSONHandler: func(r *ffapi.APIRequest) (output interface{}, err error) {
+				if s.tracker == nil {
+					return nil, i18n.NewError(r.Req.Context(), signermsgs.MsgTrackingNotEnabled)
+				}
+				return s.tracker.get(r.Req.Context(), r.PP["hash"])
+			},
+		},
+		{
+			Name:            "getChains",
+			Path:            "chains",
+			Method:          http.MethodGet,
+			Description:     signermsgs.APIEndpointsGetChains,
+			Tag:             adminAPITagChains,
+			JSONOutputValue: func() interface{} { return []*ChainStatus{} },
+			JSONOutputCodes: []int{http.StatusOK},
+			JSONHandler: func(r *ffapi.APIRequest) (output interface{}, err error) {
+				return s.getAdminChains(r.Req.Context()), nil
+			},
+		},
+	}
+}
+
+// adminRouter serves the admin API, with the generated OpenAPI spec and a Swagger UI on /api
+func (s *rpcServer) adminRouter() *mux.Router {
+	requestTimeout := signerconfig.AdminConfig.GetDuration(ffapi.ConfAPIRequestTimeout)
+	publicURL := signerconfig.AdminConfig.GetString(httpserver.HTTPConfPublicURL)
+	hf := &ffapi.HandlerFactory{
+		DefaultRequestTimeout: requestTimeout,
+		MaxTimeout:            requestTimeout,
+	}
+	oah := &ffapi.OpenAPIHandlerFactory{
+		BaseSwaggerGenOptions: ffapi.SwaggerGenOptions{
+			Title:                     adminAPITitle,
+			Version:                   adminAPIVersion,
+			PanicOnMissingDescription: true,
+			DefaultRequestTimeout:     requestTimeout,
+		},
+		// The servers in the OpenAPI spec use the configured public URL, or the address of the request
+		DynamicPublicURLBuilder: func(req *http.Request) string {
+			if publicURL != "" {
+				return publicURL
+			}
+			if req.TLS != nil {
+				return "https://" + req.Host
+			}
+			return "http://" + req.Host
+		},
+	}
+
+	routes := s.adminRoutes()
+	mux := mux.NewRouter().UseEncodedPath()
+	if s.auth != nil {
+		mux.Use(s.adminAuthMiddleware)
+	}
+	for _, route := range routes {
+		mux.Path(adminAPIPath + "/" + route.Path).Methods(route.Method).HandlerFunc(hf.RouteHandler(route))
+	}
+	mux.Path("/api/openapi.yaml").Methods(http.MethodGet).HandlerFunc(hf.APIWrapper(oah.OpenAPIHandler(adminAPIPath, ffapi.OpenAPIFormatYAML, routes)))
+	mux.Path("/api/openapi.json").Methods(http.MethodGet).HandlerFunc(hf.APIWrapper(oah.OpenAPIHandler(adminAPIPath, ffapi.OpenAPIFormatJSON, routes)))
+	mux.Path("/api").Methods(http.MethodGet).HandlerFunc(hf.APIWrapper(oah.SwaggerUIHandler("/api/openapi.yaml")))
+	return mux
+}
+
+func (s *rpcServer) getAdminAccounts(ctx context.Context) ([]*AdminAccount, error) {
+	addrs, err := s.wallet.GetAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]*AdminAccount, len(addrs))
+	for i, addr := range addrs {
+		if accounts[i], err = s.getAdminAccount(ctx, *addr); err != nil {
+			return nil, err
+		}
+	}
+	return accounts, nil
+}
+
+// findAdminAccount parses the address from the path, and checks it is an account in the wallet
+func (s *rpcServer) findAdminAccount(ctx context.Context, addrString string) (*ethtypes.Address0xHex, error) {
+	addr, err := ethtypes.NewAddress(addrString)
+	if err != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidAccountAddress, addrString)
+	}
+	addrs, err := s.wallet.GetAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range addrs {
+		if *a == *addr {
+			return addr, nil
+		}
+	}
+	return nil, i18n.NewError(ctx, signermsgs.MsgWalletNotAvailable, addr)
+}
+
+func (s *rpcServer) getAdminAccount(ctx context.Context, addr ethtypes.Address0xHex) (account *AdminAccount, err error) {
+	account = &AdminAccount{Address: &addr}
+	if mw, ok := s.wallet.(ethsigner.WalletMetadata); ok {
+		if account.Metadata, err = mw.GetAccountMetadata(ctx, addr); err != nil {
+			return nil, err
+		}
+	}
+	if cw, ok := s.wallet.(ethsigner.WalletCache); ok {
+		cached := cw.IsKeyCached(ctx, addr)
+		account.Cached = &cached
+	}
+	return account, nil
+}
+
+func (s *rpcServer) evictAdminAccount(ctx context.Context, addrString string) (*AdminAccount, error) {
+	cw, ok := s.wallet.(ethsigner.WalletCache)
+	if !ok {
+		return nil, i18n.NewError(ctx, signermsgs.MsgWalletNotSupported, "key caching")
+	}
+	addr, err := s.findAdminAccount(ctx, addrString)
+	if err != nil {
+		return nil, err
+	}
+	cw.EvictKey(ctx, *addr)
+	return s.getAdminAccount(ctx, *addr)
+}
+
+func (s *rpcServer) decideApproval(r *ffapi.APIRequest, approve bool) (*Approval, error) {
+	if s.approvals == nil {
+		return nil, i18n.NewError(r.Req.Context(), signermsgs.MsgApprovalsNotEnabled)
+	}
+	// With authentication the approver is the authenticated identity. Without it the admin API
+	// is only served on a loopback address, so we record the name the caller supplies.
+	decision := r.Input.(*ApprovalDecision)
+	approver := decision.Approver
+	if id := authIdentityFromContext(r.Req.Context()); id != nil {
+		approver = id.Name
+	}
+	return s.approvals.decide(r.Req.Context(), r.PP["id"], approve, approver, decision.Comment)
+}
+
+func (s *rpcServer) getAdminChains(ctx context.Context) []*ChainStatus {
+	ctx, cancelCtx := context.WithTimeout(ctx, s.healthTimeout)
+	defer cancelCtx()
+
+	chains := s.allChains()
+	statuses := make([]*ChainStatus, len(chains))
+	for i, c := range chains {
+		statuses[i] = c.checkHealth(ctx)
+		if c.multiBackend != nil {
+			statuses[i].Endpoints = c.multiBackend.EndpointStatus()
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,413 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/httpserver"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/mocks/ethsignermocks"
+	"github.com/hyperledger/firefly-signer/mocks/rpcbackendmocks"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testAdminAddr1 = "0xfb075bb99f2aa4c49955bf703509a227d7a12248"
+	testAdminAddr2 = "0x497eedc4299dea2f2a364be10025d0ad0f702de3"
+)
+
+func adminRequest(s *rpcServer, method, path string, result interface{}) int {
+	req := httptest.NewRequest(method, path, nil)
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	s.adminRouter().ServeHTTP(w, req)
+	_ = json.Unmarshal(w.Body.Bytes(), result)
+	return w.Code
+}
+
+func newTestAdminCacheWallet(s *rpcServer) *ethsignermocks.WalletCache {
+	w := &ethsignermocks.WalletCache{}
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{
+		ethtypes.MustNewAddress(testAdminAddr1),
+		ethtypes.MustNewAddress(testAdminAddr2),
+	}, nil)
+	s.wallet = w
+	return w
+}
+
+func TestAdminTypesDocumented(t *testing.T) {
+	ffapi.CheckObjectDocumented(&AdminAccount{})
+	ffapi.CheckObjectDocumented(&ChainStatus{})
+}
+
+func TestAdminGetAccountsWithMetadata(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	w := &ethsignermocks.WalletMetadata{}
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{
+		ethtypes.MustNewAddress(testAdminAddr1),
+	}, nil)
+	w.On("GetAccountMetadata", mock.Anything, *ethtypes.MustNewAddress(testAdminAddr1)).Return(map[string]interface{}{
+		"description": "key one",
+	}, nil)
+	s.wallet = w
+
+	var accounts []*AdminAccount
+	code := adminRequest(s, http.MethodGet, "/api/v1/accounts", &accounts)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, accounts, 1)
+	assert.Equal(t, testAdminAddr1, accounts[0].Address.String())
+	assert.Equal(t, "key one", accounts[0].Metadata["description"])
+	assert.Nil(t, accounts[0].Cached)
+
+	var account AdminAccount
+	code = adminRequest(s, http.MethodGet, "/api/v1/accounts/"+strings.ToUpper(testAdminAddr1[2:]), &account)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "key one", account.Metadata["description"])
+
+}
+
+func TestAdminGetAccountsMetadataFail(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	w := &ethsignermocks.WalletMetadata{}
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{
+		ethtypes.MustNewAddress(testAdminAddr1),
+	}, nil)
+	w.On("GetAccountMetadata", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+	s.wallet = w
+
+	var res map[string]interface{}
+	code := adminRequest(s, http.MethodGet, "/api/v1/accounts", &res)
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Regexp(t, "pop", res["error"])
+
+}
+
+func TestAdminGetAccountsFail(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("GetAccounts", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	var res map[string]interface{}
+	code := adminRequest(s, http.MethodGet, "/api/v1/accounts", &res)
+	assert.Equal(t, http.StatusInternalServerError, code)
+	code = adminRequest(s, http.MethodGet, "/api/v1/accounts/"+testAdminAddr1, &res)
+	assert.Equal(t, http.StatusInternalServerError, code)
+
+}
+
+func TestAdminGetAccountErrors(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+	newTestAdminCacheWallet(s)
+
+	var res map[string]interface{}
+	code := adminRequest(s, http.MethodGet, "/api/v1/accounts/not_an_address", &res)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Regexp(t, "FF22141", res["error"])
+
+	code = adminRequest(s, http.MethodGet, "/api/v1/accounts/0x1f185718734552d08278aa70f804580bab5fd2b4", &res)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Regexp(t, "FF22014", res["error"])
+
+}
+
+func TestAdminEvictAccountCache(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+	w := newTestAdminCacheWallet(s)
+	addr := *ethtypes.MustNewAddress(testAdminAddr2)
+	w.On("IsKeyCached", mock.Anything, *ethtypes.MustNewAddress(testAdminAddr1)).Return(false)
+	w.On("IsKeyCached", mock.Anything, addr).Return(true).Once()
+	w.On("EvictKey", mock.Anything, addr).Return(true).Once()
+	w.On("IsKeyCached", mock.Anything, addr).Return(false).Once()
+
+	var accounts []*AdminAccount
+	code := adminRequest(s, http.MethodGet, "/api/v1/accounts", &accounts)
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, *accounts[0].Cached)
+	assert.True(t, *accounts[1].Cached)
+
+	var account AdminAccount
+	code = adminRequest(s, http.MethodDelete, "/api/v1/accounts/"+testAdminAddr2+"/cache", &account)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, testAdminAddr2, account.Address.String())
+	assert.False(t, *account.Cached)
+
+	var res map[string]interface{}
+	code = adminRequest(s, http.MethodDelete, "/api/v1/accounts/0x1f185718734552d08278aa70f804580bab5fd2b4/cache", &res)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	w.AssertExpectations(t)
+
+}
+
+func TestAdminEvictAccountCacheNotSupported(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	var res map[string]interface{}
+	code := adminRequest(s, http.MethodDelete, "/api/v1/accounts/"+testAdminAddr1+"/cache", &res)
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Regexp(t, "FF22094", res["error"])
+
+}
+
+func TestAdminRefresh(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+	w := newTestAdminCacheWallet(s)
+	w.On("Refresh", mock.Anything).Return(nil).Once()
+	w.On("IsKeyCached", mock.Anything, mock.Anything).Return(false)
+
+	var accounts []*AdminAccount
+	code := adminRequest(s, http.MethodPost, "/api/v1/refresh", &accounts)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, accounts, 2)
+
+	w.On("Refresh", mock.Anything).Return(fmt.Errorf("pop"))
+	var res map[string]interface{}
+	code = adminRequest(s, http.MethodPost, "/api/v1/refresh", &res)
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Regexp(t, "pop", res["error"])
+
+}
+
+func TestAdminGetChains(t *testing.T) {
+
+	s, done := newTestChainsServer(t, testChain("polygon"), testChain("mainnet"))
+	defer done()
+	s.chains["mainnet"].chainID = 1
+	s.chains["polygon"].chainID = 137
+
+	mockBackendHealth(s.chains["mainnet"].backend.(*rpcbackendmocks.Backend), 1, 1000)
+	mockBackendHealth(s.chains["polygon"].backend.(*rpcbackendmocks.Backend), 137, 0)
+
+	var chains []*ChainStatus
+	code := adminRequest(s, http.MethodGet, "/api/v1/chains", &chains)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, chains, 2)
+	assert.Equal(t, "mainnet", chains[0].Name)
+	assert.Equal(t, int64(1), chains[0].ChainID)
+	assert.True(t, chains[0].Healthy)
+	assert.Equal(t, uint64(1000), chains[0].BlockNumber.Uint64())
+	assert.Equal(t, "polygon", chains[1].Name)
+	assert.Equal(t, int64(137), chains[1].ChainID)
+	assert.Nil(t, chains[1].Endpoints)
+
+}
+
+func TestAdminGetChainsMultiEndpoint(t *testing.T) {
+	signerconfig.Reset()
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfURLs, []string{"http://127.0.0.1:1", "http://127.0.0.1:2"})
+
+	ss, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.NoError(t, err)
+	s := ss.(*rpcServer)
+	defer s.Stop()
+
+	var chains []*ChainStatus
+	code := adminRequest(s, http.MethodGet, "/api/v1/chains", &chains)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, chains, 1)
+	assert.False(t, chains[0].Healthy)
+	assert.Len(t, chains[0].Endpoints, 2)
+	assert.Equal(t, "http://127.0.0.1:2", chains[0].Endpoints[1].URL)
+}
+
+func TestAdminServerOpenAPI(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	adminPort := strings.Split(ln.Addr().String(), ":")[1]
+	ln.Close()
+
+	_, s, done := newTestServer(t, func() {
+		config.Set(signerconfig.AdminEnabled, true)
+		signerconfig.AdminConfig.Set(httpserver.HTTPConfPort, adminPort)
+		signerconfig.AdminConfig.Set(httpserver.HTTPConfAddress, "127.0.0.1")
+	})
+	defer done()
+
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "net_version").Run(func(args mock.Arguments) {
+		args[1].(*ethtypes.HexInteger).BigInt().SetInt64(12345)
+	}).Return(nil)
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Initialize", mock.Anything).Return(nil)
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{
+		ethtypes.MustNewAddress(testAdminAddr1),
+	}, nil)
+	err = s.Start()
+	assert.NoError(t, err)
+
+	adminURL := fmt.Sprintf("http://127.0.0.1:%s", adminPort)
+	var spec map[string]interface{}
+	res, err := resty.New().R().SetResult(&spec).Get(adminURL + "/api/openapi.json")
+	assert.NoError(t, err)
+	assert.True(t, res.IsSuccess())
+	paths := spec["paths"].(map[string]interface{})
+	assert.Contains(t, paths, "/accounts")
+	assert.Contains(t, paths, "/accounts/{address}")
+	assert.Contains(t, paths, "/accounts/{address}/cache")
+	assert.Contains(t, paths, "/refresh")
+	assert.Contains(t, paths, "/chains")
+	assert.Equal(t, adminURL+"/api/v1", spec["servers"].([]interface{})[0].(map[string]interface{})["url"])
+
+	res, err = resty.New().R().Get(adminURL + "/api/openapi.yaml")
+	assert.NoError(t, err)
+	assert.True(t, res.IsSuccess())
+
+	res, err = resty.New().R().Get(adminURL + "/api")
+	assert.NoError(t, err)
+	assert.True(t, res.IsSuccess())
+	assert.Contains(t, res.String(), "/api/openapi.yaml")
+
+	var accounts []*AdminAccount
+	res, err = resty.New().R().SetResult(&accounts).Get(adminURL + "/api/v1/accounts")
+	assert.NoError(t, err)
+	assert.True(t, res.IsSuccess())
+	assert.Len(t, accounts, 1)
+
+}
+
+func TestBadAdminConfig(t *testing.T) {
+
+	signerconfig.Reset()
+	config.Set(signerconfig.AdminEnabled, true)
+	config.Set(signerconfig.AuthEnabled, true)
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfPort, 0)
+	signerconfig.AdminConfig.Set(httpserver.HTTPConfAddress, ":::::")
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Error(t, err)
+
+}
+
+func TestAdminRequiresLoopbackWithoutAuth(t *testing.T) {
+
+	signerconfig.Reset()
+	config.Set(signerconfig.AdminEnabled, true)
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfPort, 0)
+	signerconfig.AdminConfig.Set(httpserver.HTTPConfPort, 0)
+	signerconfig.AdminConfig.Set(httpserver.HTTPConfAddress, "0.0.0.0")
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22195.*0.0.0.0", err)
+
+	for _, address := range []string{"localhost", "127.0.0.1"} {
+		signerconfig.AdminConfig.Set(httpserver.HTTPConfAddress, address)
+		_, err = NewServer(context.Background(), &ethsignermocks.Wallet{})
+		assert.NoError(t, err)
+	}
+
+}
+
+func setTestAdminIdentities() {
+	config.Set(signerconfig.AuthEnabled, true)
+	config.Set(signerconfig.AuthIdentities, []interface{}{
+		map[string]interface{}{
+			"name":      "team1",
+			"addresses": []interface{}{testAdminAddr1},
+			"apiKeys":   []interface{}{"key1"},
+		},
+		map[string]interface{}{
+			"name":    "ops",
+			"apiKeys": []interface{}{"opskey"},
+			"admin":   true,
+		},
+	})
+}
+
+func adminRequestWithAPIKey(s *rpcServer, method, path, apiKey string, body interface{}, result interface{}) int {
+	b, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, strings.NewReader(string(b)))
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	w := httptest.NewRecorder()
+	s.adminRouter().ServeHTTP(w, req)
+	_ = json.Unmarshal(w.Body.Bytes(), result)
+	return w.Code
+}
+
+func TestAdminAuth(t *testing.T) {
+
+	_, s, done := newTestServer(t, setTestAdminIdentities)
+	defer done()
+	w := newTestAdminCacheWallet(s)
+	w.On("IsKeyCached", mock.Anything, mock.Anything).Return(false)
+
+	var res map[string]interface{}
+	code := adminRequestWithAPIKey(s, http.MethodGet, "/api/v1/accounts", "", nil, &res)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Regexp(t, "FF22116", res["error"])
+
+	code = adminRequestWithAPIKey(s, http.MethodGet, "/api/v1/accounts", "wrong", nil, &res)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Regexp(t, "FF22117", res["error"])
+
+	code = adminRequestWithAPIKey(s, http.MethodPost, "/api/v1/refresh", "key1", nil, &res)
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Regexp(t, "FF22194.*team1", res["error"])
+
+	var accounts []*AdminAccount
+	code = adminRequestWithAPIKey(s, http.MethodGet, "/api/v1/accounts", "opskey", nil, &accounts)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, accounts, 2)
+
+}
+
+func TestAdminOpenAPIPublicURL(t *testing.T) {
+
+	_, s, done := newTestServer(t, func() {
+		signerconfig.AdminConfig.Set(httpserver.HTTPConfPublicURL, "https://signer.example.com/admin")
+	})
+	defer done()
+
+	var spec map[string]interface{}
+	code := adminRequest(s, http.MethodGet, "/api/openapi.json", &spec)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "https://signer.example.com/admin/api/v1", spec["servers"].([]interface{})[0].(map[string]interface{})["url"])
+
+}
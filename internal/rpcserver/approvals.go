@@ -0,0 +1,377 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+)
+
+// rpcCodeApprovalPending is returned when a transaction is held for approval, with the approval
+// request in the error data, so the caller can query it with ffsigner_getApproval
+const rpcCodeApprovalPending rpcbackend.RPCCode = -32006
+
+type ApprovalStatus string
+
+const (
+	ApprovalStatusPending   ApprovalStatus = "pending"
+	ApprovalStatusApproved  ApprovalStatus = "approved" // while it is signed and submitted
+	ApprovalStatusSubmitted ApprovalStatus = "submitted"
+	ApprovalStatusFailed    ApprovalStatus = "failed"
+	ApprovalStatusRejected  ApprovalStatus = "rejected"
+	ApprovalStatusExpired   ApprovalStatus = "expired"
+)
+
+const (
+	approvalReasonValue       = "value"
+	approvalReasonDestination = "destination"
+	approvalReasonSelector    = "selector"
+)
+
+// Approval is an eth_sendTransaction request that is held until it is approved or rejected
+type Approval struct {
+	ID              *fftypes.UUID             `ffstruct:"Approval" json:"id"`
+	Status          ApprovalStatus            `ffstruct:"Approval" json:"status"`
+	Chain           string                    `ffstruct:"Approval" json:"chain"`
+	From            *ethtypes.Address0xHex    `ffstruct:"Approval" json:"from"`
+	Transaction     *ethsigner.Transaction    `ffstruct:"Approval" json:"transaction"`
+	Reasons         []string                  `ffstruct:"Approval" json:"reasons"`
+	Identity        string                    `ffstruct:"Approval" json:"identity,omitempty"`
+	Created         *fftypes.FFTime           `ffstruct:"Approval" json:"created"`
+	Expires         *fftypes.FFTime           `ffstruct:"Approval" json:"expires"`
+	Decided         *fftypes.FFTime           `ffstruct:"Approval" json:"decided,omitempty"`
+	Approver        string                    `ffstruct:"Approval" json:"approver,omitempty"`
+	Comment         string                    `ffstruct:"Approval" json:"comment,omitempty"`
+	TransactionHash ethtypes.HexBytes0xPrefix `ffstruct:"Approval" json:"transactionHash,omitempty"`
+	Error           string                    `ffstruct:"Approval" json:"error,omitempty"`
+}
+
+// ApprovalDecision is the input to approve or reject a pending transaction
+type ApprovalDecision struct {
+	Approver string `ffstruct:"ApprovalDecision" json:"approver,omitempty"`
+	Comment  string `ffstruct:"ApprovalDecision" json:"comment,omitempty"`
+}
+
+// approvalQueue holds eth_sendTransaction requests that match the approval criteria, until an approver
+// approves or rejects them through the admin API, or they expire. Approved transactions are prepared
+// again and signed with the context of the original request, so the identity of the caller is
+// authorized, rate limited and audited as for any other transaction.
+//
+// Approval requests are only held in memory, so pending requests are lost on restart.
+type approvalQueue struct {
+	valueThreshold    *big.Int
+	knownDestinations map[ethtypes.Address0xHex]bool
+	selectors         map[string]bool
+	timeout           time.Duration
+	retention         time.Duration
+	wait              bool
+
+	mux      sync.Mutex
+	requests map[fftypes.UUID]*approvalRequest
+}
+
+type approvalRequest struct {
+	approval *Approval
+	chain    *chain
+	ctx      context.Context // the context of the original request, without its cancellation
+	rpcReq   *rpcbackend.RPCRequest
+	timer    *time.Timer
+	done     chan struct{} // closed once the request is submitted, failed, rejected or expired
+	rpcRes   *rpcbackend.RPCResponse
+	err      error
+}
+
+func newApprovalQueue(ctx context.Context) (*approvalQueue, error) {
+	if !config.GetBool(signerconfig.ApprovalEnabled) {
+		return nil, nil
+	}
+	aq := &approvalQueue{
+		knownDestinations: make(map[ethtypes.Address0xHex]bool),
+		selectors:         make(map[string]bool),
+		timeout:           config.GetDuration(signerconfig.ApprovalTimeout),
+		retention:         config.GetDuration(signerconfig.ApprovalRetention),
+		wait:              config.GetBool(signerconfig.ApprovalWait),
+		requests:          make(map[fftypes.UUID]*approvalRequest),
+	}
+	if threshold := config.GetString(signerconfig.ApprovalValueThreshold); threshold != "" {
+		i, err := ethtypes.BigIntegerFromString(ctx, threshold)
+		if err != nil || i.Sign() < 0 {
+			return nil, i18n.NewError(ctx, signermsgs.MsgInvalidApprovalConfig, "valueThreshold", threshold)
+		}
+		aq.valueThreshold = i
+	}
+	for _, s := range config.GetStringSlice(signerconfig.ApprovalKnownDestinations) {
+		addr, err := ethtypes.NewAddress(s)
+		if err != nil {
+			return nil, i18n.NewError(ctx, signermsgs.MsgInvalidApprovalConfig, "destination", s)
+		}
+		aq.knownDestinations[*addr] = true
+	}
+	for _, s := range config.GetStringSlice(signerconfig.ApprovalSelectors) {
+		selector, err := ethtypes.NewHexBytes0xPrefix(s)
+		if err != nil || len(selector) != 4 {
+			return nil, i18n.NewError(ctx, signermsgs.MsgInvalidApprovalConfig, "selector", s)
+		}
+		aq.selectors[selector.String()] = true
+	}
+	return aq, nil
+}
+
+// criteria returns the approval criteria the transaction matches, if any. No transactions match
+// if approvals are not enabled.
+func (aq *approvalQueue) criteria(txn *ethsigner.Transaction) []string {
+	if aq == nil {
+		return nil
+	}
+	var reasons []string
+	if aq.valueThreshold != nil && txn.Value.BigInt().Cmp(aq.valueThreshold) > 0 {
+		reasons = append(reasons, approvalReasonValue)
+	}
+	if len(aq.knownDestinations) > 0 && (txn.To == nil || !aq.knownDestinations[*txn.To]) {
+		reasons = append(reasons, approvalReasonDestination)
+	}
+	if len(txn.Data) >= 4 && aq.selectors["0x"+hex.EncodeToString(txn.Data[0:4])] {
+		reasons = append(reasons, approvalReasonSelector)
+	}
+	return reasons
+}
+
+// hold adds the request to the queue. Unless configured to wait for the decision, the caller
+// gets back an error with the pending approval request immediately.
+func (aq *approvalQueue) hold(ctx context.Context, c *chain, rpcReq *rpcbackend.RPCRequest, prepared *preparedTransaction, reasons []string) (*rpcbackend.RPCResponse, error) {
+	now := time.Now()
+	created, expires := fftypes.FFTime(now), fftypes.FFTime(now.Add(aq.timeout))
+	req := &approvalRequest{
+		approval: &Approval{
+			ID:          fftypes.NewUUID(),
+			Status:      ApprovalStatusPending,
+			Chain:       c.name,
+			From:        prepared.from,
+			Transaction: prepared.txn,
+			Reasons:     reasons,
+			Created:     &created,
+			Expires:     &expires,
+		},
+		chain: c,
+		ctx:   context.WithoutCancel(ctx),
+		done:  make(chan struct{}),
+	}
+	if id := authIdentityFromContext(ctx); id != nil {
+		req.approval.Identity = id.Name
+	}
+	// The original request is updated when the transaction is sent, so we keep our own copy
+	rpcReqCopy := *rpcReq
+	req.rpcReq = &rpcReqCopy
+
+	aq.mux.Lock()
+	aq.pruneLocked(now)
+	aq.requests[*req.approval.ID] = req
+	req.timer = time.AfterFunc(aq.timeout, func() { aq.expire(req) })
+	approval := *req.approval
+	aq.mux.Unlock()
+
+	log.L(ctx).Infof("Transaction from %s held for approval in request %s, as it matches the criteria %v", approval.From, approval.ID, reasons)
+	if !aq.wait {
+		return approvalErrorResponse(ctx, &approval, rpcReq.ID)
+	}
+
+	select {
+	case <-req.done:
+		return aq.result(ctx, req, rpcReq.ID)
+	case <-ctx.Done():
+		// The caller can still query the request, which stays in the queue
+		return approvalErrorResponse(ctx, &approval, rpcReq.ID)
+	}
+}
+
+// result returns the outcome of a decided request, to a caller that waited for the decision
+func (aq *approvalQueue) result(ctx context.Context, req *approvalRequest, id *fftypes.JSONAny) (*rpcbackend.RPCResponse, error) {
+	aq.mux.Lock()
+	approval := *req.approval
+	rpcRes, err := req.rpcRes, req.err
+	aq.mux.Unlock()
+	if rpcRes != nil {
+		rpcRes.ID = id
+		return rpcRes, err
+	}
+	return approvalErrorResponse(ctx, &approval, id)
+}
+
+func (aq *approvalQueue) expire(req *approvalRequest) {
+	aq.mux.Lock()
+	defer aq.mux.Unlock()
+	if req.approval.Status == ApprovalStatusPending {
+		log.L(req.ctx).Warnf("Approval request %s expired", req.approval.ID)
+		req.approval.Status = ApprovalStatusExpired
+		req.approval.Decided = fftypes.Now()
+		close(req.done)
+	}
+}
+
+// pruneLocked removes the requests that were decided longer ago than the retention period
+func (aq *approvalQueue) pruneLocked(now time.Time) {
+	for id, req := range aq.requests {
+		if req.approval.Decided != nil && req.approval.Status != ApprovalStatusApproved &&
+			now.Sub(*req.approval.Decided.Time()) > aq.retention {
+			delete(aq.requests, id)
+		}
+	}
+}
+
+func (aq *approvalQueue) list(status ApprovalStatus) []*Approval {
+	aq.mux.Lock()
+	defer aq.mux.Unlock()
+	aq.pruneLocked(time.Now())
+	approvals := make([]*Approval, 0, len(aq.requests))
+	for _, req := range aq.requests {
+		if status == "" || req.approval.Status == status {
+			approval := *req.approval
+			approvals = append(approvals, &approval)
+		}
+	}
+	sort.Slice(approvals, func(i, j int) bool {
+		return approvals[i].Created.Time().Before(*approvals[j].Created.Time())
+	})
+	return approvals
+}
+
+func (aq *approvalQueue) get(ctx context.Context, idString string) (*Approval, error) {
+	id, err := fftypes.ParseUUID(ctx, idString)
+	if err != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidApprovalID, idString)
+	}
+	aq.mux.Lock()
+	defer aq.mux.Unlock()
+	req, ok := aq.requests[*id]
+	if !ok {
+		return nil, i18n.NewError(ctx, signermsgs.MsgApprovalNotFound, id)
+	}
+	approval := *req.approval
+	return &approval, nil
+}
+
+// decide approves or rejects a pending request. An approved transaction is signed and submitted
+// before returning, so the approver sees the transaction hash or the error.
+// The identity that requested the transaction cannot approve it.
+func (aq *approvalQueue) decide(ctx context.Context, idString string, approve bool, approver, comment string) (*Approval, error) {
+	id, err := fftypes.ParseUUID(ctx, idString)
+	if err != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidApprovalID, idString)
+	}
+
+	aq.mux.Lock()
+	req, ok := aq.requests[*id]
+	if !ok {
+		aq.mux.Unlock()
+		return nil, i18n.NewError(ctx, signermsgs.MsgApprovalNotFound, id)
+	}
+	if req.approval.Status != ApprovalStatusPending {
+		status := req.approval.Status
+		aq.mux.Unlock()
+		return nil, i18n.NewError(ctx, signermsgs.MsgApprovalNotPending, id, status)
+	}
+	if approve && req.approval.Identity != "" && req.approval.Identity == approver {
+		aq.mux.Unlock()
+		return nil, i18n.NewError(ctx, signermsgs.MsgApprovalSelfApproval, approver, id)
+	}
+	req.timer.Stop()
+	req.approval.Decided = fftypes.Now()
+	req.approval.Approver = approver
+	req.approval.Comment = comment
+	if !approve {
+		req.approval.Status = ApprovalStatusRejected
+		close(req.done)
+		approval := *req.approval
+		aq.mux.Unlock()
+		log.L(ctx).Infof("Approval request %s rejected by '%s'", id, approver)
+		return &approval, nil
+	}
+	req.approval.Status = ApprovalStatusApproved
+	aq.mux.Unlock()
+
+	log.L(ctx).Infof("Approval request %s approved by '%s'", id, approver)
+	rpcRes, err := req.chain.sendTransaction(req.ctx, req.rpcReq)
+
+	aq.mux.Lock()
+	defer aq.mux.Unlock()
+	req.rpcRes, req.err = rpcRes, err
+	if err != nil {
+		req.approval.Status = ApprovalStatusFailed
+		req.approval.Error = err.Error()
+	} else {
+		req.approval.Status = ApprovalStatusSubmitted
+		_ = json.Unmarshal(rpcRes.Result.Bytes(), &req.approval.TransactionHash)
+	}
+	close(req.done)
+	approval := *req.approval
+	return &approval, nil
+}
+
+// processGetApproval handles ffsigner_getApproval, which has parameters [id], so a caller can
+// query an approval request for an account it is authorized to use
+func (c *chain) processGetApproval(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+	if c.s.approvals == nil {
+		err := i18n.NewError(ctx, signermsgs.MsgApprovalsNotEnabled)
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+	if len(rpcReq.Params) < 1 {
+		err := i18n.NewError(ctx, signermsgs.MsgInvalidParamCount, 1, len(rpcReq.Params))
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+	var id string
+	_ = json.Unmarshal(rpcReq.Params[0].Bytes(), &id)
+	approval, err := c.s.approvals.get(ctx, id)
+	if err != nil {
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+	if err := authIdentityFromContext(ctx).authorize(ctx, *approval.From); err != nil {
+		return authErrorResponse(err, rpcReq.ID), err
+	}
+	return rpcResultResponse(rpcReq.ID, approval), nil
+}
+
+// approvalErrorResponse returns an error for a request that is pending, rejected or expired,
+// with the approval request in the error data
+func approvalErrorResponse(ctx context.Context, approval *Approval, id *fftypes.JSONAny) (*rpcbackend.RPCResponse, error) {
+	var rpcRes *rpcbackend.RPCResponse
+	var err error
+	if approval.Status == ApprovalStatusPending {
+		err = i18n.NewError(ctx, signermsgs.MsgApprovalPending, approval.ID)
+		rpcRes = rpcbackend.RPCErrorResponse(err, id, rpcCodeApprovalPending)
+	} else {
+		err = i18n.NewError(ctx, signermsgs.MsgApprovalDeclined, approval.ID, approval.Status)
+		rpcRes = rpcbackend.RPCErrorResponse(err, id, rpcCodeTransactionRejected)
+	}
+	b, _ := json.Marshal(approval)
+	rpcRes.Error.Data = *fftypes.JSONAnyPtrBytes(b)
+	return rpcRes, err
+}
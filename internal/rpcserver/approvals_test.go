@@ -0,0 +1,518 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/mocks/ethsignermocks"
+	"github.com/hyperledger/firefly-signer/mocks/rpcbackendmocks"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testApprovalFrom  = "0xfb075bb99f2aa4c49955bf703509a227d7a12248"
+	testApprovalKnown = "0x497eedc4299dea2f2a364be10025d0ad0f702de3"
+	testApprovalOther = "0x3c99f2a4b366d46bcf2277639a135a6d1288eceb"
+)
+
+func newTestApprovalServer(t *testing.T, conf ...func()) (*rpcServer, func()) {
+	_, s, done := newTestServer(t, append([]func(){func() {
+		config.Set(signerconfig.ApprovalEnabled, true)
+		config.Set(signerconfig.ApprovalValueThreshold, "1000")
+	}}, conf...)...)
+	return s, done
+}
+
+func mockApprovedSend(s *rpcServer, signErr error) {
+	w := s.wallet.(*ethsignermocks.Wallet)
+	if signErr != nil {
+		w.On("Sign", mock.Anything, mock.Anything, mock.Anything).Return(nil, signErr)
+		return
+	}
+	w.On("Sign", mock.Anything, mock.Anything, mock.Anything).Return([]byte{0x01, 0x02}, nil)
+	s.backend.(*rpcbackendmocks.Backend).On("SyncRequest", mock.Anything, mock.MatchedBy(func(rpcReq *rpcbackend.RPCRequest) bool {
+		return rpcReq.Method == "eth_sendRawTransaction" && rpcReq.Params[0].String() == `"0x0102"`
+	})).Return(&rpcbackend.RPCResponse{
+		JSONRpc: "2.0",
+		ID:      fftypes.JSONAnyPtr("1"),
+		Result:  fftypes.JSONAnyPtr(`"0xf1c0"`),
+	}, nil)
+}
+
+func testApprovalSend(ctx context.Context, s *rpcServer, value string) (*rpcbackend.RPCResponse, error) {
+	return s.processRPC(ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_sendTransaction",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(fmt.Sprintf(`{
+			"from": "%s",
+			"to": "%s",
+			"nonce": "0x5",
+			"gas": "0x5208",
+			"gasPrice": "0x1",
+			"value": "%s"
+		}`, testApprovalFrom, testApprovalKnown, value))},
+	})
+}
+
+func testHeldSend(t *testing.T, ctx context.Context, s *rpcServer, value string) *Approval {
+	rpcRes, err := testApprovalSend(ctx, s, value)
+	assert.Regexp(t, "FF22150", err)
+	assert.Equal(t, int64(-32006), rpcRes.Error.Code)
+	var approval Approval
+	err = json.Unmarshal(rpcRes.Error.Data.Bytes(), &approval)
+	assert.NoError(t, err)
+	assert.Equal(t, ApprovalStatusPending, approval.Status)
+	return &approval
+}
+
+func adminApprovalDecision(s *rpcServer, path string, decision *ApprovalDecision, result interface{}) int {
+	b, _ := json.Marshal(decision)
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(string(b)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.adminRouter().ServeHTTP(w, req)
+	_ = json.Unmarshal(w.Body.Bytes(), result)
+	return w.Code
+}
+
+func waitForPendingApproval(t *testing.T, s *rpcServer) *Approval {
+	var approvals []*Approval
+	assert.Eventually(t, func() bool {
+		approvals = s.approvals.list(ApprovalStatusPending)
+		return len(approvals) == 1
+	}, 5*time.Second, time.Millisecond)
+	return approvals[0]
+}
+
+func TestApprovalTypesDocumented(t *testing.T) {
+	ffapi.CheckObjectDocumented(&Approval{})
+	ffapi.CheckObjectDocumented(&ApprovalDecision{})
+}
+
+func TestApprovalsBadConfig(t *testing.T) {
+	signerconfig.Reset()
+	assert.Nil(t, nilApprovalQueue(t))
+
+	for _, conf := range []func(){
+		func() { config.Set(signerconfig.ApprovalValueThreshold, "lots") },
+		func() { config.Set(signerconfig.ApprovalValueThreshold, "-1") },
+		func() { config.Set(signerconfig.ApprovalKnownDestinations, []string{"not an address"}) },
+		func() { config.Set(signerconfig.ApprovalSelectors, []string{"0xa9059cbb00"}) },
+		func() { config.Set(signerconfig.ApprovalSelectors, []string{"wrong"}) },
+	} {
+		signerconfig.Reset()
+		config.Set(signerconfig.ApprovalEnabled, true)
+		conf()
+		_, err := newApprovalQueue(context.Background())
+		assert.Regexp(t, "FF22149", err)
+	}
+
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22149", err)
+}
+
+func nilApprovalQueue(t *testing.T) *approvalQueue {
+	aq, err := newApprovalQueue(context.Background())
+	assert.NoError(t, err)
+	return aq
+}
+
+func TestApprovalCriteria(t *testing.T) {
+	signerconfig.Reset()
+	config.Set(signerconfig.ApprovalEnabled, true)
+	config.Set(signerconfig.ApprovalValueThreshold, "1000")
+	config.Set(signerconfig.ApprovalKnownDestinations, []string{testApprovalKnown})
+	config.Set(signerconfig.ApprovalSelectors, []string{"0xA9059CBB"})
+	aq, err := newApprovalQueue(context.Background())
+	assert.NoError(t, err)
+
+	known := ethtypes.MustNewAddress(testApprovalKnown)
+	assert.Empty(t, aq.criteria(&ethsigner.Transaction{To: known, Value: ethtypes.NewHexInteger64(1000)}))
+	assert.Empty(t, aq.criteria(&ethsigner.Transaction{To: known, Data: ethtypes.MustNewHexBytes0xPrefix("0x095ea7b3")}))
+	assert.Equal(t, []string{"value"}, aq.criteria(&ethsigner.Transaction{To: known, Value: ethtypes.NewHexInteger64(1001)}))
+	assert.Equal(t, []string{"destination"}, aq.criteria(&ethsigner.Transaction{To: ethtypes.MustNewAddress(testApprovalOther)}))
+	assert.Equal(t, []string{"destination"}, aq.criteria(&ethsigner.Transaction{})) // deployment
+	assert.Equal(t, []string{"value", "destination", "selector"}, aq.criteria(&ethsigner.Transaction{
+		To:    ethtypes.MustNewAddress(testApprovalOther),
+		Value: ethtypes.NewHexInteger64(1001),
+		Data:  ethtypes.MustNewHexBytes0xPrefix("0xa9059cbb0000"),
+	}))
+}
+
+func TestSendTransactionBelowThresholdNotHeld(t *testing.T) {
+
+	s, done := newTestApprovalServer(t)
+	defer done()
+	mockApprovedSend(s, nil)
+
+	rpcRes, err := testApprovalSend(s.ctx, s, "0x3e8")
+	assert.NoError(t, err)
+	assert.Equal(t, `"0xf1c0"`, rpcRes.Result.String())
+	assert.Empty(t, s.approvals.list(""))
+
+}
+
+func TestSendTransactionHeldAndApproved(t *testing.T) {
+
+	s, done := newTestApprovalServer(t)
+	defer done()
+
+	ctx := withAuthIdentity(s.ctx, &authIdentity{
+		Name:     "team1",
+		accounts: map[ethtypes.Address0xHex]bool{*ethtypes.MustNewAddress(testApprovalFrom): true},
+	})
+	approval := testHeldSend(t, ctx, s, "0x3e9")
+	assert.Equal(t, defaultChainName, approval.Chain)
+	assert.Equal(t, testApprovalFrom, approval.From.String())
+	assert.Equal(t, []string{"value"}, approval.Reasons)
+	assert.Equal(t, "team1", approval.Identity)
+	assert.Equal(t, int64(0x3e9), approval.Transaction.Value.Int64())
+	s.wallet.(*ethsignermocks.Wallet).AssertNotCalled(t, "Sign", mock.Anything, mock.Anything, mock.Anything)
+
+	var approvals []*Approval
+	code := adminRequest(s, http.MethodGet, "/api/v1/approvals", &approvals)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, approvals, 1)
+	code = adminRequest(s, http.MethodGet, "/api/v1/approvals?status=rejected", &approvals)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, approvals)
+
+	// The caller can query the request on the JSON/RPC server
+	rpcRes, err := s.processRPC(ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("2"),
+		Method: "ffsigner_getApproval",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, approval.ID))},
+	})
+	assert.NoError(t, err)
+	var queried Approval
+	err = json.Unmarshal(rpcRes.Result.Bytes(), &queried)
+	assert.NoError(t, err)
+	assert.Equal(t, ApprovalStatusPending, queried.Status)
+
+	mockApprovedSend(s, nil)
+	var approved Approval
+	code = adminApprovalDecision(s, fmt.Sprintf("/api/v1/approvals/%s/approve", approval.ID), &ApprovalDecision{
+		Approver: "alice",
+		Comment:  "monthly payment",
+	}, &approved)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, ApprovalStatusSubmitted, approved.Status)
+	assert.Equal(t, "0xf1c0", approved.TransactionHash.String())
+	assert.Equal(t, "alice", approved.Approver)
+	assert.Equal(t, "monthly payment", approved.Comment)
+	assert.NotNil(t, approved.Decided)
+
+	var res map[string]interface{}
+	code = adminApprovalDecision(s, fmt.Sprintf("/api/v1/approvals/%s/reject", approval.ID), &ApprovalDecision{}, &res)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Regexp(t, "FF22152.*submitted", res["error"])
+
+	code = adminRequest(s, http.MethodGet, fmt.Sprintf("/api/v1/approvals/%s", approval.ID), &queried)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, ApprovalStatusSubmitted, queried.Status)
+
+}
+
+func TestSendTransactionHeldApprovedSignFails(t *testing.T) {
+
+	s, done := newTestApprovalServer(t)
+	defer done()
+
+	approval := testHeldSend(t, s.ctx, s, "0x3e9")
+	mockApprovedSend(s, fmt.Errorf("pop"))
+
+	var approved Approval
+	code := adminApprovalDecision(s, fmt.Sprintf("/api/v1/approvals/%s/approve", approval.ID), &ApprovalDecision{}, &approved)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, ApprovalStatusFailed, approved.Status)
+	assert.Equal(t, "pop", approved.Error)
+
+}
+
+func TestSendTransactionHeldAndRejected(t *testing.T) {
+
+	s, done := newTestApprovalServer(t)
+	defer done()
+
+	approval := testHeldSend(t, s.ctx, s, "0x3e9")
+
+	var rejected Approval
+	code := adminApprovalDecision(s, fmt.Sprintf("/api/v1/approvals/%s/reject", approval.ID), &ApprovalDecision{
+		Approver: "bob",
+	}, &rejected)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, ApprovalStatusRejected, rejected.Status)
+	assert.Equal(t, "bob", rejected.Approver)
+	s.wallet.(*ethsignermocks.Wallet).AssertNotCalled(t, "Sign", mock.Anything, mock.Anything, mock.Anything)
+
+}
+
+func TestSignTransactionMatchingApprovalCriteria(t *testing.T) {
+
+	s, done := newTestApprovalServer(t)
+	defer done()
+
+	rpcRes, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTransaction",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(fmt.Sprintf(`{
+			"from": "%s",
+			"to": "%s",
+			"nonce": "0x5",
+			"gas": "0x5208",
+			"gasPrice": "0x1",
+			"value": "0x3e9"
+		}`, testApprovalFrom, testApprovalKnown))},
+	})
+	assert.Regexp(t, "FF22197.*value", err)
+	assert.Equal(t, int64(-32003), rpcRes.Error.Code)
+	assert.Empty(t, s.approvals.list(""))
+	s.wallet.(*ethsignermocks.Wallet).AssertNotCalled(t, "Sign", mock.Anything, mock.Anything, mock.Anything)
+
+}
+
+func TestApprovalAuthenticatedApprover(t *testing.T) {
+
+	s, done := newTestApprovalServer(t, setTestAdminIdentities)
+	defer done()
+	mockApprovedSend(s, nil)
+
+	requester := s.auth.identities["team1"]
+	approval := testHeldSend(t, withAuthIdentity(s.ctx, requester), s, "0x3e9")
+	assert.Equal(t, "team1", approval.Identity)
+
+	// The requester cannot approve its own transaction, even when it is an admin
+	requester.Admin = true
+	var res map[string]interface{}
+	code := adminRequestWithAPIKey(s, http.MethodPost, fmt.Sprintf("/api/v1/approvals/%s/approve", approval.ID), "key1", &ApprovalDecision{}, &res)
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Regexp(t, "FF22196.*team1", res["error"])
+
+	// The approver in the request body is ignored in favour of the authenticated identity
+	var approved Approval
+	code = adminRequestWithAPIKey(s, http.MethodPost, fmt.Sprintf("/api/v1/approvals/%s/approve", approval.ID), "opskey", &ApprovalDecision{
+		Approver: "someone-else",
+		Comment:  "checked",
+	}, &approved)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, ApprovalStatusSubmitted, approved.Status)
+	assert.Equal(t, "ops", approved.Approver)
+	assert.Equal(t, "checked", approved.Comment)
+
+}
+
+func TestSendTransactionWaitForApproval(t *testing.T) {
+
+	s, done := newTestApprovalServer(t, func() {
+		config.Set(signerconfig.ApprovalWait, true)
+	})
+	defer done()
+	mockApprovedSend(s, nil)
+
+	result := make(chan *rpcbackend.RPCResponse)
+	go func() {
+		rpcRes, err := testApprovalSend(s.ctx, s, "0x3e9")
+		assert.NoError(t, err)
+		result <- rpcRes
+	}()
+
+	approval := waitForPendingApproval(t, s)
+	_, err := s.approvals.decide(s.ctx, approval.ID.String(), true, "alice", "")
+	assert.NoError(t, err)
+
+	rpcRes := <-result
+	assert.Equal(t, `"0xf1c0"`, rpcRes.Result.String())
+	assert.Equal(t, `1`, rpcRes.ID.String())
+
+}
+
+func TestSendTransactionWaitForApprovalRejected(t *testing.T) {
+
+	s, done := newTestApprovalServer(t, func() {
+		config.Set(signerconfig.ApprovalWait, true)
+	})
+	defer done()
+
+	result := make(chan *rpcbackend.RPCResponse)
+	go func() {
+		rpcRes, err := testApprovalSend(s.ctx, s, "0x3e9")
+		assert.Regexp(t, "FF22153.*rejected", err)
+		result <- rpcRes
+	}()
+
+	approval := waitForPendingApproval(t, s)
+	_, err := s.approvals.decide(s.ctx, approval.ID.String(), false, "bob", "")
+	assert.NoError(t, err)
+
+	rpcRes := <-result
+	assert.Equal(t, int64(-32003), rpcRes.Error.Code)
+	assert.Contains(t, rpcRes.Error.Data.String(), `"status":"rejected"`)
+
+}
+
+func TestSendTransactionWaitForApprovalExpired(t *testing.T) {
+
+	s, done := newTestApprovalServer(t, func() {
+		config.Set(signerconfig.ApprovalWait, true)
+		config.Set(signerconfig.ApprovalTimeout, "10ms")
+	})
+	defer done()
+
+	rpcRes, err := testApprovalSend(s.ctx, s, "0x3e9")
+	assert.Regexp(t, "FF22153.*expired", err)
+	assert.Equal(t, int64(-32003), rpcRes.Error.Code)
+
+	approvals := s.approvals.list(ApprovalStatusExpired)
+	assert.Len(t, approvals, 1)
+	_, err = s.approvals.decide(s.ctx, approvals[0].ID.String(), true, "", "")
+	assert.Regexp(t, "FF22152.*expired", err)
+
+}
+
+func TestSendTransactionWaitForApprovalCallerGone(t *testing.T) {
+
+	s, done := newTestApprovalServer(t, func() {
+		config.Set(signerconfig.ApprovalWait, true)
+	})
+	defer done()
+
+	ctx, cancelCtx := context.WithCancel(s.ctx)
+	go func() {
+		waitForPendingApproval(t, s)
+		cancelCtx()
+	}()
+
+	// The request stays in the queue, so the caller can query it
+	approval := testHeldSend(t, ctx, s, "0x3e9")
+	queried, err := s.approvals.get(s.ctx, approval.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, ApprovalStatusPending, queried.Status)
+
+}
+
+func TestApprovalsPruned(t *testing.T) {
+
+	s, done := newTestApprovalServer(t, func() {
+		config.Set(signerconfig.ApprovalRetention, "1ms")
+	})
+	defer done()
+
+	approval := testHeldSend(t, s.ctx, s, "0x3e9")
+	testHeldSend(t, s.ctx, s, "0x3ea")
+	_, err := s.approvals.decide(s.ctx, approval.ID.String(), false, "", "")
+	assert.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+	approvals := s.approvals.list("")
+	assert.Len(t, approvals, 1)
+	assert.Equal(t, int64(0x3ea), approvals[0].Transaction.Value.Int64())
+
+}
+
+func TestApprovalsNotFound(t *testing.T) {
+
+	s, done := newTestApprovalServer(t)
+	defer done()
+
+	var res map[string]interface{}
+	code := adminRequest(s, http.MethodGet, "/api/v1/approvals/not_a_uuid", &res)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Regexp(t, "FF22155", res["error"])
+
+	code = adminRequest(s, http.MethodGet, "/api/v1/approvals/"+fftypes.NewUUID().String(), &res)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Regexp(t, "FF22151", res["error"])
+
+	code = adminApprovalDecision(s, "/api/v1/approvals/not_a_uuid/approve", &ApprovalDecision{}, &res)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Regexp(t, "FF22155", res["error"])
+
+	code = adminApprovalDecision(s, "/api/v1/approvals/"+fftypes.NewUUID().String()+"/approve", &ApprovalDecision{}, &res)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Regexp(t, "FF22151", res["error"])
+
+}
+
+func TestGetApprovalErrors(t *testing.T) {
+
+	s, done := newTestApprovalServer(t)
+	defer done()
+
+	approval := testHeldSend(t, s.ctx, s, "0x3e9")
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "ffsigner_getApproval",
+	})
+	assert.Regexp(t, "FF22019", err)
+
+	_, err = s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "ffsigner_getApproval",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(`"not_a_uuid"`)},
+	})
+	assert.Regexp(t, "FF22155", err)
+
+	// Only callers that can use the account can see the request
+	rpcRes, err := s.processRPC(withAuthIdentity(s.ctx, &authIdentity{Name: "team2"}), &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "ffsigner_getApproval",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, approval.ID))},
+	})
+	assert.Regexp(t, "FF22119", err)
+	assert.NotNil(t, rpcRes.Error)
+
+}
+
+func TestApprovalsNotEnabled(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	var res map[string]interface{}
+	code := adminRequest(s, http.MethodGet, "/api/v1/approvals", &res)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Regexp(t, "FF22154", res["error"])
+	code = adminRequest(s, http.MethodGet, "/api/v1/approvals/"+fftypes.NewUUID().String(), &res)
+	assert.Equal(t, http.StatusNotFound, code)
+	code = adminApprovalDecision(s, "/api/v1/approvals/"+fftypes.NewUUID().String()+"/reject", &ApprovalDecision{}, &res)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "ffsigner_getApproval",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(`"` + fftypes.NewUUID().String() + `"`)},
+	})
+	assert.Regexp(t, "FF22154", err)
+
+}
@@ -0,0 +1,247 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+)
+
+// rpcCodeUnauthorized is the EIP-1193 code for a method or account the caller is not authorized to use
+const rpcCodeUnauthorized rpcbackend.RPCCode = 4100
+
+// authIdentity is a client of the server, which can only use the accounts listed for it,
+// and can only use the admin API if it is an admin
+type authIdentity struct {
+	Name      string                   `json:"name"`
+	Addresses []*ethtypes.Address0xHex `json:"addresses"`
+	APIKeys   []string                 `json:"apiKeys,omitempty"`
+	Admin     bool                     `json:"admin,omitempty"`
+
+	accounts map[ethtypes.Address0xHex]bool
+}
+
+// authenticator resolves the identity of each request from an API key, a JWT bearer token,
+// or a verified TLS client certificate, in that order. If the request presents credentials
+// that are not valid, the next method is not tried.
+type authenticator struct {
+	apiKeyHeader  string
+	apiKeys       map[[32]byte]*authIdentity // by the SHA-256 hash of the key
+	identities    map[string]*authIdentity
+	jwt           *jwtVerifier // nil if JWT bearer tokens are not accepted
+	identityClaim string
+	mtls          bool
+}
+
+type authIdentityContextKey struct{}
+
+// newAuthenticator returns nil if authentication is disabled
+func newAuthenticator(ctx context.Context) (*authenticator, error) {
+	if !config.GetBool(signerconfig.AuthEnabled) {
+		return nil, nil
+	}
+	a := &authenticator{
+		apiKeyHeader:  config.GetString(signerconfig.AuthAPIKeyHeader),
+		apiKeys:       make(map[[32]byte]*authIdentity),
+		identities:    make(map[string]*authIdentity),
+		identityClaim: config.GetString(signerconfig.AuthJWTIdentityClaim),
+		mtls:          config.GetBool(signerconfig.AuthMTLSEnabled),
+	}
+
+	var identities []*authIdentity
+	b, _ := json.Marshal(config.GetObjectArray(signerconfig.AuthIdentities))
+	if err := json.Unmarshal(b, &identities); err != nil {
+		return nil, i18n.WrapError(ctx, err, signermsgs.MsgInvalidAuthIdentities)
+	}
+	for _, id := range identities {
+		if id.Name == "" {
+			return nil, i18n.NewError(ctx, signermsgs.MsgInvalidAuthIdentities)
+		}
+		if _, exists := a.identities[id.Name]; exists {
+			return nil, i18n.NewError(ctx, signermsgs.MsgDuplicateAuthIdentity, id.Name)
+		}
+		a.identities[id.Name] = id
+		id.accounts = make(map[ethtypes.Address0xHex]bool)
+		for _, addr := range id.Addresses {
+			id.accounts[*addr] = true
+		}
+		for _, apiKey := range id.APIKeys {
+			hash := sha256.Sum256([]byte(apiKey))
+			if _, exists := a.apiKeys[hash]; exists {
+				return nil, i18n.NewError(ctx, signermsgs.MsgDuplicateAPIKey, id.Name)
+			}
+			a.apiKeys[hash] = id
+		}
+	}
+
+	hmacSecret := config.GetString(signerconfig.AuthJWTHMACSecret)
+	publicKeyFile := config.GetString(signerconfig.AuthJWTPublicKeyFile)
+	if hmacSecret != "" || publicKeyFile != "" {
+		a.jwt = &jwtVerifier{
+			issuer:        config.GetString(signerconfig.AuthJWTIssuer),
+			audience:      config.GetString(signerconfig.AuthJWTAudience),
+			requireExpiry: config.GetBool(signerconfig.AuthJWTRequireExpiry),
+			now:           time.Now,
+		}
+		if publicKeyFile != "" {
+			publicKey, err := loadJWTPublicKey(ctx, publicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			a.jwt.publicKey = publicKey
+		} else {
+			a.jwt.hmacSecret = []byte(hmacSecret)
+		}
+	}
+	return a, nil
+}
+
+// authenticate returns the identity of the request, or an error with an HTTP status hint
+func (a *authenticator) authenticate(r *http.Request) (*authIdentity, error) {
+	ctx := r.Context()
+
+	if apiKey := r.Header.Get(a.apiKeyHeader); apiKey != "" {
+		id, ok := a.apiKeys[sha256.Sum256([]byte(apiKey))]
+		if !ok {
+			return nil, i18n.NewError(ctx, signermsgs.MsgAuthInvalidAPIKey)
+		}
+		return id, nil
+	}
+
+	if authHeader := r.Header.Get("Authorization"); a.jwt != nil && strings.HasPrefix(authHeader, "Bearer ") {
+		claims, err := a.jwt.verify(ctx, strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil {
+			return nil, err
+		}
+		name, _ := claims[a.identityClaim].(string)
+		if name == "" {
+			return nil, i18n.NewError(ctx, signermsgs.MsgJWTMissingIdentity, a.identityClaim)
+		}
+		return a.getIdentity(ctx, name)
+	}
+
+	// The TLS layer has already verified the certificate against the configured CA
+	if a.mtls && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return a.getIdentity(ctx, r.TLS.VerifiedChains[0][0].Subject.CommonName)
+	}
+
+	return nil, i18n.NewError(ctx, signermsgs.MsgAuthRequired)
+}
+
+func (a *authenticator) getIdentity(ctx context.Context, name string) (*authIdentity, error) {
+	id, ok := a.identities[name]
+	if !ok {
+		return nil, i18n.NewError(ctx, signermsgs.MsgAuthUnknownIdentity, name)
+	}
+	return id, nil
+}
+
+// authMiddleware rejects requests that cannot be authenticated, and adds the identity to the
+// context of the others
+func (s *rpcServer) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id, err := s.auth.authenticate(r)
+		if err != nil {
+			log.L(ctx).Errorf("Authentication failed from %s: %s", r.RemoteAddr, err)
+			// We have not read the request, so do not have its ID
+			s.replyRPC(ctx, w, authErrorResponse(err, fftypes.JSONAnyPtr("1")), authErrorStatus(err))
+			return
+		}
+		log.L(ctx).Debugf("Authenticated identity '%s'", id.Name)
+		next.ServeHTTP(w, r.WithContext(withAuthIdentity(ctx, id)))
+	})
+}
+
+// adminAuthMiddleware rejects requests to the admin API that are not from an admin identity, and adds
+// the identity to the context of the others, so approval decisions are recorded against it
+func (s *rpcServer) adminAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id, err := s.auth.authenticate(r)
+		if err == nil && !id.Admin {
+			err = i18n.NewError(ctx, signermsgs.MsgAdminNotAuthorized, id.Name)
+		}
+		if err != nil {
+			log.L(ctx).Errorf("Admin authentication failed from %s: %s", r.RemoteAddr, err)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(authErrorStatus(err))
+			_ = json.NewEncoder(w).Encode(&fftypes.RESTError{Error: err.Error()})
+			return
+		}
+		log.L(ctx).Debugf("Authenticated admin identity '%s'", id.Name)
+		next.ServeHTTP(w, r.WithContext(withAuthIdentity(ctx, id)))
+	})
+}
+
+func authErrorStatus(err error) int {
+	var ffErr i18n.FFError
+	if errors.As(err, &ffErr) {
+		return ffErr.HTTPStatus()
+	}
+	return http.StatusUnauthorized
+}
+
+func withAuthIdentity(ctx context.Context, id *authIdentity) context.Context {
+	return context.WithValue(ctx, authIdentityContextKey{}, id)
+}
+
+// authIdentityFromContext returns nil if authentication is disabled
+func authIdentityFromContext(ctx context.Context) *authIdentity {
+	id, _ := ctx.Value(authIdentityContextKey{}).(*authIdentity)
+	return id
+}
+
+// authorize checks the identity can use the account. All accounts are available without authentication.
+func (id *authIdentity) authorize(ctx context.Context, addr ethtypes.Address0xHex) error {
+	if id != nil && !id.accounts[addr] {
+		return i18n.NewError(ctx, signermsgs.MsgAccountNotAuthorized, id.Name, addr)
+	}
+	return nil
+}
+
+// filterAccounts returns only the accounts the identity can use
+func (id *authIdentity) filterAccounts(accounts []*ethtypes.Address0xHex) []*ethtypes.Address0xHex {
+	if id == nil {
+		return accounts
+	}
+	filtered := make([]*ethtypes.Address0xHex, 0, len(accounts))
+	for _, addr := range accounts {
+		if id.accounts[*addr] {
+			filtered = append(filtered, addr)
+		}
+	}
+	return filtered
+}
+
+func authErrorResponse(err error, id *fftypes.JSONAny) *rpcbackend.RPCResponse {
+	return rpcbackend.RPCErrorResponse(err, id, rpcCodeUnauthorized)
+}
@@ -0,0 +1,304 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/mocks/ethsignermocks"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testAuthAddr1 = "0xfb075bb99f2aa4c49955bf703509a227d7a12248"
+	testAuthAddr2 = "0x497eedc4299dea2f2a364be10025d0ad0f702de3"
+)
+
+func setTestAuthIdentities() {
+	config.Set(signerconfig.AuthEnabled, true)
+	config.Set(signerconfig.AuthIdentities, []interface{}{
+		map[string]interface{}{
+			"name":      "team1",
+			"addresses": []interface{}{testAuthAddr1},
+			"apiKeys":   []interface{}{"key1"},
+		},
+		map[string]interface{}{
+			"name":      "team2",
+			"addresses": []interface{}{testAuthAddr2},
+			"apiKeys":   []interface{}{"key2a", "key2b"},
+		},
+	})
+}
+
+func newTestAuthServer(t *testing.T, conf ...func()) (*rpcServer, func()) {
+	_, s, done := newTestServer(t, append([]func(){setTestAuthIdentities}, conf...)...)
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{
+		ethtypes.MustNewAddress(testAuthAddr1),
+		ethtypes.MustNewAddress(testAuthAddr2),
+	}, nil).Maybe()
+	return s, done
+}
+
+func authRPCRequest(s *rpcServer, rpcReq *rpcbackend.RPCRequest, modify func(r *http.Request)) (int, *rpcbackend.RPCResponse) {
+	b, _ := json.Marshal(rpcReq)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(b)))
+	modify(req)
+	w := httptest.NewRecorder()
+	s.router().ServeHTTP(w, req)
+	var rpcRes rpcbackend.RPCResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &rpcRes)
+	return w.Code, &rpcRes
+}
+
+func ethAccountsRequest() *rpcbackend.RPCRequest {
+	return &rpcbackend.RPCRequest{ID: fftypes.JSONAnyPtr("1"), Method: "eth_accounts"}
+}
+
+func TestAuthDisabled(t *testing.T) {
+	signerconfig.Reset()
+	a, err := newAuthenticator(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, a)
+
+	var id *authIdentity
+	assert.NoError(t, id.authorize(context.Background(), *ethtypes.MustNewAddress(testAuthAddr1)))
+	assert.Len(t, id.filterAccounts([]*ethtypes.Address0xHex{ethtypes.MustNewAddress(testAuthAddr1)}), 1)
+}
+
+func TestAuthAPIKey(t *testing.T) {
+	s, done := newTestAuthServer(t)
+	defer done()
+
+	status, rpcRes := authRPCRequest(s, ethAccountsRequest(), func(r *http.Request) {
+		r.Header.Set("X-API-Key", "key2b")
+	})
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `["`+testAuthAddr2+`"]`, rpcRes.Result.String())
+
+	status, rpcRes = authRPCRequest(s, ethAccountsRequest(), func(r *http.Request) {
+		r.Header.Set("X-API-Key", "wrong")
+	})
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, int64(rpcCodeUnauthorized), rpcRes.Error.Code)
+	assert.Regexp(t, "FF22117", rpcRes.Error.Message)
+
+	status, rpcRes = authRPCRequest(s, ethAccountsRequest(), func(r *http.Request) {})
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Regexp(t, "FF22116", rpcRes.Error.Message)
+}
+
+func TestAuthJWT(t *testing.T) {
+	secret := []byte("jwtsecret")
+	s, done := newTestAuthServer(t, func() {
+		config.Set(signerconfig.AuthJWTHMACSecret, string(secret))
+		config.Set(signerconfig.AuthJWTIdentityClaim, "team")
+	})
+	defer done()
+	s.auth.jwt.now = func() time.Time { return testJWTNow }
+
+	withToken := func(claims map[string]interface{}) func(r *http.Request) {
+		return func(r *http.Request) {
+			r.Header.Set("Authorization", "Bearer "+newTestJWT(t, "HS256", secret, claims))
+		}
+	}
+
+	status, rpcRes := authRPCRequest(s, ethAccountsRequest(), withToken(map[string]interface{}{"team": "team1"}))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `["`+testAuthAddr1+`"]`, rpcRes.Result.String())
+
+	status, rpcRes = authRPCRequest(s, ethAccountsRequest(), withToken(map[string]interface{}{"team": "team3"}))
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Regexp(t, "FF22118.*team3", rpcRes.Error.Message)
+
+	status, rpcRes = authRPCRequest(s, ethAccountsRequest(), withToken(map[string]interface{}{"sub": "team1"}))
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Regexp(t, "FF22131.*team", rpcRes.Error.Message)
+
+	status, rpcRes = authRPCRequest(s, ethAccountsRequest(), withToken(map[string]interface{}{
+		"team": "team1",
+		"exp":  testJWTNow.Unix() - 1,
+	}))
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Regexp(t, "FF22127", rpcRes.Error.Message)
+
+	status, rpcRes = authRPCRequest(s, ethAccountsRequest(), withToken(map[string]interface{}{
+		"team": "team1",
+		"exp":  nil,
+	}))
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Regexp(t, "FF22200", rpcRes.Error.Message)
+}
+
+func TestAuthJWTPublicKeyFile(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	publicKeyFile := writeTestPEM(t, "PUBLIC KEY", der)
+
+	s, done := newTestAuthServer(t, func() {
+		config.Set(signerconfig.AuthJWTPublicKeyFile, publicKeyFile)
+	})
+	defer done()
+	s.auth.jwt.now = func() time.Time { return testJWTNow }
+
+	status, rpcRes := authRPCRequest(s, ethAccountsRequest(), func(r *http.Request) {
+		r.Header.Set("Authorization", "Bearer "+newTestJWT(t, "ES256", key, map[string]interface{}{"sub": "team2"}))
+	})
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `["`+testAuthAddr2+`"]`, rpcRes.Result.String())
+}
+
+func TestAuthMTLS(t *testing.T) {
+	s, done := newTestAuthServer(t, func() {
+		config.Set(signerconfig.AuthMTLSEnabled, true)
+	})
+	defer done()
+
+	withCert := func(cn string) func(r *http.Request) {
+		return func(r *http.Request) {
+			r.TLS = &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{{Subject: pkixName(cn)}}},
+			}
+		}
+	}
+
+	status, rpcRes := authRPCRequest(s, ethAccountsRequest(), withCert("team1"))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `["`+testAuthAddr1+`"]`, rpcRes.Result.String())
+
+	status, rpcRes = authRPCRequest(s, ethAccountsRequest(), withCert("someone"))
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Regexp(t, "FF22118.*someone", rpcRes.Error.Message)
+
+	// A certificate that was presented, but not verified, is not used
+	status, _ = authRPCRequest(s, ethAccountsRequest(), func(r *http.Request) {
+		r.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Subject: pkixName("team1")}},
+		}
+	})
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestAuthSendTransactionFromOtherAccount(t *testing.T) {
+	s, done := newTestAuthServer(t)
+	defer done()
+
+	status, rpcRes := authRPCRequest(s, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_sendTransaction",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(`{
+			"from": "` + testAuthAddr2 + `",
+			"to": "` + testAuthAddr1 + `",
+			"nonce": "0x1"
+		}`)},
+	}, func(r *http.Request) {
+		r.Header.Set("X-API-Key", "key1")
+	})
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, int64(rpcCodeUnauthorized), rpcRes.Error.Code)
+	assert.Regexp(t, "FF22119.*team1.*"+testAuthAddr2, rpcRes.Error.Message)
+	s.wallet.(*ethsignermocks.Wallet).AssertNotCalled(t, "Sign", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthSignMessagesFromOtherAccount(t *testing.T) {
+	_, s, done := newTestServer(t)
+	defer done()
+
+	ctx := withAuthIdentity(s.ctx, &authIdentity{
+		Name:     "team1",
+		accounts: map[ethtypes.Address0xHex]bool{*ethtypes.MustNewAddress(testAuthAddr1): true},
+	})
+
+	eip191Wallet := &ethsignermocks.WalletEIP191{}
+	s.wallet = eip191Wallet
+	rpcRes, err := s.processRPC(ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "personal_sign",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(`"0x01"`), fftypes.JSONAnyPtr(`"` + testAuthAddr2 + `"`)},
+	})
+	assert.Regexp(t, "FF22119", err)
+	assert.Equal(t, int64(rpcCodeUnauthorized), rpcRes.Error.Code)
+	eip191Wallet.AssertNotCalled(t, "SignEIP191PersonalMessage", mock.Anything, mock.Anything, mock.Anything)
+
+	typedDataWallet := &ethsignermocks.WalletTypedData{}
+	s.wallet = typedDataWallet
+	rpcRes, err = s.processRPC(ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTypedData_v4",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(`"` + testAuthAddr2 + `"`), fftypes.JSONAnyPtr(`{}`)},
+	})
+	assert.Regexp(t, "FF22119", err)
+	assert.Equal(t, int64(rpcCodeUnauthorized), rpcRes.Error.Code)
+	typedDataWallet.AssertNotCalled(t, "SignTypedDataV4", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthBadConfig(t *testing.T) {
+	for _, identities := range []interface{}{
+		[]interface{}{map[string]interface{}{"name": "team1", "addresses": []interface{}{"not an address"}}},
+		[]interface{}{map[string]interface{}{"addresses": []interface{}{testAuthAddr1}}},
+	} {
+		signerconfig.Reset()
+		config.Set(signerconfig.AuthEnabled, true)
+		config.Set(signerconfig.AuthIdentities, identities)
+		_, err := newAuthenticator(context.Background())
+		assert.Regexp(t, "FF22120", err)
+	}
+
+	signerconfig.Reset()
+	config.Set(signerconfig.AuthEnabled, true)
+	config.Set(signerconfig.AuthIdentities, []interface{}{
+		map[string]interface{}{"name": "team1"},
+		map[string]interface{}{"name": "team1"},
+	})
+	_, err := newAuthenticator(context.Background())
+	assert.Regexp(t, "FF22121.*team1", err)
+
+	signerconfig.Reset()
+	config.Set(signerconfig.AuthEnabled, true)
+	config.Set(signerconfig.AuthIdentities, []interface{}{
+		map[string]interface{}{"name": "team1", "apiKeys": []interface{}{"key1"}},
+		map[string]interface{}{"name": "team2", "apiKeys": []interface{}{"key1"}},
+	})
+	_, err = newAuthenticator(context.Background())
+	assert.Regexp(t, "FF22122.*team2", err)
+
+	signerconfig.Reset()
+	config.Set(signerconfig.AuthEnabled, true)
+	config.Set(signerconfig.AuthJWTPublicKeyFile, filepath.Join(t.TempDir(), "missing.pem"))
+	_, err = NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22123", err)
+}
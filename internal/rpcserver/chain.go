@@ -0,0 +1,176 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+)
+
+const (
+	backendTransportHTTP      = "http"
+	backendTransportWebSocket = "websocket"
+)
+
+// chain is a blockchain that requests are signed for and submitted to, with its own backend,
+// chain ID, nonces and gas/fee settings. The wallet and signing policy are shared by all chains
+// in the server.
+type chain struct {
+	s       *rpcServer
+	name    string
+	backend rpcbackend.Backend
+	chainID int64
+	nonces  *nonceManager
+	gas     *gasManager
+
+	multiBackend rpcbackend.MultiEndpointBackend
+	wsBackend    rpcbackend.WebSocketRPCClient
+
+	lastError lastError // from the backend health checks
+}
+
+func newChain(ctx context.Context, s *rpcServer, name string, backendConf, nonceManagerConf, gasConf config.Section) (c *chain, err error) {
+	c = &chain{
+		s:       s,
+		name:    name,
+		chainID: backendConf.GetInt64(signerconfig.BackendConfChainID),
+	}
+	urls := backendConf.GetStringSlice(signerconfig.BackendConfURLs)
+	clientOptions := rpcbackend.RPCClientOptions{
+		Metrics: s.metrics.BackendMetrics(name),
+	}
+	switch transport := backendConf.GetString(signerconfig.BackendConfTransport); transport {
+	case backendTransportHTTP:
+		if len(urls) > 0 {
+			if c.multiBackend, err = newMultiEndpointBackend(ctx, backendConf, urls, clientOptions); err != nil {
+				return nil, err
+			}
+			c.backend = c.multiBackend
+			break
+		}
+		httpClient, err := ffresty.New(ctx, backendConf)
+		if err != nil {
+			return nil, err
+		}
+		c.backend = rpcbackend.NewRPCClientWithOption(httpClient, clientOptions)
+	case backendTransportWebSocket:
+		if len(urls) > 0 {
+			return nil, i18n.NewError(ctx, signermsgs.MsgMultiEndpointTransport)
+		}
+		// A single persistent connection carries all requests, as well as any subscriptions
+		if c.wsBackend, err = newWSBackend(ctx, backendConf, urls, clientOptions.Metrics); err != nil {
+			return nil, err
+		}
+		c.backend = c.wsBackend
+	default:
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidBackendTransport, transport)
+	}
+	c.nonces = newNonceManager(c.backend,
+		nonceManagerConf.GetBool(signerconfig.NonceManagerConfCacheEnabled),
+		nonceManagerConf.GetString(signerconfig.NonceManagerConfJournalFile),
+	)
+	c.gas, err = newGasManager(ctx, gasConf, c.backend)
+	if err != nil {
+		return nil, err
+	}
+	if s.wsUpgrader != nil && c.wsBackend == nil {
+		// Requests go over HTTP, but we need a WebSocket connection for subscriptions
+		if c.wsBackend, err = newWSBackend(ctx, backendConf, urls, clientOptions.Metrics); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// newMultiEndpointBackend creates a client for each URL, with the rest of the HTTP configuration
+// shared from the backend section
+func newMultiEndpointBackend(ctx context.Context, backendConf config.Section, urls []string, clientOptions rpcbackend.RPCClientOptions) (rpcbackend.MultiEndpointBackend, error) {
+	httpConf, err := ffresty.GenerateConfig(ctx, backendConf)
+	if err != nil {
+		return nil, err
+	}
+	clients := make([]*resty.Client, len(urls))
+	for i, url := range urls {
+		endpointConf := *httpConf
+		endpointConf.URL = url
+		clients[i] = ffresty.NewWithConfig(ctx, endpointConf)
+	}
+	return rpcbackend.NewMultiEndpointBackend(ctx, clients, rpcbackend.MultiEndpointOptions{
+		Strategy:            rpcbackend.EndpointStrategy(backendConf.GetString(signerconfig.BackendConfStrategy)),
+		HealthCheckInterval: backendConf.GetDuration(signerconfig.BackendConfHealthCheckInterval),
+		HealthCheckTimeout:  backendConf.GetDuration(signerconfig.BackendConfHealthCheckTimeout),
+		MaxBlockLag:         backendConf.GetUint64(signerconfig.BackendConfHealthCheckMaxBlockLag),
+		SenderPinTTL:        backendConf.GetDuration(signerconfig.BackendConfSenderPinTTL),
+		RPCClientOptions:    clientOptions,
+	})
+}
+
+// newWSBackend creates the WebSocket client for the chain. When multiple backend URLs are configured,
+// the connection uses the explicit ws.url if there is one, or otherwise the first of the URLs
+func newWSBackend(ctx context.Context, backendConf config.Section, urls []string, metrics rpcbackend.RPCClientMetrics) (rpcbackend.WebSocketRPCClient, error) {
+	wsConf, err := wsclient.GenerateConfig(ctx, backendConf)
+	if err != nil {
+		return nil, err
+	}
+	if wsConf.HTTPURL == "" && wsConf.WebSocketURL == "" && len(urls) > 0 {
+		wsConf.HTTPURL = urls[0]
+	}
+	return rpcbackend.NewWSRPCClientWithOption(wsConf, rpcbackend.WSRPCClientOptions{
+		Metrics: metrics,
+	}), nil
+}
+
+// start connects to the backend, and queries the chain ID if it is not configured
+func (c *chain) start(ctx context.Context) error {
+	// Connect first, as the WebSocket might be our backend for all requests
+	if c.wsBackend != nil {
+		if err := c.wsBackend.Connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	if c.chainID < 0 {
+		var chainID ethtypes.HexInteger
+		rpcErr := c.backend.CallRPC(ctx, &chainID, "net_version")
+		if rpcErr != nil {
+			return i18n.WrapError(ctx, rpcErr.Error(), signermsgs.MsgQueryChainID)
+		}
+		c.chainID = chainID.BigInt().Int64()
+		log.L(ctx).Infof("Chain ID %d detected for chain '%s'", c.chainID, c.name)
+	}
+
+	return c.nonces.loadJournal(ctx)
+}
+
+func (c *chain) stop() {
+	if c.wsBackend != nil {
+		c.wsBackend.Close()
+	}
+	if c.multiBackend != nil {
+		c.multiBackend.Close()
+	}
+}
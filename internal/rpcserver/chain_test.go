@@ -0,0 +1,310 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/httpserver"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/mocks/ethsignermocks"
+	"github.com/hyperledger/firefly-signer/mocks/rpcbackendmocks"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setTestChains loads the chains array from a config file, which is the only way to supply an array
+func setTestChains(t *testing.T, chains ...map[string]interface{}) {
+	// YAML is a superset of JSON
+	b, err := json.Marshal(map[string]interface{}{"chains": chains})
+	assert.NoError(t, err)
+	configFile := filepath.Join(t.TempDir(), "ffsigner.yaml")
+	err = os.WriteFile(configFile, b, 0600)
+	assert.NoError(t, err)
+	err = config.ReadConfig("ffsigner", configFile)
+	assert.NoError(t, err)
+}
+
+func testChain(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": name,
+		"backend": map[string]interface{}{
+			"url": "http://" + name + ":8545",
+		},
+		"gas": map[string]interface{}{
+			"autoPopulate": false,
+		},
+	}
+}
+
+func newTestChainsServer(t *testing.T, chains ...map[string]interface{}) (*rpcServer, func()) {
+	signerconfig.Reset()
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfPort, 0)
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfAddress, "127.0.0.1")
+	setTestChains(t, chains...)
+
+	ss, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.NoError(t, err)
+	s := ss.(*rpcServer)
+	s.ready.Store(true)
+	for _, c := range s.chains {
+		bm := &rpcbackendmocks.Backend{}
+		c.backend = bm
+		c.nonces.backend = bm
+		c.gas.backend = bm
+	}
+
+	return s, func() {
+		s.Stop()
+		_ = s.WaitStop()
+	}
+}
+
+func mockChainID(c *chain, chainID int64) {
+	c.backend.(*rpcbackendmocks.Backend).On("CallRPC", mock.Anything, mock.Anything, "net_version").Run(func(args mock.Arguments) {
+		hi := args[1].(*ethtypes.HexInteger)
+		hi.BigInt().SetInt64(chainID)
+	}).Return(nil)
+}
+
+func postChainRPC(s *rpcServer, path string, rpcReq *rpcbackend.RPCRequest) (int, *rpcbackend.RPCResponse) {
+	b, _ := json.Marshal(rpcReq)
+	w := httptest.NewRecorder()
+	s.router().ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(string(b))))
+	var rpcRes rpcbackend.RPCResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &rpcRes)
+	return w.Code, &rpcRes
+}
+
+func TestNamedChainsStart(t *testing.T) {
+
+	s, done := newTestChainsServer(t, testChain("mainnet"), testChain("polygon"))
+	defer done()
+
+	assert.Nil(t, s.chain)
+	assert.Len(t, s.chains, 2)
+	mockChainID(s.chains["mainnet"], 1)
+	mockChainID(s.chains["polygon"], 137)
+
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Initialize", mock.Anything).Return(nil)
+	err := s.Start()
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(1), s.chains["mainnet"].chainID)
+	assert.Equal(t, int64(137), s.chains["polygon"].chainID)
+}
+
+func TestNamedChainsRouting(t *testing.T) {
+
+	s, done := newTestChainsServer(t, testChain("mainnet"), testChain("polygon"))
+	defer done()
+
+	bm := s.chains["polygon"].backend.(*rpcbackendmocks.Backend)
+	bm.On("SyncRequest", mock.Anything, mock.MatchedBy(func(rpcReq *rpcbackend.RPCRequest) bool {
+		return rpcReq.Method == "eth_blockNumber"
+	})).Return(&rpcbackend.RPCResponse{
+		JSONRpc: "2.0",
+		ID:      fftypes.JSONAnyPtr("1"),
+		Result:  fftypes.JSONAnyPtr(`"0x12345"`),
+	}, nil)
+
+	status, rpcRes := postChainRPC(s, "/chains/polygon", &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_blockNumber",
+	})
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `"0x12345"`, rpcRes.Result.String())
+	s.chains["mainnet"].backend.(*rpcbackendmocks.Backend).AssertNotCalled(t, "SyncRequest", mock.Anything, mock.Anything)
+
+	// There is no default chain, or chain with this name
+	status, _ = postChainRPC(s, "/", &rpcbackend.RPCRequest{ID: fftypes.JSONAnyPtr("1"), Method: "eth_blockNumber"})
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = postChainRPC(s, "/chains/unknown", &rpcbackend.RPCRequest{ID: fftypes.JSONAnyPtr("1"), Method: "eth_blockNumber"})
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestNamedChainSignsWithChainID(t *testing.T) {
+
+	s, done := newTestChainsServer(t, testChain("mainnet"), testChain("polygon"))
+	defer done()
+	s.chains["polygon"].chainID = 137
+
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Sign", mock.Anything, mock.Anything, int64(137)).Return([]byte{0x01, 0x02}, nil)
+
+	bm := s.chains["polygon"].backend.(*rpcbackendmocks.Backend)
+	bm.On("SyncRequest", mock.Anything, mock.MatchedBy(func(rpcReq *rpcbackend.RPCRequest) bool {
+		return rpcReq.Method == "eth_sendRawTransaction" && rpcReq.Params[0].String() == `"0x0102"`
+	})).Return(&rpcbackend.RPCResponse{
+		JSONRpc: "2.0",
+		ID:      fftypes.JSONAnyPtr("1"),
+		Result:  fftypes.JSONAnyPtr(`"0xf1c0"`),
+	}, nil)
+
+	status, rpcRes := postChainRPC(s, "/chains/polygon", &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_sendTransaction",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(`{
+			"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248",
+			"to": "0x497eedc4299dea2f2a364be10025d0ad0f702de3",
+			"nonce": "0x5",
+			"gas": "0x5208",
+			"gasPrice": "0x1"
+		}`)},
+	})
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `"0xf1c0"`, rpcRes.Result.String())
+	w.AssertExpectations(t)
+}
+
+func TestNamedChainsWithDefault(t *testing.T) {
+
+	signerconfig.Reset()
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfPort, 0)
+	signerconfig.BackendConfig.Set("url", "http://default:8545")
+	setTestChains(t, testChain("polygon"))
+
+	ss, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.NoError(t, err)
+	s := ss.(*rpcServer)
+	defer s.Stop()
+
+	assert.NotNil(t, s.chain)
+	assert.Equal(t, defaultChainName, s.chain.name)
+	assert.Len(t, s.chains, 1)
+	assert.NotSame(t, s.chain.backend, s.chains["polygon"].backend)
+}
+
+func TestNamedChainsWebSocket(t *testing.T) {
+
+	signerconfig.Reset()
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfPort, 0)
+	config.Set(signerconfig.WebSocketEnabled, true)
+	setTestChains(t, testChain("polygon"))
+
+	ss, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.NoError(t, err)
+	s := ss.(*rpcServer)
+	defer s.Stop()
+
+	assert.Nil(t, s.chain)
+	assert.NotNil(t, s.chains["polygon"].wsBackend)
+}
+
+func TestNamedChainBadName(t *testing.T) {
+
+	signerconfig.Reset()
+	setTestChains(t, map[string]interface{}{"name": "bad name!"})
+
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22113.*0", err)
+}
+
+func TestNamedChainDuplicateName(t *testing.T) {
+
+	signerconfig.Reset()
+	setTestChains(t, testChain("polygon"), testChain("polygon"))
+
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22114.*polygon", err)
+}
+
+func TestNamedChainBadBackend(t *testing.T) {
+
+	signerconfig.Reset()
+	setTestChains(t, map[string]interface{}{
+		"name": "polygon",
+		"backend": map[string]interface{}{
+			"transport": "carrier-pigeon",
+		},
+	})
+
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22108", err)
+}
+
+func TestNamedChainBadGas(t *testing.T) {
+
+	signerconfig.Reset()
+	setTestChains(t, map[string]interface{}{
+		"name": "polygon",
+		"gas": map[string]interface{}{
+			"maxFeePerGas": "wrong",
+		},
+	})
+
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22100.*chains.0.gas.maxFeePerGas", err)
+}
+
+func TestDefaultChainBadConfigWithNamedChains(t *testing.T) {
+
+	signerconfig.Reset()
+	signerconfig.BackendConfig.Set("url", "http://default:8545")
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfTransport, "carrier-pigeon")
+	setTestChains(t, testChain("polygon"))
+
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22108", err)
+}
+
+func TestNamedChainStartFail(t *testing.T) {
+
+	s, done := newTestChainsServer(t, testChain("polygon"))
+	defer done()
+
+	bm := s.chains["polygon"].backend.(*rpcbackendmocks.Backend)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "net_version").Return(&rpcbackend.RPCError{Message: "pop"})
+
+	err := s.Start()
+	assert.Regexp(t, "FF22115.*polygon.*pop", err)
+}
+
+func TestNamedChainSharesPolicy(t *testing.T) {
+
+	s, done := newTestChainsServer(t, testChain("polygon"))
+	defer done()
+	s.chains["polygon"].chainID = 137
+	s.policy.rules = &policyRules{
+		AllowedTo: []*ethtypes.Address0xHex{ethtypes.MustNewAddress("0x0000000000000000000000000000000000000001")},
+	}
+
+	status, rpcRes := postChainRPC(s, "/chains/polygon", &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTransaction",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(`{
+			"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248",
+			"to": "0x497eedc4299dea2f2a364be10025d0ad0f702de3",
+			"nonce": "0x5"
+		}`)},
+	})
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, int64(-32003), rpcRes.Error.Code)
+	s.wallet.(*ethsignermocks.Wallet).AssertNotCalled(t, "Sign", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,234 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+)
+
+// gasManager fills in the gas limit and fee fields of a transaction that the caller did not supply,
+// so that we do not sign a transaction with zero values that the node will reject.
+//
+//   - The gas limit is estimated with eth_estimateGas, and multiplied by a configurable factor
+//   - If the latest block has a baseFeePerGas the chain supports EIP-1559, and eth_feeHistory
+//     is used to calculate maxPriorityFeePerGas and maxFeePerGas
+//   - Otherwise eth_gasPrice is used for a legacy gasPrice
+//   - Calculated fees are capped at the configured ceilings
+type gasManager struct {
+	backend               rpcbackend.Backend
+	enabled               bool
+	estimateMultiplier    float64
+	feeHistoryBlocks      int
+	priorityFeePercentile float64
+	maxGasPrice           *big.Int
+	maxFeePerGas          *big.Int
+	maxPriorityFeePerGas  *big.Int
+
+	mux     sync.Mutex
+	eip1559 *bool // whether the chain supports EIP-1559, cached once queried
+}
+
+type feeHistory struct {
+	BaseFeePerGas []*ethtypes.HexInteger   `json:"baseFeePerGas"`
+	Reward        [][]*ethtypes.HexInteger `json:"reward"`
+}
+
+type blockBaseFee struct {
+	BaseFeePerGas *ethtypes.HexInteger `json:"baseFeePerGas"`
+}
+
+func newGasManager(ctx context.Context, conf config.Section, backend rpcbackend.Backend) (gm *gasManager, err error) {
+	gm = &gasManager{
+		backend:               backend,
+		enabled:               conf.GetBool(signerconfig.GasConfAutoPopulate),
+		estimateMultiplier:    conf.GetFloat64(signerconfig.GasConfEstimateMultiplier),
+		feeHistoryBlocks:      conf.GetInt(signerconfig.GasConfFeeHistoryBlocks),
+		priorityFeePercentile: conf.GetFloat64(signerconfig.GasConfPriorityFeePercentile),
+	}
+	if gm.maxGasPrice, err = parseFeeCeiling(ctx, conf, signerconfig.GasConfMaxGasPrice); err != nil {
+		return nil, err
+	}
+	if gm.maxFeePerGas, err = parseFeeCeiling(ctx, conf, signerconfig.GasConfMaxFeePerGas); err != nil {
+		return nil, err
+	}
+	if gm.maxPriorityFeePerGas, err = parseFeeCeiling(ctx, conf, signerconfig.GasConfMaxPriorityFeePerGas); err != nil {
+		return nil, err
+	}
+	return gm, nil
+}
+
+func parseFeeCeiling(ctx context.Context, conf config.Section, key string) (*big.Int, error) {
+	s := conf.GetString(key)
+	if s == "" {
+		return nil, nil
+	}
+	i, err := ethtypes.BigIntegerFromString(ctx, s)
+	if err != nil || i.Sign() <= 0 {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidFeeCeiling, s, conf.Resolve(key))
+	}
+	return i, nil
+}
+
+// populate fills in any missing gas and fee fields on the transaction
+func (gm *gasManager) populate(ctx context.Context, txn *ethsigner.Transaction) error {
+	if !gm.enabled {
+		return nil
+	}
+
+	if txn.GasLimit == nil {
+		if err := gm.estimateGas(ctx, txn); err != nil {
+			return err
+		}
+	}
+
+	// If the caller chose legacy pricing, or supplied both EIP-1559 fields, there is nothing to do
+	if txn.GasPrice != nil || (txn.MaxFeePerGas != nil && txn.MaxPriorityFeePerGas != nil) {
+		return nil
+	}
+
+	// If the caller supplied one of the EIP-1559 fields, we fill in the other
+	eip1559 := txn.MaxFeePerGas != nil || txn.MaxPriorityFeePerGas != nil
+	if !eip1559 {
+		var err error
+		if eip1559, err = gm.isEIP1559(ctx); err != nil {
+			return err
+		}
+	}
+	if eip1559 {
+		return gm.populateEIP1559Fees(ctx, txn)
+	}
+	return gm.populateGasPrice(ctx, txn)
+}
+
+func (gm *gasManager) estimateGas(ctx context.Context, txn *ethsigner.Transaction) error {
+	var estimate *ethtypes.HexInteger
+	rpcErr := gm.backend.CallRPC(ctx, &estimate, "eth_estimateGas", txn)
+	if rpcErr != nil {
+		return i18n.NewError(ctx, signermsgs.MsgGasEstimateFailed, rpcErr.Message)
+	}
+	gasLimit, _ := new(big.Float).Mul(
+		new(big.Float).SetInt(estimate.BigInt()),
+		big.NewFloat(gm.estimateMultiplier),
+	).Int(nil)
+	log.L(ctx).Debugf("Gas estimate %s multiplied by %f to %s", estimate.BigInt(), gm.estimateMultiplier, gasLimit)
+	txn.GasLimit = ethtypes.NewHexInteger(gasLimit)
+	return nil
+}
+
+// isEIP1559 checks once whether the latest block has a base fee, and caches the result.
+// A restart is needed to switch to EIP-1559 pricing if the chain is upgraded while we are running.
+func (gm *gasManager) isEIP1559(ctx context.Context) (bool, error) {
+	gm.mux.Lock()
+	defer gm.mux.Unlock()
+	if gm.eip1559 == nil {
+		var block *blockBaseFee
+		rpcErr := gm.backend.CallRPC(ctx, &block, "eth_getBlockByNumber", "latest", false)
+		if rpcErr != nil {
+			return false, i18n.NewError(ctx, signermsgs.MsgFeeQueryFailed, "eth_getBlockByNumber", rpcErr.Message)
+		}
+		eip1559 := block != nil && block.BaseFeePerGas != nil
+		log.L(ctx).Infof("Chain EIP-1559 support: %t", eip1559)
+		gm.eip1559 = &eip1559
+	}
+	return *gm.eip1559, nil
+}
+
+func (gm *gasManager) populateGasPrice(ctx context.Context, txn *ethsigner.Transaction) error {
+	var gasPrice *ethtypes.HexInteger
+	rpcErr := gm.backend.CallRPC(ctx, &gasPrice, "eth_gasPrice")
+	if rpcErr != nil {
+		return i18n.NewError(ctx, signermsgs.MsgFeeQueryFailed, "eth_gasPrice", rpcErr.Message)
+	}
+	txn.GasPrice = ethtypes.NewHexInteger(capFee(ctx, "gasPrice", gasPrice.BigInt(), gm.maxGasPrice))
+	return nil
+}
+
+func (gm *gasManager) populateEIP1559Fees(ctx context.Context, txn *ethsigner.Transaction) error {
+	var history feeHistory
+	rpcErr := gm.backend.CallRPC(ctx, &history, "eth_feeHistory",
+		ethtypes.NewHexInteger64(int64(gm.feeHistoryBlocks)), "latest", []float64{gm.priorityFeePercentile})
+	if rpcErr != nil {
+		return i18n.NewError(ctx, signermsgs.MsgFeeQueryFailed, "eth_feeHistory", rpcErr.Message)
+	}
+
+	// The last entry in baseFeePerGas is the base fee of the next block
+	baseFee := new(big.Int)
+	if len(history.BaseFeePerGas) > 0 {
+		baseFee = history.BaseFeePerGas[len(history.BaseFeePerGas)-1].BigInt()
+	}
+
+	populatedPriorityFee := txn.MaxPriorityFeePerGas == nil
+	if populatedPriorityFee {
+		priorityFee := medianReward(history.Reward)
+		priorityFee = capFee(ctx, "maxPriorityFeePerGas", priorityFee, gm.maxPriorityFeePerGas)
+		if txn.MaxFeePerGas != nil && priorityFee.Cmp(txn.MaxFeePerGas.BigInt()) > 0 {
+			// The priority fee can never exceed the max fee
+			priorityFee = txn.MaxFeePerGas.BigInt()
+		}
+		txn.MaxPriorityFeePerGas = ethtypes.NewHexInteger(priorityFee)
+	}
+
+	if txn.MaxFeePerGas == nil {
+		// Allow for the base fee doubling before the transaction is mined
+		maxFee := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), txn.MaxPriorityFeePerGas.BigInt())
+		txn.MaxFeePerGas = ethtypes.NewHexInteger(capFee(ctx, "maxFeePerGas", maxFee, gm.maxFeePerGas))
+		if txn.MaxPriorityFeePerGas.BigInt().Cmp(txn.MaxFeePerGas.BigInt()) > 0 {
+			// The ceiling on the max fee applied, and nodes reject a priority fee above the max fee
+			if !populatedPriorityFee {
+				return i18n.NewError(ctx, signermsgs.MsgPriorityFeeExceedsMaxFee, txn.MaxPriorityFeePerGas.BigInt(), txn.MaxFeePerGas.BigInt())
+			}
+			log.L(ctx).Warnf("Reducing maxPriorityFeePerGas %s to the maxFeePerGas ceiling %s", txn.MaxPriorityFeePerGas.BigInt(), txn.MaxFeePerGas.BigInt())
+			txn.MaxPriorityFeePerGas = ethtypes.NewHexInteger(txn.MaxFeePerGas.BigInt())
+		}
+	}
+	return nil
+}
+
+// medianReward returns the median of the rewards at the requested percentile across the blocks
+func medianReward(rewards [][]*ethtypes.HexInteger) *big.Int {
+	values := make([]*big.Int, 0, len(rewards))
+	for _, blockRewards := range rewards {
+		if len(blockRewards) > 0 {
+			values = append(values, blockRewards[0].BigInt())
+		}
+	}
+	if len(values) == 0 {
+		return new(big.Int)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Cmp(values[j]) < 0 })
+	return values[len(values)/2]
+}
+
+func capFee(ctx context.Context, name string, value, ceiling *big.Int) *big.Int {
+	if ceiling != nil && value.Cmp(ceiling) > 0 {
+		log.L(ctx).Warnf("Calculated %s %s exceeds configured ceiling %s", name, value, ceiling)
+		return ceiling
+	}
+	return value
+}
@@ -0,0 +1,357 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/mocks/ethsignermocks"
+	"github.com/hyperledger/firefly-signer/mocks/rpcbackendmocks"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestGasManager(t *testing.T, conf ...func()) (*gasManager, *rpcbackendmocks.Backend) {
+	signerconfig.Reset()
+	for _, fn := range conf {
+		fn()
+	}
+	bm := rpcbackendmocks.NewBackend(t)
+	gm, err := newGasManager(context.Background(), signerconfig.GasConfig, bm)
+	assert.NoError(t, err)
+	return gm, bm
+}
+
+func mockRPCResult(bm *rpcbackendmocks.Backend, method string, result string) *mock.Call {
+	return bm.On("CallRPC", mock.Anything, mock.Anything, method, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			err := json.Unmarshal([]byte(result), args[1])
+			if err != nil {
+				panic(err)
+			}
+		}).Return(nil)
+}
+
+func mockRPCNoParams(bm *rpcbackendmocks.Backend, method string, result string) *mock.Call {
+	return bm.On("CallRPC", mock.Anything, mock.Anything, method).
+		Run(func(args mock.Arguments) {
+			err := json.Unmarshal([]byte(result), args[1])
+			if err != nil {
+				panic(err)
+			}
+		}).Return(nil)
+}
+
+func mockEstimateGas(bm *rpcbackendmocks.Backend, result string) *mock.Call {
+	return bm.On("CallRPC", mock.Anything, mock.Anything, "eth_estimateGas", mock.Anything).
+		Run(func(args mock.Arguments) {
+			err := json.Unmarshal([]byte(result), args[1])
+			if err != nil {
+				panic(err)
+			}
+		}).Return(nil)
+}
+
+func mockLatestBlock(bm *rpcbackendmocks.Backend, result string) *mock.Call {
+	return bm.On("CallRPC", mock.Anything, mock.Anything, "eth_getBlockByNumber", "latest", false).
+		Run(func(args mock.Arguments) {
+			err := json.Unmarshal([]byte(result), args[1])
+			if err != nil {
+				panic(err)
+			}
+		}).Return(nil)
+}
+
+const testFeeHistory = `{
+	"oldestBlock": "0x10",
+	"baseFeePerGas": ["0x64", "0x6e", "0x78"],
+	"gasUsedRatio": [0.5, 0.6],
+	"reward": [["0x5"], ["0x3"], ["0xa"]]
+}`
+
+func TestGasDisabled(t *testing.T) {
+	gm, _ := newTestGasManager(t, func() {
+		signerconfig.GasConfig.Set(signerconfig.GasConfAutoPopulate, false)
+	})
+	txn := &ethsigner.Transaction{}
+	err := gm.populate(context.Background(), txn)
+	assert.NoError(t, err)
+	assert.Nil(t, txn.GasLimit)
+	assert.Nil(t, txn.GasPrice)
+}
+
+func TestGasLegacy(t *testing.T) {
+	gm, bm := newTestGasManager(t)
+	mockEstimateGas(bm, `"0x2710"`).Once() // 10000
+	mockLatestBlock(bm, `{"number":"0x1"}`).Once()
+	mockRPCNoParams(bm, "eth_gasPrice", `"0x3b9aca00"`).Twice()
+
+	txn := &ethsigner.Transaction{}
+	err := gm.populate(context.Background(), txn)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(15000), txn.GasLimit.Int64())
+	assert.Equal(t, int64(1000000000), txn.GasPrice.Int64())
+	assert.Nil(t, txn.MaxFeePerGas)
+	assert.Nil(t, txn.MaxPriorityFeePerGas)
+
+	// Chain type is cached, and the supplied gas limit is used
+	txn = &ethsigner.Transaction{GasLimit: ethtypes.NewHexInteger64(21000)}
+	err = gm.populate(context.Background(), txn)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21000), txn.GasLimit.Int64())
+	assert.Equal(t, int64(1000000000), txn.GasPrice.Int64())
+}
+
+func TestGasLegacyCeiling(t *testing.T) {
+	gm, bm := newTestGasManager(t, func() {
+		signerconfig.GasConfig.Set(signerconfig.GasConfMaxGasPrice, "500000000")
+	})
+	mockLatestBlock(bm, `null`).Once()
+	mockRPCNoParams(bm, "eth_gasPrice", `"0x3b9aca00"`).Once()
+
+	txn := &ethsigner.Transaction{GasLimit: ethtypes.NewHexInteger64(21000)}
+	err := gm.populate(context.Background(), txn)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(500000000), txn.GasPrice.Int64())
+}
+
+func TestGasEIP1559(t *testing.T) {
+	gm, bm := newTestGasManager(t, func() {
+		signerconfig.GasConfig.Set(signerconfig.GasConfEstimateMultiplier, 1.0)
+	})
+	mockEstimateGas(bm, `"0x5208"`).Once()
+	mockLatestBlock(bm, `{"baseFeePerGas":"0x64"}`).Once()
+	mockRPCResult(bm, "eth_feeHistory", testFeeHistory).Once()
+
+	txn := &ethsigner.Transaction{}
+	err := gm.populate(context.Background(), txn)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21000), txn.GasLimit.Int64())
+	assert.Nil(t, txn.GasPrice)
+	assert.Equal(t, int64(5), txn.MaxPriorityFeePerGas.Int64())
+	assert.Equal(t, int64(2*0x78+5), txn.MaxFeePerGas.Int64())
+
+	feeHistoryCall := bm.Calls[len(bm.Calls)-1]
+	assert.Equal(t, "0x14", feeHistoryCall.Arguments[3].(*ethtypes.HexInteger).String())
+	assert.Equal(t, []float64{50}, feeHistoryCall.Arguments[5])
+}
+
+func TestGasEIP1559Ceilings(t *testing.T) {
+	gm, bm := newTestGasManager(t, func() {
+		signerconfig.GasConfig.Set(signerconfig.GasConfMaxPriorityFeePerGas, "4")
+		signerconfig.GasConfig.Set(signerconfig.GasConfMaxFeePerGas, "0x64")
+	})
+	mockLatestBlock(bm, `{"baseFeePerGas":"0x64"}`).Once()
+	mockRPCResult(bm, "eth_feeHistory", testFeeHistory).Once()
+
+	txn := &ethsigner.Transaction{GasLimit: ethtypes.NewHexInteger64(21000)}
+	err := gm.populate(context.Background(), txn)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), txn.MaxPriorityFeePerGas.Int64())
+	assert.Equal(t, int64(100), txn.MaxFeePerGas.Int64())
+}
+
+func TestGasEIP1559MaxFeeCeilingBelowPriorityFee(t *testing.T) {
+	gm, bm := newTestGasManager(t, func() {
+		signerconfig.GasConfig.Set(signerconfig.GasConfMaxFeePerGas, "3")
+	})
+	mockLatestBlock(bm, `{"baseFeePerGas":"0x64"}`).Once()
+	mockRPCResult(bm, "eth_feeHistory", testFeeHistory).Once()
+
+	txn := &ethsigner.Transaction{GasLimit: ethtypes.NewHexInteger64(21000)}
+	err := gm.populate(context.Background(), txn)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), txn.MaxPriorityFeePerGas.Int64())
+	assert.Equal(t, int64(3), txn.MaxFeePerGas.Int64())
+}
+
+func TestGasEIP1559MaxFeeCeilingBelowCallerPriorityFee(t *testing.T) {
+	gm, bm := newTestGasManager(t, func() {
+		signerconfig.GasConfig.Set(signerconfig.GasConfMaxFeePerGas, "3")
+	})
+	mockRPCResult(bm, "eth_feeHistory", testFeeHistory).Once()
+
+	txn := &ethsigner.Transaction{
+		GasLimit:             ethtypes.NewHexInteger64(21000),
+		MaxPriorityFeePerGas: ethtypes.NewHexInteger64(7),
+	}
+	err := gm.populate(context.Background(), txn)
+	assert.Regexp(t, "FF22193", err)
+}
+
+func TestGasEIP1559CallerSuppliedMaxFee(t *testing.T) {
+	gm, bm := newTestGasManager(t)
+	mockRPCResult(bm, "eth_feeHistory", testFeeHistory).Once()
+
+	txn := &ethsigner.Transaction{
+		GasLimit:     ethtypes.NewHexInteger64(21000),
+		MaxFeePerGas: ethtypes.NewHexInteger64(2),
+	}
+	err := gm.populate(context.Background(), txn)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), txn.MaxPriorityFeePerGas.Int64())
+	assert.Equal(t, int64(2), txn.MaxFeePerGas.Int64())
+}
+
+func TestGasEIP1559CallerSuppliedPriorityFee(t *testing.T) {
+	gm, bm := newTestGasManager(t)
+	mockRPCResult(bm, "eth_feeHistory", `{"baseFeePerGas":[],"reward":[]}`).Once()
+
+	txn := &ethsigner.Transaction{
+		GasLimit:             ethtypes.NewHexInteger64(21000),
+		MaxPriorityFeePerGas: ethtypes.NewHexInteger64(7),
+	}
+	err := gm.populate(context.Background(), txn)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), txn.MaxPriorityFeePerGas.Int64())
+	assert.Equal(t, int64(7), txn.MaxFeePerGas.Int64())
+}
+
+func TestGasCallerSuppliedAll(t *testing.T) {
+	gm, _ := newTestGasManager(t)
+
+	txn := &ethsigner.Transaction{
+		GasLimit: ethtypes.NewHexInteger64(21000),
+		GasPrice: ethtypes.NewHexInteger64(0),
+	}
+	err := gm.populate(context.Background(), txn)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), txn.GasPrice.Int64())
+}
+
+func TestGasMedianRewardEmpty(t *testing.T) {
+	assert.Equal(t, int64(0), medianReward([][]*ethtypes.HexInteger{{}}).Int64())
+}
+
+func TestGasEstimateFail(t *testing.T) {
+	gm, bm := newTestGasManager(t)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_estimateGas", mock.Anything).
+		Return(&rpcbackend.RPCError{Message: "execution reverted"})
+
+	err := gm.populate(context.Background(), &ethsigner.Transaction{})
+	assert.Regexp(t, "FF22098.*execution reverted", err)
+}
+
+func TestGasLatestBlockFail(t *testing.T) {
+	gm, bm := newTestGasManager(t)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_getBlockByNumber", "latest", false).
+		Return(&rpcbackend.RPCError{Message: "pop"})
+
+	err := gm.populate(context.Background(), &ethsigner.Transaction{GasLimit: ethtypes.NewHexInteger64(21000)})
+	assert.Regexp(t, "FF22099.*eth_getBlockByNumber.*pop", err)
+}
+
+func TestGasPriceFail(t *testing.T) {
+	gm, bm := newTestGasManager(t)
+	mockLatestBlock(bm, `{}`)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_gasPrice").
+		Return(&rpcbackend.RPCError{Message: "pop"})
+
+	err := gm.populate(context.Background(), &ethsigner.Transaction{GasLimit: ethtypes.NewHexInteger64(21000)})
+	assert.Regexp(t, "FF22099.*eth_gasPrice.*pop", err)
+}
+
+func TestGasFeeHistoryFail(t *testing.T) {
+	gm, bm := newTestGasManager(t)
+	mockLatestBlock(bm, `{"baseFeePerGas":"0x64"}`)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_feeHistory", mock.Anything, mock.Anything, mock.Anything).
+		Return(&rpcbackend.RPCError{Message: "pop"})
+
+	err := gm.populate(context.Background(), &ethsigner.Transaction{GasLimit: ethtypes.NewHexInteger64(21000)})
+	assert.Regexp(t, "FF22099.*eth_feeHistory.*pop", err)
+}
+
+func TestGasBadCeilings(t *testing.T) {
+	for _, key := range []string{
+		signerconfig.GasConfMaxGasPrice,
+		signerconfig.GasConfMaxFeePerGas,
+		signerconfig.GasConfMaxPriorityFeePerGas,
+	} {
+		signerconfig.Reset()
+		signerconfig.GasConfig.Set(key, "-1")
+		_, err := newGasManager(context.Background(), signerconfig.GasConfig, &rpcbackendmocks.Backend{})
+		assert.Regexp(t, "FF22100", err)
+	}
+}
+
+func TestNewServerBadGasCeiling(t *testing.T) {
+	signerconfig.Reset()
+	signerconfig.GasConfig.Set(signerconfig.GasConfMaxGasPrice, "wrong")
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22100", err)
+}
+
+func TestSendTransactionPopulatesGas(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+	s.gas.enabled = true
+
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Sign", mock.Anything, mock.MatchedBy(func(txn *ethsigner.Transaction) bool {
+		return txn.Nonce.Int64() == 10 &&
+			txn.GasLimit.Int64() == 31500 &&
+			txn.MaxPriorityFeePerGas.Int64() == 5 &&
+			txn.MaxFeePerGas.Int64() == 2*0x78+5
+	}), mock.Anything).Return([]byte{0x01}, nil)
+
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	mockPendingNonce(bm, 10)
+	mockEstimateGas(bm, `"0x5208"`)
+	mockLatestBlock(bm, `{"baseFeePerGas":"0x64"}`)
+	mockRPCResult(bm, "eth_feeHistory", testFeeHistory)
+	bm.On("SyncRequest", mock.Anything, mock.Anything).Return(&rpcbackend.RPCResponse{}, nil)
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_sendTransaction",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`{"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248"}`),
+		},
+	})
+	assert.NoError(t, err)
+
+	bm.AssertExpectations(t)
+	w.AssertExpectations(t)
+}
+
+func TestSendTransactionPopulateGasFail(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+	s.gas.enabled = true
+
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_estimateGas", mock.Anything).
+		Return(&rpcbackend.RPCError{Message: "execution reverted"})
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_sendTransaction",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`{"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248"}`),
+		},
+	})
+	assert.Regexp(t, "FF22098", err)
+}
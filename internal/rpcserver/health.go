@@ -0,0 +1,208 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+)
+
+const (
+	livenessPath  = "/livez"
+	readinessPath = "/readyz"
+	statusPath    = "/status"
+)
+
+// HealthStatus is the detailed status of the server, returned by the status endpoint.
+// The wallet and chains are only checked once the server has started.
+type HealthStatus struct {
+	Ready  bool           `json:"ready"`
+	Wallet *WalletStatus  `json:"wallet,omitempty"`
+	Chains []*ChainStatus `json:"chains,omitempty"`
+}
+
+// WalletStatus is the status of the wallet shared by all chains
+type WalletStatus struct {
+	Healthy       bool            `json:"healthy"`
+	Accounts      int             `json:"accounts"`
+	LastError     string          `json:"lastError,omitempty"`
+	LastErrorTime *fftypes.FFTime `json:"lastErrorTime,omitempty"`
+}
+
+// ChainStatus is the status of the backend of a chain. The endpoints of a multi-endpoint backend
+// are only included by the admin API, as their URLs can contain credentials.
+type ChainStatus struct {
+	Name          string                       `ffstruct:"ChainStatus" json:"name"`
+	ChainID       int64                        `ffstruct:"ChainStatus" json:"chainId"`
+	Healthy       bool                         `ffstruct:"ChainStatus" json:"healthy"`
+	BlockNumber   *ethtypes.HexUint64          `ffstruct:"ChainStatus" json:"blockNumber,omitempty"`
+	LastError     string                       `ffstruct:"ChainStatus" json:"lastError,omitempty"`
+	LastErrorTime *fftypes.FFTime              `ffstruct:"ChainStatus" json:"lastErrorTime,omitempty"`
+	Endpoints     []*rpcbackend.EndpointStatus `ffstruct:"ChainStatus" json:"endpoints,omitempty"`
+}
+
+// lastError keeps the most recent error from the checks of a component, which is
+// still reported after the component recovers
+type lastError struct {
+	mux  sync.Mutex
+	err  string
+	time *fftypes.FFTime
+}
+
+func (le *lastError) record(err error) {
+	le.mux.Lock()
+	defer le.mux.Unlock()
+	le.err = err.Error()
+	le.time = fftypes.Now()
+}
+
+func (le *lastError) get() (string, *fftypes.FFTime) {
+	le.mux.Lock()
+	defer le.mux.Unlock()
+	return le.err, le.time
+}
+
+func (s *rpcServer) livenessHandler(w http.ResponseWriter, r *http.Request) {
+	s.replyRPC(r.Context(), w, map[string]interface{}{}, http.StatusOK)
+}
+
+func (s *rpcServer) readinessHandler(w http.ResponseWriter, r *http.Request) {
+	status := s.checkHealth(r.Context())
+	s.replyRPC(r.Context(), w, &HealthStatus{Ready: status.Ready}, healthHTTPStatus(status))
+}
+
+func (s *rpcServer) statusHandler(w http.ResponseWriter, r *http.Request) {
+	status := s.checkHealth(r.Context())
+	s.replyRPC(r.Context(), w, status, healthHTTPStatus(status))
+}
+
+func healthHTTPStatus(status *HealthStatus) int {
+	if status.Ready {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
+
+// whenReady rejects JSON/RPC requests until the wallet is initialized and the chain IDs are known
+func (s *rpcServer) whenReady(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.ready.Load() {
+			err := i18n.NewError(r.Context(), signermsgs.MsgServerNotReady)
+			s.replyRPC(r.Context(), w, rpcbackend.RPCErrorResponse(err, fftypes.JSONAnyPtr("1"), rpcbackend.RPCCodeInternalError), http.StatusServiceUnavailable)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// checkHealth checks the wallet and the backend of each chain. The server is ready if it has
+// started, and all of the checks pass.
+func (s *rpcServer) checkHealth(ctx context.Context) *HealthStatus {
+	status := &HealthStatus{Ready: s.ready.Load()}
+	if !status.Ready {
+		return status
+	}
+
+	ctx, cancelCtx := context.WithTimeout(ctx, s.healthTimeout)
+	defer cancelCtx()
+
+	status.Wallet = s.checkWalletHealth(ctx)
+	status.Ready = status.Wallet.Healthy
+	for _, c := range s.allChains() {
+		chainStatus := c.checkHealth(ctx)
+		status.Chains = append(status.Chains, chainStatus)
+		status.Ready = status.Ready && chainStatus.Healthy
+	}
+	return status
+}
+
+// allChains returns the default chain (if configured) followed by the named chains in name order
+func (s *rpcServer) allChains() []*chain {
+	chains := make([]*chain, 0, len(s.chains)+1)
+	if s.chain != nil {
+		chains = append(chains, s.chain)
+	}
+	names := make([]string, 0, len(s.chains))
+	for name := range s.chains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		chains = append(chains, s.chains[name])
+	}
+	return chains
+}
+
+func (s *rpcServer) checkWalletHealth(ctx context.Context) *WalletStatus {
+	status := &WalletStatus{}
+	accounts, err := s.wallet.GetAccounts(ctx)
+	if err == nil {
+		status.Accounts = len(accounts)
+		if hw, ok := s.wallet.(ethsigner.WalletHealth); ok {
+			err = hw.CheckHealth(ctx)
+		}
+	}
+	if err != nil {
+		log.L(ctx).Errorf("Wallet health check failed: %s", err)
+		s.walletLastError.record(err)
+	}
+	status.Healthy = err == nil
+	status.LastError, status.LastErrorTime = s.walletLastError.get()
+	return status
+}
+
+// checkHealth checks the backend still answers eth_chainId with the chain ID of the chain, and gets
+// the current block height
+func (c *chain) checkHealth(ctx context.Context) *ChainStatus {
+	status := &ChainStatus{
+		Name:    c.name,
+		ChainID: c.chainID,
+	}
+	var err error
+	var chainID ethtypes.HexInteger
+	rpcErr := c.backend.CallRPC(ctx, &chainID, "eth_chainId")
+	if rpcErr == nil && c.chainID >= 0 && chainID.BigInt().Cmp(big.NewInt(c.chainID)) != 0 {
+		err = i18n.NewError(ctx, signermsgs.MsgBackendChainIDMismatch, c.name, chainID.BigInt(), c.chainID)
+	}
+	if rpcErr == nil && err == nil {
+		var blockNumber ethtypes.HexUint64
+		if rpcErr = c.backend.CallRPC(ctx, &blockNumber, "eth_blockNumber"); rpcErr == nil {
+			status.BlockNumber = &blockNumber
+		}
+	}
+	if rpcErr != nil {
+		err = rpcErr.Error()
+	}
+	if err != nil {
+		log.L(ctx).Errorf("Backend health check failed for chain '%s': %s", c.name, err)
+		c.lastError.record(err)
+	}
+	status.Healthy = err == nil
+	status.LastError, status.LastErrorTime = c.lastError.get()
+	return status
+}
@@ -0,0 +1,307 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/mocks/ethsignermocks"
+	"github.com/hyperledger/firefly-signer/mocks/rpcbackendmocks"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func getHealth(s *rpcServer, path string, modify ...func(r *http.Request)) (int, *HealthStatus) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for _, fn := range modify {
+		fn(req)
+	}
+	w := httptest.NewRecorder()
+	s.router().ServeHTTP(w, req)
+	var status HealthStatus
+	_ = json.Unmarshal(w.Body.Bytes(), &status)
+	return w.Code, &status
+}
+
+func mockBackendHealth(bm *rpcbackendmocks.Backend, chainID int64, blockNumber uint64) {
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_chainId").Run(func(args mock.Arguments) {
+		args[1].(*ethtypes.HexInteger).BigInt().SetInt64(chainID)
+	}).Return(nil)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_blockNumber").Run(func(args mock.Arguments) {
+		*(args[1].(*ethtypes.HexUint64)) = ethtypes.HexUint64(blockNumber)
+	}).Return(nil)
+}
+
+func TestHealthNotReady(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+	s.ready.Store(false)
+
+	code, _ := getHealth(s, livenessPath)
+	assert.Equal(t, http.StatusOK, code)
+
+	code, status := getHealth(s, readinessPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, status.Ready)
+
+	code, status = getHealth(s, statusPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, status.Ready)
+	assert.Nil(t, status.Wallet)
+
+	code, rpcRes := postChainRPC(s, "/", &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_accounts",
+	})
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Regexp(t, "FF22139", rpcRes.Error.Message)
+
+}
+
+func TestHealthReady(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+	s.chainID = 12345
+
+	mockBackendHealth(s.backend.(*rpcbackendmocks.Backend), 12345, 1000)
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{
+		ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248"),
+		ethtypes.MustNewAddress("0x497eedc4299dea2f2a364be10025d0ad0f702de3"),
+	}, nil)
+
+	code, status := getHealth(s, readinessPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, status.Ready)
+	assert.Nil(t, status.Wallet)
+
+	code, status = getHealth(s, statusPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, status.Ready)
+	assert.Equal(t, &WalletStatus{Healthy: true, Accounts: 2}, status.Wallet)
+	assert.Len(t, status.Chains, 1)
+	assert.Equal(t, "default", status.Chains[0].Name)
+	assert.Equal(t, int64(12345), status.Chains[0].ChainID)
+	assert.True(t, status.Chains[0].Healthy)
+	assert.Equal(t, uint64(1000), status.Chains[0].BlockNumber.Uint64())
+	assert.Empty(t, status.Chains[0].LastError)
+
+}
+
+func TestHealthBackendFailKeepsLastError(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_chainId").Return(&rpcbackend.RPCError{Message: "pop"}).Once()
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{}, nil)
+
+	code, status := getHealth(s, statusPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, status.Ready)
+	assert.True(t, status.Wallet.Healthy)
+	assert.False(t, status.Chains[0].Healthy)
+	assert.Nil(t, status.Chains[0].BlockNumber)
+	assert.Equal(t, "pop", status.Chains[0].LastError)
+	assert.NotNil(t, status.Chains[0].LastErrorTime)
+
+	// Once the backend recovers we are ready again, but still report the last error
+	mockBackendHealth(bm, 12345, 1001)
+	code, status = getHealth(s, statusPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, status.Chains[0].Healthy)
+	assert.Equal(t, "pop", status.Chains[0].LastError)
+
+}
+
+func TestHealthBlockNumberFail(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_chainId").Return(nil)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_blockNumber").Return(&rpcbackend.RPCError{Message: "pop"})
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{}, nil)
+
+	code, status := getHealth(s, readinessPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, status.Ready)
+
+}
+
+func TestHealthChainIDMismatch(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+	s.chainID = 1
+
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	mockBackendHealth(bm, 12345, 1000)
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{}, nil)
+
+	code, status := getHealth(s, statusPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, status.Ready)
+	assert.False(t, status.Chains[0].Healthy)
+	assert.Nil(t, status.Chains[0].BlockNumber)
+	assert.Regexp(t, "FF22202.*default.*12345.*1$", status.Chains[0].LastError)
+	bm.AssertNotCalled(t, "CallRPC", mock.Anything, mock.Anything, "eth_blockNumber")
+
+}
+
+func TestHealthWalletGetAccountsFail(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	mockBackendHealth(s.backend.(*rpcbackendmocks.Backend), 12345, 1000)
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("GetAccounts", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	code, status := getHealth(s, statusPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, status.Wallet.Healthy)
+	assert.Equal(t, "pop", status.Wallet.LastError)
+	assert.True(t, status.Chains[0].Healthy)
+
+}
+
+func TestHealthWalletListenerStopped(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	mockBackendHealth(s.backend.(*rpcbackendmocks.Backend), 12345, 1000)
+	w := &ethsignermocks.WalletHealth{}
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{
+		ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248"),
+	}, nil)
+	w.On("CheckHealth", mock.Anything).Return(fmt.Errorf("FF22140: listener stopped"))
+	s.wallet = w
+
+	code, status := getHealth(s, statusPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, status.Wallet.Healthy)
+	assert.Equal(t, 1, status.Wallet.Accounts)
+	assert.Regexp(t, "FF22140", status.Wallet.LastError)
+
+}
+
+func TestHealthNamedChainsInOrder(t *testing.T) {
+
+	s, done := newTestChainsServer(t, testChain("polygon"), testChain("mainnet"))
+	defer done()
+
+	mockBackendHealth(s.chains["mainnet"].backend.(*rpcbackendmocks.Backend), 12345, 1000)
+	mockBackendHealth(s.chains["polygon"].backend.(*rpcbackendmocks.Backend), 12345, 2000)
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{}, nil)
+
+	code, status := getHealth(s, statusPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, status.Chains, 2)
+	assert.Equal(t, "mainnet", status.Chains[0].Name)
+	assert.Equal(t, "polygon", status.Chains[1].Name)
+	assert.Equal(t, uint64(2000), status.Chains[1].BlockNumber.Uint64())
+
+}
+
+func TestHealthStatusRequiresAuth(t *testing.T) {
+
+	s, done := newTestAuthServer(t)
+	defer done()
+
+	mockBackendHealth(s.backend.(*rpcbackendmocks.Backend), 12345, 1000)
+
+	// The probes do not need authentication
+	code, _ := getHealth(s, livenessPath)
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = getHealth(s, readinessPath)
+	assert.Equal(t, http.StatusOK, code)
+
+	code, _ = getHealth(s, statusPath)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, status := getHealth(s, statusPath, func(r *http.Request) {
+		r.Header.Set("X-API-Key", "key1")
+	})
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, status.Ready)
+
+}
+
+func TestHealthProbesServedWhileStarting(t *testing.T) {
+
+	url, s, done := newTestServer(t)
+	defer done()
+	s.ready.Store(false)
+
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "net_version").Run(func(args mock.Arguments) {
+		args[1].(*ethtypes.HexInteger).BigInt().SetInt64(12345)
+	}).Return(nil)
+	mockBackendHealth(bm, 12345, 1000)
+
+	initializing := make(chan struct{})
+	initialize := make(chan struct{})
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Initialize", mock.Anything).Run(func(args mock.Arguments) {
+		close(initializing)
+		<-initialize
+	}).Return(nil)
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{}, nil)
+
+	started := make(chan error)
+	go func() {
+		started <- s.Start()
+	}()
+	<-initializing
+
+	res, err := resty.New().R().Get(url + livenessPath)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode())
+	res, err = resty.New().R().Get(url + readinessPath)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode())
+	res, err = resty.New().R().SetBody(`{"jsonrpc":"2.0","id":1,"method":"eth_accounts"}`).Post(url)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode())
+	assert.True(t, strings.Contains(res.String(), "FF22139"))
+
+	close(initialize)
+	assert.NoError(t, <-started)
+
+	res, err = resty.New().R().Get(url + readinessPath)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode())
+
+}
@@ -0,0 +1,273 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers the hash functions used by the JWT algorithms
+	_ "crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+)
+
+type jwtKeyType int
+
+const (
+	jwtKeyHMAC jwtKeyType = iota
+	jwtKeyRSA
+	jwtKeyRSAPSS
+	jwtKeyECDSA
+	jwtKeyEd25519
+)
+
+type jwtAlgorithm struct {
+	keyType jwtKeyType
+	hash    crypto.Hash
+}
+
+var jwtAlgorithms = map[string]jwtAlgorithm{
+	"HS256": {jwtKeyHMAC, crypto.SHA256},
+	"HS384": {jwtKeyHMAC, crypto.SHA384},
+	"HS512": {jwtKeyHMAC, crypto.SHA512},
+	"RS256": {jwtKeyRSA, crypto.SHA256},
+	"RS384": {jwtKeyRSA, crypto.SHA384},
+	"RS512": {jwtKeyRSA, crypto.SHA512},
+	"PS256": {jwtKeyRSAPSS, crypto.SHA256},
+	"PS384": {jwtKeyRSAPSS, crypto.SHA384},
+	"PS512": {jwtKeyRSAPSS, crypto.SHA512},
+	"ES256": {jwtKeyECDSA, crypto.SHA256},
+	"ES384": {jwtKeyECDSA, crypto.SHA384},
+	"ES512": {jwtKeyECDSA, crypto.SHA512},
+	"EdDSA": {jwtKeyEd25519, 0},
+}
+
+// jwtECDSACurves are the curves of the ECDSA algorithms, as each algorithm is only defined for one curve
+var jwtECDSACurves = map[string]string{
+	"ES256": "P-256",
+	"ES384": "P-384",
+	"ES512": "P-521",
+}
+
+// jwtVerifier verifies JWT bearer tokens locally, with an HMAC secret or a public key.
+//
+// Only the algorithms that match the configured key are accepted, so a token cannot
+// choose to be verified as HMAC using the public key as the secret (or "none").
+// Tokens must have an expiry unless requireExpiry is disabled, so a leaked token cannot
+// be used forever.
+type jwtVerifier struct {
+	hmacSecret    []byte
+	publicKey     crypto.PublicKey
+	issuer        string
+	audience      string
+	requireExpiry bool
+	now           func() time.Time
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+// loadJWTPublicKey reads a PEM encoded public key, or certificate, from a file
+func loadJWTPublicKey(ctx context.Context, filename string) (crypto.PublicKey, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, signermsgs.MsgInvalidJWTPublicKey, filename)
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidJWTPublicKey, filename)
+	}
+	var publicKey crypto.PublicKey
+	switch block.Type {
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			publicKey = cert.PublicKey
+		}
+	case "RSA PUBLIC KEY":
+		publicKey, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, signermsgs.MsgInvalidJWTPublicKey, filename)
+	}
+	return publicKey, nil
+}
+
+// verify checks the signature, expiry, issuer and audience of the token, and returns the claims
+func (jv *jwtVerifier) verify(ctx context.Context, token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, i18n.NewError(ctx, signermsgs.MsgJWTMalformed)
+	}
+	var header jwtHeader
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgJWTMalformed)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgJWTMalformed)
+	}
+	if err := jv.verifySignature(ctx, header.Alg, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgJWTMalformed)
+	}
+	if err := jv.verifyClaims(ctx, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func decodeJWTSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func (jv *jwtVerifier) verifySignature(ctx context.Context, alg string, signed, signature []byte) error {
+	algorithm, ok := jwtAlgorithms[alg]
+	if !ok {
+		return i18n.NewError(ctx, signermsgs.MsgJWTUnsupportedAlgorithm, alg)
+	}
+	var digest []byte
+	if algorithm.hash != 0 {
+		hash := algorithm.hash.New()
+		hash.Write(signed)
+		digest = hash.Sum(nil)
+	}
+
+	valid := false
+	switch publicKey := jv.publicKey.(type) {
+	case nil:
+		if algorithm.keyType != jwtKeyHMAC || jv.hmacSecret == nil {
+			return i18n.NewError(ctx, signermsgs.MsgJWTUnsupportedAlgorithm, alg)
+		}
+		mac := hmac.New(algorithm.hash.New, jv.hmacSecret)
+		mac.Write(signed)
+		valid = hmac.Equal(mac.Sum(nil), signature)
+	case *rsa.PublicKey:
+		switch algorithm.keyType {
+		case jwtKeyRSA:
+			valid = rsa.VerifyPKCS1v15(publicKey, algorithm.hash, digest, signature) == nil
+		case jwtKeyRSAPSS:
+			valid = rsa.VerifyPSS(publicKey, algorithm.hash, digest, signature, nil) == nil
+		default:
+			return i18n.NewError(ctx, signermsgs.MsgJWTUnsupportedAlgorithm, alg)
+		}
+	case *ecdsa.PublicKey:
+		if algorithm.keyType != jwtKeyECDSA || jwtECDSACurves[alg] != publicKey.Curve.Params().Name {
+			return i18n.NewError(ctx, signermsgs.MsgJWTUnsupportedAlgorithm, alg)
+		}
+		// The signature is the fixed length R and S values concatenated
+		keySize := (publicKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) == 2*keySize {
+			r := new(big.Int).SetBytes(signature[:keySize])
+			s := new(big.Int).SetBytes(signature[keySize:])
+			valid = ecdsa.Verify(publicKey, digest, r, s)
+		}
+	case ed25519.PublicKey:
+		if algorithm.keyType != jwtKeyEd25519 {
+			return i18n.NewError(ctx, signermsgs.MsgJWTUnsupportedAlgorithm, alg)
+		}
+		valid = ed25519.Verify(publicKey, signed, signature)
+	default:
+		return i18n.NewError(ctx, signermsgs.MsgJWTUnsupportedAlgorithm, alg)
+	}
+	if !valid {
+		return i18n.NewError(ctx, signermsgs.MsgJWTInvalidSignature)
+	}
+	return nil
+}
+
+func (jv *jwtVerifier) verifyClaims(ctx context.Context, claims map[string]interface{}) error {
+	now := jv.now().Unix()
+	exp, hasExp, err := jwtNumericDate(ctx, claims, "exp")
+	if err != nil {
+		return err
+	}
+	if !hasExp && jv.requireExpiry {
+		return i18n.NewError(ctx, signermsgs.MsgJWTMissingExpiry)
+	}
+	if hasExp && now >= exp {
+		return i18n.NewError(ctx, signermsgs.MsgJWTExpired)
+	}
+	nbf, hasNbf, err := jwtNumericDate(ctx, claims, "nbf")
+	if err != nil {
+		return err
+	}
+	if hasNbf && now < nbf {
+		return i18n.NewError(ctx, signermsgs.MsgJWTNotYetValid)
+	}
+	if jv.issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != jv.issuer {
+			return i18n.NewError(ctx, signermsgs.MsgJWTInvalidIssuer, iss)
+		}
+	}
+	if jv.audience != "" && !jwtAudienceIncludes(claims["aud"], jv.audience) {
+		return i18n.NewError(ctx, signermsgs.MsgJWTInvalidAudience, jv.audience)
+	}
+	return nil
+}
+
+// jwtNumericDate returns a time claim in seconds, which must be a number when it is present
+func jwtNumericDate(ctx context.Context, claims map[string]interface{}, name string) (int64, bool, error) {
+	v, ok := claims[name]
+	if !ok {
+		return 0, false, nil
+	}
+	seconds, ok := v.(float64)
+	if !ok {
+		return 0, false, i18n.NewError(ctx, signermsgs.MsgJWTMalformed)
+	}
+	return int64(seconds), true, nil
+}
+
+// jwtAudienceIncludes handles the aud claim as a single string, or an array of strings
+func jwtAudienceIncludes(aud interface{}, audience string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
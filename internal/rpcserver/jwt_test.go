@@ -0,0 +1,330 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testJWTNow = time.Unix(1700000000, 0)
+
+// newTestJWT builds a token signed with the supplied key, which is a []byte HMAC secret or a private key.
+// The token expires an hour after testJWTNow, unless the claims have an exp - which is omitted when nil.
+func newTestJWT(t *testing.T, alg string, key interface{}, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	withExpiry := map[string]interface{}{"exp": testJWTNow.Unix() + 3600}
+	for k, v := range claims {
+		if v == nil {
+			delete(withExpiry, k)
+		} else {
+			withExpiry[k] = v
+		}
+	}
+	payload, _ := json.Marshal(withExpiry)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	var signature []byte
+	var err error
+	algorithm := jwtAlgorithms[alg]
+	var digest []byte
+	if algorithm.hash != 0 {
+		hash := algorithm.hash.New()
+		hash.Write([]byte(signed))
+		digest = hash.Sum(nil)
+	}
+	switch key := key.(type) {
+	case []byte:
+		mac := hmac.New(algorithm.hash.New, key)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		if algorithm.keyType == jwtKeyRSAPSS {
+			signature, err = rsa.SignPSS(rand.Reader, key, algorithm.hash, digest, nil)
+		} else {
+			signature, err = rsa.SignPKCS1v15(rand.Reader, key, algorithm.hash, digest)
+		}
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, key, digest)
+		keySize := (key.Curve.Params().BitSize + 7) / 8
+		signature = make([]byte, 2*keySize)
+		r.FillBytes(signature[:keySize])
+		s.FillBytes(signature[keySize:])
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, []byte(signed))
+	}
+	assert.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func newTestJWTVerifier(publicKey crypto.PublicKey) *jwtVerifier {
+	return &jwtVerifier{
+		publicKey:     publicKey,
+		requireExpiry: true,
+		now:           func() time.Time { return testJWTNow },
+	}
+}
+
+func writeTestPEM(t *testing.T, blockType string, der []byte) string {
+	filename := filepath.Join(t.TempDir(), "key.pem")
+	err := os.WriteFile(filename, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+	assert.NoError(t, err)
+	return filename
+}
+
+func TestJWTHMAC(t *testing.T) {
+	ctx := context.Background()
+	jv := newTestJWTVerifier(nil)
+	jv.hmacSecret = []byte("secret")
+
+	for _, alg := range []string{"HS256", "HS384", "HS512"} {
+		claims, err := jv.verify(ctx, newTestJWT(t, alg, jv.hmacSecret, map[string]interface{}{"sub": "team1"}))
+		assert.NoError(t, err)
+		assert.Equal(t, "team1", claims["sub"])
+	}
+
+	_, err := jv.verify(ctx, newTestJWT(t, "HS256", []byte("wrong"), map[string]interface{}{"sub": "team1"}))
+	assert.Regexp(t, "FF22126", err)
+}
+
+func TestJWTRSA(t *testing.T) {
+	ctx := context.Background()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	jv := newTestJWTVerifier(&key.PublicKey)
+
+	for _, alg := range []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"} {
+		_, err := jv.verify(ctx, newTestJWT(t, alg, key, map[string]interface{}{"sub": "team1"}))
+		assert.NoError(t, err)
+	}
+
+	// A token cannot choose to be verified with the public key as an HMAC secret
+	der := x509.MarshalPKCS1PublicKey(&key.PublicKey)
+	_, err = jv.verify(ctx, newTestJWT(t, "HS256", der, map[string]interface{}{"sub": "team1"}))
+	assert.Regexp(t, "FF22125.*HS256", err)
+	_, err = jv.verify(ctx, newTestJWT(t, "ES256", der, map[string]interface{}{"sub": "team1"}))
+	assert.Regexp(t, "FF22125.*ES256", err)
+}
+
+func TestJWTECDSA(t *testing.T) {
+	ctx := context.Background()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	jv := newTestJWTVerifier(&key.PublicKey)
+
+	_, err = jv.verify(ctx, newTestJWT(t, "ES256", key, map[string]interface{}{"sub": "team1"}))
+	assert.NoError(t, err)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	_, err = jv.verify(ctx, newTestJWT(t, "ES256", otherKey, map[string]interface{}{"sub": "team1"}))
+	assert.Regexp(t, "FF22126", err)
+
+	_, err = jv.verify(ctx, newTestJWT(t, "RS256", []byte{}, map[string]interface{}{"sub": "team1"}))
+	assert.Regexp(t, "FF22125.*RS256", err)
+
+	// Each ECDSA algorithm is only accepted with the curve it is defined for
+	for _, alg := range []string{"ES384", "ES512"} {
+		_, err = jv.verify(ctx, newTestJWT(t, alg, key, map[string]interface{}{"sub": "team1"}))
+		assert.Regexp(t, "FF22125.*"+alg, err)
+	}
+	jv = newTestJWTVerifier(&otherKey.PublicKey)
+	_, err = jv.verify(ctx, newTestJWT(t, "ES384", otherKey, map[string]interface{}{"sub": "team1"}))
+	assert.NoError(t, err)
+	_, err = jv.verify(ctx, newTestJWT(t, "ES256", otherKey, map[string]interface{}{"sub": "team1"}))
+	assert.Regexp(t, "FF22125.*ES256", err)
+}
+
+func TestJWTEd25519(t *testing.T) {
+	ctx := context.Background()
+	publicKey, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	jv := newTestJWTVerifier(publicKey)
+
+	_, err = jv.verify(ctx, newTestJWT(t, "EdDSA", key, map[string]interface{}{"sub": "team1"}))
+	assert.NoError(t, err)
+
+	_, err = jv.verify(ctx, newTestJWT(t, "HS256", []byte{}, map[string]interface{}{"sub": "team1"}))
+	assert.Regexp(t, "FF22125.*HS256", err)
+}
+
+func TestJWTUnsupportedAlgorithms(t *testing.T) {
+	ctx := context.Background()
+	jv := newTestJWTVerifier(nil)
+	jv.hmacSecret = []byte("secret")
+
+	_, err := jv.verify(ctx, newTestJWT(t, "none", nil, map[string]interface{}{"sub": "team1"}))
+	assert.Regexp(t, "FF22125.*none", err)
+
+	_, err = jv.verify(ctx, newTestJWT(t, "RS256", nil, map[string]interface{}{"sub": "team1"}))
+	assert.Regexp(t, "FF22125.*RS256", err)
+
+	jv = newTestJWTVerifier("not a key")
+	_, err = jv.verify(ctx, newTestJWT(t, "RS256", nil, map[string]interface{}{"sub": "team1"}))
+	assert.Regexp(t, "FF22125.*RS256", err)
+}
+
+func TestJWTClaims(t *testing.T) {
+	ctx := context.Background()
+	secret := []byte("secret")
+	jv := newTestJWTVerifier(nil)
+	jv.hmacSecret = secret
+	jv.issuer = "https://issuer.example.com"
+	jv.audience = "ffsigner"
+
+	valid := map[string]interface{}{
+		"sub": "team1",
+		"iss": "https://issuer.example.com",
+		"aud": "ffsigner",
+		"exp": testJWTNow.Unix() + 60,
+		"nbf": testJWTNow.Unix() - 60,
+	}
+	_, err := jv.verify(ctx, newTestJWT(t, "HS256", secret, valid))
+	assert.NoError(t, err)
+
+	withClaim := func(k string, v interface{}) map[string]interface{} {
+		claims := make(map[string]interface{})
+		for ck, cv := range valid {
+			claims[ck] = cv
+		}
+		claims[k] = v
+		return claims
+	}
+	_, err = jv.verify(ctx, newTestJWT(t, "HS256", secret, withClaim("aud", []string{"other", "ffsigner"})))
+	assert.NoError(t, err)
+	_, err = jv.verify(ctx, newTestJWT(t, "HS256", secret, withClaim("aud", []string{"other"})))
+	assert.Regexp(t, "FF22130.*ffsigner", err)
+	_, err = jv.verify(ctx, newTestJWT(t, "HS256", secret, withClaim("aud", 12345)))
+	assert.Regexp(t, "FF22130", err)
+	_, err = jv.verify(ctx, newTestJWT(t, "HS256", secret, withClaim("iss", "https://other.example.com")))
+	assert.Regexp(t, "FF22129.*other", err)
+	_, err = jv.verify(ctx, newTestJWT(t, "HS256", secret, withClaim("exp", testJWTNow.Unix())))
+	assert.Regexp(t, "FF22127", err)
+	_, err = jv.verify(ctx, newTestJWT(t, "HS256", secret, withClaim("nbf", testJWTNow.Unix()+1)))
+	assert.Regexp(t, "FF22128", err)
+	_, err = jv.verify(ctx, newTestJWT(t, "HS256", secret, withClaim("exp", "tomorrow")))
+	assert.Regexp(t, "FF22124", err)
+	_, err = jv.verify(ctx, newTestJWT(t, "HS256", secret, withClaim("nbf", "yesterday")))
+	assert.Regexp(t, "FF22124", err)
+}
+
+func TestJWTMissingExpiry(t *testing.T) {
+	ctx := context.Background()
+	secret := []byte("secret")
+	jv := newTestJWTVerifier(nil)
+	jv.hmacSecret = secret
+	noExpiry := newTestJWT(t, "HS256", secret, map[string]interface{}{"sub": "team1", "exp": nil})
+
+	_, err := jv.verify(ctx, noExpiry)
+	assert.Regexp(t, "FF22200", err)
+
+	jv.requireExpiry = false
+	_, err = jv.verify(ctx, noExpiry)
+	assert.NoError(t, err)
+}
+
+func TestJWTMalformed(t *testing.T) {
+	ctx := context.Background()
+	jv := newTestJWTVerifier(nil)
+	jv.hmacSecret = []byte("secret")
+	valid := newTestJWT(t, "HS256", jv.hmacSecret, map[string]interface{}{"sub": "team1"})
+
+	for _, token := range []string{
+		"",
+		"a.b",
+		"!!!.e30.",
+		base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256"}`)) + ".e30.!!!",
+		valid[:len(valid)-1] + "!",
+	} {
+		_, err := jv.verify(ctx, token)
+		assert.Regexp(t, "FF22124", err)
+	}
+
+	// Signed correctly, but the claims are not a JSON object
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`[]`))
+	mac := hmac.New(jwtAlgorithms["HS256"].hash.New, jv.hmacSecret)
+	mac.Write([]byte(header + "." + payload))
+	_, err := jv.verify(ctx, header+"."+payload+"."+base64.RawURLEncoding.EncodeToString(mac.Sum(nil)))
+	assert.Regexp(t, "FF22124", err)
+}
+
+func TestLoadJWTPublicKey(t *testing.T) {
+	ctx := context.Background()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	pkixDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	publicKey, err := loadJWTPublicKey(ctx, writeTestPEM(t, "PUBLIC KEY", pkixDER))
+	assert.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(publicKey))
+
+	publicKey, err = loadJWTPublicKey(ctx, writeTestPEM(t, "RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(&key.PublicKey)))
+	assert.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(publicKey))
+
+	cert, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkixName("issuer"),
+		NotBefore:    testJWTNow,
+		NotAfter:     testJWTNow.Add(time.Hour),
+	}, &x509.Certificate{Subject: pkixName("issuer")}, &key.PublicKey, key)
+	assert.NoError(t, err)
+	publicKey, err = loadJWTPublicKey(ctx, writeTestPEM(t, "CERTIFICATE", cert))
+	assert.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(publicKey))
+}
+
+func TestLoadJWTPublicKeyFail(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := loadJWTPublicKey(ctx, filepath.Join(t.TempDir(), "missing.pem"))
+	assert.Regexp(t, "FF22123", err)
+
+	notPEM := filepath.Join(t.TempDir(), "key.pem")
+	err = os.WriteFile(notPEM, []byte("not a PEM file"), 0600)
+	assert.NoError(t, err)
+	_, err = loadJWTPublicKey(ctx, notPEM)
+	assert.Regexp(t, "FF22123", err)
+
+	_, err = loadJWTPublicKey(ctx, writeTestPEM(t, "PUBLIC KEY", []byte("not DER")))
+	assert.Regexp(t, "FF22123", err)
+}
+
+func pkixName(cn string) pkix.Name {
+	return pkix.Name{CommonName: cn}
+}
@@ -0,0 +1,343 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"golang.org/x/time/rate"
+)
+
+// rpcCodeLimitExceeded is the EIP-1474 code for a request that exceeds a limit
+const rpcCodeLimitExceeded rpcbackend.RPCCode = -32005
+
+const (
+	limitAddressRate  = "addressRate"
+	limitIdentityRate = "identityRate"
+	limitMaxValue     = "maxValue"
+	limitMaxFee       = "maxFee"
+)
+
+// limiter applies token bucket rate limits to the signing operations of each address and client
+// identity, and rolling window quotas to the total value and fees of the transactions signed for
+// each address on each chain - as the native assets of different chains are not interchangeable.
+// The transactions in the window are optionally written to a state file, so that the quotas
+// survive restarts.
+type limiter struct {
+	addressRate   rate.Limit // zero if disabled
+	addressBurst  int
+	identityRate  rate.Limit // zero if disabled
+	identityBurst int
+	quotaWindow   time.Duration
+	maxValue      *big.Int // nil if disabled
+	maxFee        *big.Int // nil if disabled
+	stateFile     string
+	now           func() time.Time
+
+	mux             sync.Mutex
+	addressBuckets  map[ethtypes.Address0xHex]*rate.Limiter
+	identityBuckets map[string]*rate.Limiter
+	spends          map[quotaKey][]*quotaSpend
+}
+
+// quotaKey identifies the quotas of an address on a chain
+type quotaKey struct {
+	chain string
+	addr  ethtypes.Address0xHex
+}
+
+// quotaSpend is a transaction counted against the quotas of an address, until it leaves the window
+type quotaSpend struct {
+	Time  *fftypes.FFTime      `json:"time"`
+	Value *ethtypes.HexInteger `json:"value"`
+	Fee   *ethtypes.HexInteger `json:"fee"`
+}
+
+// limitViolation is the structured error returned in the data of the JSON/RPC error
+type limitViolation struct {
+	Limit string `json:"limit"`
+	err   error
+}
+
+func (lv *limitViolation) Error() string {
+	return lv.err.Error()
+}
+
+// newLimiter returns nil if no rate limits or quotas are configured
+func newLimiter(ctx context.Context) (l *limiter, err error) {
+	l = &limiter{
+		addressRate:     rate.Limit(config.GetFloat64(signerconfig.LimitsAddressRate)),
+		addressBurst:    config.GetInt(signerconfig.LimitsAddressBurst),
+		identityRate:    rate.Limit(config.GetFloat64(signerconfig.LimitsIdentityRate)),
+		identityBurst:   config.GetInt(signerconfig.LimitsIdentityBurst),
+		quotaWindow:     config.GetDuration(signerconfig.LimitsQuotaWindow),
+		stateFile:       config.GetString(signerconfig.LimitsQuotaStateFile),
+		now:             time.Now,
+		addressBuckets:  make(map[ethtypes.Address0xHex]*rate.Limiter),
+		identityBuckets: make(map[string]*rate.Limiter),
+		spends:          make(map[quotaKey][]*quotaSpend),
+	}
+	if l.maxValue, err = parseQuotaLimit(ctx, limitMaxValue, config.GetString(signerconfig.LimitsQuotaMaxValue)); err != nil {
+		return nil, err
+	}
+	if l.maxFee, err = parseQuotaLimit(ctx, limitMaxFee, config.GetString(signerconfig.LimitsQuotaMaxFee)); err != nil {
+		return nil, err
+	}
+	if l.addressRate <= 0 && l.identityRate <= 0 && l.maxValue == nil && l.maxFee == nil {
+		return nil, nil
+	}
+	// A bucket with no burst would never allow a signing operation
+	l.addressBurst = max(l.addressBurst, 1)
+	l.identityBurst = max(l.identityBurst, 1)
+	if l.quotaEnabled() {
+		if err := l.loadState(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+func parseQuotaLimit(ctx context.Context, name, value string) (*big.Int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	i, ok := new(big.Int).SetString(value, 0)
+	if !ok || i.Sign() < 0 {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidQuotaLimit, name, value)
+	}
+	return i, nil
+}
+
+func (l *limiter) quotaEnabled() bool {
+	return l.maxValue != nil || l.maxFee != nil
+}
+
+func (l *limiter) loadState(ctx context.Context) error {
+	if l.stateFile == "" {
+		return nil
+	}
+	b, err := os.ReadFile(l.stateFile)
+	if os.IsNotExist(err) {
+		log.L(ctx).Infof("Spend quota state file '%s' does not exist, and will be created", l.stateFile)
+		return nil
+	}
+	// The state is the transactions of each address, for each chain
+	var state map[string]map[string][]*quotaSpend
+	if err == nil {
+		err = json.Unmarshal(b, &state)
+	}
+	if err != nil {
+		return i18n.WrapError(ctx, err, signermsgs.MsgQuotaStateReadFailed, l.stateFile)
+	}
+	for chain, chainSpends := range state {
+		for addrStr, spends := range chainSpends {
+			addr, err := ethtypes.NewAddress(addrStr)
+			if err != nil {
+				return i18n.WrapError(ctx, err, signermsgs.MsgQuotaStateReadFailed, l.stateFile)
+			}
+			l.spends[quotaKey{chain: chain, addr: *addr}] = spends
+		}
+	}
+	log.L(ctx).Infof("Loaded spend quotas for %d chains from '%s'", len(state), l.stateFile)
+	return nil
+}
+
+// writeState must be called with the lock held
+func (l *limiter) writeState(ctx context.Context) {
+	if l.stateFile == "" {
+		return
+	}
+	state := make(map[string]map[string][]*quotaSpend)
+	for key, spends := range l.spends {
+		if len(spends) > 0 {
+			if state[key.chain] == nil {
+				state[key.chain] = make(map[string][]*quotaSpend)
+			}
+			state[key.chain][key.addr.String()] = spends
+		}
+	}
+	if err := writeStateFile(l.stateFile, state); err != nil {
+		// The quotas are still enforced in memory
+		log.L(ctx).Errorf("Failed to write spend quota state file '%s': %s", l.stateFile, err)
+	}
+}
+
+// checkRate consumes a token from the buckets of the address and identity, for a signing operation
+func (l *limiter) checkRate(ctx context.Context, id *authIdentity, addr ethtypes.Address0xHex) error {
+	if l == nil {
+		return nil
+	}
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	return l.checkRateLocked(ctx, id, addr)
+}
+
+func (l *limiter) checkRateLocked(ctx context.Context, id *authIdentity, addr ethtypes.Address0xHex) error {
+	now := l.now()
+	// Check both buckets have a token, before taking one from either
+	var addressBucket, identityBucket *rate.Limiter
+	if l.addressRate > 0 {
+		addressBucket = l.addressBuckets[addr]
+		if addressBucket == nil {
+			addressBucket = rate.NewLimiter(l.addressRate, l.addressBurst)
+			l.addressBuckets[addr] = addressBucket
+		}
+		if addressBucket.TokensAt(now) < 1 {
+			return l.reject(ctx, limitAddressRate, signermsgs.MsgRateLimitExceeded, "address", addr)
+		}
+	}
+	if l.identityRate > 0 && id != nil {
+		identityBucket = l.identityBuckets[id.Name]
+		if identityBucket == nil {
+			identityBucket = rate.NewLimiter(l.identityRate, l.identityBurst)
+			l.identityBuckets[id.Name] = identityBucket
+		}
+		if identityBucket.TokensAt(now) < 1 {
+			return l.reject(ctx, limitIdentityRate, signermsgs.MsgRateLimitExceeded, "identity", id.Name)
+		}
+	}
+	if addressBucket != nil {
+		addressBucket.AllowN(now, 1)
+	}
+	if identityBucket != nil {
+		identityBucket.AllowN(now, 1)
+	}
+	return nil
+}
+
+// reserve applies the rate limits and spend quotas to a transaction on a chain. If the transaction is
+// within the quotas, it is counted against them - and must be released if it is not signed.
+func (l *limiter) reserve(ctx context.Context, id *authIdentity, chain string, addr ethtypes.Address0xHex, txn *ethsigner.Transaction) (*quotaSpend, error) {
+	if l == nil {
+		return nil, nil
+	}
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	key := quotaKey{chain: chain, addr: addr}
+	var spend *quotaSpend
+	if l.quotaEnabled() {
+		now := fftypes.FFTime(l.now())
+		spend = &quotaSpend{
+			Time:  &now,
+			Value: ethtypes.NewHexInteger(new(big.Int).Set(txn.Value.BigInt())),
+			Fee:   ethtypes.NewHexInteger(transactionMaxFee(txn)),
+		}
+		usedValue, usedFee := l.usedQuota(key)
+		if err := l.checkQuota(ctx, limitMaxValue, key, l.maxValue, usedValue, spend.Value.BigInt()); err != nil {
+			return nil, err
+		}
+		if err := l.checkQuota(ctx, limitMaxFee, key, l.maxFee, usedFee, spend.Fee.BigInt()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := l.checkRateLocked(ctx, id, addr); err != nil {
+		return nil, err
+	}
+
+	if spend != nil {
+		l.spends[key] = append(l.spends[key], spend)
+		l.writeState(ctx)
+	}
+	return spend, nil
+}
+
+// release removes a reserved transaction from the quotas, when it was not signed
+func (l *limiter) release(ctx context.Context, chain string, addr ethtypes.Address0xHex, spend *quotaSpend) {
+	if l == nil || spend == nil {
+		return
+	}
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	key := quotaKey{chain: chain, addr: addr}
+	spends := l.spends[key]
+	for i, s := range spends {
+		if s == spend {
+			l.spends[key] = append(spends[0:i:i], spends[i+1:]...)
+			l.writeState(ctx)
+			return
+		}
+	}
+}
+
+// usedQuota removes the transactions that have left the window, and returns the totals of the rest.
+// Must be called with the lock held.
+func (l *limiter) usedQuota(key quotaKey) (value, fee *big.Int) {
+	cutoff := l.now().Add(-l.quotaWindow)
+	value, fee = new(big.Int), new(big.Int)
+	spends := l.spends[key]
+	inWindow := spends[:0]
+	for _, s := range spends {
+		if time.Time(*s.Time).After(cutoff) {
+			inWindow = append(inWindow, s)
+			value.Add(value, s.Value.BigInt())
+			fee.Add(fee, s.Fee.BigInt())
+		}
+	}
+	l.spends[key] = inWindow
+	return value, fee
+}
+
+func (l *limiter) checkQuota(ctx context.Context, name string, key quotaKey, limit, used, requested *big.Int) error {
+	if limit != nil && new(big.Int).Add(used, requested).Cmp(limit) > 0 {
+		return l.reject(ctx, name, signermsgs.MsgQuotaExceeded, name, key.addr, key.chain, used, l.quotaWindow, requested, limit)
+	}
+	return nil
+}
+
+func (l *limiter) reject(ctx context.Context, limit string, msg i18n.ErrorMessageKey, inserts ...interface{}) error {
+	err := i18n.NewError(ctx, msg, inserts...)
+	log.L(ctx).Warnf("Signing limit exceeded: %s", err)
+	return &limitViolation{Limit: limit, err: err}
+}
+
+// transactionMaxFee is the most the transaction can spend on gas: the gas limit multiplied by the
+// maxFeePerGas, or the gasPrice for a legacy transaction
+func transactionMaxFee(txn *ethsigner.Transaction) *big.Int {
+	feePerGas := txn.GasPrice
+	if txn.MaxFeePerGas != nil {
+		feePerGas = txn.MaxFeePerGas
+	}
+	return new(big.Int).Mul(txn.GasLimit.BigInt(), feePerGas.BigInt())
+}
+
+func limitErrorResponse(err error, id *fftypes.JSONAny) *rpcbackend.RPCResponse {
+	var lv *limitViolation
+	if !errors.As(err, &lv) {
+		return rpcbackend.RPCErrorResponse(err, id, rpcbackend.RPCCodeInternalError)
+	}
+	rpcRes := rpcbackend.RPCErrorResponse(err, id, rpcCodeLimitExceeded)
+	b, _ := json.Marshal(lv)
+	rpcRes.Error.Data = *fftypes.JSONAnyPtrBytes(b)
+	return rpcRes
+}
@@ -0,0 +1,487 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/mocks/ethsignermocks"
+	"github.com/hyperledger/firefly-signer/mocks/rpcbackendmocks"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testLimitsAddr1 = "0xfb075bb99f2aa4c49955bf703509a227d7a12248"
+	testLimitsAddr2 = "0x497eedc4299dea2f2a364be10025d0ad0f702de3"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (tc *testClock) Now() time.Time {
+	return tc.now
+}
+
+func newTestLimiter(t *testing.T, conf ...func()) (*limiter, *testClock) {
+	signerconfig.Reset()
+	for _, fn := range conf {
+		fn()
+	}
+	l, err := newLimiter(context.Background())
+	assert.NoError(t, err)
+	clock := &testClock{now: time.Unix(1700000000, 0)}
+	l.now = clock.Now
+	return l, clock
+}
+
+func testValueTxn(value, gas, feePerGas int64) *ethsigner.Transaction {
+	return &ethsigner.Transaction{
+		Value:        ethtypes.NewHexInteger64(value),
+		GasLimit:     ethtypes.NewHexInteger64(gas),
+		MaxFeePerGas: ethtypes.NewHexInteger64(feePerGas),
+	}
+}
+
+func TestLimitsDisabled(t *testing.T) {
+	signerconfig.Reset()
+	l, err := newLimiter(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, l)
+
+	ctx := context.Background()
+	addr := *ethtypes.MustNewAddress(testLimitsAddr1)
+	assert.NoError(t, l.checkRate(ctx, nil, addr))
+	spend, err := l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(1, 1, 1))
+	assert.NoError(t, err)
+	assert.Nil(t, spend)
+	l.release(ctx, defaultChainName, addr, spend)
+}
+
+func TestLimitsAddressRate(t *testing.T) {
+	l, clock := newTestLimiter(t, func() {
+		config.Set(signerconfig.LimitsAddressRate, 1)
+		config.Set(signerconfig.LimitsAddressBurst, 2)
+	})
+	ctx := context.Background()
+	addr1 := *ethtypes.MustNewAddress(testLimitsAddr1)
+	addr2 := *ethtypes.MustNewAddress(testLimitsAddr2)
+
+	assert.NoError(t, l.checkRate(ctx, nil, addr1))
+	assert.NoError(t, l.checkRate(ctx, nil, addr1))
+	err := l.checkRate(ctx, nil, addr1)
+	assert.Regexp(t, "FF22142.*address.*"+testLimitsAddr1, err)
+	assert.Equal(t, limitAddressRate, err.(*limitViolation).Limit)
+
+	// Each address has its own bucket
+	assert.NoError(t, l.checkRate(ctx, nil, addr2))
+
+	// A token is added each second
+	clock.now = clock.now.Add(time.Second)
+	assert.NoError(t, l.checkRate(ctx, nil, addr1))
+	assert.Error(t, l.checkRate(ctx, nil, addr1))
+}
+
+func TestLimitsIdentityRate(t *testing.T) {
+	l, _ := newTestLimiter(t, func() {
+		config.Set(signerconfig.LimitsAddressRate, 1)
+		config.Set(signerconfig.LimitsAddressBurst, 2)
+		config.Set(signerconfig.LimitsIdentityRate, 1)
+		config.Set(signerconfig.LimitsIdentityBurst, 1)
+	})
+	ctx := context.Background()
+	addr := *ethtypes.MustNewAddress(testLimitsAddr1)
+	team1 := &authIdentity{Name: "team1"}
+	team2 := &authIdentity{Name: "team2"}
+
+	assert.NoError(t, l.checkRate(ctx, team1, addr))
+	err := l.checkRate(ctx, team1, addr)
+	assert.Regexp(t, "FF22142.*identity.*team1", err)
+	assert.Equal(t, limitIdentityRate, err.(*limitViolation).Limit)
+
+	// The rejected request did not use a token from the address bucket
+	assert.NoError(t, l.checkRate(ctx, team2, addr))
+	assert.Regexp(t, "FF22142.*address", l.checkRate(ctx, nil, addr))
+}
+
+func TestLimitsMinimumBurst(t *testing.T) {
+	l, _ := newTestLimiter(t, func() {
+		config.Set(signerconfig.LimitsAddressRate, 1)
+		config.Set(signerconfig.LimitsAddressBurst, 0)
+	})
+	assert.NoError(t, l.checkRate(context.Background(), nil, *ethtypes.MustNewAddress(testLimitsAddr1)))
+}
+
+func TestLimitsQuotaMaxValue(t *testing.T) {
+	l, clock := newTestLimiter(t, func() {
+		config.Set(signerconfig.LimitsQuotaMaxValue, "1000")
+		config.Set(signerconfig.LimitsQuotaWindow, "1h")
+	})
+	ctx := context.Background()
+	addr := *ethtypes.MustNewAddress(testLimitsAddr1)
+
+	_, err := l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(600, 0, 0))
+	assert.NoError(t, err)
+	clock.now = clock.now.Add(30 * time.Minute)
+	_, err = l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(400, 0, 0))
+	assert.NoError(t, err)
+
+	_, err = l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(1, 0, 0))
+	assert.Regexp(t, "FF22143.*maxValue.*1000 wei signed in the last 1h0m0s, plus 1 wei requested.*limit of 1000 wei", err)
+	assert.Equal(t, limitMaxValue, err.(*limitViolation).Limit)
+
+	// Once the first transaction leaves the window, there is room again
+	clock.now = clock.now.Add(31 * time.Minute)
+	_, err = l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(600, 0, 0))
+	assert.NoError(t, err)
+	assert.Len(t, l.spends[quotaKey{chain: defaultChainName, addr: addr}], 2)
+}
+
+func TestLimitsQuotaPerChain(t *testing.T) {
+	l, _ := newTestLimiter(t, func() {
+		config.Set(signerconfig.LimitsQuotaMaxValue, "1000")
+	})
+	ctx := context.Background()
+	addr := *ethtypes.MustNewAddress(testLimitsAddr1)
+
+	// The native assets of each chain are counted separately
+	_, err := l.reserve(ctx, nil, "mainnet", addr, testValueTxn(1000, 0, 0))
+	assert.NoError(t, err)
+	spend, err := l.reserve(ctx, nil, "sepolia", addr, testValueTxn(1000, 0, 0))
+	assert.NoError(t, err)
+
+	_, err = l.reserve(ctx, nil, "mainnet", addr, testValueTxn(1, 0, 0))
+	assert.Regexp(t, "FF22143.*maxValue.*"+testLimitsAddr1+".*on chain 'mainnet'", err)
+
+	// Releasing on one chain does not affect the other
+	l.release(ctx, "mainnet", addr, spend)
+	assert.Len(t, l.spends[quotaKey{chain: "sepolia", addr: addr}], 1)
+	l.release(ctx, "sepolia", addr, spend)
+	assert.Empty(t, l.spends[quotaKey{chain: "sepolia", addr: addr}])
+	assert.Len(t, l.spends[quotaKey{chain: "mainnet", addr: addr}], 1)
+}
+
+func TestLimitsQuotaMaxFee(t *testing.T) {
+	l, _ := newTestLimiter(t, func() {
+		config.Set(signerconfig.LimitsQuotaMaxFee, "0x2710") // 10000
+	})
+	ctx := context.Background()
+	addr := *ethtypes.MustNewAddress(testLimitsAddr1)
+
+	_, err := l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(0, 100, 50))
+	assert.NoError(t, err)
+
+	// Legacy transactions use the gasPrice
+	_, err = l.reserve(ctx, nil, defaultChainName, addr, &ethsigner.Transaction{
+		GasLimit: ethtypes.NewHexInteger64(100),
+		GasPrice: ethtypes.NewHexInteger64(51),
+	})
+	assert.Regexp(t, "FF22143.*maxFee.*5000 wei.*5100 wei requested", err)
+	assert.Equal(t, limitMaxFee, err.(*limitViolation).Limit)
+}
+
+func TestLimitsQuotaRelease(t *testing.T) {
+	l, _ := newTestLimiter(t, func() {
+		config.Set(signerconfig.LimitsQuotaMaxValue, "1000")
+	})
+	ctx := context.Background()
+	addr := *ethtypes.MustNewAddress(testLimitsAddr1)
+
+	spend1, err := l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(500, 0, 0))
+	assert.NoError(t, err)
+	spend2, err := l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(500, 0, 0))
+	assert.NoError(t, err)
+
+	l.release(ctx, defaultChainName, addr, spend1)
+	assert.Equal(t, []*quotaSpend{spend2}, l.spends[quotaKey{chain: defaultChainName, addr: addr}])
+	l.release(ctx, defaultChainName, addr, spend1) // no-op
+
+	_, err = l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(500, 0, 0))
+	assert.NoError(t, err)
+}
+
+func TestLimitsQuotaCheckedBeforeRate(t *testing.T) {
+	l, _ := newTestLimiter(t, func() {
+		config.Set(signerconfig.LimitsAddressRate, 1)
+		config.Set(signerconfig.LimitsAddressBurst, 1)
+		config.Set(signerconfig.LimitsQuotaMaxValue, "1000")
+	})
+	ctx := context.Background()
+	addr := *ethtypes.MustNewAddress(testLimitsAddr1)
+
+	// A transaction over the quota does not use a token
+	_, err := l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(1001, 0, 0))
+	assert.Regexp(t, "FF22143", err)
+	_, err = l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(1000, 0, 0))
+	assert.NoError(t, err)
+
+	// A transaction over the rate does not use any quota
+	_, err = l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(0, 0, 0))
+	assert.Regexp(t, "FF22142", err)
+	assert.Len(t, l.spends[quotaKey{chain: defaultChainName, addr: addr}], 1)
+}
+
+func TestLimitsQuotaStateFile(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "quotas.json")
+	conf := func() {
+		config.Set(signerconfig.LimitsQuotaMaxValue, "1000")
+		config.Set(signerconfig.LimitsQuotaStateFile, stateFile)
+	}
+	ctx := context.Background()
+	addr := *ethtypes.MustNewAddress(testLimitsAddr1)
+
+	l, clock := newTestLimiter(t, conf)
+	_, err := l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(700, 0, 0))
+	assert.NoError(t, err)
+
+	// A restart does not reset the quota
+	l, err = newLimiter(ctx)
+	assert.NoError(t, err)
+	l.now = clock.Now
+	_, err = l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(301, 0, 0))
+	assert.Regexp(t, "FF22143.*700 wei", err)
+	_, err = l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(300, 0, 0))
+	assert.NoError(t, err)
+
+	_, err = l.reserve(ctx, nil, "sepolia", addr, testValueTxn(1000, 0, 0))
+	assert.NoError(t, err)
+
+	l, err = newLimiter(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, l.spends[quotaKey{chain: defaultChainName, addr: addr}], 2)
+	assert.Len(t, l.spends[quotaKey{chain: "sepolia", addr: addr}], 1)
+
+	// The state is keyed by chain, and then by address
+	var state map[string]map[string][]*quotaSpend
+	b, err := os.ReadFile(stateFile)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(b, &state))
+	assert.Len(t, state[defaultChainName][testLimitsAddr1], 2)
+	assert.Len(t, state["sepolia"][testLimitsAddr1], 1)
+}
+
+func TestLimitsQuotaStateFileBad(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "quotas.json")
+	signerconfig.Reset()
+	config.Set(signerconfig.LimitsQuotaMaxFee, "1000")
+	config.Set(signerconfig.LimitsQuotaStateFile, stateFile)
+
+	err := os.WriteFile(stateFile, []byte(`!!! not JSON`), 0600)
+	assert.NoError(t, err)
+	_, err = newLimiter(context.Background())
+	assert.Regexp(t, "FF22145", err)
+
+	err = os.WriteFile(stateFile, []byte(`{"default":{"bad address":[]}}`), 0600)
+	assert.NoError(t, err)
+	_, err = newLimiter(context.Background())
+	assert.Regexp(t, "FF22145", err)
+}
+
+func TestLimitsQuotaStateFileWriteFail(t *testing.T) {
+	l, _ := newTestLimiter(t, func() {
+		config.Set(signerconfig.LimitsQuotaMaxValue, "1000")
+		config.Set(signerconfig.LimitsQuotaStateFile, filepath.Join(t.TempDir(), "missing", "quotas.json"))
+	})
+	ctx := context.Background()
+	addr := *ethtypes.MustNewAddress(testLimitsAddr1)
+
+	// The quota is still enforced in memory
+	_, err := l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(1000, 0, 0))
+	assert.NoError(t, err)
+	_, err = l.reserve(ctx, nil, defaultChainName, addr, testValueTxn(1, 0, 0))
+	assert.Regexp(t, "FF22143", err)
+}
+
+func TestLimitsBadConfig(t *testing.T) {
+	signerconfig.Reset()
+	config.Set(signerconfig.LimitsQuotaMaxValue, "lots")
+	_, err := newLimiter(context.Background())
+	assert.Regexp(t, "FF22144.*maxValue", err)
+
+	signerconfig.Reset()
+	config.Set(signerconfig.LimitsQuotaMaxFee, "-1")
+	_, err = newLimiter(context.Background())
+	assert.Regexp(t, "FF22144.*maxFee", err)
+
+	_, err = NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22144", err)
+}
+
+func TestLimitErrorResponseNotLimit(t *testing.T) {
+	rpcRes := limitErrorResponse(fmt.Errorf("pop"), fftypes.JSONAnyPtr("1"))
+	assert.Equal(t, int64(rpcbackend.RPCCodeInternalError), rpcRes.Error.Code)
+}
+
+func TestSendTransactionQuotaExceeded(t *testing.T) {
+
+	_, s, done := newTestServer(t, func() {
+		config.Set(signerconfig.LimitsQuotaMaxValue, "1000")
+	})
+	defer done()
+
+	rpcRes, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_sendTransaction",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`{
+				"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248",
+				"value": "0x3e9"
+			}`),
+		},
+	})
+	assert.Regexp(t, "FF22143", err)
+	assert.Equal(t, int64(-32005), rpcRes.Error.Code)
+	assert.JSONEq(t, `{"limit":"maxValue"}`, rpcRes.Error.Data.String())
+
+}
+
+func TestSignTransactionFailReleasesQuota(t *testing.T) {
+
+	_, s, done := newTestServer(t, func() {
+		config.Set(signerconfig.LimitsQuotaMaxValue, "1000")
+	})
+	defer done()
+
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Sign", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTransaction",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`{
+				"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248",
+				"nonce": "0x0",
+				"value": "0x3e8"
+			}`),
+		},
+	})
+	assert.Regexp(t, "pop", err)
+	assert.Empty(t, s.limits.spends[quotaKey{chain: defaultChainName, addr: *ethtypes.MustNewAddress(testLimitsAddr1)}])
+
+}
+
+func TestSendTransactionRejectedReleasesQuota(t *testing.T) {
+
+	_, s, done := newTestServer(t, func() {
+		config.Set(signerconfig.LimitsQuotaMaxValue, "1000")
+	})
+	defer done()
+
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Sign", mock.Anything, mock.Anything, mock.Anything).Return([]byte{0x01, 0x02}, nil)
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	bm.On("SyncRequest", mock.Anything, mock.Anything).Return(&rpcbackend.RPCResponse{
+		JSONRpc: "2.0",
+		ID:      fftypes.JSONAnyPtr("1"),
+		Error:   &rpcbackend.RPCError{Code: -32000, Message: "insufficient funds for gas * price + value"},
+	}, fmt.Errorf("insufficient funds for gas * price + value")).Once()
+	bm.On("SyncRequest", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("connection reset")).Once()
+
+	sendTransaction := func() error {
+		_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+			ID:     fftypes.JSONAnyPtr("1"),
+			Method: "eth_sendTransaction",
+			Params: []*fftypes.JSONAny{
+				fftypes.JSONAnyPtr(`{
+					"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248",
+					"nonce": "0x0",
+					"value": "0x3e8"
+				}`),
+			},
+		})
+		return err
+	}
+
+	// The node definitely rejected the transaction, so it does not use the quota
+	err := sendTransaction()
+	assert.Regexp(t, "insufficient funds", err)
+	assert.Empty(t, s.limits.spends[quotaKey{chain: defaultChainName, addr: *ethtypes.MustNewAddress(testLimitsAddr1)}])
+
+	// The node might have accepted the transaction, so it stays in the quota
+	err = sendTransaction()
+	assert.Regexp(t, "connection reset", err)
+	assert.Len(t, s.limits.spends[quotaKey{chain: defaultChainName, addr: *ethtypes.MustNewAddress(testLimitsAddr1)}], 1)
+	bm.AssertExpectations(t)
+
+}
+
+func TestSignMessageRateLimited(t *testing.T) {
+
+	_, s, done := newTestServer(t, func() {
+		config.Set(signerconfig.LimitsAddressRate, 1)
+		config.Set(signerconfig.LimitsAddressBurst, 1)
+	})
+	defer done()
+	s.wallet = &ethsignermocks.WalletEIP191{}
+	addr := *ethtypes.MustNewAddress(testLimitsAddr1)
+	assert.NoError(t, s.limits.checkRate(s.ctx, nil, addr))
+
+	rpcRes, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "personal_sign",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"0x68656c6c6f"`),
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+		},
+	})
+	assert.Regexp(t, "FF22142", err)
+	assert.Equal(t, int64(-32005), rpcRes.Error.Code)
+	assert.JSONEq(t, `{"limit":"addressRate"}`, rpcRes.Error.Data.String())
+
+}
+
+func TestSignTypedDataRateLimited(t *testing.T) {
+
+	_, s, done := newTestServer(t, func() {
+		config.Set(signerconfig.LimitsAddressRate, 1)
+		config.Set(signerconfig.LimitsAddressBurst, 1)
+	})
+	defer done()
+	w := &ethsignermocks.WalletTypedData{}
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{
+		ethtypes.MustNewAddress(testLimitsAddr1),
+	}, nil)
+	s.wallet = w
+	addr := *ethtypes.MustNewAddress(testLimitsAddr1)
+	assert.NoError(t, s.limits.checkRate(s.ctx, nil, addr))
+
+	rpcRes, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTypedData_v4",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+			fftypes.JSONAnyPtr(`{"primaryType":"EIP712Domain"}`),
+		},
+	})
+	assert.Regexp(t, "FF22142", err)
+	assert.Equal(t, int64(-32005), rpcRes.Error.Code)
+
+}
@@ -0,0 +1,221 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+)
+
+// nonceManager assigns nonces to transactions that are signed without one.
+//
+//   - A lock is held per address, from nonce assignment until the transaction is submitted,
+//     so concurrent requests (including those in a batch) get sequential nonces
+//   - The next nonce is cached in memory, seeded from the pending transaction count on the chain
+//   - Optionally the next nonce for each address is written to a journal file, so that
+//     the cache survives restarts (before the node has seen the transactions)
+type nonceManager struct {
+	backend     rpcbackend.Backend
+	cacheNonces bool
+	journalFile string
+
+	mux       sync.Mutex
+	addresses map[ethtypes.Address0xHex]*addressNonces
+}
+
+type addressNonces struct {
+	lock      chan struct{}
+	seeded    bool   // we have queried the chain in this process (only updated with lock held)
+	next      uint64 // the next nonce to assign, if seeded (only updated with lock held)
+	journaled uint64 // the next nonce recorded in the journal (updated with nonceManager lock)
+}
+
+type nonceOutcome int
+
+const (
+	nonceReleased nonceOutcome = iota // the nonce was not used, so should be assigned to the next transaction
+	nonceConsumed                     // the nonce was used
+	nonceReset                        // the state of the nonce is unknown, and should be re-queried from the chain
+)
+
+// nonceAssignment is returned with the per-address lock held, which is released on complete()
+type nonceAssignment struct {
+	nm    *nonceManager
+	addr  ethtypes.Address0xHex
+	an    *addressNonces
+	nonce uint64
+	done  bool
+}
+
+func newNonceManager(backend rpcbackend.Backend, cacheNonces bool, journalFile string) *nonceManager {
+	return &nonceManager{
+		backend:     backend,
+		cacheNonces: cacheNonces,
+		journalFile: journalFile,
+		addresses:   make(map[ethtypes.Address0xHex]*addressNonces),
+	}
+}
+
+func (nm *nonceManager) getAddressNonces(addr ethtypes.Address0xHex) *addressNonces {
+	nm.mux.Lock()
+	defer nm.mux.Unlock()
+	an, ok := nm.addresses[addr]
+	if !ok {
+		an = &addressNonces{lock: make(chan struct{}, 1)}
+		nm.addresses[addr] = an
+	}
+	return an
+}
+
+func (nm *nonceManager) loadJournal(ctx context.Context) error {
+	if nm.journalFile == "" {
+		return nil
+	}
+	b, err := os.ReadFile(nm.journalFile)
+	if os.IsNotExist(err) {
+		log.L(ctx).Infof("Nonce journal '%s' does not exist, and will be created", nm.journalFile)
+		return nil
+	}
+	var journal map[string]ethtypes.HexUint64
+	if err == nil {
+		err = json.Unmarshal(b, &journal)
+	}
+	if err != nil {
+		return i18n.WrapError(ctx, err, signermsgs.MsgNonceJournalReadFailed, nm.journalFile)
+	}
+	for addrStr, next := range journal {
+		addr, err := ethtypes.NewAddress(addrStr)
+		if err != nil {
+			return i18n.WrapError(ctx, err, signermsgs.MsgNonceJournalReadFailed, nm.journalFile)
+		}
+		nm.getAddressNonces(*addr).journaled = next.Uint64()
+	}
+	log.L(ctx).Infof("Loaded nonces for %d addresses from journal '%s'", len(journal), nm.journalFile)
+	return nil
+}
+
+func (nm *nonceManager) writeJournal(ctx context.Context, addr ethtypes.Address0xHex, next uint64) {
+	if nm.journalFile == "" {
+		return
+	}
+	nm.mux.Lock()
+	defer nm.mux.Unlock()
+	nm.addresses[addr].journaled = next
+	journal := make(map[string]ethtypes.HexUint64, len(nm.addresses))
+	for a, an := range nm.addresses {
+		if an.journaled > 0 {
+			journal[a.String()] = ethtypes.HexUint64(an.journaled)
+		}
+	}
+	if err := writeStateFile(nm.journalFile, journal); err != nil {
+		// We do not fail the submission here, as the transaction has already been sent
+		log.L(ctx).Errorf("Failed to write nonce journal '%s': %s", nm.journalFile, err)
+	}
+}
+
+// assignNonce waits for the lock on the address, and returns the next nonce to use.
+// complete() must be called on the returned assignment to release the lock.
+func (nm *nonceManager) assignNonce(ctx context.Context, addr ethtypes.Address0xHex) (*nonceAssignment, error) {
+	an := nm.getAddressNonces(addr)
+	select {
+	case an.lock <- struct{}{}:
+	case <-ctx.Done():
+		return nil, i18n.NewError(ctx, signermsgs.MsgNonceLockCanceled, addr)
+	}
+
+	if !an.seeded || !nm.cacheNonces {
+		var pendingCount *ethtypes.HexInteger
+		rpcErr := nm.backend.CallRPC(ctx, &pendingCount, "eth_getTransactionCount", &addr, "pending")
+		if rpcErr != nil {
+			<-an.lock
+			return nil, rpcErr.Error()
+		}
+		an.next = pendingCount.BigInt().Uint64()
+		// The journal might be ahead of the node, if we submitted transactions before a restart
+		// that the node has not yet seen (or has dropped)
+		nm.mux.Lock()
+		if an.journaled > an.next {
+			log.L(ctx).Infof("Using nonce %d for %s from journal (chain pending nonce=%d)", an.journaled, addr, an.next)
+			an.next = an.journaled
+		}
+		nm.mux.Unlock()
+		an.seeded = true
+	}
+
+	log.L(ctx).Debugf("Assigned nonce %d for %s", an.next, addr)
+	return &nonceAssignment{
+		nm:    nm,
+		addr:  addr,
+		an:    an,
+		nonce: an.next,
+	}, nil
+}
+
+// submitOutcome determines what happened to the nonce, based on the result of eth_sendRawTransaction.
+// The nonce is only released when the node definitely rejected the transaction with a JSON/RPC error.
+// Other failures (timeouts, connection errors, 5xx responses) are uncertain, as the node might have
+// accepted the transaction, so the nonce is kept and any gap is resolved by the reset on "nonce" errors.
+func submitOutcome(rpcRes *rpcbackend.RPCResponse, err error) nonceOutcome {
+	if err == nil {
+		return nonceConsumed
+	}
+	errLower := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(errLower, "already known"), strings.Contains(errLower, "known transaction"):
+		// The node already has this exact transaction
+		return nonceConsumed
+	case strings.Contains(errLower, "nonce"):
+		// "nonce too low" etc. mean we are out of step with the chain
+		return nonceReset
+	case rpcRes != nil && rpcRes.Error != nil && rpcRes.Error.Code != 0 &&
+		rpcRes.Error.Code != int64(rpcbackend.RPCCodeInternalError):
+		// The node rejected the transaction, such as for insufficient funds
+		return nonceReleased
+	default:
+		return nonceConsumed
+	}
+}
+
+// complete releases the lock on the address, updating the nonce cache based on the outcome.
+// It is safe to call multiple times, with only the first call having any effect.
+func (na *nonceAssignment) complete(ctx context.Context, outcome nonceOutcome) {
+	if na == nil || na.done {
+		return
+	}
+	na.done = true
+	switch outcome {
+	case nonceConsumed:
+		na.an.next = na.nonce + 1
+		na.nm.writeJournal(ctx, na.addr, na.an.next)
+	case nonceReset:
+		log.L(ctx).Warnf("Resetting nonce cache for %s after nonce %d was rejected", na.addr, na.nonce)
+		na.an.seeded = false
+		na.nm.writeJournal(ctx, na.addr, 0)
+	default:
+		log.L(ctx).Debugf("Released nonce %d for %s", na.nonce, na.addr)
+	}
+	<-na.an.lock
+}
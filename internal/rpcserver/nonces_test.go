@@ -0,0 +1,265 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/firefly-signer/mocks/rpcbackendmocks"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testNonceAddr = "0xfb075bb99f2aa4c49955bf703509a227d7a12248"
+
+func newTestNonceManager(t *testing.T, cacheNonces bool, journalFile string) (*nonceManager, *rpcbackendmocks.Backend) {
+	bm := rpcbackendmocks.NewBackend(t)
+	return newNonceManager(bm, cacheNonces, journalFile), bm
+}
+
+func mockPendingNonce(bm *rpcbackendmocks.Backend, nonce int64) *mock.Call {
+	return bm.On("CallRPC", mock.Anything, mock.Anything, "eth_getTransactionCount", mock.Anything, "pending").Run(func(args mock.Arguments) {
+		hi := args[1].(**ethtypes.HexInteger)
+		*hi = (*ethtypes.HexInteger)(big.NewInt(nonce))
+	}).Return(nil)
+}
+
+func TestNonceCachedSequential(t *testing.T) {
+	ctx := context.Background()
+	nm, bm := newTestNonceManager(t, true, "")
+	mockPendingNonce(bm, 10).Once()
+	addr := *ethtypes.MustNewAddress(testNonceAddr)
+
+	na, err := nm.assignNonce(ctx, addr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), na.nonce)
+	na.complete(ctx, nonceConsumed)
+	na.complete(ctx, nonceReset) // no-op
+
+	na, err = nm.assignNonce(ctx, addr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), na.nonce)
+	na.complete(ctx, nonceReleased)
+
+	na, err = nm.assignNonce(ctx, addr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), na.nonce)
+	na.complete(ctx, nonceConsumed)
+
+	assert.Equal(t, uint64(12), nm.addresses[addr].next)
+}
+
+func TestNonceCacheDisabled(t *testing.T) {
+	ctx := context.Background()
+	nm, bm := newTestNonceManager(t, false, "")
+	mockPendingNonce(bm, 10).Twice()
+	addr := *ethtypes.MustNewAddress(testNonceAddr)
+
+	for i := 0; i < 2; i++ {
+		na, err := nm.assignNonce(ctx, addr)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(10), na.nonce)
+		na.complete(ctx, nonceConsumed)
+	}
+}
+
+func TestNonceConcurrentAssignment(t *testing.T) {
+	ctx := context.Background()
+	nm, bm := newTestNonceManager(t, true, "")
+	mockPendingNonce(bm, 0).Once()
+	addr := *ethtypes.MustNewAddress(testNonceAddr)
+
+	const count = 20
+	nonces := make(chan uint64, count)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			na, err := nm.assignNonce(ctx, addr)
+			assert.NoError(t, err)
+			nonces <- na.nonce
+			na.complete(ctx, nonceConsumed)
+		}()
+	}
+	wg.Wait()
+	close(nonces)
+
+	seen := make(map[uint64]bool)
+	for n := range nonces {
+		assert.False(t, seen[n])
+		seen[n] = true
+	}
+	assert.Len(t, seen, count)
+	assert.Equal(t, uint64(count), nm.addresses[addr].next)
+}
+
+func TestNonceResetRequeries(t *testing.T) {
+	ctx := context.Background()
+	nm, bm := newTestNonceManager(t, true, "")
+	mockPendingNonce(bm, 10).Once()
+	mockPendingNonce(bm, 15).Once()
+	addr := *ethtypes.MustNewAddress(testNonceAddr)
+
+	na, err := nm.assignNonce(ctx, addr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), na.nonce)
+	na.complete(ctx, submitOutcome(nil, fmt.Errorf("nonce too low")))
+
+	na, err = nm.assignNonce(ctx, addr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(15), na.nonce)
+	na.complete(ctx, nonceConsumed)
+}
+
+func TestNonceQueryFailReleasesLock(t *testing.T) {
+	ctx := context.Background()
+	nm, bm := newTestNonceManager(t, true, "")
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_getTransactionCount", mock.Anything, "pending").
+		Return(&rpcbackend.RPCError{Message: "pop"}).Once()
+	mockPendingNonce(bm, 10).Once()
+	addr := *ethtypes.MustNewAddress(testNonceAddr)
+
+	_, err := nm.assignNonce(ctx, addr)
+	assert.Regexp(t, "pop", err)
+
+	na, err := nm.assignNonce(ctx, addr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), na.nonce)
+	na.complete(ctx, nonceReleased)
+}
+
+func TestNonceLockCanceled(t *testing.T) {
+	nm, bm := newTestNonceManager(t, true, "")
+	mockPendingNonce(bm, 10).Once()
+	addr := *ethtypes.MustNewAddress(testNonceAddr)
+
+	na, err := nm.assignNonce(context.Background(), addr)
+	assert.NoError(t, err)
+	defer na.complete(context.Background(), nonceReleased)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = nm.assignNonce(ctx, addr)
+	assert.Regexp(t, "FF22097", err)
+}
+
+func TestSubmitOutcome(t *testing.T) {
+	rejected := func(msg string) *rpcbackend.RPCResponse {
+		return &rpcbackend.RPCResponse{Error: &rpcbackend.RPCError{Code: -32000, Message: msg}}
+	}
+	assert.Equal(t, nonceConsumed, submitOutcome(&rpcbackend.RPCResponse{}, nil))
+	assert.Equal(t, nonceConsumed, submitOutcome(rejected("already known"), fmt.Errorf("already known")))
+	assert.Equal(t, nonceConsumed, submitOutcome(rejected("Known transaction: 0x1234"), fmt.Errorf("Known transaction: 0x1234")))
+	assert.Equal(t, nonceReset, submitOutcome(rejected("nonce too low"), fmt.Errorf("nonce too low")))
+	assert.Equal(t, nonceReset, submitOutcome(rejected("Nonce too high"), fmt.Errorf("Nonce too high")))
+	assert.Equal(t, nonceReleased, submitOutcome(rejected("insufficient funds for gas * price + value"), fmt.Errorf("insufficient funds for gas * price + value")))
+}
+
+func TestSubmitOutcomeUncertainKeepsNonce(t *testing.T) {
+	// A timeout or connection error is reported by us rather than the node, so the node might have the transaction
+	err := fmt.Errorf("FF22012: Backend RPC request failed: context deadline exceeded")
+	assert.Equal(t, nonceConsumed, submitOutcome(rpcbackend.RPCErrorResponse(err, nil, rpcbackend.RPCCodeInternalError), err))
+	// A 5xx response without a JSON/RPC error
+	assert.Equal(t, nonceConsumed, submitOutcome(&rpcbackend.RPCResponse{}, fmt.Errorf("502 Bad Gateway")))
+	assert.Equal(t, nonceConsumed, submitOutcome(nil, fmt.Errorf("pop")))
+}
+
+func TestNonceJournalRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	journalFile := path.Join(t.TempDir(), "nonces.json")
+	addr := *ethtypes.MustNewAddress(testNonceAddr)
+
+	nm, bm := newTestNonceManager(t, true, journalFile)
+	err := nm.loadJournal(ctx)
+	assert.NoError(t, err)
+	mockPendingNonce(bm, 10).Once()
+	na, err := nm.assignNonce(ctx, addr)
+	assert.NoError(t, err)
+	na.complete(ctx, nonceConsumed)
+
+	b, err := os.ReadFile(journalFile)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"`+testNonceAddr+`":"0xb"}`, string(b))
+
+	// A restart where the node has not seen the transaction uses the journal
+	nm, bm = newTestNonceManager(t, true, journalFile)
+	err = nm.loadJournal(ctx)
+	assert.NoError(t, err)
+	mockPendingNonce(bm, 10).Once()
+	na, err = nm.assignNonce(ctx, addr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), na.nonce)
+	na.complete(ctx, nonceReset)
+
+	b, err = os.ReadFile(journalFile)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(b))
+
+	// A restart where the node is ahead of the journal uses the node
+	nm, bm = newTestNonceManager(t, true, journalFile)
+	err = nm.loadJournal(ctx)
+	assert.NoError(t, err)
+	mockPendingNonce(bm, 20).Once()
+	na, err = nm.assignNonce(ctx, addr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20), na.nonce)
+	na.complete(ctx, nonceReleased)
+}
+
+func TestNonceJournalBadJSON(t *testing.T) {
+	journalFile := path.Join(t.TempDir(), "nonces.json")
+	err := os.WriteFile(journalFile, []byte(`!json`), 0600)
+	assert.NoError(t, err)
+
+	nm, _ := newTestNonceManager(t, true, journalFile)
+	err = nm.loadJournal(context.Background())
+	assert.Regexp(t, "FF22096", err)
+}
+
+func TestNonceJournalBadAddress(t *testing.T) {
+	journalFile := path.Join(t.TempDir(), "nonces.json")
+	err := os.WriteFile(journalFile, []byte(`{"wrong":"0x1"}`), 0600)
+	assert.NoError(t, err)
+
+	nm, _ := newTestNonceManager(t, true, journalFile)
+	err = nm.loadJournal(context.Background())
+	assert.Regexp(t, "FF22096", err)
+}
+
+func TestNonceJournalWriteFail(t *testing.T) {
+	ctx := context.Background()
+	journalFile := path.Join(t.TempDir(), "missing", "nonces.json")
+	addr := *ethtypes.MustNewAddress(testNonceAddr)
+
+	nm, bm := newTestNonceManager(t, true, journalFile)
+	mockPendingNonce(bm, 10).Once()
+	na, err := nm.assignNonce(ctx, addr)
+	assert.NoError(t, err)
+	na.complete(ctx, nonceConsumed)
+
+	// The in-memory state is still updated
+	assert.Equal(t, uint64(11), nm.addresses[addr].next)
+}
@@ -0,0 +1,233 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+)
+
+// rpcCodeTransactionRejected is the EIP-1474 code for a transaction that is rejected
+// by the signer, rather than the node
+const rpcCodeTransactionRejected rpcbackend.RPCCode = -32003
+
+const (
+	policySourceConfig = "config"
+	policySourceWallet = "wallet"
+
+	policyRuleAllowedTo        = "allowedTo"
+	policyRuleMaxValue         = "maxValue"
+	policyRuleMaxFeePerGas     = "maxFeePerGas"
+	policyRuleAllowedFunctions = "allowedFunctions"
+)
+
+// policyEngine checks transactions against a set of rules before they are signed.
+// Rules are loaded from config, and optionally from a property in the metadata of each
+// key in the wallet. A transaction must pass both sets of rules.
+type policyEngine struct {
+	rules                  *policyRules
+	wallet                 ethsigner.WalletMetadata
+	walletMetadataProperty string
+}
+
+type policyRules struct {
+	AllowedTo        []*ethtypes.Address0xHex `json:"allowedTo,omitempty"`
+	MaxValue         *ethtypes.HexInteger     `json:"maxValue,omitempty"`
+	MaxFeePerGas     *ethtypes.HexInteger     `json:"maxFeePerGas,omitempty"`
+	AllowedFunctions []*functionPolicy        `json:"allowedFunctions,omitempty"`
+}
+
+// functionPolicy restricts the functions that can be called on a destination contract,
+// to those in the supplied ABI
+type functionPolicy struct {
+	To        *ethtypes.Address0xHex `json:"to"`
+	Functions abi.ABI                `json:"functions"`
+}
+
+// policyViolation is the structured error returned in the data of the JSON/RPC error
+type policyViolation struct {
+	Rule   string `json:"rule"`
+	Source string `json:"source"`
+	err    error
+}
+
+func (pv *policyViolation) Error() string {
+	return pv.err.Error()
+}
+
+func newPolicyEngine(ctx context.Context, wallet ethsigner.Wallet) (*policyEngine, error) {
+	conf := map[string]interface{}{
+		policyRuleAllowedTo:        config.GetStringSlice(signerconfig.PolicyAllowedTo),
+		policyRuleAllowedFunctions: config.GetObjectArray(signerconfig.PolicyAllowedFunctions),
+	}
+	if maxValue := config.GetString(signerconfig.PolicyMaxValue); maxValue != "" {
+		conf[policyRuleMaxValue] = maxValue
+	}
+	if maxFeePerGas := config.GetString(signerconfig.PolicyMaxFeePerGas); maxFeePerGas != "" {
+		conf[policyRuleMaxFeePerGas] = maxFeePerGas
+	}
+	rules, err := parsePolicyRules(ctx, policySourceConfig, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	pe := &policyEngine{
+		rules:                  rules,
+		walletMetadataProperty: config.GetString(signerconfig.PolicyWalletMetadataProperty),
+	}
+	if pe.walletMetadataProperty != "" {
+		var ok bool
+		if pe.wallet, ok = wallet.(ethsigner.WalletMetadata); !ok {
+			return nil, i18n.NewError(ctx, signermsgs.MsgWalletNotSupported, "metadata")
+		}
+	}
+	return pe, nil
+}
+
+func parsePolicyRules(ctx context.Context, source string, data interface{}) (*policyRules, error) {
+	var rules policyRules
+	b, err := json.Marshal(data)
+	if err == nil {
+		err = json.Unmarshal(b, &rules)
+	}
+	if err != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidSigningPolicy, source, err)
+	}
+	for _, fp := range rules.AllowedFunctions {
+		if fp.To == nil || len(fp.Functions) == 0 {
+			return nil, i18n.NewError(ctx, signermsgs.MsgInvalidSigningPolicy, source, policyRuleAllowedFunctions)
+		}
+	}
+	return &rules, nil
+}
+
+func (pe *policyEngine) check(ctx context.Context, from ethtypes.Address0xHex, txn *ethsigner.Transaction) error {
+	if err := pe.checkConfigRules(ctx, txn); err != nil {
+		return err
+	}
+	if pe.wallet == nil {
+		return nil
+	}
+
+	metadata, err := pe.wallet.GetAccountMetadata(ctx, from)
+	if err != nil {
+		return err
+	}
+	walletPolicy, ok := metadata[pe.walletMetadataProperty]
+	if !ok {
+		return nil
+	}
+	rules, err := parsePolicyRules(ctx, policySourceWallet, walletPolicy)
+	if err != nil {
+		return err
+	}
+	return rules.check(ctx, policySourceWallet, txn)
+}
+
+// checkConfigRules checks the transaction against the rules from config only, for transactions
+// from senders that do not need to be in the wallet
+func (pe *policyEngine) checkConfigRules(ctx context.Context, txn *ethsigner.Transaction) error {
+	return pe.rules.check(ctx, policySourceConfig, txn)
+}
+
+func (pr *policyRules) check(ctx context.Context, source string, txn *ethsigner.Transaction) error {
+	reject := func(rule string, msg i18n.ErrorMessageKey, inserts ...interface{}) error {
+		err := i18n.NewError(ctx, msg, append([]interface{}{rule, source}, inserts...)...)
+		log.L(ctx).Warnf("Signing policy rejected transaction: %s", err)
+		return &policyViolation{Rule: rule, Source: source, err: err}
+	}
+
+	if len(pr.AllowedTo) > 0 {
+		if txn.To == nil {
+			return reject(policyRuleAllowedTo, signermsgs.MsgPolicyDeployNotAllowed)
+		}
+		allowed := false
+		for _, addr := range pr.AllowedTo {
+			if *addr == *txn.To {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return reject(policyRuleAllowedTo, signermsgs.MsgPolicyDestinationNotAllowed, txn.To)
+		}
+	}
+
+	if pr.MaxValue != nil && txn.Value.BigInt().Cmp(pr.MaxValue.BigInt()) > 0 {
+		return reject(policyRuleMaxValue, signermsgs.MsgPolicyValueExceeded, txn.Value.BigInt(), pr.MaxValue.BigInt())
+	}
+
+	if pr.MaxFeePerGas != nil {
+		feePerGas := txn.GasPrice
+		if txn.MaxFeePerGas != nil {
+			feePerGas = txn.MaxFeePerGas
+		}
+		if feePerGas.BigInt().Cmp(pr.MaxFeePerGas.BigInt()) > 0 {
+			return reject(policyRuleMaxFeePerGas, signermsgs.MsgPolicyFeeExceeded, feePerGas.BigInt(), pr.MaxFeePerGas.BigInt())
+		}
+	}
+
+	if txn.To != nil {
+		for _, fp := range pr.AllowedFunctions {
+			if *fp.To == *txn.To && !fp.allows(txn.Data) {
+				selector := txn.Data
+				if len(selector) > 4 {
+					selector = selector[0:4]
+				}
+				return reject(policyRuleAllowedFunctions, signermsgs.MsgPolicyFunctionNotAllowed, selector, txn.To)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (fp *functionPolicy) allows(data []byte) bool {
+	if len(data) < 4 {
+		return false
+	}
+	for _, e := range fp.Functions {
+		if e.IsFunction() && bytes.Equal(e.FunctionSelectorBytes(), data[0:4]) {
+			return true
+		}
+	}
+	return false
+}
+
+func policyErrorResponse(err error, id *fftypes.JSONAny) *rpcbackend.RPCResponse {
+	var pv *policyViolation
+	if !errors.As(err, &pv) {
+		return rpcbackend.RPCErrorResponse(err, id, rpcbackend.RPCCodeInternalError)
+	}
+	rpcRes := rpcbackend.RPCErrorResponse(err, id, rpcCodeTransactionRejected)
+	b, _ := json.Marshal(pv)
+	rpcRes.Error.Data = *fftypes.JSONAnyPtrBytes(b)
+	return rpcRes
+}
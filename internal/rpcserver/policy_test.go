@@ -0,0 +1,276 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/mocks/ethsignermocks"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testPolicyFrom  = "0xfb075bb99f2aa4c49955bf703509a227d7a12248"
+	testPolicyToken = "0x3c99f2a4b366d46bcf2277639a135a6d1288eceb"
+	testPolicyOther = "0x497eedc4299dea2f2a364be10025d0ad0f702de3"
+)
+
+var testTransferABI = []interface{}{
+	map[string]interface{}{
+		"type": "function",
+		"name": "transfer",
+		"inputs": []interface{}{
+			map[string]interface{}{"name": "to", "type": "address"},
+			map[string]interface{}{"name": "value", "type": "uint256"},
+		},
+	},
+}
+
+func newTestPolicyEngine(t *testing.T, wallet ethsigner.Wallet, conf ...func()) *policyEngine {
+	signerconfig.Reset()
+	for _, fn := range conf {
+		fn()
+	}
+	pe, err := newPolicyEngine(context.Background(), wallet)
+	assert.NoError(t, err)
+	return pe
+}
+
+func testPolicyCheck(pe *policyEngine, txn *ethsigner.Transaction) error {
+	return pe.check(context.Background(), *ethtypes.MustNewAddress(testPolicyFrom), txn)
+}
+
+func TestPolicyNoRules(t *testing.T) {
+	pe := newTestPolicyEngine(t, &ethsignermocks.Wallet{})
+	err := testPolicyCheck(pe, &ethsigner.Transaction{
+		Value: ethtypes.NewHexInteger64(1000000),
+	})
+	assert.NoError(t, err)
+}
+
+func TestPolicyAllowedTo(t *testing.T) {
+	pe := newTestPolicyEngine(t, &ethsignermocks.Wallet{}, func() {
+		config.Set(signerconfig.PolicyAllowedTo, []string{testPolicyToken})
+	})
+
+	err := testPolicyCheck(pe, &ethsigner.Transaction{To: ethtypes.MustNewAddress(testPolicyToken)})
+	assert.NoError(t, err)
+
+	err = testPolicyCheck(pe, &ethsigner.Transaction{To: ethtypes.MustNewAddress(testPolicyOther)})
+	assert.Regexp(t, "FF22103.*allowedTo.*config", err)
+	assert.Equal(t, policyRuleAllowedTo, err.(*policyViolation).Rule)
+
+	err = testPolicyCheck(pe, &ethsigner.Transaction{})
+	assert.Regexp(t, "FF22102", err)
+}
+
+func TestPolicyMaxValue(t *testing.T) {
+	pe := newTestPolicyEngine(t, &ethsignermocks.Wallet{}, func() {
+		config.Set(signerconfig.PolicyMaxValue, "1000")
+	})
+
+	err := testPolicyCheck(pe, &ethsigner.Transaction{Value: ethtypes.NewHexInteger64(1000)})
+	assert.NoError(t, err)
+
+	err = testPolicyCheck(pe, &ethsigner.Transaction{Value: ethtypes.NewHexInteger64(1001)})
+	assert.Regexp(t, "FF22104.*1001.*1000", err)
+}
+
+func TestPolicyMaxFeePerGas(t *testing.T) {
+	pe := newTestPolicyEngine(t, &ethsignermocks.Wallet{}, func() {
+		config.Set(signerconfig.PolicyMaxFeePerGas, "0x64")
+	})
+
+	err := testPolicyCheck(pe, &ethsigner.Transaction{GasPrice: ethtypes.NewHexInteger64(100)})
+	assert.NoError(t, err)
+
+	err = testPolicyCheck(pe, &ethsigner.Transaction{GasPrice: ethtypes.NewHexInteger64(101)})
+	assert.Regexp(t, "FF22105.*101.*100", err)
+
+	err = testPolicyCheck(pe, &ethsigner.Transaction{
+		GasPrice:     ethtypes.NewHexInteger64(1),
+		MaxFeePerGas: ethtypes.NewHexInteger64(200),
+	})
+	assert.Regexp(t, "FF22105.*200.*100", err)
+}
+
+func TestPolicyAllowedFunctions(t *testing.T) {
+	pe := newTestPolicyEngine(t, &ethsignermocks.Wallet{}, func() {
+		config.Set(signerconfig.PolicyAllowedFunctions, []interface{}{
+			map[string]interface{}{
+				"to":        testPolicyToken,
+				"functions": testTransferABI,
+			},
+		})
+	})
+
+	err := testPolicyCheck(pe, &ethsigner.Transaction{
+		To:   ethtypes.MustNewAddress(testPolicyToken),
+		Data: ethtypes.MustNewHexBytes0xPrefix("0xa9059cbb000000000000000000000000497eedc4299dea2f2a364be10025d0ad0f702de3"),
+	})
+	assert.NoError(t, err)
+
+	// approve(address,uint256) is not allowed
+	err = testPolicyCheck(pe, &ethsigner.Transaction{
+		To:   ethtypes.MustNewAddress(testPolicyToken),
+		Data: ethtypes.MustNewHexBytes0xPrefix("0x095ea7b3000000000000000000000000497eedc4299dea2f2a364be10025d0ad0f702de3"),
+	})
+	assert.Regexp(t, "FF22106.*0x095ea7b3", err)
+
+	err = testPolicyCheck(pe, &ethsigner.Transaction{
+		To: ethtypes.MustNewAddress(testPolicyToken),
+	})
+	assert.Regexp(t, "FF22106", err)
+
+	// Other destinations are not restricted
+	err = testPolicyCheck(pe, &ethsigner.Transaction{
+		To:   ethtypes.MustNewAddress(testPolicyOther),
+		Data: ethtypes.MustNewHexBytes0xPrefix("0x095ea7b3"),
+	})
+	assert.NoError(t, err)
+}
+
+func TestPolicyBadConfig(t *testing.T) {
+	signerconfig.Reset()
+	config.Set(signerconfig.PolicyMaxValue, "wrong")
+	_, err := newPolicyEngine(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22101", err)
+
+	signerconfig.Reset()
+	config.Set(signerconfig.PolicyAllowedFunctions, []interface{}{
+		map[string]interface{}{"functions": testTransferABI},
+	})
+	_, err = newPolicyEngine(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22101.*allowedFunctions", err)
+
+	signerconfig.Reset()
+	config.Set(signerconfig.PolicyWalletMetadataProperty, "policy")
+	_, err = newPolicyEngine(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22094", err)
+}
+
+func TestNewServerBadPolicy(t *testing.T) {
+	signerconfig.Reset()
+	config.Set(signerconfig.PolicyMaxValue, "wrong")
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22101", err)
+}
+
+func TestPolicyWalletMetadata(t *testing.T) {
+	w := ethsignermocks.NewWalletMetadata(t)
+	pe := newTestPolicyEngine(t, w, func() {
+		config.Set(signerconfig.PolicyWalletMetadataProperty, "policy")
+		config.Set(signerconfig.PolicyMaxValue, "1000")
+	})
+	w.On("GetAccountMetadata", mock.Anything, *ethtypes.MustNewAddress(testPolicyFrom)).Return(map[string]interface{}{
+		"policy": map[string]interface{}{
+			"maxValue":  "100",
+			"allowedTo": []interface{}{testPolicyToken},
+		},
+	}, nil)
+
+	err := testPolicyCheck(pe, &ethsigner.Transaction{
+		To:    ethtypes.MustNewAddress(testPolicyToken),
+		Value: ethtypes.NewHexInteger64(100),
+	})
+	assert.NoError(t, err)
+
+	err = testPolicyCheck(pe, &ethsigner.Transaction{
+		To:    ethtypes.MustNewAddress(testPolicyToken),
+		Value: ethtypes.NewHexInteger64(101),
+	})
+	assert.Regexp(t, "FF22104.*maxValue.*wallet", err)
+	assert.Equal(t, policySourceWallet, err.(*policyViolation).Source)
+
+	// The config rules are checked first
+	err = testPolicyCheck(pe, &ethsigner.Transaction{
+		To:    ethtypes.MustNewAddress(testPolicyToken),
+		Value: ethtypes.NewHexInteger64(1001),
+	})
+	assert.Regexp(t, "FF22104.*maxValue.*config", err)
+}
+
+func TestPolicyWalletMetadataNoPolicy(t *testing.T) {
+	w := ethsignermocks.NewWalletMetadata(t)
+	pe := newTestPolicyEngine(t, w, func() {
+		config.Set(signerconfig.PolicyWalletMetadataProperty, "policy")
+	})
+	w.On("GetAccountMetadata", mock.Anything, mock.Anything).Return(nil, nil)
+
+	err := testPolicyCheck(pe, &ethsigner.Transaction{})
+	assert.NoError(t, err)
+}
+
+func TestPolicyWalletMetadataFail(t *testing.T) {
+	w := ethsignermocks.NewWalletMetadata(t)
+	pe := newTestPolicyEngine(t, w, func() {
+		config.Set(signerconfig.PolicyWalletMetadataProperty, "policy")
+	})
+	w.On("GetAccountMetadata", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	err := testPolicyCheck(pe, &ethsigner.Transaction{})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestPolicyWalletMetadataBadPolicy(t *testing.T) {
+	w := ethsignermocks.NewWalletMetadata(t)
+	pe := newTestPolicyEngine(t, w, func() {
+		config.Set(signerconfig.PolicyWalletMetadataProperty, "policy")
+	})
+	w.On("GetAccountMetadata", mock.Anything, mock.Anything).Return(map[string]interface{}{
+		"policy": "wrong",
+	}, nil)
+
+	err := testPolicyCheck(pe, &ethsigner.Transaction{})
+	assert.Regexp(t, "FF22101.*wallet", err)
+}
+
+func TestPolicyErrorResponseNotPolicy(t *testing.T) {
+	rpcRes := policyErrorResponse(fmt.Errorf("pop"), fftypes.JSONAnyPtr("1"))
+	assert.Equal(t, int64(rpcbackend.RPCCodeInternalError), rpcRes.Error.Code)
+}
+
+func TestSendTransactionPolicyRejected(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+	s.policy.rules.MaxValue = ethtypes.NewHexInteger64(1000)
+
+	rpcRes, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_sendTransaction",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`{
+				"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248",
+				"value": "0x3e9"
+			}`),
+		},
+	})
+	assert.Regexp(t, "FF22104", err)
+	assert.Equal(t, int64(-32003), rpcRes.Error.Code)
+	assert.JSONEq(t, `{"rule":"maxValue","source":"config"}`, rpcRes.Error.Data.String())
+
+}
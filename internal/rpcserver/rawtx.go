@@ -0,0 +1,106 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rlp"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+)
+
+// rawTransactionValidator checks transactions that were signed elsewhere, and submitted with
+// eth_sendRawTransaction, before they are passed to the backend
+type rawTransactionValidator struct {
+	allowUnprotected bool
+	policy           bool
+}
+
+func newRawTransactionValidator() *rawTransactionValidator {
+	if !config.GetBool(signerconfig.RawTransactionsValidate) {
+		return nil
+	}
+	return &rawTransactionValidator{
+		allowUnprotected: config.GetBool(signerconfig.RawTransactionsAllowUnprotected),
+		policy:           config.GetBool(signerconfig.RawTransactionsPolicy),
+	}
+}
+
+// processEthSendRawTransaction decodes the raw transaction and recovers the sender, which checks the
+// payload is well formed and signed for the chain ID of this chain, then optionally applies the
+// signing policy, before passing the request to the backend unchanged
+func (c *chain) processEthSendRawTransaction(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+	rv := c.s.rawTransactions
+	if rv == nil {
+		return c.backend.SyncRequest(ctx, rpcReq)
+	}
+
+	if len(rpcReq.Params) != 1 {
+		err := i18n.NewError(ctx, signermsgs.MsgInvalidParamCount, 1, len(rpcReq.Params))
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+
+	var raw ethtypes.HexBytes0xPrefix
+	err := json.Unmarshal(rpcReq.Params[0].Bytes(), &raw)
+	if err != nil {
+		err := i18n.WrapError(ctx, err, signermsgs.MsgInvalidRawTransaction)
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+
+	from, txn, err := ethsigner.RecoverRawTransaction(ctx, raw, c.chainID)
+	if err != nil {
+		err := i18n.WrapError(ctx, err, signermsgs.MsgInvalidRawTransaction)
+		log.L(ctx).Warnf("Rejected raw transaction: %s", err)
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+
+	if !rv.allowUnprotected && isUnprotectedLegacy(raw) {
+		err := i18n.NewError(ctx, signermsgs.MsgUnprotectedRawTransaction, from, c.chainID)
+		log.L(ctx).Warnf("Rejected raw transaction: %s", err)
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+
+	if rv.policy {
+		err = c.s.policy.checkConfigRules(ctx, txn.Transaction)
+		if err != nil {
+			return policyErrorResponse(err, rpcReq.ID), err
+		}
+	}
+
+	log.L(ctx).Debugf("Validated raw transaction from %s with nonce %s", from, txn.Nonce.BigInt())
+	return c.backend.SyncRequest(ctx, rpcReq)
+}
+
+// isUnprotectedLegacy returns true for a legacy transaction signed before EIP-155, with a V value
+// of 27 or 28 rather than one that includes the chain ID. Must only be called on a payload that
+// has already been decoded successfully.
+func isUnprotectedLegacy(raw ethtypes.HexBytes0xPrefix) bool {
+	if raw[0] < 0xc0 {
+		return false
+	}
+	decoded, _, _ := rlp.Decode(raw)
+	v := decoded.(rlp.List)[6].ToData().IntOrZero().Int64()
+	return v == 27 || v == 28
+}
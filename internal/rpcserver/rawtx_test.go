@@ -0,0 +1,256 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/mocks/rpcbackendmocks"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rlp"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testRawChainID = 1001
+
+func testRawTransaction(t *testing.T, value int64, sign func(txn *ethsigner.Transaction, kp *secp256k1.KeyPair) ([]byte, error)) ethtypes.HexBytes0xPrefix {
+	kp, err := secp256k1.GenerateSecp256k1KeyPair()
+	assert.NoError(t, err)
+	raw, err := sign(&ethsigner.Transaction{
+		Nonce:                ethtypes.NewHexInteger64(3),
+		GasLimit:             ethtypes.NewHexInteger64(21000),
+		GasPrice:             ethtypes.NewHexInteger64(100),
+		MaxPriorityFeePerGas: ethtypes.NewHexInteger64(10),
+		MaxFeePerGas:         ethtypes.NewHexInteger64(200),
+		To:                   ethtypes.MustNewAddress("0x497eedc4299dea2f2a364be10025d0ad0f702de3"),
+		Value:                ethtypes.NewHexInteger64(value),
+	}, kp)
+	assert.NoError(t, err)
+	return raw
+}
+
+func signEIP1559(chainID int64) func(txn *ethsigner.Transaction, kp *secp256k1.KeyPair) ([]byte, error) {
+	return func(txn *ethsigner.Transaction, kp *secp256k1.KeyPair) ([]byte, error) {
+		return txn.SignEIP1559(kp, chainID)
+	}
+}
+
+func signLegacyEIP155(chainID int64) func(txn *ethsigner.Transaction, kp *secp256k1.KeyPair) ([]byte, error) {
+	return func(txn *ethsigner.Transaction, kp *secp256k1.KeyPair) ([]byte, error) {
+		return txn.SignLegacyEIP155(kp, chainID)
+	}
+}
+
+func signLegacyOriginal(txn *ethsigner.Transaction, kp *secp256k1.KeyPair) ([]byte, error) {
+	return txn.SignLegacyOriginal(kp)
+}
+
+func newTestRawTxServer(t *testing.T, conf ...func()) (*rpcServer, *rpcbackendmocks.Backend, func()) {
+	_, s, done := newTestServer(t, conf...)
+	s.chainID = testRawChainID
+	return s, s.backend.(*rpcbackendmocks.Backend), done
+}
+
+func testSendRaw(s *rpcServer, params ...*fftypes.JSONAny) (*rpcbackend.RPCResponse, error) {
+	return s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_sendRawTransaction",
+		Params: params,
+	})
+}
+
+func rawParam(raw ethtypes.HexBytes0xPrefix) *fftypes.JSONAny {
+	return fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, raw))
+}
+
+func mockSendRawPassthrough(bm *rpcbackendmocks.Backend, raw ethtypes.HexBytes0xPrefix) {
+	bm.On("SyncRequest", mock.Anything, mock.MatchedBy(func(rpcReq *rpcbackend.RPCRequest) bool {
+		return rpcReq.Method == "eth_sendRawTransaction" && rpcReq.Params[0].String() == rawParam(raw).String()
+	})).Return(&rpcbackend.RPCResponse{
+		JSONRpc: "2.0",
+		ID:      fftypes.JSONAnyPtr("1"),
+		Result:  fftypes.JSONAnyPtr(`"0xf1c0"`),
+	}, nil)
+}
+
+func TestSendRawTransactionValidated(t *testing.T) {
+
+	s, bm, done := newTestRawTxServer(t)
+	defer done()
+
+	for _, sign := range []func(txn *ethsigner.Transaction, kp *secp256k1.KeyPair) ([]byte, error){
+		signEIP1559(testRawChainID),
+		signLegacyEIP155(testRawChainID),
+		signLegacyOriginal,
+	} {
+		raw := testRawTransaction(t, 100, sign)
+		mockSendRawPassthrough(bm, raw)
+		rpcRes, err := testSendRaw(s, rawParam(raw))
+		assert.NoError(t, err)
+		assert.Equal(t, `"0xf1c0"`, rpcRes.Result.String())
+	}
+
+}
+
+func TestSendRawTransactionWrongChain(t *testing.T) {
+
+	s, bm, done := newTestRawTxServer(t)
+	defer done()
+
+	rpcRes, err := testSendRaw(s, rawParam(testRawTransaction(t, 100, signEIP1559(2002))))
+	assert.Regexp(t, "FF22156.*FF22086", err)
+	assert.Equal(t, int64(rpcbackend.RPCCodeInvalidRequest), rpcRes.Error.Code)
+
+	_, err = testSendRaw(s, rawParam(testRawTransaction(t, 100, signLegacyEIP155(2002))))
+	assert.Regexp(t, "FF22156.*FF22085", err)
+
+	bm.AssertNotCalled(t, "SyncRequest", mock.Anything, mock.Anything)
+
+}
+
+func TestSendRawTransactionMalformed(t *testing.T) {
+
+	s, bm, done := newTestRawTxServer(t)
+	defer done()
+
+	_, err := testSendRaw(s)
+	assert.Regexp(t, "FF22019", err)
+
+	_, err = testSendRaw(s, fftypes.JSONAnyPtr(`"not hex"`))
+	assert.Regexp(t, "FF22156", err)
+
+	_, err = testSendRaw(s, fftypes.JSONAnyPtr(`"0x"`))
+	assert.Regexp(t, "FF22156.*FF22081", err)
+
+	_, err = testSendRaw(s, fftypes.JSONAnyPtr(`"0x05"`))
+	assert.Regexp(t, "FF22156.*FF22082", err)
+
+	_, err = testSendRaw(s, fftypes.JSONAnyPtr(`"0x02ff"`))
+	assert.Regexp(t, "FF22156.*FF22084", err)
+
+	bm.AssertNotCalled(t, "SyncRequest", mock.Anything, mock.Anything)
+
+}
+
+func TestSendRawTransactionOversizedSignature(t *testing.T) {
+
+	s, bm, done := newTestRawTxServer(t)
+	defer done()
+
+	raw := testRawTransaction(t, 100, signEIP1559(testRawChainID))
+	decoded, _, err := rlp.Decode(raw[1:])
+	assert.NoError(t, err)
+	rlpList := decoded.(rlp.List)
+
+	// R or S longer than the 32 bytes of the compact signature must be rejected, not panic
+	for _, i := range []int{10, 11} {
+		oversized := make(rlp.List, len(rlpList))
+		copy(oversized, rlpList)
+		oversized[i] = append(rlp.Data{0x01}, make(rlp.Data, 32)...)
+		rpcRes, err := testSendRaw(s, rawParam(append([]byte{ethsigner.TransactionType1559}, oversized.Encode()...)))
+		assert.Regexp(t, "FF22156.*FF22198", err)
+		assert.Equal(t, int64(rpcbackend.RPCCodeInvalidRequest), rpcRes.Error.Code)
+	}
+
+	bm.AssertNotCalled(t, "SyncRequest", mock.Anything, mock.Anything)
+
+}
+
+func TestSendRawTransactionUnprotected(t *testing.T) {
+
+	s, bm, done := newTestRawTxServer(t, func() {
+		config.Set(signerconfig.RawTransactionsAllowUnprotected, false)
+	})
+	defer done()
+
+	rpcRes, err := testSendRaw(s, rawParam(testRawTransaction(t, 100, signLegacyOriginal)))
+	assert.Regexp(t, "FF22157", err)
+	assert.Equal(t, int64(rpcbackend.RPCCodeInvalidRequest), rpcRes.Error.Code)
+	bm.AssertNotCalled(t, "SyncRequest", mock.Anything, mock.Anything)
+
+	for _, sign := range []func(txn *ethsigner.Transaction, kp *secp256k1.KeyPair) ([]byte, error){
+		signEIP1559(testRawChainID),
+		signLegacyEIP155(testRawChainID),
+	} {
+		raw := testRawTransaction(t, 100, sign)
+		mockSendRawPassthrough(bm, raw)
+		_, err = testSendRaw(s, rawParam(raw))
+		assert.NoError(t, err)
+	}
+
+}
+
+func TestSendRawTransactionPolicy(t *testing.T) {
+
+	s, bm, done := newTestRawTxServer(t, func() {
+		config.Set(signerconfig.RawTransactionsPolicy, true)
+		config.Set(signerconfig.PolicyMaxValue, "1000")
+	})
+	defer done()
+
+	rpcRes, err := testSendRaw(s, rawParam(testRawTransaction(t, 1001, signEIP1559(testRawChainID))))
+	assert.Regexp(t, "FF22104", err)
+	assert.Equal(t, int64(rpcCodeTransactionRejected), rpcRes.Error.Code)
+	var pv policyViolation
+	err = json.Unmarshal(rpcRes.Error.Data.Bytes(), &pv)
+	assert.NoError(t, err)
+	assert.Equal(t, policyRuleMaxValue, pv.Rule)
+	bm.AssertNotCalled(t, "SyncRequest", mock.Anything, mock.Anything)
+
+	raw := testRawTransaction(t, 1000, signEIP1559(testRawChainID))
+	mockSendRawPassthrough(bm, raw)
+	_, err = testSendRaw(s, rawParam(raw))
+	assert.NoError(t, err)
+
+}
+
+func TestSendRawTransactionPolicyNotApplied(t *testing.T) {
+
+	s, bm, done := newTestRawTxServer(t, func() {
+		config.Set(signerconfig.PolicyMaxValue, "1000")
+	})
+	defer done()
+
+	raw := testRawTransaction(t, 1001, signEIP1559(testRawChainID))
+	mockSendRawPassthrough(bm, raw)
+	_, err := testSendRaw(s, rawParam(raw))
+	assert.NoError(t, err)
+
+}
+
+func TestSendRawTransactionValidationDisabled(t *testing.T) {
+
+	s, bm, done := newTestRawTxServer(t, func() {
+		config.Set(signerconfig.RawTransactionsValidate, false)
+	})
+	defer done()
+
+	mockSendRawPassthrough(bm, ethtypes.MustNewHexBytes0xPrefix("0x05"))
+	rpcRes, err := testSendRaw(s, fftypes.JSONAnyPtr(`"0x05"`))
+	assert.NoError(t, err)
+	assert.Equal(t, `"0xf1c0"`, rpcRes.Result.String())
+
+}
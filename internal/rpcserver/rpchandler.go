@@ -31,46 +31,49 @@ import (
 	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
 )
 
-func (s *rpcServer) rpcHandler(w http.ResponseWriter, r *http.Request) {
+func (c *chain) rpcHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context() // will include logging ID from FireFly server framework
 
 	b, err := io.ReadAll(r.Body)
 	if err != nil {
-		s.replyRPCParseError(ctx, w, b)
+		c.s.replyRPCParseError(ctx, w, b)
 		return
 	}
 
 	log.L(ctx).Tracef("RPC --> %s", b)
 
-	if s.sniffFirstByte(b) == '[' {
-		s.handleRPCBatch(ctx, w, b)
+	if c.s.sniffFirstByte(b) == '[' {
+		c.handleRPCBatch(ctx, w, b)
 		return
 	}
 
 	var rpcRequest rpcbackend.RPCRequest
 	err = json.Unmarshal(b, &rpcRequest)
 	if err != nil {
-		s.replyRPCParseError(ctx, w, b)
+		c.s.replyRPCParseError(ctx, w, b)
 		return
 	}
-	rpcResponse, err := s.processRPC(ctx, &rpcRequest)
+	rpcResponse, err := c.processRPC(ctx, &rpcRequest)
 	if err != nil {
-		s.replyRPC(ctx, w, rpcResponse, http.StatusInternalServerError)
+		c.s.replyRPC(ctx, w, rpcResponse, http.StatusInternalServerError)
 		return
 	}
-	s.replyRPC(ctx, w, rpcResponse, http.StatusOK)
+	c.s.replyRPC(ctx, w, rpcResponse, http.StatusOK)
 
 }
 
 func (s *rpcServer) replyRPCParseError(ctx context.Context, w http.ResponseWriter, b []byte) {
 	log.L(ctx).Errorf("Request could not be parsed: %s", b)
-	rpcError := rpcbackend.RPCErrorResponse(
+	s.replyRPC(ctx, w, rpcParseErrorResponse(ctx), http.StatusBadRequest)
+}
+
+func rpcParseErrorResponse(ctx context.Context) *rpcbackend.RPCResponse {
+	return rpcbackend.RPCErrorResponse(
 		i18n.NewError(ctx, signermsgs.MsgInvalidRequest),
 		fftypes.JSONAnyPtr("1"), // we couldn't parse the request ID
 		rpcbackend.RPCCodeInvalidRequest,
 	)
-	s.replyRPC(ctx, w, rpcError, http.StatusBadRequest)
 }
 
 func (s *rpcServer) replyRPC(ctx context.Context, w http.ResponseWriter, result interface{}, status int) {
@@ -95,16 +98,29 @@ func (s *rpcServer) sniffFirstByte(data []byte) byte {
 	return 0x00
 }
 
-func (s *rpcServer) handleRPCBatch(ctx context.Context, w http.ResponseWriter, batchBytes []byte) {
+func (c *chain) handleRPCBatch(ctx context.Context, w http.ResponseWriter, batchBytes []byte) {
 
 	var rpcArray []*rpcbackend.RPCRequest
 	err := json.Unmarshal(batchBytes, &rpcArray)
 	if err != nil || len(rpcArray) == 0 {
 		log.L(ctx).Errorf("Bad RPC array received %s", batchBytes)
-		s.replyRPCParseError(ctx, w, batchBytes)
+		c.s.replyRPCParseError(ctx, w, batchBytes)
 		return
 	}
 
+	rpcResponses, failed := c.s.processRPCBatch(ctx, rpcArray, c.processRPC)
+	status := http.StatusOK
+	if failed {
+		status = http.StatusInternalServerError
+	}
+	c.s.replyRPC(ctx, w, rpcResponses, status)
+}
+
+type rpcProcessor func(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error)
+
+// processRPCBatch processes each request in the batch in parallel, returning the responses in order,
+// and whether any of the requests failed
+func (s *rpcServer) processRPCBatch(ctx context.Context, rpcArray []*rpcbackend.RPCRequest, process rpcProcessor) ([]*rpcbackend.RPCResponse, bool) {
 	// Kick off a routine to fill in each
 	rpcResponses := make([]*rpcbackend.RPCResponse, len(rpcArray))
 	results := make(chan error)
@@ -113,16 +129,16 @@ func (s *rpcServer) handleRPCBatch(ctx context.Context, w http.ResponseWriter, b
 		rpcReq := r
 		go func() {
 			var err error
-			rpcResponses[responseNumber], err = s.processRPC(ctx, rpcReq)
+			rpcResponses[responseNumber], err = process(ctx, rpcReq)
 			results <- err
 		}()
 	}
-	status := 200
+	failed := false
 	for range rpcArray {
 		err := <-results
 		if err != nil {
-			status = http.StatusInternalServerError
+			failed = true
 		}
 	}
-	s.replyRPC(ctx, w, rpcResponses, status)
+	return rpcResponses, failed
 }
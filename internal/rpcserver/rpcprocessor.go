@@ -1,4 +1,4 @@
-// Copyright © 2022 Kaleido, Inc.
+// Copyright © 2026 Kaleido, Inc.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -17,19 +17,47 @@
 package rpcserver
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/internal/audit"
+	"github.com/hyperledger/firefly-signer/internal/metrics"
 	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/eip712"
 	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
 	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
 	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"golang.org/x/crypto/sha3"
 )
 
-func (s *rpcServer) processRPC(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+// SignTransactionResult is the result of eth_signTransaction, with the raw signed
+// transaction bytes and the decoded transaction
+type SignTransactionResult struct {
+	Raw ethtypes.HexBytes0xPrefix `json:"raw"`
+	Tx  *SignedTransaction        `json:"tx"`
+}
+
+// SignedTransaction is the transaction decoded from the raw signed payload, with the transaction hash
+type SignedTransaction struct {
+	*ethsigner.Transaction
+	Hash ethtypes.HexBytes0xPrefix `json:"hash"`
+}
+
+func (c *chain) processRPC(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+	startTime := time.Now()
+	rpcRes, err := c.processRPCMethod(ctx, rpcReq)
+	c.s.metrics.RPCRequestCompleted(ctx, c.name, rpcReq.Method, time.Since(startTime), err)
+	return rpcRes, err
+}
+
+func (c *chain) processRPCMethod(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
 	if rpcReq.ID == nil {
 		err := i18n.NewError(ctx, signermsgs.MsgMissingRequestID)
 		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
@@ -37,71 +65,446 @@ func (s *rpcServer) processRPC(ctx context.Context, rpcReq *rpcbackend.RPCReques
 
 	switch rpcReq.Method {
 	case "eth_accounts", "personal_accounts":
-		return s.processEthAccounts(ctx, rpcReq)
+		return c.processEthAccounts(ctx, rpcReq)
 	case "eth_sendTransaction":
-		return s.processEthSendTransaction(ctx, rpcReq)
+		return c.processEthSendTransaction(ctx, rpcReq)
+	case "eth_signTransaction":
+		return c.processEthSignTransaction(ctx, rpcReq)
+	case "eth_sendRawTransaction":
+		return c.processEthSendRawTransaction(ctx, rpcReq)
+	case "personal_sign":
+		return c.processPersonalSign(ctx, rpcReq)
+	case "eth_sign":
+		return c.processEthSign(ctx, rpcReq)
+	case "eth_signTypedData_v4", "eth_signTypedData":
+		return c.processEthSignTypedDataV4(ctx, rpcReq)
+	case "ffsigner_getApproval":
+		return c.processGetApproval(ctx, rpcReq)
+	case "ffsigner_getTransaction":
+		return c.processGetTransaction(ctx, rpcReq)
 	default:
-		return s.backend.SyncRequest(ctx, rpcReq)
+		return c.backend.SyncRequest(ctx, rpcReq)
 	}
 }
 
-func (s *rpcServer) processEthAccounts(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
-	accounts, err := s.wallet.GetAccounts(ctx)
+func (c *chain) processEthAccounts(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+	accounts, err := c.s.wallet.GetAccounts(ctx)
 	if err != nil {
 		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInternalError), err
 	}
-	b, _ := json.Marshal(&accounts)
-	return &rpcbackend.RPCResponse{
-		JSONRpc: "2.0",
-		ID:      rpcReq.ID,
-		Result:  fftypes.JSONAnyPtrBytes(b),
-	}, nil
+	accounts = authIdentityFromContext(ctx).filterAccounts(accounts)
+	return rpcResultResponse(rpcReq.ID, &accounts), nil
+}
+
+func (c *chain) processEthSendTransaction(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+	// Transactions that match the approval criteria are held until they are approved, and are
+	// then prepared again so the fees are current when they are signed
+	prepared, rpcRes, err := c.prepareTransaction(ctx, rpcReq)
+	if err != nil {
+		return rpcRes, err
+	}
+	if reasons := c.s.approvals.criteria(prepared.txn); len(reasons) > 0 {
+		return c.s.approvals.hold(ctx, c, rpcReq, prepared, reasons)
+	}
+	return c.sendPreparedTransaction(ctx, rpcReq, prepared)
+}
+
+// sendTransaction signs the transaction in the request, and submits it to the chain with eth_sendRawTransaction.
+// It is used once a held transaction is approved, so does not check the approval criteria.
+func (c *chain) sendTransaction(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+	prepared, rpcRes, err := c.prepareTransaction(ctx, rpcReq)
+	if err != nil {
+		return rpcRes, err
+	}
+	return c.sendPreparedTransaction(ctx, rpcReq, prepared)
+}
+
+func (c *chain) sendPreparedTransaction(ctx context.Context, rpcReq *rpcbackend.RPCRequest, prepared *preparedTransaction) (*rpcbackend.RPCResponse, error) {
+
+	signed, rpcRes, err := c.signPreparedTransaction(ctx, rpcReq, prepared)
+	if err != nil {
+		return rpcRes, err
+	}
+
+	// Progress with the original request, now updated with a raw transaction fully signed
+	rpcReq.Method = "eth_sendRawTransaction"
+	rpcReq.Params = []*fftypes.JSONAny{fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, signed.raw))}
+	rpcRes, err = c.backend.SyncRequest(ctx, rpcReq)
+
+	// Let the nonce manager know if the nonce was used. A transaction the node rejected is not
+	// counted against the quotas either.
+	outcome := submitOutcome(rpcRes, err)
+	signed.nonce.complete(ctx, outcome)
+	if outcome == nonceReleased {
+		c.s.limits.release(ctx, c.name, *signed.from, signed.spend)
+	}
+	if err == nil && c.s.tracker != nil {
+		c.s.tracker.track(ctx, c, prepared.txn, signed, rpcRes)
+	}
+	return rpcRes, err
+
 }
 
-func (s *rpcServer) processEthSendTransaction(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+// processEthSignTransaction signs the transaction and returns it to the caller, without submitting it
+// to the chain. The result uses the same {raw,tx} structure as the eth_signTransaction method in geth.
+func (c *chain) processEthSignTransaction(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+
+	signed, rpcRes, err := c.signTransactionRequest(ctx, rpcReq)
+	if err != nil {
+		return rpcRes, err
+	}
+	defer signed.nonce.complete(ctx, nonceReleased)
+
+	// Decode what we signed, so the caller gets back exactly what is in the raw payload
+	_, signedTx, err := ethsigner.RecoverRawTransaction(ctx, signed.raw, c.chainID)
+	if err != nil {
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInternalError), err
+	}
+	signedTx.From = json.RawMessage(fmt.Sprintf(`"%s"`, signed.from))
+
+	// We have handed the signed transaction back to the caller to submit, so the nonce is used
+	signed.nonce.complete(ctx, nonceConsumed)
+
+	return rpcResultResponse(rpcReq.ID, &SignTransactionResult{
+		Raw: signed.raw,
+		Tx: &SignedTransaction{
+			Transaction: signedTx.Transaction,
+			Hash:        ethtypes.HexBytes0xPrefix(keccak256(signed.raw)),
+		},
+	}), nil
+}
+
+type preparedTransaction struct {
+	txn  *ethsigner.Transaction
+	from *ethtypes.Address0xHex
+}
+
+type signedTransactionRequest struct {
+	raw   ethtypes.HexBytes0xPrefix
+	from  *ethtypes.Address0xHex
+	nonce *nonceAssignment // nil if the nonce was supplied by the caller
+	spend *quotaSpend      // nil if the quotas are disabled
+}
+
+// signTransactionRequest prepares the transaction in the request, and signs it.
+// A transaction signed without being submitted cannot be held for approval, so one that
+// matches the approval criteria is rejected.
+//
+// If a nonce was assigned, the caller must complete the nonce assignment.
+// In all error paths an RPCResponse is returned, to send back to the caller.
+func (c *chain) signTransactionRequest(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*signedTransactionRequest, *rpcbackend.RPCResponse, error) {
+	prepared, rpcRes, err := c.prepareTransaction(ctx, rpcReq)
+	if err != nil {
+		return nil, rpcRes, err
+	}
+	if reasons := c.s.approvals.criteria(prepared.txn); len(reasons) > 0 {
+		err := i18n.NewError(ctx, signermsgs.MsgApprovalRequiresSend, reasons)
+		return nil, rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcCodeTransactionRejected), err
+	}
+	return c.signPreparedTransaction(ctx, rpcReq, prepared)
+}
+
+// prepareTransaction parses the transaction from the first parameter of the request, checks the caller
+// is authorized to use the from address, fills in gas/fees if required, checks the signing policy,
+// and simulates it if enabled.
+//
+// In all error paths an RPCResponse is returned, to send back to the caller.
+func (c *chain) prepareTransaction(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*preparedTransaction, *rpcbackend.RPCResponse, error) {
 
 	if len(rpcReq.Params) < 1 {
 		err := i18n.NewError(ctx, signermsgs.MsgInvalidParamCount, 1, len(rpcReq.Params))
-		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+		return nil, rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
 	}
 
 	var txn ethsigner.Transaction
 	err := json.Unmarshal(rpcReq.Params[0].Bytes(), &txn)
 	if err != nil {
 		err := i18n.WrapError(ctx, err, signermsgs.MsgInvalidTransaction)
-		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeParseError), err
+		return nil, rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeParseError), err
 	}
 
 	if txn.From == nil {
 		err := i18n.NewError(ctx, signermsgs.MsgMissingFrom)
-		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+		return nil, rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+
+	prepared := &preparedTransaction{txn: &txn, from: new(ethtypes.Address0xHex)}
+	err = json.Unmarshal(txn.From, prepared.from)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The caller must be authorized to use the account, before we do anything else with the transaction
+	err = authIdentityFromContext(ctx).authorize(ctx, *prepared.from)
+	if err != nil {
+		return nil, authErrorResponse(err, rpcReq.ID), err
+	}
+
+	// Fill in any gas and fee fields the caller did not supply
+	err = c.gas.populate(ctx, &txn)
+	if err != nil {
+		return nil, rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInternalError), err
 	}
 
-	// We have trivial nonce management built-in for sequential signing API calls, by making a JSON/RPC request
-	// to the up-stream node. This should not be relied upon for production use cases.
+	// Check the transaction is allowed by the signing policy, before we assign a nonce
+	err = c.s.policy.check(ctx, *prepared.from, &txn)
+	if err != nil {
+		return nil, policyErrorResponse(err, rpcReq.ID), err
+	}
+
+	// Simulate the transaction, so one that would revert does not use a nonce or any quota
+	err = c.simulate(ctx, &txn)
+	if err != nil {
+		return nil, simulationErrorResponse(err, rpcReq.ID), err
+	}
+
+	return prepared, nil, nil
+}
+
+// signPreparedTransaction applies the rate limits and spend quotas, assigns a nonce if required,
+// and signs the transaction with the wallet.
+//
+// If a nonce was assigned, the caller must complete the nonce assignment.
+// In all error paths an RPCResponse is returned, to send back to the caller.
+func (c *chain) signPreparedTransaction(ctx context.Context, rpcReq *rpcbackend.RPCRequest, prepared *preparedTransaction) (*signedTransactionRequest, *rpcbackend.RPCResponse, error) {
+
+	txn := prepared.txn
+	signed := &signedTransactionRequest{from: prepared.from}
+
+	// Apply the rate limits and spend quotas, once the transaction has passed the policy
+	var err error
+	signed.spend, err = c.s.limits.reserve(ctx, authIdentityFromContext(ctx), c.name, *signed.from, txn)
+	if err != nil {
+		return nil, limitErrorResponse(err, rpcReq.ID), err
+	}
+
+	// The nonce manager holds a lock on the address until the nonce is completed, so concurrent
+	// requests for the same address are assigned sequential nonces.
 	// See FireFly Transaction Manager, or FireFly EthConnect, for more advanced nonce management capabilities.
 	if txn.Nonce == nil {
-		var from ethtypes.Address0xHex
-		err := json.Unmarshal(txn.From, &from)
+		signed.nonce, err = c.nonces.assignNonce(ctx, *signed.from)
 		if err != nil {
-			return nil, err
-		}
-		rpcErr := s.backend.CallRPC(ctx, &txn.Nonce, "eth_getTransactionCount", &from, "pending")
-		if rpcErr != nil {
-			return rpcbackend.RPCErrorResponse(rpcErr.Error(), rpcReq.ID, rpcbackend.RPCCodeInternalError), rpcErr.Error()
+			c.s.limits.release(ctx, c.name, *signed.from, signed.spend)
+			return nil, rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInternalError), err
 		}
+		txn.Nonce = ethtypes.NewHexIntegerU64(signed.nonce.nonce)
 	}
 
 	// Sign the transaction
-	var hexData ethtypes.HexBytes0xPrefix
-	hexData, err = s.wallet.Sign(ctx, &txn, s.chainID)
+	signed.raw, err = c.s.wallet.Sign(ctx, txn, c.chainID)
+	c.s.metrics.SigningCompleted(ctx, signed.from.String(), transactionSigningType(txn), err)
+	if err == nil {
+		entry := &audit.Entry{
+			Type:   audit.EntryTypeTransaction,
+			From:   signed.from,
+			TxHash: keccak256(signed.raw),
+			To:     txn.To,
+			Value:  txn.Value,
+		}
+		if len(txn.Data) >= 4 {
+			entry.Selector = txn.Data[0:4]
+		}
+		err = c.recordSigning(ctx, entry)
+	}
+	if err != nil {
+		signed.nonce.complete(ctx, nonceReleased)
+		c.s.limits.release(ctx, c.name, *signed.from, signed.spend)
+		return nil, rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInternalError), err
+	}
+	return signed, nil, nil
+
+}
+
+// processPersonalSign handles personal_sign, which has parameters [message, address(, password)]
+func (c *chain) processPersonalSign(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+	if len(rpcReq.Params) < 2 {
+		err := i18n.NewError(ctx, signermsgs.MsgInvalidParamCount, 2, len(rpcReq.Params))
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+	return c.signEIP191PersonalMessage(ctx, rpcReq, rpcReq.Params[1], rpcReq.Params[0])
+}
+
+// processEthSign handles eth_sign, which has parameters [address, message]. As in Ethereum clients,
+// the message is prefixed using EIP-191 version 0x45 before signing (never signed as a raw hash).
+func (c *chain) processEthSign(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+	if len(rpcReq.Params) < 2 {
+		err := i18n.NewError(ctx, signermsgs.MsgInvalidParamCount, 2, len(rpcReq.Params))
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+	return c.signEIP191PersonalMessage(ctx, rpcReq, rpcReq.Params[0], rpcReq.Params[1])
+}
+
+func (c *chain) signEIP191PersonalMessage(ctx context.Context, rpcReq *rpcbackend.RPCRequest, fromParam, messageParam *fftypes.JSONAny) (*rpcbackend.RPCResponse, error) {
+	wallet, ok := c.s.wallet.(ethsigner.WalletEIP191)
+	if !ok {
+		err := i18n.NewError(ctx, signermsgs.MsgWalletNotSupported, rpcReq.Method)
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+
+	var from ethtypes.Address0xHex
+	if err := json.Unmarshal(fromParam.Bytes(), &from); err != nil {
+		err := i18n.NewError(ctx, signermsgs.MsgInvalidParam, 0, rpcReq.Method, err)
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+
+	if err := authIdentityFromContext(ctx).authorize(ctx, from); err != nil {
+		return authErrorResponse(err, rpcReq.ID), err
+	}
+
+	message, err := parseMessageParam(ctx, rpcReq.Method, messageParam)
+	if err != nil {
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+
+	if err := c.s.limits.checkRate(ctx, authIdentityFromContext(ctx), from); err != nil {
+		return limitErrorResponse(err, rpcReq.ID), err
+	}
+
+	result, err := wallet.SignEIP191PersonalMessage(ctx, from, message)
+	c.s.metrics.SigningCompleted(ctx, from.String(), metrics.SigningTypeMessage, err)
+	if err == nil {
+		err = c.recordSigning(ctx, &audit.Entry{
+			Type:        audit.EntryTypeMessage,
+			From:        &from,
+			MessageHash: result.Hash,
+		})
+	}
 	if err != nil {
 		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInternalError), err
 	}
+	return rpcResultResponse(rpcReq.ID, result.SignatureRSV), nil
+}
 
-	// Progress with the original request, now updated with a raw transaction fully signed
-	rpcReq.Method = "eth_sendRawTransaction"
-	rpcReq.Params = []*fftypes.JSONAny{fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, hexData))}
-	return s.backend.SyncRequest(ctx, rpcReq)
+// processEthSignTypedDataV4 handles eth_signTypedData_v4, which has parameters [address, typedData].
+// The typed data can be supplied as a JSON object, or as a string containing the JSON object.
+func (c *chain) processEthSignTypedDataV4(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+	if len(rpcReq.Params) < 2 {
+		err := i18n.NewError(ctx, signermsgs.MsgInvalidParamCount, 2, len(rpcReq.Params))
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+
+	wallet, ok := c.s.wallet.(ethsigner.WalletTypedData)
+	if !ok {
+		err := i18n.NewError(ctx, signermsgs.MsgWalletNotSupported, rpcReq.Method)
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+
+	var from ethtypes.Address0xHex
+	if err := json.Unmarshal(rpcReq.Params[0].Bytes(), &from); err != nil {
+		err := i18n.NewError(ctx, signermsgs.MsgInvalidParam, 0, rpcReq.Method, err)
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+
+	if err := authIdentityFromContext(ctx).authorize(ctx, from); err != nil {
+		return authErrorResponse(err, rpcReq.ID), err
+	}
+
+	typedData, err := parseTypedDataParam(ctx, rpcReq.Method, rpcReq.Params[1])
+	if err != nil {
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+
+	if err := c.checkAccountAvailable(ctx, &from); err != nil {
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+
+	if err := c.s.limits.checkRate(ctx, authIdentityFromContext(ctx), from); err != nil {
+		return limitErrorResponse(err, rpcReq.ID), err
+	}
+
+	result, err := wallet.SignTypedDataV4(ctx, from, typedData)
+	c.s.metrics.SigningCompleted(ctx, from.String(), metrics.SigningTypeTypedData, err)
+	if err == nil {
+		// The hash in the result is the encoded payload, which the signer hashes
+		err = c.recordSigning(ctx, &audit.Entry{
+			Type:          audit.EntryTypeTypedData,
+			From:          &from,
+			TypedDataHash: keccak256(result.Hash),
+		})
+	}
+	if err != nil {
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInternalError), err
+	}
+	return rpcResultResponse(rpcReq.ID, result.SignatureRSV), nil
+}
+
+func parseTypedDataParam(ctx context.Context, method string, param *fftypes.JSONAny) (*eip712.TypedData, error) {
+	b := param.Bytes()
+	var typedDataStr string
+	if err := json.Unmarshal(b, &typedDataStr); err == nil {
+		b = []byte(typedDataStr)
+	}
+	// We retain numbers as json.Number, so large integers in the domain and message do not lose precision
+	var typedData eip712.TypedData
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&typedData); err != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidParam, 1, method, err)
+	}
+	return &typedData, nil
+}
+
+// checkAccountAvailable ensures the address is one of the accounts returned by the wallet
+func (c *chain) checkAccountAvailable(ctx context.Context, addr *ethtypes.Address0xHex) error {
+	accounts, err := c.s.wallet.GetAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	for _, a := range accounts {
+		if *a == *addr {
+			return nil
+		}
+	}
+	return i18n.NewError(ctx, signermsgs.MsgWalletNotAvailable, addr)
+}
+
+// parseMessageParam accepts a 0x prefixed hex string (the standard), or falls back to using the
+// UTF-8 bytes of the string as the message (which many wallets accept)
+func parseMessageParam(ctx context.Context, method string, param *fftypes.JSONAny) ([]byte, error) {
+	var messageStr string
+	if err := json.Unmarshal(param.Bytes(), &messageStr); err != nil {
+		return nil, i18n.WrapError(ctx, err, signermsgs.MsgInvalidMessageData, method)
+	}
+	if strings.HasPrefix(messageStr, "0x") {
+		if b, err := hex.DecodeString(messageStr[2:]); err == nil {
+			return b, nil
+		}
+	}
+	return []byte(messageStr), nil
+}
+
+// transactionSigningType matches the choice of transaction type made by ethsigner.Transaction.Sign
+func transactionSigningType(txn *ethsigner.Transaction) string {
+	if txn.MaxPriorityFeePerGas.BigInt().Sign() > 0 || txn.MaxFeePerGas.BigInt().Sign() > 0 {
+		return metrics.SigningTypeEIP1559
+	}
+	return metrics.SigningTypeLegacy
+}
+
+// recordSigning writes an entry to the audit log, if enabled, before the signature is returned to the caller
+func (c *chain) recordSigning(ctx context.Context, entry *audit.Entry) error {
+	if c.s.audit == nil {
+		return nil
+	}
+	if id := authIdentityFromContext(ctx); id != nil {
+		entry.Identity = id.Name
+	}
+	entry.Chain = c.name
+	entry.ChainID = c.chainID
+	return c.s.audit.Record(ctx, entry)
+}
+
+func rpcResultResponse(id *fftypes.JSONAny, result interface{}) *rpcbackend.RPCResponse {
+	b, _ := json.Marshal(result)
+	return &rpcbackend.RPCResponse{
+		JSONRpc: "2.0",
+		ID:      id,
+		Result:  fftypes.JSONAnyPtrBytes(b),
+	}
+}
 
+func keccak256(b []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(b)
+	return hash.Sum(nil)
 }
@@ -17,14 +17,26 @@
 package rpcserver
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/internal/audit"
+	"github.com/hyperledger/firefly-signer/internal/metrics"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
 	"github.com/hyperledger/firefly-signer/mocks/ethsignermocks"
 	"github.com/hyperledger/firefly-signer/mocks/rpcbackendmocks"
+	"github.com/hyperledger/firefly-signer/pkg/eip712"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
 	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
 	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -207,3 +219,701 @@ func TestSignSignFail(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 
 }
+
+func TestSignSignFailReleasesNonce(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Sign", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop")).Once()
+	w.On("Sign", mock.Anything, mock.MatchedBy(func(txn *ethsigner.Transaction) bool {
+		return txn.Nonce.BigInt().Int64() == 10
+	}), mock.Anything).Return([]byte{0x01}, nil).Once()
+
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	mockPendingNonce(bm, 10).Once()
+	bm.On("SyncRequest", mock.Anything, mock.Anything).Return(&rpcbackend.RPCResponse{}, nil)
+
+	req := func() *rpcbackend.RPCRequest {
+		return &rpcbackend.RPCRequest{
+			ID:     fftypes.JSONAnyPtr("1"),
+			Method: "eth_sendTransaction",
+			Params: []*fftypes.JSONAny{
+				fftypes.JSONAnyPtr(`{"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248"}`),
+			},
+		}
+	}
+	_, err := s.processRPC(s.ctx, req())
+	assert.Regexp(t, "pop", err)
+
+	// The nonce is re-used from the cache
+	_, err = s.processRPC(s.ctx, req())
+	assert.NoError(t, err)
+
+	bm.AssertExpectations(t)
+	w.AssertExpectations(t)
+}
+
+func TestSendTransactionNonceTooLowResetsNonce(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Sign", mock.Anything, mock.MatchedBy(func(txn *ethsigner.Transaction) bool {
+		return txn.Nonce.BigInt().Int64() == 10
+	}), mock.Anything).Return([]byte{0x01}, nil).Once()
+	w.On("Sign", mock.Anything, mock.MatchedBy(func(txn *ethsigner.Transaction) bool {
+		return txn.Nonce.BigInt().Int64() == 15
+	}), mock.Anything).Return([]byte{0x02}, nil).Once()
+
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	mockPendingNonce(bm, 10).Once()
+	mockPendingNonce(bm, 15).Once()
+	bm.On("SyncRequest", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("nonce too low")).Once()
+	bm.On("SyncRequest", mock.Anything, mock.Anything).Return(&rpcbackend.RPCResponse{}, nil).Once()
+
+	req := func() *rpcbackend.RPCRequest {
+		return &rpcbackend.RPCRequest{
+			ID:     fftypes.JSONAnyPtr("1"),
+			Method: "eth_sendTransaction",
+			Params: []*fftypes.JSONAny{
+				fftypes.JSONAnyPtr(`{"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248"}`),
+			},
+		}
+	}
+	_, err := s.processRPC(s.ctx, req())
+	assert.Regexp(t, "nonce too low", err)
+
+	_, err = s.processRPC(s.ctx, req())
+	assert.NoError(t, err)
+
+	bm.AssertExpectations(t)
+	w.AssertExpectations(t)
+}
+
+func TestSignTransactionOK(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+	s.chainID = 12345
+
+	kp, err := secp256k1.GenerateSecp256k1KeyPair()
+	assert.NoError(t, err)
+
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Sign", mock.Anything, mock.Anything, int64(12345)).Return(func(ctx context.Context, txn *ethsigner.Transaction, chainID int64) ([]byte, error) {
+		return txn.Sign(kp, chainID)
+	})
+
+	rpcRes, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTransaction",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(fmt.Sprintf(`{
+				"from": "%s",
+				"to": "0xfb075bb99f2aa4c49955bf703509a227d7a12248",
+				"nonce": "0x123",
+				"gas": "0x5208",
+				"maxFeePerGas": "0x3b9aca00",
+				"value": "0x64"
+			}`, kp.Address)),
+		},
+	})
+	assert.NoError(t, err)
+
+	var res SignTransactionResult
+	err = json.Unmarshal(rpcRes.Result.Bytes(), &res)
+	assert.NoError(t, err)
+
+	from, decoded, err := ethsigner.RecoverRawTransaction(s.ctx, res.Raw, 12345)
+	assert.NoError(t, err)
+	assert.Equal(t, kp.Address, *from)
+	assert.Equal(t, int64(0x123), decoded.Nonce.Int64())
+	assert.Equal(t, int64(0x123), res.Tx.Nonce.Int64())
+	assert.Equal(t, int64(0x64), res.Tx.Value.Int64())
+	assert.Equal(t, "0xfb075bb99f2aa4c49955bf703509a227d7a12248", res.Tx.To.String())
+	assert.JSONEq(t, fmt.Sprintf(`"%s"`, kp.Address), string(res.Tx.From))
+	assert.Len(t, res.Tx.Hash, 32)
+
+}
+
+func TestSignTransactionGetNonceFail(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_getTransactionCount", mock.Anything, "pending").Return(&rpcbackend.RPCError{Message: "pop"})
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTransaction",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`{
+				"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248"
+			}`),
+		},
+	})
+	assert.Regexp(t, "pop", err)
+
+}
+
+func TestSignTransactionBadSignedPayload(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Sign", mock.Anything, mock.Anything, mock.Anything).Return([]byte{0xff}, nil)
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTransaction",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`{
+				"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248",
+				"nonce": "0x123"
+			}`),
+		},
+	})
+	assert.Regexp(t, "FF22083", err)
+
+}
+
+func TestPersonalSignOK(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	w := &ethsignermocks.WalletEIP191{}
+	s.wallet = w
+	w.On("SignEIP191PersonalMessage", mock.Anything, *ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248"), []byte("Hello World")).
+		Return(&ethsigner.EIP191Result{SignatureRSV: []byte{0x01, 0x02}}, nil)
+
+	rpcRes, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "personal_sign",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"0x48656c6c6f20576f726c64"`),
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `"0x0102"`, rpcRes.Result.String())
+
+}
+
+func TestEthSignUTF8MessageOK(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	w := &ethsignermocks.WalletEIP191{}
+	s.wallet = w
+	w.On("SignEIP191PersonalMessage", mock.Anything, *ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248"), []byte("Hello World")).
+		Return(&ethsigner.EIP191Result{SignatureRSV: []byte{0x01, 0x02}}, nil)
+
+	rpcRes, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_sign",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+			fftypes.JSONAnyPtr(`"Hello World"`),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `"0x0102"`, rpcRes.Result.String())
+
+}
+
+func TestPersonalSignMissingParams(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "personal_sign",
+	})
+	assert.Regexp(t, "FF22019", err)
+
+	_, err = s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_sign",
+	})
+	assert.Regexp(t, "FF22019", err)
+
+}
+
+func TestPersonalSignWalletNotSupported(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "personal_sign",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"0x48656c6c6f20576f726c64"`),
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+		},
+	})
+	assert.Regexp(t, "FF22094.*personal_sign", err)
+
+}
+
+func TestPersonalSignBadFrom(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+	s.wallet = &ethsignermocks.WalletEIP191{}
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "personal_sign",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"0x48656c6c6f20576f726c64"`),
+			fftypes.JSONAnyPtr(`"bad address"`),
+		},
+	})
+	assert.Regexp(t, "FF22011", err)
+
+}
+
+func TestPersonalSignBadMessage(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+	s.wallet = &ethsignermocks.WalletEIP191{}
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "personal_sign",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`{}`),
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+		},
+	})
+	assert.Regexp(t, "FF22095", err)
+
+}
+
+func TestPersonalSignFail(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	w := &ethsignermocks.WalletEIP191{}
+	s.wallet = w
+	w.On("SignEIP191PersonalMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "personal_sign",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"0x48656c6c6f20576f726c64"`),
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+		},
+	})
+	assert.Regexp(t, "pop", err)
+
+}
+
+const sampleTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "chainId", "type": "uint256"}
+		],
+		"Mail": [
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {"name": "test-app", "chainId": 123456789012345678901234567890},
+	"message": {"contents": "hello"}
+}`
+
+func newTestTypedDataServer(t *testing.T) (*rpcServer, *ethsignermocks.WalletTypedData, func()) {
+	_, s, done := newTestServer(t)
+	w := &ethsignermocks.WalletTypedData{}
+	s.wallet = w
+	return s, w, done
+}
+
+func TestSignTypedDataV4ObjectOK(t *testing.T) {
+
+	s, w, done := newTestTypedDataServer(t)
+	defer done()
+
+	kp, err := secp256k1.GenerateSecp256k1KeyPair()
+	assert.NoError(t, err)
+
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{&kp.Address}, nil)
+	w.On("SignTypedDataV4", mock.Anything, kp.Address, mock.MatchedBy(func(td *eip712.TypedData) bool {
+		return td.PrimaryType == "Mail" && td.Domain["chainId"] == json.Number("123456789012345678901234567890")
+	})).Return(func(ctx context.Context, from ethtypes.Address0xHex, td *eip712.TypedData) (*ethsigner.EIP712Result, error) {
+		return ethsigner.SignTypedDataV4(ctx, kp, td)
+	})
+
+	rpcRes, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTypedData_v4",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, kp.Address)),
+			fftypes.JSONAnyPtr(sampleTypedData),
+		},
+	})
+	assert.NoError(t, err)
+
+	var sigRSV ethtypes.HexBytes0xPrefix
+	err = json.Unmarshal(rpcRes.Result.Bytes(), &sigRSV)
+	assert.NoError(t, err)
+	assert.Len(t, sigRSV, 65)
+
+}
+
+func TestSignTypedDataStringOK(t *testing.T) {
+
+	s, w, done := newTestTypedDataServer(t)
+	defer done()
+
+	from := ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248")
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{from}, nil)
+	w.On("SignTypedDataV4", mock.Anything, *from, mock.MatchedBy(func(td *eip712.TypedData) bool {
+		return td.PrimaryType == "Mail"
+	})).Return(&ethsigner.EIP712Result{SignatureRSV: []byte{0x01, 0x02}}, nil)
+
+	typedDataStr, err := json.Marshal(sampleTypedData)
+	assert.NoError(t, err)
+	rpcRes, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTypedData",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+			fftypes.JSONAnyPtrBytes(typedDataStr),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `"0x0102"`, rpcRes.Result.String())
+
+}
+
+func TestSignTypedDataMissingParams(t *testing.T) {
+
+	s, _, done := newTestTypedDataServer(t)
+	defer done()
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTypedData_v4",
+	})
+	assert.Regexp(t, "FF22019", err)
+
+}
+
+func TestSignTypedDataWalletNotSupported(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTypedData_v4",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+			fftypes.JSONAnyPtr(sampleTypedData),
+		},
+	})
+	assert.Regexp(t, "FF22094", err)
+
+}
+
+func TestSignTypedDataBadFrom(t *testing.T) {
+
+	s, _, done := newTestTypedDataServer(t)
+	defer done()
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTypedData_v4",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"bad address"`),
+			fftypes.JSONAnyPtr(sampleTypedData),
+		},
+	})
+	assert.Regexp(t, "FF22011", err)
+
+}
+
+func TestSignTypedDataBadPayload(t *testing.T) {
+
+	s, _, done := newTestTypedDataServer(t)
+	defer done()
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTypedData_v4",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+			fftypes.JSONAnyPtr(`"not json"`),
+		},
+	})
+	assert.Regexp(t, "FF22011", err)
+
+}
+
+func TestSignTypedDataAccountNotAvailable(t *testing.T) {
+
+	s, w, done := newTestTypedDataServer(t)
+	defer done()
+
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{
+		ethtypes.MustNewAddress("0x497eedc4299dea2f2a364be10025d0ad0f702de3"),
+	}, nil)
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTypedData_v4",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+			fftypes.JSONAnyPtr(sampleTypedData),
+		},
+	})
+	assert.Regexp(t, "FF22014", err)
+
+}
+
+func TestSignTypedDataGetAccountsFail(t *testing.T) {
+
+	s, w, done := newTestTypedDataServer(t)
+	defer done()
+
+	w.On("GetAccounts", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTypedData_v4",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+			fftypes.JSONAnyPtr(sampleTypedData),
+		},
+	})
+	assert.Regexp(t, "pop", err)
+
+}
+
+func TestSignTypedDataSignFail(t *testing.T) {
+
+	s, w, done := newTestTypedDataServer(t)
+	defer done()
+
+	from := ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248")
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{from}, nil)
+	w.On("SignTypedDataV4", mock.Anything, *from, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTypedData_v4",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+			fftypes.JSONAnyPtr(sampleTypedData),
+		},
+	})
+	assert.Regexp(t, "pop", err)
+
+}
+
+func newTestAuditServer(t *testing.T) (*rpcServer, string, func()) {
+	auditFile := filepath.Join(t.TempDir(), "audit.jsonl")
+	_, s, done := newTestServer(t, func() {
+		config.Set(signerconfig.AuditEnabled, true)
+		signerconfig.AuditConfig.Set(audit.ConfigFile, auditFile)
+	})
+	return s, auditFile, done
+}
+
+func readTestAuditEntries(t *testing.T, auditFile string) []*audit.Entry {
+	result, err := audit.Verify(context.Background(), auditFile, false)
+	assert.NoError(t, err)
+	b, err := os.ReadFile(auditFile)
+	assert.NoError(t, err)
+	var entries []*audit.Entry
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		var entry audit.Entry
+		err := json.Unmarshal([]byte(line), &entry)
+		assert.NoError(t, err)
+		entries = append(entries, &entry)
+	}
+	assert.Len(t, entries, result.Entries)
+	return entries
+}
+
+func TestSignTransactionAudited(t *testing.T) {
+
+	s, auditFile, done := newTestAuditServer(t)
+	defer done()
+	s.chainID = 12345
+
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Sign", mock.Anything, mock.Anything, int64(12345)).Return([]byte{0x01}, nil)
+
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	bm.On("SyncRequest", mock.Anything, mock.Anything).Return(&rpcbackend.RPCResponse{}, nil)
+
+	_, err := s.processRPC(withAuthIdentity(s.ctx, &authIdentity{
+		Name:     "team1",
+		accounts: map[ethtypes.Address0xHex]bool{*ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248"): true},
+	}), &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_sendTransaction",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`{
+				"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248",
+				"to": "0x497eedc4299dea2f2a364be10025d0ad0f702de3",
+				"nonce": "0x123",
+				"value": "0x64",
+				"data": "0xa9059cbb0000"
+			}`),
+		},
+	})
+	assert.NoError(t, err)
+
+	entries := readTestAuditEntries(t, auditFile)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, audit.EntryTypeTransaction, entries[0].Type)
+	assert.Equal(t, "team1", entries[0].Identity)
+	assert.Equal(t, defaultChainName, entries[0].Chain)
+	assert.Equal(t, int64(12345), entries[0].ChainID)
+	assert.Equal(t, "0xfb075bb99f2aa4c49955bf703509a227d7a12248", entries[0].From.String())
+	assert.Equal(t, ethtypes.HexBytes0xPrefix(keccak256([]byte{0x01})), entries[0].TxHash)
+	assert.Equal(t, "0x497eedc4299dea2f2a364be10025d0ad0f702de3", entries[0].To.String())
+	assert.Equal(t, int64(0x64), entries[0].Value.Int64())
+	assert.Equal(t, "0xa9059cbb", entries[0].Selector.String())
+
+}
+
+func TestSignTransactionAuditFailReleasesNonce(t *testing.T) {
+
+	s, _, done := newTestAuditServer(t)
+	defer done()
+	s.audit.Close()
+
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Sign", mock.Anything, mock.Anything, mock.Anything).Return([]byte{0x01}, nil)
+
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	mockPendingNonce(bm, 10).Once()
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_sendTransaction",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`{"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248"}`),
+		},
+	})
+	assert.Regexp(t, "FF22134", err)
+
+	// The transaction was not submitted, and the nonce is available for the next one
+	nonce, err := s.nonces.assignNonce(s.ctx, *ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), nonce.nonce)
+	nonce.complete(s.ctx, nonceReleased)
+	bm.AssertExpectations(t)
+
+}
+
+func TestPersonalSignAudited(t *testing.T) {
+
+	s, auditFile, done := newTestAuditServer(t)
+	defer done()
+
+	w := &ethsignermocks.WalletEIP191{}
+	s.wallet = w
+	w.On("SignEIP191PersonalMessage", mock.Anything, *ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248"), []byte("Hello World")).
+		Return(&ethsigner.EIP191Result{Hash: ethsigner.EIP191PersonalSignHash([]byte("Hello World")), SignatureRSV: []byte{0x01, 0x02}}, nil)
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "personal_sign",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"Hello World"`),
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+		},
+	})
+	assert.NoError(t, err)
+
+	entries := readTestAuditEntries(t, auditFile)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, audit.EntryTypeMessage, entries[0].Type)
+	assert.Empty(t, entries[0].Identity)
+	assert.Equal(t, ethsigner.EIP191PersonalSignHash([]byte("Hello World")), entries[0].MessageHash)
+
+}
+
+func TestPersonalSignAuditFail(t *testing.T) {
+
+	s, _, done := newTestAuditServer(t)
+	defer done()
+	s.audit.Close()
+
+	w := &ethsignermocks.WalletEIP191{}
+	s.wallet = w
+	w.On("SignEIP191PersonalMessage", mock.Anything, mock.Anything, mock.Anything).
+		Return(&ethsigner.EIP191Result{SignatureRSV: []byte{0x01, 0x02}}, nil)
+
+	rpcRes, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "personal_sign",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"Hello World"`),
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+		},
+	})
+	assert.Regexp(t, "FF22134", err)
+	assert.Nil(t, rpcRes.Result)
+
+}
+
+func TestSignTypedDataAudited(t *testing.T) {
+
+	s, auditFile, done := newTestAuditServer(t)
+	defer done()
+	w := &ethsignermocks.WalletTypedData{}
+	s.wallet = w
+
+	kp, err := secp256k1.GenerateSecp256k1KeyPair()
+	assert.NoError(t, err)
+
+	var signed *ethsigner.EIP712Result
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{&kp.Address}, nil)
+	w.On("SignTypedDataV4", mock.Anything, kp.Address, mock.Anything).Return(func(ctx context.Context, from ethtypes.Address0xHex, td *eip712.TypedData) (*ethsigner.EIP712Result, error) {
+		signed, err = ethsigner.SignTypedDataV4(ctx, kp, td)
+		return signed, err
+	})
+
+	_, err = s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_signTypedData_v4",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, kp.Address)),
+			fftypes.JSONAnyPtr(sampleTypedData),
+		},
+	})
+	assert.NoError(t, err)
+
+	entries := readTestAuditEntries(t, auditFile)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, audit.EntryTypeTypedData, entries[0].Type)
+	assert.Equal(t, kp.Address, *entries[0].From)
+	assert.Equal(t, ethtypes.HexBytes0xPrefix(keccak256(signed.Hash)), entries[0].TypedDataHash)
+
+}
+
+func TestTransactionSigningType(t *testing.T) {
+	assert.Equal(t, metrics.SigningTypeLegacy, transactionSigningType(&ethsigner.Transaction{GasPrice: ethtypes.NewHexInteger64(100)}))
+	assert.Equal(t, metrics.SigningTypeEIP1559, transactionSigningType(&ethsigner.Transaction{MaxFeePerGas: ethtypes.NewHexInteger64(100)}))
+	assert.Equal(t, metrics.SigningTypeEIP1559, transactionSigningType(&ethsigner.Transaction{MaxPriorityFeePerGas: ethtypes.NewHexInteger64(1)}))
+}
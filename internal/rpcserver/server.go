@@ -1,4 +1,4 @@
-// Copyright © 2023 Kaleido, Inc.
+// Copyright © 2026 Kaleido, Inc.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -18,18 +18,25 @@ package rpcserver
 
 import (
 	"context"
+	"net"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/httpserver"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly-signer/internal/audit"
+	"github.com/hyperledger/firefly-signer/internal/metrics"
 	"github.com/hyperledger/firefly-signer/internal/signerconfig"
 	"github.com/hyperledger/firefly-signer/internal/signermsgs"
 	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
-	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
-	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
 )
 
 type Server interface {
@@ -38,44 +45,198 @@ type Server interface {
 	WaitStop() error
 }
 
+// defaultChainName is used for the chain configured at the root of the config, served on the root path
+const defaultChainName = "default"
+
 func NewServer(ctx context.Context, wallet ethsigner.Wallet) (ss Server, err error) {
 
-	httpClient, err := ffresty.New(ctx, signerconfig.BackendConfig)
+	s := &rpcServer{
+		apiServerDone:     make(chan error),
+		metricsServerDone: make(chan error),
+		adminServerDone:   make(chan error),
+		wallet:            wallet,
+		chains:            make(map[string]*chain),
+		healthTimeout:     config.GetDuration(signerconfig.HealthTimeout),
+	}
+	if config.GetBool(signerconfig.MetricsEnabled) {
+		s.metrics = metrics.Get()
+	}
+	s.policy, err = newPolicyEngine(ctx, wallet)
 	if err != nil {
 		return nil, err
 	}
-	s := &rpcServer{
-		backend:       rpcbackend.NewRPCClient(httpClient),
-		apiServerDone: make(chan error),
-		wallet:        wallet,
-		chainID:       config.GetInt64(signerconfig.BackendChainID),
+	s.auth, err = newAuthenticator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.limits, err = newLimiter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.simulation, err = newSimulator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.approvals, err = newApprovalQueue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.rawTransactions = newRawTransactionValidator()
+	s.tracker, err = newTxTracker(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	if config.GetBool(signerconfig.AuditEnabled) {
+		s.audit, err = audit.NewLogger(ctx, audit.ReadConfig(signerconfig.AuditConfig))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if config.GetBool(signerconfig.WebSocketEnabled) {
+		s.wsUpgrader = &websocket.Upgrader{
+			ReadBufferSize:  int(config.GetByteSize(signerconfig.WebSocketReadBufferSize)),
+			WriteBufferSize: int(config.GetByteSize(signerconfig.WebSocketWriteBufferSize)),
+		}
+		s.wsWriteTimeout = config.GetDuration(signerconfig.WebSocketWriteTimeout)
+		s.wsConnections = make(map[string]*wsConnection)
 	}
 	s.ctx, s.cancelCtx = context.WithCancel(ctx)
 
+	// Each named chain is served on its own path, and the default chain is optional when there are named chains
+	chainCount := signerconfig.ChainsConfig.ArraySize()
+	for i := 0; i < chainCount; i++ {
+		chainConf := signerconfig.ChainsConfig.ArrayEntry(i)
+		name := chainConf.GetString(signerconfig.ChainConfName)
+		if err := fftypes.ValidateFFNameField(ctx, name, signerconfig.ChainConfName); err != nil {
+			return nil, i18n.WrapError(ctx, err, signermsgs.MsgInvalidChainName, i)
+		}
+		if _, exists := s.chains[name]; exists {
+			return nil, i18n.NewError(ctx, signermsgs.MsgDuplicateChainName, name)
+		}
+		s.chains[name], err = newChain(ctx, s, name,
+			chainConf.SubSection("backend"), chainConf.SubSection("nonceManager"), chainConf.SubSection("gas"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if chainCount == 0 || defaultBackendConfigured() {
+		s.chain, err = newChain(ctx, s, defaultChainName,
+			signerconfig.BackendConfig, signerconfig.NonceManagerConfig, signerconfig.GasConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	s.apiServer, err = httpserver.NewHTTPServer(ctx, "server", s.router(), s.apiServerDone, signerconfig.ServerConfig, signerconfig.CorsConfig)
 	if err != nil {
 		return nil, err
 	}
+	if s.metrics != nil {
+		s.metricsServer, err = httpserver.NewHTTPServer(ctx, "metrics", s.metricsRouter(), s.metricsServerDone, signerconfig.MetricsConfig, signerconfig.CorsConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if config.GetBool(signerconfig.AdminEnabled) {
+		// Without authentication anyone who can reach the admin API can approve transactions,
+		// so it is only served to local clients
+		adminAddress := signerconfig.AdminConfig.GetString(httpserver.HTTPConfAddress)
+		if s.auth == nil && !isLoopbackAddress(adminAddress) {
+			return nil, i18n.NewError(ctx, signermsgs.MsgAdminRequiresAuth, adminAddress)
+		}
+		s.adminServer, err = httpserver.NewHTTPServer(ctx, "admin", s.adminRouter(), s.adminServerDone, signerconfig.AdminConfig, signerconfig.CorsConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return s, err
 }
 
+func isLoopbackAddress(address string) bool {
+	if address == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(address)
+	return ip != nil && ip.IsLoopback()
+}
+
+func defaultBackendConfigured() bool {
+	return signerconfig.BackendConfig.GetString(ffresty.HTTPConfigURL) != "" ||
+		signerconfig.BackendConfig.GetString(wsclient.WSConfigURL) != "" ||
+		len(signerconfig.BackendConfig.GetStringSlice(signerconfig.BackendConfURLs)) > 0
+}
+
 type rpcServer struct {
+	*chain // the default chain, served on the root path - nil if only named chains are configured
+
 	ctx       context.Context
 	cancelCtx func()
-	backend   rpcbackend.Backend
 
 	started       bool
+	ready         atomic.Bool // once the wallet is initialized, and the chain IDs are known
 	apiServer     httpserver.HTTPServer
 	apiServerDone chan error
 
-	chainID int64
-	wallet  ethsigner.Wallet
+	metrics           *metrics.Manager // nil if metrics are disabled
+	metricsServer     httpserver.HTTPServer
+	metricsServerDone chan error
+
+	adminServer     httpserver.HTTPServer // nil if the admin API is disabled
+	adminServerDone chan error
+
+	wallet          ethsigner.Wallet
+	policy          *policyEngine
+	auth            *authenticator           // nil if authentication is disabled
+	limits          *limiter                 // nil if no rate limits or quotas are configured
+	simulation      *simulator               // nil if transactions are not simulated before signing
+	approvals       *approvalQueue           // nil if transactions are not held for approval
+	rawTransactions *rawTransactionValidator // nil if eth_sendRawTransaction is passed through unchanged
+	tracker         *txTracker               // nil if submitted transactions are not tracked
+	audit           *audit.Logger            // nil if the audit log is disabled
+	chains          map[string]*chain
+
+	healthTimeout   time.Duration
+	walletLastError lastError
+
+	wsUpgrader     *websocket.Upgrader
+	wsWriteTimeout time.Duration
+	wsMux          sync.Mutex
+	wsConnections  map[string]*wsConnection
 }
 
 func (s *rpcServer) router() *mux.Router {
 	mux := mux.NewRouter()
-	mux.Path("/").Methods(http.MethodPost).Handler(http.HandlerFunc(s.rpcHandler))
+	if s.metrics != nil {
+		mux.Use(s.metrics.HTTPMiddleware())
+	}
+	// The probes do not require authentication, or the server to be ready
+	mux.Path(livenessPath).Methods(http.MethodGet).HandlerFunc(s.livenessHandler)
+	mux.Path(readinessPath).Methods(http.MethodGet).HandlerFunc(s.readinessHandler)
+	api := mux.PathPrefix("/").Subrouter()
+	if s.auth != nil {
+		api.Use(s.authMiddleware)
+	}
+	api.Path(statusPath).Methods(http.MethodGet).HandlerFunc(s.statusHandler)
+	if s.chain != nil {
+		s.addChainRoutes(api, "/", s.chain)
+	}
+	for name, c := range s.chains {
+		s.addChainRoutes(api, "/chains/"+name, c)
+	}
+	return mux
+}
+
+func (s *rpcServer) addChainRoutes(mux *mux.Router, path string, c *chain) {
+	mux.Path(path).Methods(http.MethodPost).Handler(s.whenReady(c.rpcHandler))
+	if s.wsUpgrader != nil {
+		mux.Path(path).Methods(http.MethodGet).Handler(s.whenReady(c.wsHandler))
+	}
+}
+
+func (s *rpcServer) metricsRouter() *mux.Router {
+	mux := mux.NewRouter()
+	mux.Path(config.GetString(signerconfig.MetricsPath)).Methods(http.MethodGet).Handler(s.metrics.HTTPHandler())
 	return mux
 }
 
@@ -83,33 +244,77 @@ func (s *rpcServer) runAPIServer() {
 	s.apiServer.ServeHTTP(s.ctx)
 }
 
+func (s *rpcServer) runMetricsServer() {
+	s.metricsServer.ServeHTTP(s.ctx)
+}
+
+func (s *rpcServer) runAdminServer() {
+	s.adminServer.ServeHTTP(s.ctx)
+}
+
 func (s *rpcServer) Start() error {
-	if s.chainID < 0 {
-		var chainID ethtypes.HexInteger
-		rpcErr := s.backend.CallRPC(s.ctx, &chainID, "net_version")
-		if rpcErr != nil {
-			return i18n.WrapError(s.ctx, rpcErr.Error(), signermsgs.MsgQueryChainID)
+	// Serve the probes while starting, and reject JSON/RPC requests until we are ready
+	go s.runAPIServer()
+	if s.metricsServer != nil {
+		go s.runMetricsServer()
+	}
+	if s.adminServer != nil {
+		go s.runAdminServer()
+	}
+	s.started = true
+
+	if s.chain != nil {
+		if err := s.chain.start(s.ctx); err != nil {
+			return err
+		}
+	}
+	for name, c := range s.chains {
+		if err := c.start(s.ctx); err != nil {
+			return i18n.WrapError(s.ctx, err, signermsgs.MsgChainStartFailed, name)
 		}
-		s.chainID = chainID.BigInt().Int64()
+	}
+
+	if s.tracker != nil {
+		s.tracker.start(s.ctx)
 	}
 
 	err := s.wallet.Initialize(s.ctx)
 	if err != nil {
 		return err
 	}
-	go s.runAPIServer()
-	s.started = true
+
+	s.ready.Store(true)
 	return nil
 }
 
 func (s *rpcServer) Stop() {
 	s.cancelCtx()
+	if s.chain != nil {
+		s.chain.stop()
+	}
+	for _, c := range s.chains {
+		c.stop()
+	}
+	s.audit.Close()
 }
 
 func (s *rpcServer) WaitStop() (err error) {
 	if s.started {
 		s.started = false
 		err = <-s.apiServerDone
+		if s.metricsServer != nil {
+			if metricsErr := <-s.metricsServerDone; err == nil {
+				err = metricsErr
+			}
+		}
+		if s.adminServer != nil {
+			if adminErr := <-s.adminServerDone; err == nil {
+				err = adminErr
+			}
+		}
+		if s.tracker != nil {
+			s.tracker.waitStop()
+		}
 	}
 	return err
 }
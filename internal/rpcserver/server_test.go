@@ -23,8 +23,13 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
 	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/httpserver"
+	"github.com/hyperledger/firefly-common/pkg/wsclient"
 	"github.com/hyperledger/firefly-signer/internal/signerconfig"
 	"github.com/hyperledger/firefly-signer/mocks/ethsignermocks"
 	"github.com/hyperledger/firefly-signer/mocks/rpcbackendmocks"
@@ -34,7 +39,7 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-func newTestServer(t *testing.T) (string, *rpcServer, func()) {
+func newTestServer(t *testing.T, conf ...func()) (string, *rpcServer, func()) {
 	signerconfig.Reset()
 
 	ln, err := net.Listen("tcp", "127.0.0.1:0")
@@ -43,6 +48,11 @@ func newTestServer(t *testing.T) (string, *rpcServer, func()) {
 	ln.Close()
 	signerconfig.ServerConfig.Set(httpserver.HTTPConfPort, serverPort)
 	signerconfig.ServerConfig.Set(httpserver.HTTPConfAddress, "127.0.0.1")
+	// Gas population is tested separately, so most tests do not need to mock the fee queries
+	signerconfig.GasConfig.Set(signerconfig.GasConfAutoPopulate, false)
+	for _, fn := range conf {
+		fn()
+	}
 
 	w := &ethsignermocks.Wallet{}
 
@@ -50,6 +60,10 @@ func newTestServer(t *testing.T) (string, *rpcServer, func()) {
 	assert.NoError(t, err)
 	s := ss.(*rpcServer)
 	s.backend = &rpcbackendmocks.Backend{}
+	s.nonces.backend = s.backend
+	s.gas.backend = s.backend
+	// Most tests use the handlers without starting the server
+	s.ready.Store(true)
 
 	return fmt.Sprintf("http://127.0.0.1:%s", serverPort),
 		s,
@@ -74,6 +88,173 @@ func TestBadTLSConfig(t *testing.T) {
 	assert.Regexp(t, "FF00153", err)
 }
 
+func TestBadAuditConfig(t *testing.T) {
+	signerconfig.Reset()
+	config.Set(signerconfig.AuditEnabled, true)
+
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22132", err)
+}
+
+func TestBadBackendTransport(t *testing.T) {
+	signerconfig.Reset()
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfTransport, "carrier-pigeon")
+
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22108.*carrier-pigeon", err)
+}
+
+func TestBadWebSocketTransportTLSConfig(t *testing.T) {
+	signerconfig.Reset()
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfTransport, "websocket")
+	tlsConf := signerconfig.BackendConfig.SubSection("tls")
+	tlsConf.Set(fftls.HTTPConfTLSEnabled, true)
+	tlsConf.Set(fftls.HTTPConfTLSCAFile, "!!!!!badness")
+
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestWebSocketTransport(t *testing.T) {
+	signerconfig.Reset()
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfTransport, "websocket")
+	signerconfig.GasConfig.Set(signerconfig.GasConfAutoPopulate, false)
+	config.Set(signerconfig.WebSocketEnabled, true)
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfPort, 0)
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfAddress, "127.0.0.1")
+
+	toBackend, fromBackend, backendURL, closeBackend := wsclient.NewTestWSServer(nil)
+	defer closeBackend()
+	signerconfig.BackendConfig.Set(ffresty.HTTPConfigURL, backendURL)
+
+	w := &ethsignermocks.Wallet{}
+	w.On("Initialize", mock.Anything).Return(nil)
+	ss, err := NewServer(context.Background(), w)
+	assert.NoError(t, err)
+	s := ss.(*rpcServer)
+	defer func() {
+		s.Stop()
+		_ = s.WaitStop()
+	}()
+
+	// The same connection is used for requests and subscriptions
+	assert.Equal(t, s.wsBackend, s.backend)
+
+	go func() {
+		msg := <-toBackend
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":"000000001","method":"net_version"}`, msg)
+		fromBackend <- `{"jsonrpc":"2.0","id":"000000001","result":"0x3039"}`
+
+		msg = <-toBackend
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":"000000002","method":"eth_blockNumber"}`, msg)
+		fromBackend <- `{"jsonrpc":"2.0","id":"000000002","result":"0x1"}`
+	}()
+
+	err = s.Start()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12345), s.chainID)
+
+	rpcRes, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_blockNumber",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `"0x1"`, rpcRes.Result.String())
+}
+
+func TestMultiEndpointBackend(t *testing.T) {
+	signerconfig.Reset()
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfURLs, []string{"http://node1:8545", "http://node2:8545"})
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfStrategy, "roundRobin")
+
+	ss, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.NoError(t, err)
+	s := ss.(*rpcServer)
+	defer s.Stop()
+
+	assert.Equal(t, s.multiBackend, s.backend)
+	status := s.multiBackend.EndpointStatus()
+	assert.Len(t, status, 2)
+	assert.Equal(t, "http://node2:8545", status[1].URL)
+}
+
+func TestMultiEndpointBackendWebSocketSubscriptions(t *testing.T) {
+	signerconfig.Reset()
+	config.Set(signerconfig.WebSocketEnabled, true)
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfPort, 0)
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfAddress, "127.0.0.1")
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfChainID, 12345)
+
+	// The subscription connection is made to the first of the URLs
+	_, _, backendURL, closeBackend := wsclient.NewTestWSServer(nil)
+	defer closeBackend()
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfURLs, []string{backendURL, "http://127.0.0.1:1"})
+
+	w := &ethsignermocks.Wallet{}
+	w.On("Initialize", mock.Anything).Return(nil)
+	ss, err := NewServer(context.Background(), w)
+	assert.NoError(t, err)
+	s := ss.(*rpcServer)
+	defer func() {
+		s.Stop()
+		_ = s.WaitStop()
+	}()
+
+	assert.Equal(t, s.multiBackend, s.backend)
+	assert.NotNil(t, s.wsBackend)
+	err = s.Start()
+	assert.NoError(t, err)
+}
+
+func TestMultiEndpointBackendWebSocketURL(t *testing.T) {
+	signerconfig.Reset()
+	config.Set(signerconfig.WebSocketEnabled, true)
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfPort, 0)
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfAddress, "127.0.0.1")
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfChainID, 12345)
+
+	// An explicit WebSocket URL takes precedence over the endpoints
+	_, _, backendURL, closeBackend := wsclient.NewTestWSServer(nil)
+	defer closeBackend()
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfURLs, []string{"http://127.0.0.1:1"})
+	signerconfig.BackendConfig.Set(wsclient.WSConfigURL, backendURL)
+
+	w := &ethsignermocks.Wallet{}
+	w.On("Initialize", mock.Anything).Return(nil)
+	ss, err := NewServer(context.Background(), w)
+	assert.NoError(t, err)
+	s := ss.(*rpcServer)
+	defer func() {
+		s.Stop()
+		_ = s.WaitStop()
+	}()
+
+	err = s.Start()
+	assert.NoError(t, err)
+}
+
+func TestMultiEndpointBackendBadConfig(t *testing.T) {
+	signerconfig.Reset()
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfURLs, []string{"http://node1:8545"})
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfStrategy, "random")
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22110", err)
+
+	signerconfig.Reset()
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfURLs, []string{"http://node1:8545"})
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfTransport, "websocket")
+	_, err = NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22112", err)
+
+	signerconfig.Reset()
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfURLs, []string{"http://node1:8545"})
+	tlsConf := signerconfig.BackendConfig.SubSection("tls")
+	tlsConf.Set(fftls.HTTPConfTLSEnabled, true)
+	tlsConf.Set(fftls.HTTPConfTLSCAFile, "!!!!!badness")
+	_, err = NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF00153", err)
+}
+
 func TestStartStop(t *testing.T) {
 
 	_, s, done := newTestServer(t)
@@ -136,3 +317,54 @@ func TestBadConfig(t *testing.T) {
 	assert.Error(t, err)
 
 }
+
+func TestMetricsServer(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	metricsPort := strings.Split(ln.Addr().String(), ":")[1]
+	ln.Close()
+
+	url, s, done := newTestServer(t, func() {
+		config.Set(signerconfig.MetricsEnabled, true)
+		signerconfig.MetricsConfig.Set(httpserver.HTTPConfPort, metricsPort)
+		signerconfig.MetricsConfig.Set(httpserver.HTTPConfAddress, "127.0.0.1")
+	})
+	defer done()
+
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "net_version").Run(func(args mock.Arguments) {
+		hi := args[1].(*ethtypes.HexInteger)
+		hi.BigInt().SetInt64(12345)
+	}).Return(nil)
+
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Initialize", mock.Anything).Return(nil)
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{}, nil)
+	err = s.Start()
+	assert.NoError(t, err)
+
+	res, err := resty.New().R().
+		SetBody(`{"jsonrpc":"2.0","id":1,"method":"eth_accounts"}`).
+		Post(url)
+	assert.NoError(t, err)
+	assert.True(t, res.IsSuccess())
+
+	res, err = resty.New().R().Get(fmt.Sprintf("http://127.0.0.1:%s/metrics", metricsPort))
+	assert.NoError(t, err)
+	assert.True(t, res.IsSuccess())
+	assert.Contains(t, res.String(), `ff_signer_rpc_requests_total{chain="default",component="ffsigner",method="eth_accounts",status="success"}`)
+	assert.Contains(t, res.String(), `ff_jsonrpc_http_requests_total`)
+
+}
+
+func TestBadMetricsConfig(t *testing.T) {
+
+	signerconfig.Reset()
+	config.Set(signerconfig.MetricsEnabled, true)
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfPort, 0)
+	signerconfig.MetricsConfig.Set(httpserver.HTTPConfAddress, ":::::")
+	_, err := NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Error(t, err)
+
+}
@@ -0,0 +1,179 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+)
+
+// rpcCodeExecutionReverted is the code geth returns for an eth_call that reverts, with the revert data
+// as a hex string in the error data. We return the same, so client libraries can decode it themselves.
+const rpcCodeExecutionReverted rpcbackend.RPCCode = 3
+
+// panicError is the error Solidity uses for failed assertions, arithmetic errors and other runtime failures
+var panicError = &abi.Entry{
+	Type:   abi.Error,
+	Name:   "Panic",
+	Inputs: abi.ParameterArray{{Name: "code", Type: "uint256"}},
+}
+
+// panicCodes are the meanings of the Panic(uint256) codes, from the Solidity documentation
+var panicCodes = map[int64]string{
+	0x00: "generic compiler inserted panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "incorrectly encoded storage byte array",
+	0x31: "pop on an empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to a zero-initialized internal function",
+}
+
+// simulator runs each transaction with eth_call before it is signed, so a transaction that would
+// revert on-chain is rejected with a useful reason, rather than using a nonce and failing later
+type simulator struct {
+	block  string
+	errors abi.ABI // Error(string) is always included by abi.ParseError
+}
+
+// simulationRevert is returned when the simulation of a transaction reverts
+type simulationRevert struct {
+	data ethtypes.HexBytes0xPrefix
+	err  error
+}
+
+func (sr *simulationRevert) Error() string {
+	return sr.err.Error()
+}
+
+func newSimulator(ctx context.Context) (*simulator, error) {
+	if !config.GetBool(signerconfig.SimulationEnabled) {
+		return nil, nil
+	}
+	var errorABI abi.ABI
+	b, err := json.Marshal(config.GetObjectArray(signerconfig.SimulationErrors))
+	if err == nil {
+		err = json.Unmarshal(b, &errorABI)
+	}
+	if err == nil {
+		err = errorABI.ValidateCtx(ctx)
+	}
+	if err != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidSimulationErrors, err)
+	}
+	sim := &simulator{
+		block:  config.GetString(signerconfig.SimulationBlock),
+		errors: abi.ABI{panicError},
+	}
+	for _, e := range errorABI {
+		if e.Type == abi.Error {
+			sim.errors = append(sim.errors, e)
+		}
+	}
+	return sim, nil
+}
+
+// simulate calls the transaction with eth_call, and returns an error if it reverts, or cannot be simulated
+func (c *chain) simulate(ctx context.Context, txn *ethsigner.Transaction) error {
+	sim := c.s.simulation
+	if sim == nil {
+		return nil
+	}
+
+	var result ethtypes.HexBytes0xPrefix
+	rpcErr := c.backend.CallRPC(ctx, &result, "eth_call", txn, sim.block)
+	if rpcErr == nil {
+		return nil
+	}
+
+	data, reverted := revertData(rpcErr)
+	if !reverted {
+		return i18n.NewError(ctx, signermsgs.MsgSimulationFailed, rpcErr.Message)
+	}
+	err := i18n.NewError(ctx, signermsgs.MsgSimulationReverted, sim.decodeRevert(ctx, data))
+	log.L(ctx).Warnf("Simulation rejected transaction: %s", err)
+	return &simulationRevert{data: data, err: err}
+}
+
+// revertData extracts the revert data from an eth_call error, and whether the call reverted.
+// Geth returns code 3 with the data as a hex string, some other nodes nest the data in an object,
+// and a revert with no data is only identified by the message.
+func revertData(rpcErr *rpcbackend.RPCError) (ethtypes.HexBytes0xPrefix, bool) {
+	var data ethtypes.HexBytes0xPrefix
+	if err := json.Unmarshal(rpcErr.Data.Bytes(), &data); err == nil && len(data) > 0 {
+		return data, true
+	}
+	var nested struct {
+		Data ethtypes.HexBytes0xPrefix `json:"data"`
+	}
+	if err := json.Unmarshal(rpcErr.Data.Bytes(), &nested); err == nil && len(nested.Data) > 0 {
+		return nested.Data, true
+	}
+	return nil, rpcErr.Code == int64(rpcCodeExecutionReverted) || strings.Contains(strings.ToLower(rpcErr.Message), "revert")
+}
+
+// decodeRevert returns a readable reason for the revert data, using Error(string), Panic(uint256),
+// and the configured custom errors
+func (sim *simulator) decodeRevert(ctx context.Context, data ethtypes.HexBytes0xPrefix) string {
+	if len(data) == 0 {
+		return "no revert data"
+	}
+	e, cv, ok := sim.errors.ParseErrorCtx(ctx, data)
+	if !ok {
+		return fmt.Sprintf("unknown error %s", data)
+	}
+	if e == panicError {
+		code := cv.Children[0].Value.(*big.Int)
+		meaning := "unknown panic code"
+		if code.IsInt64() && panicCodes[code.Int64()] != "" {
+			meaning = panicCodes[code.Int64()]
+		}
+		return fmt.Sprintf("Panic(0x%x): %s", code, meaning)
+	}
+	return abi.FormatErrorStringCtx(ctx, e, cv)
+}
+
+func simulationErrorResponse(err error, id *fftypes.JSONAny) *rpcbackend.RPCResponse {
+	var sr *simulationRevert
+	if !errors.As(err, &sr) {
+		return rpcbackend.RPCErrorResponse(err, id, rpcbackend.RPCCodeInternalError)
+	}
+	rpcRes := rpcbackend.RPCErrorResponse(err, id, rpcCodeExecutionReverted)
+	if len(sr.data) > 0 {
+		b, _ := json.Marshal(sr.data)
+		rpcRes.Error.Data = *fftypes.JSONAnyPtrBytes(b)
+	}
+	return rpcRes
+}
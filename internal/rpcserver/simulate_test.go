@@ -0,0 +1,266 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/mocks/ethsignermocks"
+	"github.com/hyperledger/firefly-signer/mocks/rpcbackendmocks"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testInsufficientBalanceABI = []interface{}{
+	map[string]interface{}{
+		"type": "error",
+		"name": "InsufficientBalance",
+		"inputs": []interface{}{
+			map[string]interface{}{"name": "available", "type": "uint256"},
+			map[string]interface{}{"name": "required", "type": "uint256"},
+		},
+	},
+	map[string]interface{}{
+		"type": "function",
+		"name": "transfer",
+		"inputs": []interface{}{
+			map[string]interface{}{"name": "to", "type": "address"},
+			map[string]interface{}{"name": "value", "type": "uint256"},
+		},
+	},
+}
+
+func newTestSimulationServer(t *testing.T, conf ...func()) (*rpcServer, *rpcbackendmocks.Backend, func()) {
+	_, s, done := newTestServer(t, append([]func(){func() {
+		config.Set(signerconfig.SimulationEnabled, true)
+	}}, conf...)...)
+	return s, s.backend.(*rpcbackendmocks.Backend), done
+}
+
+func mockEthCallRevert(bm *rpcbackendmocks.Backend, rpcErr *rpcbackend.RPCError) {
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_call", mock.Anything, "pending").Return(rpcErr)
+}
+
+func testRevertData(t *testing.T, e *abi.Entry, values ...interface{}) ethtypes.HexBytes0xPrefix {
+	data, err := e.EncodeCallDataValues(values)
+	assert.NoError(t, err)
+	return data
+}
+
+func testSimulatedSend(s *rpcServer) (*rpcbackend.RPCResponse, error) {
+	return s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_sendTransaction",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(`{
+			"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248",
+			"to": "0x497eedc4299dea2f2a364be10025d0ad0f702de3",
+			"gas": "0x5208",
+			"gasPrice": "0x1"
+		}`)},
+	})
+}
+
+func TestSimulationDisabled(t *testing.T) {
+	signerconfig.Reset()
+	sim, err := newSimulator(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, sim)
+}
+
+func TestSimulationBadErrorABI(t *testing.T) {
+	signerconfig.Reset()
+	config.Set(signerconfig.SimulationEnabled, true)
+	config.Set(signerconfig.SimulationErrors, []interface{}{
+		map[string]interface{}{
+			"type":   "error",
+			"name":   "Bad",
+			"inputs": []interface{}{map[string]interface{}{"name": "a", "type": "wrong"}},
+		},
+	})
+	_, err := newSimulator(context.Background())
+	assert.Regexp(t, "FF22146", err)
+
+	_, err = NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22146", err)
+
+	config.Set(signerconfig.SimulationErrors, []interface{}{
+		map[string]interface{}{"type": "error", "inputs": "not an array"},
+	})
+	_, err = newSimulator(context.Background())
+	assert.Regexp(t, "FF22146", err)
+}
+
+func TestSendTransactionSimulationSucceeds(t *testing.T) {
+
+	s, bm, done := newTestSimulationServer(t, func() {
+		config.Set(signerconfig.SimulationBlock, "latest")
+	})
+	defer done()
+
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_call", mock.Anything, "latest").
+		Run(func(args mock.Arguments) {
+			b, _ := json.Marshal(args[3])
+			assert.JSONEq(t, `{
+				"from": "0xfb075bb99f2aa4c49955bf703509a227d7a12248",
+				"to": "0x497eedc4299dea2f2a364be10025d0ad0f702de3",
+				"gas": "0x5208",
+				"gasPrice": "0x1",
+				"data": "0x"
+			}`, string(b))
+		}).
+		Return(nil)
+	bm.On("CallRPC", mock.Anything, mock.Anything, "eth_getTransactionCount", mock.Anything, "pending").
+		Run(func(args mock.Arguments) {
+			*(args[1].(**ethtypes.HexInteger)) = ethtypes.NewHexInteger64(5)
+		}).
+		Return(nil)
+	bm.On("SyncRequest", mock.Anything, mock.MatchedBy(func(rpcReq *rpcbackend.RPCRequest) bool {
+		return rpcReq.Method == "eth_sendRawTransaction"
+	})).Return(&rpcbackend.RPCResponse{
+		JSONRpc: "2.0",
+		ID:      fftypes.JSONAnyPtr("1"),
+		Result:  fftypes.JSONAnyPtr(`"0xf1c0"`),
+	}, nil)
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("Sign", mock.Anything, mock.Anything, mock.Anything).Return([]byte{0x01, 0x02}, nil)
+
+	rpcRes, err := testSimulatedSend(s)
+	assert.NoError(t, err)
+	assert.Equal(t, `"0xf1c0"`, rpcRes.Result.String())
+	bm.AssertExpectations(t)
+
+}
+
+func TestSendTransactionSimulationRevertErrorString(t *testing.T) {
+
+	s, bm, done := newTestSimulationServer(t)
+	defer done()
+
+	data := testRevertData(t, &abi.Entry{
+		Type: abi.Error, Name: "Error", Inputs: abi.ParameterArray{{Name: "reason", Type: "string"}},
+	}, "Not enough tokens")
+	mockEthCallRevert(bm, &rpcbackend.RPCError{
+		Code:    3,
+		Message: "execution reverted: Not enough tokens",
+		Data:    *fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, data)),
+	})
+
+	rpcRes, err := testSimulatedSend(s)
+	assert.Regexp(t, `FF22147.*Error\("Not enough tokens"\)`, err)
+	assert.Equal(t, int64(3), rpcRes.Error.Code)
+	assert.Equal(t, fmt.Sprintf(`"%s"`, data), rpcRes.Error.Data.String())
+
+	// The revert was detected before a nonce was assigned, or anything was signed
+	bm.AssertNotCalled(t, "CallRPC", mock.Anything, mock.Anything, "eth_getTransactionCount", mock.Anything, mock.Anything)
+	s.wallet.(*ethsignermocks.Wallet).AssertNotCalled(t, "Sign", mock.Anything, mock.Anything, mock.Anything)
+
+}
+
+func TestSendTransactionSimulationRevertPanic(t *testing.T) {
+
+	s, bm, done := newTestSimulationServer(t)
+	defer done()
+
+	mockEthCallRevert(bm, &rpcbackend.RPCError{
+		Code: 3,
+		Data: *fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, testRevertData(t, panicError, 0x11))),
+	})
+
+	_, err := testSimulatedSend(s)
+	assert.Regexp(t, `FF22147.*Panic\(0x11\): arithmetic overflow or underflow`, err)
+
+}
+
+func TestSendTransactionSimulationRevertCustomError(t *testing.T) {
+
+	s, bm, done := newTestSimulationServer(t, func() {
+		config.Set(signerconfig.SimulationErrors, testInsufficientBalanceABI)
+	})
+	defer done()
+
+	var errorABI abi.ABI
+	b, _ := json.Marshal(testInsufficientBalanceABI)
+	err := json.Unmarshal(b, &errorABI)
+	assert.NoError(t, err)
+	assert.Len(t, s.simulation.errors, 2) // Panic and InsufficientBalance
+
+	// Some nodes nest the revert data in an object
+	mockEthCallRevert(bm, &rpcbackend.RPCError{
+		Code:    -32000,
+		Message: "execution reverted",
+		Data:    *fftypes.JSONAnyPtr(fmt.Sprintf(`{"data":"%s"}`, testRevertData(t, errorABI[0], 100, 250))),
+	})
+
+	_, err = testSimulatedSend(s)
+	assert.Regexp(t, `FF22147.*InsufficientBalance\("100","250"\)`, err)
+
+}
+
+func TestSimulationDecodeRevert(t *testing.T) {
+
+	s, _, done := newTestSimulationServer(t)
+	defer done()
+	ctx := context.Background()
+
+	assert.Equal(t, "no revert data", s.simulation.decodeRevert(ctx, nil))
+	assert.Equal(t, "Panic(0x99): unknown panic code", s.simulation.decodeRevert(ctx, testRevertData(t, panicError, 0x99)))
+	assert.Equal(t, "Panic(0x10000000000000000): unknown panic code", s.simulation.decodeRevert(ctx, testRevertData(t, panicError, "0x10000000000000000")))
+	assert.Equal(t, "unknown error 0x12345678", s.simulation.decodeRevert(ctx, ethtypes.MustNewHexBytes0xPrefix("0x12345678")))
+
+}
+
+func TestSendTransactionSimulationRevertNoData(t *testing.T) {
+
+	s, bm, done := newTestSimulationServer(t)
+	defer done()
+
+	mockEthCallRevert(bm, &rpcbackend.RPCError{
+		Code:    -32000,
+		Message: "execution reverted",
+	})
+
+	rpcRes, err := testSimulatedSend(s)
+	assert.Regexp(t, "FF22147.*no revert data", err)
+	assert.Equal(t, int64(3), rpcRes.Error.Code)
+	assert.Empty(t, string(rpcRes.Error.Data))
+
+}
+
+func TestSendTransactionSimulationFailed(t *testing.T) {
+
+	s, bm, done := newTestSimulationServer(t)
+	defer done()
+
+	mockEthCallRevert(bm, &rpcbackend.RPCError{
+		Code:    -32000,
+		Message: "insufficient funds for gas * price + value",
+	})
+
+	rpcRes, err := testSimulatedSend(s)
+	assert.Regexp(t, "FF22148.*insufficient funds", err)
+	assert.Equal(t, int64(rpcbackend.RPCCodeInternalError), rpcRes.Error.Code)
+
+}
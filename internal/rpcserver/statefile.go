@@ -0,0 +1,37 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// writeStateFile writes the JSON of the state to a temporary file, then renames it over the
+// state file, so we never leave a partially written file
+func writeStateFile(stateFile string, state interface{}) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmpFile := stateFile + ".tmp"
+	err = os.WriteFile(tmpFile, b, 0600)
+	if err == nil {
+		err = os.Rename(tmpFile, stateFile)
+	}
+	return err
+}
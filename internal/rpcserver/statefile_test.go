@@ -0,0 +1,48 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteStateFile(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	err := writeStateFile(stateFile, map[string]int{"a": 1})
+	assert.NoError(t, err)
+	err = writeStateFile(stateFile, map[string]int{"b": 2})
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(stateFile)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"b":2}`, string(b))
+	_, err = os.Stat(stateFile + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWriteStateFileFail(t *testing.T) {
+	err := writeStateFile(filepath.Join(t.TempDir(), "missing", "state.json"), map[string]int{})
+	assert.Error(t, err)
+
+	err = writeStateFile(filepath.Join(t.TempDir(), "state.json"), map[string]interface{}{"bad": make(chan int)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,452 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly-signer/pkg/ethereum"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+)
+
+type TransactionStatus string
+
+const (
+	TransactionStatusPending   TransactionStatus = "pending"
+	TransactionStatusMined     TransactionStatus = "mined" // while waiting for confirmations
+	TransactionStatusConfirmed TransactionStatus = "confirmed"
+	TransactionStatusFailed    TransactionStatus = "failed"
+	TransactionStatusDropped   TransactionStatus = "dropped"
+)
+
+// TrackedTransaction is the record of a transaction signed and submitted by the signer
+type TrackedTransaction struct {
+	Hash          ethtypes.HexBytes0xPrefix  `ffstruct:"TrackedTransaction" json:"hash"`
+	Chain         string                     `ffstruct:"TrackedTransaction" json:"chain"`
+	From          *ethtypes.Address0xHex     `ffstruct:"TrackedTransaction" json:"from"`
+	Nonce         *ethtypes.HexInteger       `ffstruct:"TrackedTransaction" json:"nonce"`
+	To            *ethtypes.Address0xHex     `ffstruct:"TrackedTransaction" json:"to,omitempty"`
+	Raw           ethtypes.HexBytes0xPrefix  `ffstruct:"TrackedTransaction" json:"raw"`
+	Status        TransactionStatus          `ffstruct:"TrackedTransaction" json:"status"`
+	Submitted     *fftypes.FFTime            `ffstruct:"TrackedTransaction" json:"submitted"`
+	Updated       *fftypes.FFTime            `ffstruct:"TrackedTransaction" json:"updated"`
+	BlockNumber   *fftypes.FFBigInt          `ffstruct:"TrackedTransaction" json:"blockNumber,omitempty"`
+	BlockHash     ethtypes.HexBytes0xPrefix  `ffstruct:"TrackedTransaction" json:"blockHash,omitempty"`
+	Confirmations int64                      `ffstruct:"TrackedTransaction" json:"confirmations"`
+	Receipt       *ethereum.ReceiptExtraInfo `ffstruct:"TrackedTransaction" json:"receipt,omitempty"`
+	Resubmits     int                        `ffstruct:"TrackedTransaction" json:"resubmits,omitempty"`
+	Error         string                     `ffstruct:"TrackedTransaction" json:"error,omitempty"`
+}
+
+func (tt *TrackedTransaction) complete() bool {
+	return tt.Status == TransactionStatusConfirmed || tt.Status == TransactionStatusFailed
+}
+
+// txTracker keeps a record of each transaction submitted by eth_sendTransaction, and polls the
+// backend of its chain for the receipt, until the configured number of confirmations.
+//
+//   - A failed transaction is replayed with eth_call in the block it was mined, to find the revert reason
+//   - If the node no longer knows about a pending transaction, it is optionally resubmitted
+//   - A transaction whose receipt disappears (a re-org) returns to pending
+//   - Optionally the records are written to a state file, so they survive restarts
+type txTracker struct {
+	s             *rpcServer
+	confirmations int64
+	pollInterval  time.Duration
+	retention     time.Duration
+	stateFile     string
+	resubmit      bool
+	maxResubmits  int
+	revertDecoder *simulator
+	now           func() time.Time
+
+	pollerStarted bool
+	pollerDone    chan struct{}
+
+	mux          sync.Mutex
+	transactions map[string]*TrackedTransaction // keyed by hash
+}
+
+func newTxTracker(ctx context.Context, s *rpcServer) (*txTracker, error) {
+	if !config.GetBool(signerconfig.TrackingEnabled) {
+		return nil, nil
+	}
+	t := &txTracker{
+		s:             s,
+		confirmations: config.GetInt64(signerconfig.TrackingConfirmations),
+		pollInterval:  config.GetDuration(signerconfig.TrackingPollInterval),
+		retention:     config.GetDuration(signerconfig.TrackingRetention),
+		stateFile:     config.GetString(signerconfig.TrackingStateFile),
+		resubmit:      config.GetBool(signerconfig.TrackingResubmit),
+		maxResubmits:  config.GetInt(signerconfig.TrackingMaxResubmits),
+		now:           time.Now,
+		pollerDone:    make(chan struct{}),
+		transactions:  make(map[string]*TrackedTransaction),
+	}
+	// Revert reasons are decoded with the custom errors configured for simulation, if any
+	t.revertDecoder = s.simulation
+	if t.revertDecoder == nil {
+		t.revertDecoder = &simulator{errors: abi.ABI{panicError}}
+	}
+	if err := t.loadState(ctx); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *txTracker) loadState(ctx context.Context) error {
+	if t.stateFile == "" {
+		return nil
+	}
+	b, err := os.ReadFile(t.stateFile)
+	if os.IsNotExist(err) {
+		log.L(ctx).Infof("Transaction tracking state file '%s' does not exist, and will be created", t.stateFile)
+		return nil
+	}
+	var state []*TrackedTransaction
+	if err == nil {
+		err = json.Unmarshal(b, &state)
+	}
+	if err != nil {
+		return i18n.WrapError(ctx, err, signermsgs.MsgTrackingStateReadFailed, t.stateFile)
+	}
+	for _, tt := range state {
+		t.transactions[tt.Hash.String()] = tt
+	}
+	log.L(ctx).Infof("Loaded %d tracked transactions from '%s'", len(state), t.stateFile)
+	return nil
+}
+
+// writeState must be called with the lock held
+func (t *txTracker) writeState(ctx context.Context) {
+	if t.stateFile == "" {
+		return
+	}
+	if err := writeStateFile(t.stateFile, t.sortedLocked("", nil)); err != nil {
+		// The transactions are still tracked in memory
+		log.L(ctx).Errorf("Failed to write transaction tracking state file '%s': %s", t.stateFile, err)
+	}
+}
+
+// track records a transaction that was accepted by the node. The hash is calculated locally,
+// and checked against the hash the node returned.
+func (t *txTracker) track(ctx context.Context, c *chain, txn *ethsigner.Transaction, signed *signedTransactionRequest, rpcRes *rpcbackend.RPCResponse) {
+	hash := ethtypes.HexBytes0xPrefix(keccak256(signed.raw))
+	now := fftypes.FFTime(t.now())
+	tt := &TrackedTransaction{
+		Hash:      hash,
+		Chain:     c.name,
+		From:      signed.from,
+		Nonce:     txn.Nonce,
+		To:        txn.To,
+		Raw:       signed.raw,
+		Status:    TransactionStatusPending,
+		Submitted: &now,
+		Updated:   &now,
+	}
+	var nodeHash ethtypes.HexBytes0xPrefix
+	_ = json.Unmarshal(rpcRes.Result.Bytes(), &nodeHash)
+	if !bytes.Equal(nodeHash, hash) {
+		// We track the transaction with the hash of what we signed, as that is what will be mined
+		err := i18n.NewError(ctx, signermsgs.MsgTransactionHashMismatch, rpcRes.Result.String(), hash)
+		log.L(ctx).Errorf("Transaction hash check failed: %s", err)
+		tt.Error = err.Error()
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.transactions[hash.String()] = tt
+	t.writeState(ctx)
+	log.L(ctx).Debugf("Tracking transaction %s from %s with nonce %s", hash, signed.from, txn.Nonce.BigInt())
+}
+
+func (t *txTracker) start(ctx context.Context) {
+	t.pollerStarted = true
+	go t.poller(ctx)
+}
+
+func (t *txTracker) waitStop() {
+	if t.pollerStarted {
+		<-t.pollerDone
+	}
+}
+
+func (t *txTracker) poller(ctx context.Context) {
+	defer close(t.pollerDone)
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.L(ctx).Debugf("Transaction tracker stopped")
+			return
+		case <-ticker.C:
+			t.poll(ctx)
+		}
+	}
+}
+
+// poll checks the status of every incomplete transaction, on each chain
+func (t *txTracker) poll(ctx context.Context) {
+	polled := 0
+	for _, c := range t.s.allChains() {
+		polled += t.pollChain(ctx, c)
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if pruned := t.pruneLocked(); polled > 0 || pruned > 0 {
+		t.writeState(ctx)
+	}
+}
+
+// pollChain checks the incomplete transactions of one chain, and returns how many were checked
+func (t *txTracker) pollChain(ctx context.Context, c *chain) int {
+	t.mux.Lock()
+	var incomplete []TrackedTransaction
+	for _, tt := range t.transactions {
+		if tt.Chain == c.name && !tt.complete() {
+			incomplete = append(incomplete, *tt)
+		}
+	}
+	t.mux.Unlock()
+	if len(incomplete) == 0 {
+		return 0
+	}
+
+	var head ethtypes.HexInteger
+	if rpcErr := c.backend.CallRPC(ctx, &head, "eth_blockNumber"); rpcErr != nil {
+		log.L(ctx).Warnf("Failed to query the block height of chain '%s' to track transactions: %s", c.name, rpcErr.Message)
+		return 0
+	}
+	for _, tt := range incomplete {
+		updated := t.checkTransaction(ctx, c, head.BigInt(), &tt)
+		t.mux.Lock()
+		if _, stillTracked := t.transactions[tt.Hash.String()]; stillTracked {
+			t.transactions[tt.Hash.String()] = updated
+		}
+		t.mux.Unlock()
+	}
+	return len(incomplete)
+}
+
+// checkTransaction queries the receipt of a transaction, and updates a copy of its record
+func (t *txTracker) checkTransaction(ctx context.Context, c *chain, head *big.Int, tt *TrackedTransaction) *TrackedTransaction {
+	prevStatus := tt.Status
+	var receipt *ethereum.TXReceiptJSONRPC
+	if rpcErr := c.backend.CallRPC(ctx, &receipt, "eth_getTransactionReceipt", tt.Hash); rpcErr != nil {
+		tt.Error = rpcErr.Message
+		return tt
+	}
+
+	if receipt != nil && receipt.BlockNumber != nil {
+		t.updateMined(ctx, c, head, tt, receipt)
+	} else {
+		if tt.Status == TransactionStatusMined {
+			log.L(ctx).Warnf("Receipt of transaction %s is no longer available, after a re-org", tt.Hash)
+		}
+		tt.BlockNumber, tt.BlockHash, tt.Confirmations, tt.Receipt = nil, nil, 0, nil
+		t.updatePending(ctx, c, tt)
+	}
+
+	if tt.Status != prevStatus {
+		now := fftypes.FFTime(t.now())
+		tt.Updated = &now
+		log.L(ctx).Infof("Transaction %s is %s (was %s)", tt.Hash, tt.Status, prevStatus)
+	}
+	return tt
+}
+
+func (t *txTracker) updateMined(ctx context.Context, c *chain, head *big.Int, tt *TrackedTransaction, receipt *ethereum.TXReceiptJSONRPC) {
+	blockNumber := receipt.BlockNumber.BigInt()
+	if tt.Receipt == nil || !bytes.Equal(tt.BlockHash, receipt.BlockHash) {
+		// Mined for the first time (including after the receipt was removed), or in a different block after a re-org
+		tt.Receipt = &ethereum.ReceiptExtraInfo{
+			ContractAddress:   receipt.ContractAddress,
+			CumulativeGasUsed: (*fftypes.FFBigInt)(receipt.CumulativeGasUsed.BigInt()),
+			From:              receipt.From,
+			To:                receipt.To,
+			GasUsed:           (*fftypes.FFBigInt)(receipt.GasUsed.BigInt()),
+			Status:            (*fftypes.FFBigInt)(receipt.Status.BigInt()),
+		}
+		if receipt.Status != nil && receipt.Status.BigInt().Sign() == 0 {
+			reason := t.revertReason(ctx, c, tt, receipt.BlockNumber)
+			tt.Receipt.ErrorMessage = &reason
+		}
+	}
+	tt.BlockNumber = (*fftypes.FFBigInt)(blockNumber)
+	tt.BlockHash = receipt.BlockHash
+	tt.Confirmations = 0
+	if head.Cmp(blockNumber) > 0 {
+		tt.Confirmations = new(big.Int).Sub(head, blockNumber).Int64()
+	}
+	tt.Error = ""
+
+	switch {
+	case tt.Confirmations < t.confirmations:
+		tt.Status = TransactionStatusMined
+	case tt.Receipt != nil && tt.Receipt.ErrorMessage != nil:
+		tt.Status = TransactionStatusFailed
+	default:
+		tt.Status = TransactionStatusConfirmed
+	}
+}
+
+// updatePending checks the node still knows about a transaction that has no receipt, and optionally
+// resubmits it if the node has dropped it
+func (t *txTracker) updatePending(ctx context.Context, c *chain, tt *TrackedTransaction) {
+	var info *ethereum.TXInfoJSONRPC
+	if rpcErr := c.backend.CallRPC(ctx, &info, "eth_getTransactionByHash", tt.Hash); rpcErr != nil {
+		tt.Error = rpcErr.Message
+		return
+	}
+	if info != nil {
+		tt.Status = TransactionStatusPending
+		return
+	}
+
+	if !t.resubmit || tt.Resubmits >= t.maxResubmits {
+		tt.Status = TransactionStatusDropped
+		return
+	}
+	tt.Resubmits++
+	log.L(ctx).Infof("Resubmitting transaction %s, which is unknown to the node (attempt %d)", tt.Hash, tt.Resubmits)
+	var result ethtypes.HexBytes0xPrefix
+	if rpcErr := c.backend.CallRPC(ctx, &result, "eth_sendRawTransaction", tt.Raw); rpcErr != nil {
+		log.L(ctx).Warnf("Failed to resubmit transaction %s: %s", tt.Hash, rpcErr.Message)
+		tt.Error = rpcErr.Message
+		tt.Status = TransactionStatusDropped
+		return
+	}
+	tt.Error = ""
+	tt.Status = TransactionStatusPending
+}
+
+// revertReason replays a failed transaction with eth_call in the block it was mined, to get the revert data
+func (t *txTracker) revertReason(ctx context.Context, c *chain, tt *TrackedTransaction, blockNumber *ethtypes.HexInteger) string {
+	_, txn, err := ethsigner.RecoverRawTransaction(ctx, tt.Raw, c.chainID)
+	if err != nil {
+		return err.Error()
+	}
+	txn.From = json.RawMessage(`"` + tt.From.String() + `"`)
+	var result ethtypes.HexBytes0xPrefix
+	rpcErr := c.backend.CallRPC(ctx, &result, "eth_call", txn.Transaction, blockNumber)
+	if rpcErr == nil {
+		// The transaction did not revert when replayed, for example because it ran out of gas
+		return t.revertDecoder.decodeRevert(ctx, nil)
+	}
+	data, reverted := revertData(rpcErr)
+	if !reverted {
+		return rpcErr.Message
+	}
+	return t.revertDecoder.decodeRevert(ctx, data)
+}
+
+// pruneLocked removes the complete and dropped transactions that have not changed within the retention
+// period, and returns how many were removed
+func (t *txTracker) pruneLocked() (pruned int) {
+	now := t.now()
+	for hash, tt := range t.transactions {
+		if (tt.complete() || tt.Status == TransactionStatusDropped) && now.Sub(*tt.Updated.Time()) > t.retention {
+			delete(t.transactions, hash)
+			pruned++
+		}
+	}
+	return pruned
+}
+
+// sortedLocked returns copies of the transactions, most recently submitted first
+func (t *txTracker) sortedLocked(status TransactionStatus, from *ethtypes.Address0xHex) []*TrackedTransaction {
+	transactions := make([]*TrackedTransaction, 0, len(t.transactions))
+	for _, tt := range t.transactions {
+		if (status == "" || tt.Status == status) && (from == nil || *tt.From == *from) {
+			ttCopy := *tt
+			transactions = append(transactions, &ttCopy)
+		}
+	}
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].Submitted.Time().After(*transactions[j].Submitted.Time())
+	})
+	return transactions
+}
+
+func (t *txTracker) list(ctx context.Context, status, fromString string) ([]*TrackedTransaction, error) {
+	var from *ethtypes.Address0xHex
+	if fromString != "" {
+		var err error
+		if from, err = ethtypes.NewAddress(fromString); err != nil {
+			return nil, i18n.NewError(ctx, signermsgs.MsgInvalidAccountAddress, fromString)
+		}
+	}
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.sortedLocked(TransactionStatus(status), from), nil
+}
+
+func (t *txTracker) get(ctx context.Context, hashString string) (*TrackedTransaction, error) {
+	hash, err := ethtypes.NewHexBytes0xPrefix(hashString)
+	if err != nil || len(hash) != 32 {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidTransactionHash, hashString)
+	}
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	tt, ok := t.transactions[hash.String()]
+	if !ok {
+		return nil, i18n.NewError(ctx, signermsgs.MsgTrackedTransactionNotFound, hash)
+	}
+	ttCopy := *tt
+	return &ttCopy, nil
+}
+
+// processGetTransaction handles ffsigner_getTransaction, which has parameters [hash], so a caller can
+// query the status of a transaction from an account it is authorized to use
+func (c *chain) processGetTransaction(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+	if c.s.tracker == nil {
+		err := i18n.NewError(ctx, signermsgs.MsgTrackingNotEnabled)
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+	if len(rpcReq.Params) < 1 {
+		err := i18n.NewError(ctx, signermsgs.MsgInvalidParamCount, 1, len(rpcReq.Params))
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+	var hash string
+	_ = json.Unmarshal(rpcReq.Params[0].Bytes(), &hash)
+	tt, err := c.s.tracker.get(ctx, hash)
+	if err != nil {
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+	if err := authIdentityFromContext(ctx).authorize(ctx, *tt.From); err != nil {
+		return authErrorResponse(err, rpcReq.ID), err
+	}
+	return rpcResultResponse(rpcReq.ID, tt), nil
+}
@@ -0,0 +1,631 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/mocks/ethsignermocks"
+	"github.com/hyperledger/firefly-signer/mocks/rpcbackendmocks"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly-signer/pkg/ethereum"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testTrackingChainID = 1001
+
+type testTracking struct {
+	s  *rpcServer
+	bm *rpcbackendmocks.Backend
+	kp *secp256k1.KeyPair
+}
+
+func newTestTrackingServer(t *testing.T, conf ...func()) (*testTracking, func()) {
+	_, s, done := newTestServer(t, append([]func(){func() {
+		config.Set(signerconfig.TrackingEnabled, true)
+	}}, conf...)...)
+	s.chainID = testTrackingChainID
+	kp, err := secp256k1.GenerateSecp256k1KeyPair()
+	assert.NoError(t, err)
+	s.wallet.(*ethsignermocks.Wallet).On("Sign", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, txn *ethsigner.Transaction, chainID int64) ([]byte, error) {
+			return txn.Sign(kp, chainID)
+		})
+	return &testTracking{s: s, bm: s.backend.(*rpcbackendmocks.Backend), kp: kp}, done
+}
+
+// send submits a transaction, with the node returning the supplied hash or the correct one if nil
+func (tt *testTracking) send(t *testing.T, nodeHash ethtypes.HexBytes0xPrefix) *TrackedTransaction {
+	tt.bm.On("SyncRequest", mock.Anything, mock.MatchedBy(func(rpcReq *rpcbackend.RPCRequest) bool {
+		return rpcReq.Method == "eth_sendRawTransaction"
+	})).Run(func(args mock.Arguments) {
+		if nodeHash == nil {
+			var raw ethtypes.HexBytes0xPrefix
+			err := json.Unmarshal(args[1].(*rpcbackend.RPCRequest).Params[0].Bytes(), &raw)
+			assert.NoError(t, err)
+			nodeHash = keccak256(raw)
+		}
+	}).Return(func(ctx context.Context, rpcReq *rpcbackend.RPCRequest) *rpcbackend.RPCResponse {
+		return &rpcbackend.RPCResponse{
+			JSONRpc: "2.0",
+			ID:      rpcReq.ID,
+			Result:  fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, nodeHash)),
+		}
+	}, nil).Once()
+
+	rpcRes, err := tt.s.processRPC(tt.s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "eth_sendTransaction",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(fmt.Sprintf(`{
+			"from": "%s",
+			"to": "0x497eedc4299dea2f2a364be10025d0ad0f702de3",
+			"nonce": "0x5",
+			"gas": "0x5208",
+			"gasPrice": "0x1",
+			"data": "0xfeedbeef"
+		}`, tt.kp.Address))},
+	})
+	assert.NoError(t, err)
+	var hash string
+	err = json.Unmarshal(rpcRes.Result.Bytes(), &hash)
+	assert.NoError(t, err)
+
+	transactions, err := tt.s.tracker.list(tt.s.ctx, "", "")
+	assert.NoError(t, err)
+	return transactions[0]
+}
+
+func (tt *testTracking) mockBlockNumber(n int64) {
+	tt.bm.On("CallRPC", mock.Anything, mock.Anything, "eth_blockNumber").Run(func(args mock.Arguments) {
+		args[1].(*ethtypes.HexInteger).BigInt().SetInt64(n)
+	}).Return(nil).Once()
+}
+
+func (tt *testTracking) mockReceipt(receipt *ethereum.TXReceiptJSONRPC) {
+	tt.bm.On("CallRPC", mock.Anything, mock.Anything, "eth_getTransactionReceipt", mock.Anything).Run(func(args mock.Arguments) {
+		*(args[1].(**ethereum.TXReceiptJSONRPC)) = receipt
+	}).Return(nil).Once()
+}
+
+func (tt *testTracking) mockTransactionInfo(info *ethereum.TXInfoJSONRPC) {
+	tt.bm.On("CallRPC", mock.Anything, mock.Anything, "eth_getTransactionByHash", mock.Anything).Run(func(args mock.Arguments) {
+		*(args[1].(**ethereum.TXInfoJSONRPC)) = info
+	}).Return(nil).Once()
+}
+
+func (tt *testTracking) poll(t *testing.T, hash ethtypes.HexBytes0xPrefix) *TrackedTransaction {
+	tt.s.tracker.poll(tt.s.ctx)
+	tx, err := tt.s.tracker.get(tt.s.ctx, hash.String())
+	assert.NoError(t, err)
+	return tx
+}
+
+func testReceipt(tx *TrackedTransaction, blockNumber, status int64) *ethereum.TXReceiptJSONRPC {
+	return &ethereum.TXReceiptJSONRPC{
+		BlockHash:         ethtypes.MustNewHexBytes0xPrefix(fmt.Sprintf("0x%064x", blockNumber)),
+		BlockNumber:       ethtypes.NewHexInteger64(blockNumber),
+		CumulativeGasUsed: ethtypes.NewHexInteger64(50000),
+		From:              tx.From,
+		GasUsed:           ethtypes.NewHexInteger64(21000),
+		Status:            ethtypes.NewHexInteger64(status),
+		To:                tx.To,
+		TransactionHash:   tx.Hash,
+	}
+}
+
+func TestTrackedTransactionDocumented(t *testing.T) {
+	ffapi.CheckObjectDocumented(&TrackedTransaction{})
+}
+
+func TestTrackingDisabled(t *testing.T) {
+	signerconfig.Reset()
+	tracker, err := newTxTracker(context.Background(), &rpcServer{})
+	assert.NoError(t, err)
+	assert.Nil(t, tracker)
+}
+
+func TestTrackingBadStateFile(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "tracking.json")
+	err := os.WriteFile(stateFile, []byte("!json"), 0600)
+	assert.NoError(t, err)
+
+	signerconfig.Reset()
+	config.Set(signerconfig.TrackingEnabled, true)
+	config.Set(signerconfig.TrackingStateFile, stateFile)
+	_, err = NewServer(context.Background(), &ethsignermocks.Wallet{})
+	assert.Regexp(t, "FF22162", err)
+}
+
+func TestTrackTransactionConfirmed(t *testing.T) {
+
+	tt, done := newTestTrackingServer(t, func() {
+		config.Set(signerconfig.TrackingConfirmations, 2)
+	})
+	defer done()
+
+	tx := tt.send(t, nil)
+	assert.Equal(t, TransactionStatusPending, tx.Status)
+	assert.Equal(t, defaultChainName, tx.Chain)
+	assert.Equal(t, tt.kp.Address, *tx.From)
+	assert.Equal(t, int64(5), tx.Nonce.Int64())
+	assert.Equal(t, tx.Hash, ethtypes.HexBytes0xPrefix(keccak256(tx.Raw)))
+	assert.Empty(t, tx.Error)
+
+	// Still in the mempool
+	tt.mockBlockNumber(0x0f)
+	tt.mockReceipt(nil)
+	tt.mockTransactionInfo(&ethereum.TXInfoJSONRPC{Hash: tx.Hash})
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusPending, tx.Status)
+
+	// Mined, waiting for confirmations
+	tt.mockBlockNumber(0x11)
+	tt.mockReceipt(testReceipt(tx, 0x10, 1))
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusMined, tx.Status)
+	assert.Equal(t, int64(1), tx.Confirmations)
+	assert.Equal(t, int64(0x10), tx.BlockNumber.Int64())
+	assert.Equal(t, int64(21000), tx.Receipt.GasUsed.Int64())
+	assert.Nil(t, tx.Receipt.ErrorMessage)
+
+	tt.mockBlockNumber(0x12)
+	tt.mockReceipt(testReceipt(tx, 0x10, 1))
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusConfirmed, tx.Status)
+	assert.Equal(t, int64(2), tx.Confirmations)
+
+	// Complete transactions are not polled
+	tt.s.tracker.poll(tt.s.ctx)
+	tt.bm.AssertExpectations(t)
+
+}
+
+func TestTrackTransactionReorg(t *testing.T) {
+
+	tt, done := newTestTrackingServer(t, func() {
+		config.Set(signerconfig.TrackingConfirmations, 5)
+	})
+	defer done()
+
+	tx := tt.send(t, nil)
+	tt.mockBlockNumber(0x10)
+	tt.mockReceipt(testReceipt(tx, 0x10, 1))
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusMined, tx.Status)
+	assert.Equal(t, int64(0), tx.Confirmations)
+
+	tt.mockBlockNumber(0x11)
+	tt.mockReceipt(nil)
+	tt.mockTransactionInfo(&ethereum.TXInfoJSONRPC{Hash: tx.Hash})
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusPending, tx.Status)
+	assert.Nil(t, tx.BlockNumber)
+	assert.Nil(t, tx.Receipt)
+
+	// Mined again in a different block, that reverted this time
+	tt.mockBlockNumber(0x20)
+	tt.mockReceipt(testReceipt(tx, 0x12, 0))
+	tt.bm.On("CallRPC", mock.Anything, mock.Anything, "eth_call", mock.Anything, mock.Anything).Return(nil).Once()
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusFailed, tx.Status)
+	assert.Equal(t, "no revert data", *tx.Receipt.ErrorMessage)
+
+}
+
+func TestTrackTransactionReceiptRemovedNoBlockHash(t *testing.T) {
+
+	tt, done := newTestTrackingServer(t, func() {
+		config.Set(signerconfig.TrackingConfirmations, 5)
+	})
+	defer done()
+
+	tx := tt.send(t, nil)
+	tt.mockBlockNumber(0x10)
+	tt.mockReceipt(testReceipt(tx, 0x10, 1))
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusMined, tx.Status)
+
+	tt.mockBlockNumber(0x11)
+	tt.mockReceipt(nil)
+	tt.mockTransactionInfo(&ethereum.TXInfoJSONRPC{Hash: tx.Hash})
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusPending, tx.Status)
+	assert.Nil(t, tx.Receipt)
+
+	// A node that returns a receipt without a block hash must still get the receipt recorded
+	receipt := testReceipt(tx, 0x12, 1)
+	receipt.BlockHash = nil
+	tt.mockBlockNumber(0x20)
+	tt.mockReceipt(receipt)
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusConfirmed, tx.Status)
+	assert.Equal(t, int64(21000), tx.Receipt.GasUsed.Int64())
+	assert.Nil(t, tx.Receipt.ErrorMessage)
+
+}
+
+func TestTrackTransactionFailedRevertReason(t *testing.T) {
+
+	tt, done := newTestTrackingServer(t, func() {
+		config.Set(signerconfig.SimulationEnabled, true)
+		config.Set(signerconfig.SimulationErrors, testInsufficientBalanceABI)
+	})
+	defer done()
+	tt.bm.On("CallRPC", mock.Anything, mock.Anything, "eth_call", mock.Anything, "pending").Return(nil).Once()
+
+	tx := tt.send(t, nil)
+
+	var errorABI abi.ABI
+	b, _ := json.Marshal(testInsufficientBalanceABI)
+	err := json.Unmarshal(b, &errorABI)
+	assert.NoError(t, err)
+	tt.mockBlockNumber(0x10)
+	tt.mockReceipt(testReceipt(tx, 0x10, 0))
+	tt.bm.On("CallRPC", mock.Anything, mock.Anything, "eth_call", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			txn := args[3].(*ethsigner.Transaction)
+			assert.Equal(t, "0xfeedbeef", txn.Data.String())
+			assert.JSONEq(t, fmt.Sprintf(`"%s"`, tt.kp.Address), string(txn.From))
+			assert.Equal(t, int64(0x10), args[4].(*ethtypes.HexInteger).Int64())
+		}).
+		Return(&rpcbackend.RPCError{
+			Code:    3,
+			Message: "execution reverted",
+			Data:    *fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, testRevertData(t, errorABI[0], 100, 250))),
+		}).Once()
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusFailed, tx.Status)
+	assert.Equal(t, `InsufficientBalance("100","250")`, *tx.Receipt.ErrorMessage)
+	assert.Equal(t, int64(0), tx.Receipt.Status.Int64())
+
+}
+
+func TestTrackTransactionFailedReplayError(t *testing.T) {
+
+	tt, done := newTestTrackingServer(t)
+	defer done()
+
+	tx := tt.send(t, nil)
+	tt.mockBlockNumber(0x10)
+	tt.mockReceipt(testReceipt(tx, 0x10, 0))
+	tt.bm.On("CallRPC", mock.Anything, mock.Anything, "eth_call", mock.Anything, mock.Anything).
+		Return(&rpcbackend.RPCError{Code: -32000, Message: "missing trie node"}).Once()
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusFailed, tx.Status)
+	assert.Equal(t, "missing trie node", *tx.Receipt.ErrorMessage)
+
+	// A raw transaction that cannot be decoded for the replay
+	tt.s.chainID = 2002
+	assert.Regexp(t, "FF22085", tt.s.tracker.revertReason(tt.s.ctx, tt.s.chain, tx, ethtypes.NewHexInteger64(0x10)))
+
+}
+
+func TestTrackTransactionDropped(t *testing.T) {
+
+	tt, done := newTestTrackingServer(t)
+	defer done()
+
+	tx := tt.send(t, nil)
+	tt.mockBlockNumber(0x10)
+	tt.mockReceipt(nil)
+	tt.mockTransactionInfo(nil)
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusDropped, tx.Status)
+	assert.Zero(t, tx.Resubmits)
+	tt.bm.AssertNotCalled(t, "CallRPC", mock.Anything, mock.Anything, "eth_sendRawTransaction", mock.Anything)
+
+	// Dropped transactions are still polled, in case they are mined
+	tt.mockBlockNumber(0x10)
+	tt.mockReceipt(testReceipt(tx, 0x10, 1))
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusConfirmed, tx.Status)
+
+}
+
+func TestTrackTransactionResubmitted(t *testing.T) {
+
+	tt, done := newTestTrackingServer(t, func() {
+		config.Set(signerconfig.TrackingResubmit, true)
+		config.Set(signerconfig.TrackingMaxResubmits, 2)
+	})
+	defer done()
+
+	tx := tt.send(t, nil)
+
+	tt.mockBlockNumber(0x10)
+	tt.mockReceipt(nil)
+	tt.mockTransactionInfo(nil)
+	tt.bm.On("CallRPC", mock.Anything, mock.Anything, "eth_sendRawTransaction", tx.Raw).Return(nil).Once()
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusPending, tx.Status)
+	assert.Equal(t, 1, tx.Resubmits)
+
+	tt.mockBlockNumber(0x10)
+	tt.mockReceipt(nil)
+	tt.mockTransactionInfo(nil)
+	tt.bm.On("CallRPC", mock.Anything, mock.Anything, "eth_sendRawTransaction", tx.Raw).
+		Return(&rpcbackend.RPCError{Code: -32000, Message: "nonce too low"}).Once()
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusDropped, tx.Status)
+	assert.Equal(t, 2, tx.Resubmits)
+	assert.Equal(t, "nonce too low", tx.Error)
+
+	// No more resubmits
+	tt.mockBlockNumber(0x10)
+	tt.mockReceipt(nil)
+	tt.mockTransactionInfo(nil)
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusDropped, tx.Status)
+	assert.Equal(t, 2, tx.Resubmits)
+	tt.bm.AssertExpectations(t)
+
+}
+
+func TestTrackTransactionQueryErrors(t *testing.T) {
+
+	tt, done := newTestTrackingServer(t)
+	defer done()
+
+	tx := tt.send(t, nil)
+
+	tt.bm.On("CallRPC", mock.Anything, mock.Anything, "eth_blockNumber").Return(&rpcbackend.RPCError{Message: "pop"}).Once()
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusPending, tx.Status)
+	assert.Empty(t, tx.Error)
+
+	tt.mockBlockNumber(0x10)
+	tt.bm.On("CallRPC", mock.Anything, mock.Anything, "eth_getTransactionReceipt", mock.Anything).Return(&rpcbackend.RPCError{Message: "receipt pop"}).Once()
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusPending, tx.Status)
+	assert.Equal(t, "receipt pop", tx.Error)
+
+	tt.mockBlockNumber(0x10)
+	tt.mockReceipt(nil)
+	tt.bm.On("CallRPC", mock.Anything, mock.Anything, "eth_getTransactionByHash", mock.Anything).Return(&rpcbackend.RPCError{Message: "info pop"}).Once()
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusPending, tx.Status)
+	assert.Equal(t, "info pop", tx.Error)
+
+}
+
+func TestTrackTransactionHashMismatch(t *testing.T) {
+
+	tt, done := newTestTrackingServer(t)
+	defer done()
+
+	tx := tt.send(t, ethtypes.MustNewHexBytes0xPrefix("0xfeedbeef"))
+	assert.Regexp(t, "FF22161.*0xfeedbeef", tx.Error)
+	assert.Equal(t, tx.Hash, ethtypes.HexBytes0xPrefix(keccak256(tx.Raw)))
+
+}
+
+func TestTrackTransactionPruned(t *testing.T) {
+
+	tt, done := newTestTrackingServer(t, func() {
+		config.Set(signerconfig.TrackingRetention, "1h")
+	})
+	defer done()
+
+	tx := tt.send(t, nil)
+	tt.mockBlockNumber(0x10)
+	tt.mockReceipt(testReceipt(tx, 0x10, 1))
+	tx = tt.poll(t, tx.Hash)
+	assert.Equal(t, TransactionStatusConfirmed, tx.Status)
+
+	tt.s.tracker.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	tt.s.tracker.poll(tt.s.ctx)
+	_, err := tt.s.tracker.get(tt.s.ctx, tx.Hash.String())
+	assert.Regexp(t, "FF22159", err)
+
+}
+
+func TestTrackingStateFile(t *testing.T) {
+
+	stateFile := filepath.Join(t.TempDir(), "tracking.json")
+	tt, done := newTestTrackingServer(t, func() {
+		config.Set(signerconfig.TrackingStateFile, stateFile)
+	})
+	defer done()
+
+	tx := tt.send(t, nil)
+
+	// A new tracker continues tracking after a restart
+	tracker, err := newTxTracker(context.Background(), tt.s)
+	assert.NoError(t, err)
+	loaded, err := tracker.get(context.Background(), tx.Hash.String())
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Raw, loaded.Raw)
+	assert.Equal(t, TransactionStatusPending, loaded.Status)
+
+	// Failure to write is logged, and the transaction is still tracked in memory
+	tt.s.tracker.stateFile = t.TempDir()
+	tt.s.tracker.transactions = map[string]*TrackedTransaction{}
+	resent := tt.send(t, nil)
+	assert.Equal(t, tx.Hash, resent.Hash)
+
+}
+
+func TestTrackingMissingStateFile(t *testing.T) {
+
+	tt, done := newTestTrackingServer(t, func() {
+		config.Set(signerconfig.TrackingStateFile, filepath.Join(t.TempDir(), "tracking.json"))
+	})
+	defer done()
+
+	transactions, err := tt.s.tracker.list(tt.s.ctx, "", "")
+	assert.NoError(t, err)
+	assert.Empty(t, transactions)
+
+}
+
+func TestTrackingPoller(t *testing.T) {
+
+	tt, done := newTestTrackingServer(t, func() {
+		config.Set(signerconfig.TrackingPollInterval, "1ms")
+	})
+	defer done()
+
+	tx := tt.send(t, nil)
+	tt.mockBlockNumber(0x10)
+	tt.mockReceipt(testReceipt(tx, 0x10, 1))
+
+	ctx, cancelCtx := context.WithCancel(tt.s.ctx)
+	tt.s.tracker.start(ctx)
+	assert.Eventually(t, func() bool {
+		tx, _ := tt.s.tracker.get(ctx, tx.Hash.String())
+		return tx.Status == TransactionStatusConfirmed
+	}, 5*time.Second, time.Millisecond)
+	cancelCtx()
+	tt.s.tracker.waitStop()
+
+}
+
+func TestTrackingAdminAPI(t *testing.T) {
+
+	tt, done := newTestTrackingServer(t)
+	defer done()
+
+	tx := tt.send(t, nil)
+
+	var transactions []*TrackedTransaction
+	code := adminRequest(tt.s, http.MethodGet, "/api/v1/transactions", &transactions)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, transactions, 1)
+	code = adminRequest(tt.s, http.MethodGet, "/api/v1/transactions?status=pending&from="+tt.kp.Address.String(), &transactions)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, transactions, 1)
+	code = adminRequest(tt.s, http.MethodGet, "/api/v1/transactions?status=confirmed", &transactions)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, transactions)
+	code = adminRequest(tt.s, http.MethodGet, "/api/v1/transactions?from=0x3c99f2a4b366d46bcf2277639a135a6d1288eceb", &transactions)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, transactions)
+
+	var res map[string]interface{}
+	code = adminRequest(tt.s, http.MethodGet, "/api/v1/transactions?from=wrong", &res)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Regexp(t, "FF22141", res["error"])
+
+	// Newest first
+	older := fftypes.FFTime(time.Now().Add(-1 * time.Hour))
+	tt.s.tracker.transactions["0x1234"] = &TrackedTransaction{
+		Hash:      ethtypes.MustNewHexBytes0xPrefix("0x1234"),
+		From:      tx.From,
+		Status:    TransactionStatusConfirmed,
+		Submitted: &older,
+	}
+	code = adminRequest(tt.s, http.MethodGet, "/api/v1/transactions", &transactions)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, transactions, 2)
+	assert.Equal(t, tx.Hash, transactions[0].Hash)
+
+	var queried TrackedTransaction
+	code = adminRequest(tt.s, http.MethodGet, "/api/v1/transactions/"+tx.Hash.String(), &queried)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, tx.Hash, queried.Hash)
+
+	code = adminRequest(tt.s, http.MethodGet, "/api/v1/transactions/0xfeedbeef", &res)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Regexp(t, "FF22160", res["error"])
+
+	code = adminRequest(tt.s, http.MethodGet, fmt.Sprintf("/api/v1/transactions/0x%064x", 12345), &res)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Regexp(t, "FF22159", res["error"])
+
+}
+
+func TestGetTransaction(t *testing.T) {
+
+	tt, done := newTestTrackingServer(t)
+	defer done()
+
+	tx := tt.send(t, nil)
+
+	ctx := withAuthIdentity(tt.s.ctx, &authIdentity{
+		Name:     "team1",
+		accounts: map[ethtypes.Address0xHex]bool{tt.kp.Address: true},
+	})
+	rpcRes, err := tt.s.processRPC(ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "ffsigner_getTransaction",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, tx.Hash))},
+	})
+	assert.NoError(t, err)
+	var queried TrackedTransaction
+	err = json.Unmarshal(rpcRes.Result.Bytes(), &queried)
+	assert.NoError(t, err)
+	assert.Equal(t, TransactionStatusPending, queried.Status)
+
+	_, err = tt.s.processRPC(ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "ffsigner_getTransaction",
+	})
+	assert.Regexp(t, "FF22019", err)
+
+	_, err = tt.s.processRPC(ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "ffsigner_getTransaction",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(`"0xfeedbeef"`)},
+	})
+	assert.Regexp(t, "FF22160", err)
+
+	// Only callers that can use the account can see the transaction
+	_, err = tt.s.processRPC(withAuthIdentity(tt.s.ctx, &authIdentity{Name: "team2"}), &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "ffsigner_getTransaction",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, tx.Hash))},
+	})
+	assert.Regexp(t, "FF22119", err)
+
+}
+
+func TestTrackingNotEnabled(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+
+	var res map[string]interface{}
+	code := adminRequest(s, http.MethodGet, "/api/v1/transactions", &res)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Regexp(t, "FF22158", res["error"])
+	code = adminRequest(s, http.MethodGet, fmt.Sprintf("/api/v1/transactions/0x%064x", 12345), &res)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	_, err := s.processRPC(s.ctx, &rpcbackend.RPCRequest{
+		ID:     fftypes.JSONAnyPtr("1"),
+		Method: "ffsigner_getTransaction",
+		Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(fmt.Sprintf(`"0x%064x"`, 12345))},
+	})
+	assert.Regexp(t, "FF22158", err)
+
+}
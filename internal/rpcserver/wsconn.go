@@ -0,0 +1,264 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+)
+
+// wsConnection is a JSON/RPC client connected over WebSocket.
+//
+// The same methods are available as over HTTP, plus eth_subscribe/eth_unsubscribe which are
+// proxied to the WebSocket backend. Each client subscription has its own subscription on
+// the backend, which is re-established by the backend client on reconnect. All subscriptions
+// are removed when the client disconnects.
+type wsConnection struct {
+	id           string
+	s            *rpcServer
+	chain        *chain
+	ctx          context.Context
+	cancelCtx    context.CancelFunc
+	conn         *websocket.Conn
+	writeTimeout time.Duration
+	sendMux      sync.Mutex
+	mux          sync.Mutex
+	subs         map[string]rpcbackend.Subscription
+	closed       chan struct{}
+}
+
+type rpcSubscriptionNotification struct {
+	JSONRpc string                `json:"jsonrpc"`
+	Method  string                `json:"method"`
+	Params  rpcSubscriptionParams `json:"params"`
+}
+
+type rpcSubscriptionParams struct {
+	Subscription string           `json:"subscription"`
+	Result       *fftypes.JSONAny `json:"result"`
+}
+
+func (ch *chain) wsHandler(w http.ResponseWriter, r *http.Request) {
+	s := ch.s
+	conn, err := s.wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already replied with an error
+		log.L(r.Context()).Errorf("WebSocket upgrade failed: %s", err)
+		return
+	}
+
+	c := &wsConnection{
+		id:           fftypes.NewUUID().String(),
+		s:            s,
+		chain:        ch,
+		conn:         conn,
+		writeTimeout: s.wsWriteTimeout,
+		subs:         make(map[string]rpcbackend.Subscription),
+		closed:       make(chan struct{}),
+	}
+	// The connection outlives the upgrade request, so we carry over the authenticated identity
+	c.ctx, c.cancelCtx = context.WithCancel(withAuthIdentity(log.WithLogField(s.ctx, "wsconn", c.id), authIdentityFromContext(r.Context())))
+	s.addWSConnection(c)
+	log.L(c.ctx).Infof("WebSocket connected from %s to chain '%s'", r.RemoteAddr, ch.name)
+
+	go c.closeOnCancel()
+	go c.receiveLoop()
+}
+
+func (s *rpcServer) addWSConnection(c *wsConnection) {
+	s.wsMux.Lock()
+	defer s.wsMux.Unlock()
+	s.wsConnections[c.id] = c
+}
+
+func (s *rpcServer) removeWSConnection(c *wsConnection) {
+	s.wsMux.Lock()
+	defer s.wsMux.Unlock()
+	delete(s.wsConnections, c.id)
+}
+
+// closeOnCancel closes the connection when the server stops, which unblocks the receive loop
+func (c *wsConnection) closeOnCancel() {
+	<-c.ctx.Done()
+	_ = c.conn.Close()
+}
+
+func (c *wsConnection) receiveLoop() {
+	defer c.cleanup()
+	for {
+		_, b, err := c.conn.ReadMessage()
+		if err != nil {
+			log.L(c.ctx).Infof("WebSocket disconnected: %s", err)
+			return
+		}
+		log.L(c.ctx).Tracef("RPC --> %s", b)
+		// Requests are processed in parallel, and responses are matched by ID
+		go c.handleMessage(b)
+	}
+}
+
+func (c *wsConnection) cleanup() {
+	c.cancelCtx()
+	c.s.removeWSConnection(c)
+
+	c.mux.Lock()
+	subs := c.subs
+	c.subs = make(map[string]rpcbackend.Subscription)
+	c.mux.Unlock()
+
+	// The connection context is cancelled, so use the server context to unsubscribe
+	for id, sub := range subs {
+		if rpcErr := sub.Unsubscribe(c.s.ctx); rpcErr != nil {
+			log.L(c.ctx).Warnf("Failed to unsubscribe %s on disconnect: %s", id, rpcErr.Message)
+		}
+	}
+	close(c.closed)
+}
+
+func (c *wsConnection) handleMessage(b []byte) {
+	ctx := c.ctx
+
+	if c.s.sniffFirstByte(b) == '[' {
+		var rpcArray []*rpcbackend.RPCRequest
+		err := json.Unmarshal(b, &rpcArray)
+		if err != nil || len(rpcArray) == 0 {
+			log.L(ctx).Errorf("Bad RPC array received %s", b)
+			c.send(ctx, rpcParseErrorResponse(ctx))
+			return
+		}
+		rpcResponses, _ := c.s.processRPCBatch(ctx, rpcArray, c.processRPC)
+		c.send(ctx, rpcResponses)
+		return
+	}
+
+	var rpcRequest rpcbackend.RPCRequest
+	err := json.Unmarshal(b, &rpcRequest)
+	if err != nil {
+		log.L(ctx).Errorf("Request could not be parsed: %s", b)
+		c.send(ctx, rpcParseErrorResponse(ctx))
+		return
+	}
+	rpcResponse, _ := c.processRPC(ctx, &rpcRequest)
+	c.send(ctx, rpcResponse)
+}
+
+func (c *wsConnection) processRPC(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+	switch rpcReq.Method {
+	case "eth_subscribe":
+		return c.processSubscribe(ctx, rpcReq)
+	case "eth_unsubscribe":
+		return c.processUnsubscribe(ctx, rpcReq)
+	default:
+		return c.chain.processRPC(ctx, rpcReq)
+	}
+}
+
+func (c *wsConnection) processSubscribe(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+	if len(rpcReq.Params) < 1 {
+		err := i18n.NewError(ctx, signermsgs.MsgInvalidParamCount, 1, len(rpcReq.Params))
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+	params := make([]interface{}, len(rpcReq.Params))
+	for i, p := range rpcReq.Params {
+		params[i] = p
+	}
+
+	// The subscription lives until the client unsubscribes, or disconnects
+	sub, rpcErr := c.chain.wsBackend.Subscribe(c.ctx, params...)
+	if rpcErr != nil {
+		return &rpcbackend.RPCResponse{JSONRpc: "2.0", ID: rpcReq.ID, Error: rpcErr}, rpcErr.Error()
+	}
+
+	// We give the client an ID that does not change if the backend reconnects
+	subID := "0x" + hex.EncodeToString(sub.LocalID()[:])
+	c.mux.Lock()
+	if c.ctx.Err() != nil {
+		// The client disconnected while we were subscribing, and cleanup has run (or is waiting for the lock)
+		c.mux.Unlock()
+		_ = sub.Unsubscribe(c.s.ctx)
+		err := i18n.NewError(ctx, signermsgs.MsgWebSocketClosed)
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInternalError), err
+	}
+	c.subs[subID] = sub
+	c.mux.Unlock()
+	log.L(ctx).Infof("Subscription %s created for WebSocket client", subID)
+
+	go c.deliverNotifications(subID, sub)
+	return rpcResultResponse(rpcReq.ID, subID), nil
+}
+
+func (c *wsConnection) processUnsubscribe(ctx context.Context, rpcReq *rpcbackend.RPCRequest) (*rpcbackend.RPCResponse, error) {
+	if len(rpcReq.Params) < 1 {
+		err := i18n.NewError(ctx, signermsgs.MsgInvalidParamCount, 1, len(rpcReq.Params))
+		return rpcbackend.RPCErrorResponse(err, rpcReq.ID, rpcbackend.RPCCodeInvalidRequest), err
+	}
+	subID := rpcReq.Params[0].AsString()
+
+	c.mux.Lock()
+	sub, ok := c.subs[subID]
+	delete(c.subs, subID)
+	c.mux.Unlock()
+	if !ok {
+		// Not an error, as the subscription might have been removed already
+		return rpcResultResponse(rpcReq.ID, false), nil
+	}
+
+	if rpcErr := sub.Unsubscribe(ctx); rpcErr != nil {
+		return &rpcbackend.RPCResponse{JSONRpc: "2.0", ID: rpcReq.ID, Error: rpcErr}, rpcErr.Error()
+	}
+	log.L(ctx).Infof("Subscription %s removed for WebSocket client", subID)
+	return rpcResultResponse(rpcReq.ID, true), nil
+}
+
+// deliverNotifications runs until the backend subscription is unsubscribed, which closes the channel
+func (c *wsConnection) deliverNotifications(subID string, sub rpcbackend.Subscription) {
+	for n := range sub.Notifications() {
+		c.send(c.ctx, &rpcSubscriptionNotification{
+			JSONRpc: "2.0",
+			Method:  "eth_subscription",
+			Params: rpcSubscriptionParams{
+				Subscription: subID,
+				Result:       n.Result,
+			},
+		})
+	}
+}
+
+func (c *wsConnection) send(ctx context.Context, msg interface{}) {
+	b, _ := json.Marshal(msg)
+	log.L(ctx).Tracef("RPC <-- %s", b)
+
+	c.sendMux.Lock()
+	defer c.sendMux.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+	if err := c.conn.WriteMessage(websocket.TextMessage, b); err != nil {
+		// The receive loop will clean up, as the connection is closed
+		log.L(ctx).Errorf("WebSocket send failed: %s", err)
+		_ = c.conn.Close()
+	}
+}
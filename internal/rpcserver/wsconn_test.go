@@ -0,0 +1,356 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/httpserver"
+	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly-signer/internal/signerconfig"
+	"github.com/hyperledger/firefly-signer/mocks/ethsignermocks"
+	"github.com/hyperledger/firefly-signer/mocks/rpcbackendmocks"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rpcbackend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestWSServer(t *testing.T) (*websocket.Conn, *rpcServer, chan string, chan string, func()) {
+	signerconfig.Reset()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	serverPort := strings.Split(ln.Addr().String(), ":")[1]
+	ln.Close()
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfPort, serverPort)
+	signerconfig.ServerConfig.Set(httpserver.HTTPConfAddress, "127.0.0.1")
+	signerconfig.GasConfig.Set(signerconfig.GasConfAutoPopulate, false)
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfChainID, 1)
+	config.Set(signerconfig.WebSocketEnabled, true)
+
+	toBackend, fromBackend, backendURL, closeBackend := wsclient.NewTestWSServer(nil)
+	signerconfig.BackendConfig.Set(ffresty.HTTPConfigURL, backendURL)
+	signerconfig.BackendConfig.Set(wsclient.WSConfigKeyInitialConnectAttempts, 1)
+
+	w := &ethsignermocks.Wallet{}
+	w.On("Initialize", mock.Anything).Return(nil)
+
+	ss, err := NewServer(context.Background(), w)
+	assert.NoError(t, err)
+	s := ss.(*rpcServer)
+	s.backend = &rpcbackendmocks.Backend{}
+	s.nonces.backend = s.backend
+	s.gas.backend = s.backend
+
+	err = s.Start()
+	assert.NoError(t, err)
+
+	var conn *websocket.Conn
+	for i := 0; i < 20; i++ {
+		conn, _, err = websocket.DefaultDialer.Dial(fmt.Sprintf("ws://127.0.0.1:%s/", serverPort), nil)
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.NoError(t, err)
+
+	return conn, s, toBackend, fromBackend, func() {
+		conn.Close()
+		s.Stop()
+		_ = s.WaitStop()
+		closeBackend()
+	}
+}
+
+func wsTestRequest(t *testing.T, conn *websocket.Conn, req string) {
+	err := conn.WriteMessage(websocket.TextMessage, []byte(req))
+	assert.NoError(t, err)
+}
+
+func wsTestReceive(t *testing.T, conn *websocket.Conn) map[string]interface{} {
+	var msg map[string]interface{}
+	_, b, err := conn.ReadMessage()
+	assert.NoError(t, err)
+	err = json.Unmarshal(b, &msg)
+	assert.NoError(t, err)
+	return msg
+}
+
+func wsTestBackendRequest(t *testing.T, toBackend chan string) *rpcbackend.RPCRequest {
+	var req rpcbackend.RPCRequest
+	err := json.Unmarshal([]byte(<-toBackend), &req)
+	assert.NoError(t, err)
+	return &req
+}
+
+func TestWSAccounts(t *testing.T) {
+
+	conn, s, _, _, done := newTestWSServer(t)
+	defer done()
+
+	w := s.wallet.(*ethsignermocks.Wallet)
+	w.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{
+		ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248"),
+	}, nil)
+
+	wsTestRequest(t, conn, `{"jsonrpc": "2.0", "id": 1, "method": "eth_accounts"}`)
+	res := wsTestReceive(t, conn)
+	assert.Equal(t, float64(1), res["id"])
+	assert.Equal(t, []interface{}{"0xfb075bb99f2aa4c49955bf703509a227d7a12248"}, res["result"])
+
+}
+
+func TestWSBatch(t *testing.T) {
+
+	conn, s, _, _, done := newTestWSServer(t)
+	defer done()
+
+	bm := s.backend.(*rpcbackendmocks.Backend)
+	bm.On("SyncRequest", mock.Anything, mock.Anything).Return(&rpcbackend.RPCResponse{
+		JSONRpc: "2.0",
+		Result:  fftypes.JSONAnyPtr(`"0x1"`),
+	}, nil)
+
+	wsTestRequest(t, conn, `[
+		{"jsonrpc": "2.0", "id": 1, "method": "eth_blockNumber"},
+		{"jsonrpc": "2.0", "id": 2, "method": "eth_blockNumber"}
+	]`)
+	_, b, err := conn.ReadMessage()
+	assert.NoError(t, err)
+	var res []*rpcbackend.RPCResponse
+	err = json.Unmarshal(b, &res)
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, "0x1", res[0].Result.AsString())
+
+}
+
+func TestWSBadRequests(t *testing.T) {
+
+	conn, _, _, _, done := newTestWSServer(t)
+	defer done()
+
+	wsTestRequest(t, conn, `{!!!`)
+	res := wsTestReceive(t, conn)
+	assert.Equal(t, float64(rpcbackend.RPCCodeInvalidRequest), res["error"].(map[string]interface{})["code"])
+
+	wsTestRequest(t, conn, `[]`)
+	res = wsTestReceive(t, conn)
+	assert.Equal(t, float64(rpcbackend.RPCCodeInvalidRequest), res["error"].(map[string]interface{})["code"])
+
+	wsTestRequest(t, conn, `{"jsonrpc": "2.0", "id": 1, "method": "eth_subscribe"}`)
+	res = wsTestReceive(t, conn)
+	assert.Regexp(t, "FF22019", res["error"].(map[string]interface{})["message"])
+
+	wsTestRequest(t, conn, `{"jsonrpc": "2.0", "id": 1, "method": "eth_unsubscribe"}`)
+	res = wsTestReceive(t, conn)
+	assert.Regexp(t, "FF22019", res["error"].(map[string]interface{})["message"])
+
+}
+
+func TestWSSubscribeNotifyUnsubscribe(t *testing.T) {
+
+	conn, _, toBackend, fromBackend, done := newTestWSServer(t)
+	defer done()
+
+	wsTestRequest(t, conn, `{"jsonrpc": "2.0", "id": 1, "method": "eth_subscribe", "params": ["newHeads"]}`)
+	subReq := wsTestBackendRequest(t, toBackend)
+	assert.Equal(t, "eth_subscribe", subReq.Method)
+	assert.Equal(t, `"newHeads"`, subReq.Params[0].String())
+	fromBackend <- fmt.Sprintf(`{"jsonrpc": "2.0", "id": %s, "result": "0xbackend1"}`, subReq.ID)
+
+	res := wsTestReceive(t, conn)
+	assert.Equal(t, float64(1), res["id"])
+	subID := res["result"].(string)
+	assert.Regexp(t, "^0x[0-9a-f]{32}$", subID)
+
+	fromBackend <- `{"jsonrpc": "2.0", "method": "eth_subscription", "params": {"subscription": "0xbackend1", "result": {"number": "0x1"}}}`
+	res = wsTestReceive(t, conn)
+	assert.Equal(t, "eth_subscription", res["method"])
+	assert.Nil(t, res["id"])
+	assert.Equal(t, subID, res["params"].(map[string]interface{})["subscription"])
+	assert.Equal(t, "0x1", res["params"].(map[string]interface{})["result"].(map[string]interface{})["number"])
+
+	wsTestRequest(t, conn, fmt.Sprintf(`{"jsonrpc": "2.0", "id": 2, "method": "eth_unsubscribe", "params": ["%s"]}`, subID))
+	unsubReq := wsTestBackendRequest(t, toBackend)
+	assert.Equal(t, "eth_unsubscribe", unsubReq.Method)
+	assert.Equal(t, `"0xbackend1"`, unsubReq.Params[0].String())
+	fromBackend <- fmt.Sprintf(`{"jsonrpc": "2.0", "id": %s, "result": true}`, unsubReq.ID)
+
+	res = wsTestReceive(t, conn)
+	assert.Equal(t, float64(2), res["id"])
+	assert.Equal(t, true, res["result"])
+
+	// A second unsubscribe does not find the subscription
+	wsTestRequest(t, conn, fmt.Sprintf(`{"jsonrpc": "2.0", "id": 3, "method": "eth_unsubscribe", "params": ["%s"]}`, subID))
+	res = wsTestReceive(t, conn)
+	assert.Equal(t, float64(3), res["id"])
+	assert.Equal(t, false, res["result"])
+
+}
+
+func TestWSSubscribeError(t *testing.T) {
+
+	conn, _, toBackend, fromBackend, done := newTestWSServer(t)
+	defer done()
+
+	wsTestRequest(t, conn, `{"jsonrpc": "2.0", "id": 1, "method": "eth_subscribe", "params": ["wrong"]}`)
+	subReq := wsTestBackendRequest(t, toBackend)
+	fromBackend <- fmt.Sprintf(`{"jsonrpc": "2.0", "id": %s, "error": {"code": -32602, "message": "pop"}}`, subReq.ID)
+
+	res := wsTestReceive(t, conn)
+	assert.Equal(t, float64(1), res["id"])
+	assert.Equal(t, "pop", res["error"].(map[string]interface{})["message"])
+
+}
+
+func TestWSUnsubscribeError(t *testing.T) {
+
+	conn, _, toBackend, fromBackend, done := newTestWSServer(t)
+	defer done()
+
+	wsTestRequest(t, conn, `{"jsonrpc": "2.0", "id": 1, "method": "eth_subscribe", "params": ["newHeads"]}`)
+	subReq := wsTestBackendRequest(t, toBackend)
+	fromBackend <- fmt.Sprintf(`{"jsonrpc": "2.0", "id": %s, "result": "0xbackend1"}`, subReq.ID)
+	subID := wsTestReceive(t, conn)["result"].(string)
+
+	wsTestRequest(t, conn, fmt.Sprintf(`{"jsonrpc": "2.0", "id": 2, "method": "eth_unsubscribe", "params": ["%s"]}`, subID))
+	unsubReq := wsTestBackendRequest(t, toBackend)
+	fromBackend <- fmt.Sprintf(`{"jsonrpc": "2.0", "id": %s, "error": {"code": -32000, "message": "pop"}}`, unsubReq.ID)
+
+	res := wsTestReceive(t, conn)
+	assert.Equal(t, "pop", res["error"].(map[string]interface{})["message"])
+
+}
+
+func TestWSDisconnectCleansUpSubscriptions(t *testing.T) {
+
+	conn, s, toBackend, fromBackend, done := newTestWSServer(t)
+	defer done()
+
+	wsTestRequest(t, conn, `{"jsonrpc": "2.0", "id": 1, "method": "eth_subscribe", "params": ["newHeads"]}`)
+	subReq := wsTestBackendRequest(t, toBackend)
+	fromBackend <- fmt.Sprintf(`{"jsonrpc": "2.0", "id": %s, "result": "0xbackend1"}`, subReq.ID)
+	wsTestReceive(t, conn)
+
+	s.wsMux.Lock()
+	assert.Len(t, s.wsConnections, 1)
+	var c *wsConnection
+	for _, c = range s.wsConnections {
+	}
+	s.wsMux.Unlock()
+
+	conn.Close()
+
+	unsubReq := wsTestBackendRequest(t, toBackend)
+	assert.Equal(t, "eth_unsubscribe", unsubReq.Method)
+	assert.Equal(t, `"0xbackend1"`, unsubReq.Params[0].String())
+	fromBackend <- fmt.Sprintf(`{"jsonrpc": "2.0", "id": %s, "result": true}`, unsubReq.ID)
+
+	<-c.closed
+	assert.Empty(t, s.wsBackend.Subscriptions())
+	s.wsMux.Lock()
+	assert.Empty(t, s.wsConnections)
+	s.wsMux.Unlock()
+
+}
+
+func TestWSSubscribeAfterDisconnect(t *testing.T) {
+
+	conn, s, toBackend, fromBackend, done := newTestWSServer(t)
+	defer done()
+
+	s.wsMux.Lock()
+	var c *wsConnection
+	for _, c = range s.wsConnections {
+	}
+	s.wsMux.Unlock()
+
+	resCh := make(chan *rpcbackend.RPCResponse)
+	go func() {
+		res, _ := c.processSubscribe(context.Background(), &rpcbackend.RPCRequest{
+			ID:     fftypes.JSONAnyPtr("1"),
+			Method: "eth_subscribe",
+			Params: []*fftypes.JSONAny{fftypes.JSONAnyPtr(`"newHeads"`)},
+		})
+		resCh <- res
+	}()
+
+	// Disconnect before the backend confirms the subscription
+	subReq := wsTestBackendRequest(t, toBackend)
+	conn.Close()
+	<-c.closed
+	fromBackend <- fmt.Sprintf(`{"jsonrpc": "2.0", "id": %s, "result": "0xbackend1"}`, subReq.ID)
+
+	res := <-resCh
+	// Depending on timing, either the subscribe is cancelled, or we unsubscribe after it completes
+	assert.Regexp(t, "FF22107|FF22063", res.Error.Message)
+
+}
+
+func TestWSUpgradeFail(t *testing.T) {
+
+	_, s, _, _, done := newTestWSServer(t)
+	defer done()
+
+	res, err := http.Get(fmt.Sprintf("http://%s/", s.apiServer.Addr()))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+}
+
+func TestWSNotEnabled(t *testing.T) {
+
+	_, s, done := newTestServer(t)
+	defer done()
+	assert.Nil(t, s.wsUpgrader)
+
+	res := httptest.NewRecorder()
+	s.router().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+
+}
+
+func TestWSBackendConnectFail(t *testing.T) {
+
+	signerconfig.Reset()
+	signerconfig.BackendConfig.Set(signerconfig.BackendConfChainID, 1)
+	config.Set(signerconfig.WebSocketEnabled, true)
+	signerconfig.BackendConfig.Set(ffresty.HTTPConfigURL, "!!!::")
+
+	w := &ethsignermocks.Wallet{}
+	w.On("Initialize", mock.Anything).Return(nil)
+	ss, err := NewServer(context.Background(), w)
+	assert.NoError(t, err)
+	err = ss.Start()
+	assert.Regexp(t, "FF00149", err)
+
+}
@@ -1,4 +1,4 @@
-// Copyright © 2022 Kaleido, Inc.
+// Copyright © 2026 Kaleido, Inc.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -18,19 +18,182 @@ package signerconfig
 
 import (
 	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/httpserver"
 	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly-signer/internal/audit"
+	"github.com/hyperledger/firefly-signer/pkg/devwallet"
 	"github.com/hyperledger/firefly-signer/pkg/fswallet"
+	"github.com/hyperledger/firefly-signer/pkg/hdwallet"
+	"github.com/hyperledger/firefly-signer/pkg/hsmwallet"
+	"github.com/hyperledger/firefly-signer/pkg/kmswallet"
 	"github.com/spf13/viper"
 )
 
 var ffc = config.AddRootKey
 
 var (
-	// BackendChainID optionally set the Chain ID manually (usually queries network ID)
-	BackendChainID = ffc("backend.chainId")
+	// AdminEnabled whether to serve the management REST API on a separate HTTP server
+	AdminEnabled = ffc("admin.enabled")
+	// ApprovalEnabled whether transactions that match the approval criteria are held for approval through the admin API
+	ApprovalEnabled = ffc("approval.enabled")
+	// ApprovalValueThreshold optional value in wei, over which transactions require approval
+	ApprovalValueThreshold = ffc("approval.valueThreshold")
+	// ApprovalKnownDestinations optional list of addresses, transactions to any other destination require approval
+	ApprovalKnownDestinations = ffc("approval.knownDestinations")
+	// ApprovalSelectors optional list of function selectors that require approval
+	ApprovalSelectors = ffc("approval.selectors")
+	// ApprovalTimeout how long a transaction is held for approval, before it expires
+	ApprovalTimeout = ffc("approval.timeout")
+	// ApprovalWait whether eth_sendTransaction waits for the decision, rather than returning the approval request ID immediately
+	ApprovalWait = ffc("approval.wait")
+	// ApprovalRetention how long decided approval requests are kept, to be queried
+	ApprovalRetention = ffc("approval.retention")
+	// AuthEnabled whether clients must authenticate, and are restricted to the accounts of their identity
+	AuthEnabled = ffc("auth.enabled")
+	// AuthAPIKeyHeader the HTTP header containing an API key
+	AuthAPIKeyHeader = ffc("auth.apiKeyHeader")
+	// AuthIdentities the list of client identities, each with its API keys and the accounts it can use
+	AuthIdentities = ffc("auth.identities")
+	// AuthJWTHMACSecret optional secret to verify HS256/HS384/HS512 JWT bearer tokens
+	AuthJWTHMACSecret = ffc("auth.jwt.hmacSecret")
+	// AuthJWTPublicKeyFile optional PEM file with the public key to verify RSA/ECDSA/Ed25519 JWT bearer tokens
+	AuthJWTPublicKeyFile = ffc("auth.jwt.publicKeyFile")
+	// AuthJWTIssuer optional issuer that JWT bearer tokens must have
+	AuthJWTIssuer = ffc("auth.jwt.issuer")
+	// AuthJWTAudience optional audience that JWT bearer tokens must include
+	AuthJWTAudience = ffc("auth.jwt.audience")
+	// AuthJWTRequireExpiry whether JWT bearer tokens must have an expiry
+	AuthJWTRequireExpiry = ffc("auth.jwt.requireExpiry")
+	// AuthJWTIdentityClaim the JWT claim containing the identity name
+	AuthJWTIdentityClaim = ffc("auth.jwt.identityClaim")
+	// AuthMTLSEnabled whether the common name of a verified TLS client certificate is used as the identity name
+	AuthMTLSEnabled = ffc("auth.mtls.enabled")
+	// AuditEnabled whether to write an audit log entry for everything signed
+	AuditEnabled = ffc("audit.enabled")
+	// HealthTimeout the maximum time for the checks of the wallet and each backend, on each readiness or status request
+	HealthTimeout = ffc("health.timeout")
+	// MetricsEnabled whether to serve Prometheus metrics on a separate HTTP server
+	MetricsEnabled = ffc("metrics.enabled")
+	// MetricsPath the path to serve the metrics on
+	MetricsPath = ffc("metrics.path")
 	// FileWalletEnabled if the Keystore V3 wallet is enabled
 	FileWalletEnabled = ffc("fileWallet.enabled")
+	// FileWalletPriority the priority of the Keystore V3 wallet, when there are other wallets configured
+	FileWalletPriority = ffc("fileWallet.priority")
+	// DevWalletEnabled if the in-memory development wallet is enabled
+	DevWalletEnabled = ffc("devWallet.enabled")
+	// DevWalletPriority the priority of the development wallet, when there are other wallets configured
+	DevWalletPriority = ffc("devWallet.priority")
+	// LimitsAddressRate optional maximum signing operations per second for each address
+	LimitsAddressRate = ffc("limits.address.rate")
+	// LimitsAddressBurst the number of signing operations an address can make at once, above its rate
+	LimitsAddressBurst = ffc("limits.address.burst")
+	// LimitsIdentityRate optional maximum signing operations per second for each authenticated client identity
+	LimitsIdentityRate = ffc("limits.identity.rate")
+	// LimitsIdentityBurst the number of signing operations an identity can make at once, above its rate
+	LimitsIdentityBurst = ffc("limits.identity.burst")
+	// LimitsQuotaWindow the rolling window the spend quotas apply to
+	LimitsQuotaWindow = ffc("limits.quota.window")
+	// LimitsQuotaMaxValue optional maximum total value in wei of the transactions signed for each address, in the window
+	LimitsQuotaMaxValue = ffc("limits.quota.maxValue")
+	// LimitsQuotaMaxFee optional maximum total of gas * maxFeePerGas in wei of the transactions signed for each address, in the window
+	LimitsQuotaMaxFee = ffc("limits.quota.maxFee")
+	// LimitsQuotaStateFile optional file to record the spending in the window, to survive restarts
+	LimitsQuotaStateFile = ffc("limits.quota.stateFile")
+	// PolicyAllowedTo optional list of addresses transactions are allowed to be sent to
+	PolicyAllowedTo = ffc("policy.allowedTo")
+	// PolicyMaxValue optional maximum value in wei for a transaction
+	PolicyMaxValue = ffc("policy.maxValue")
+	// PolicyMaxFeePerGas optional maximum gasPrice or maxFeePerGas in wei for a transaction
+	PolicyMaxFeePerGas = ffc("policy.maxFeePerGas")
+	// PolicyAllowedFunctions optional list of destinations, with the ABI functions allowed to be called on each
+	PolicyAllowedFunctions = ffc("policy.allowedFunctions")
+	// PolicyWalletMetadataProperty optional property in the wallet metadata of each key, containing additional policy rules
+	PolicyWalletMetadataProperty = ffc("policy.walletMetadataProperty")
+	// RawTransactionsValidate whether to decode eth_sendRawTransaction payloads, and check the chain ID, before passing them to the backend
+	RawTransactionsValidate = ffc("rawTransactions.validate")
+	// RawTransactionsAllowUnprotected whether to accept legacy raw transactions that do not include a chain ID
+	RawTransactionsAllowUnprotected = ffc("rawTransactions.allowUnprotected")
+	// RawTransactionsPolicy whether to apply the configured signing policy to raw transactions
+	RawTransactionsPolicy = ffc("rawTransactions.policy")
+	// SimulationEnabled whether to eth_call each transaction before signing it, and reject it if it reverts
+	SimulationEnabled = ffc("simulation.enabled")
+	// SimulationBlock the block tag or number the simulation is run against
+	SimulationBlock = ffc("simulation.block")
+	// SimulationErrors optional ABI entries for the custom errors to decode from revert data
+	SimulationErrors = ffc("simulation.errors")
+	// TrackingEnabled whether to keep a record of each transaction submitted, and poll for its receipt
+	TrackingEnabled = ffc("tracking.enabled")
+	// TrackingConfirmations the number of blocks after the receipt before a transaction is complete
+	TrackingConfirmations = ffc("tracking.confirmations")
+	// TrackingPollInterval how often to poll for the receipts of tracked transactions
+	TrackingPollInterval = ffc("tracking.pollInterval")
+	// TrackingRetention how long to keep the record of a transaction once it is complete
+	TrackingRetention = ffc("tracking.retention")
+	// TrackingStateFile optional file to record the tracked transactions in, so they survive restarts
+	TrackingStateFile = ffc("tracking.stateFile")
+	// TrackingResubmit whether to resubmit a transaction the node no longer knows about
+	TrackingResubmit = ffc("tracking.resubmit")
+	// TrackingMaxResubmits the maximum number of times to resubmit a transaction
+	TrackingMaxResubmits = ffc("tracking.maxResubmits")
+	// WebSocketEnabled whether to accept WebSocket connections on the JSON/RPC server
+	WebSocketEnabled = ffc("websocket.enabled")
+	// WebSocketWriteTimeout the maximum time to wait when sending a message to a WebSocket client
+	WebSocketWriteTimeout = ffc("websocket.writeTimeout")
+	// WebSocketReadBufferSize the read buffer size for WebSocket client connections
+	WebSocketReadBufferSize = ffc("websocket.readBufferSize")
+	// WebSocketWriteBufferSize the write buffer size for WebSocket client connections
+	WebSocketWriteBufferSize = ffc("websocket.writeBufferSize")
+)
+
+// Keys within the backend, gas and nonceManager sections. These sections are configured at the root
+// for the default chain, and within each entry of the chains array for the named chains.
+const (
+	ChainConfName                     = "name"
+	BackendConfChainID                = "chainId"
+	BackendConfTransport              = "transport"
+	BackendConfURLs                   = "urls"
+	BackendConfStrategy               = "strategy"
+	BackendConfHealthCheckInterval    = "healthCheck.interval"
+	BackendConfHealthCheckTimeout     = "healthCheck.timeout"
+	BackendConfHealthCheckMaxBlockLag = "healthCheck.maxBlockLag"
+	BackendConfSenderPinTTL           = "senderPinTTL"
+	NonceManagerConfCacheEnabled      = "cacheEnabled"
+	NonceManagerConfJournalFile       = "journalFile"
+	GasConfAutoPopulate               = "autoPopulate"
+	GasConfEstimateMultiplier         = "estimateMultiplier"
+	GasConfFeeHistoryBlocks           = "feeHistoryBlocks"
+	GasConfPriorityFeePercentile      = "priorityFeePercentile"
+	GasConfMaxGasPrice                = "maxGasPrice"
+	GasConfMaxFeePerGas               = "maxFeePerGas"
+	GasConfMaxPriorityFeePerGas       = "maxPriorityFeePerGas"
+)
+
+// Keys within each entry of the wallets array
+const (
+	WalletConfName       = "name"
+	WalletConfType       = "type"
+	WalletConfPriority   = "priority"
+	WalletConfFileWallet = "fileWallet"
+	WalletConfHDWallet   = "hdWallet"
+	WalletConfDevWallet  = "devWallet"
+	WalletConfHSMWallet  = "hsmWallet"
+	WalletConfKMSWallet  = "kmsWallet"
+)
+
+// Types of wallet in the wallets array
+const (
+	// WalletTypeFileWallet is a Keystore V3 filesystem wallet
+	WalletTypeFileWallet = "fileWallet"
+	// WalletTypeHDWallet is a BIP-32 hierarchical deterministic wallet, with a Keystore V3 seed file
+	WalletTypeHDWallet = "hdWallet"
+	// WalletTypeDevWallet is an in-memory development wallet, with accounts generated from a seed string
+	WalletTypeDevWallet = "devWallet"
+	// WalletTypeHSMWallet is a wallet with the keys in an HSM, used through PKCS#11
+	WalletTypeHSMWallet = "hsmWallet"
+	// WalletTypeKMSWallet is a wallet with the keys in a cloud KMS, used through the AWS KMS API
+	WalletTypeKMSWallet = "kmsWallet"
 )
 
 var ServerConfig config.Section
@@ -39,11 +202,60 @@ var CorsConfig config.Section
 
 var BackendConfig config.Section
 
+var NonceManagerConfig config.Section
+
+var GasConfig config.Section
+
+var ChainsConfig config.ArraySection
+
 var FileWalletConfig config.Section
 
+var DevWalletConfig config.Section
+
+var WalletsConfig config.ArraySection
+
+var AuditConfig config.Section
+
+var MetricsConfig config.Section
+
+var AdminConfig config.Section
+
 func setDefaults() {
-	viper.SetDefault(string(BackendChainID), -1)
+	viper.SetDefault(string(AdminEnabled), false)
+	viper.SetDefault(string(ApprovalEnabled), false)
+	viper.SetDefault(string(ApprovalTimeout), "1h")
+	viper.SetDefault(string(ApprovalWait), false)
+	viper.SetDefault(string(ApprovalRetention), "24h")
+	viper.SetDefault(string(AuditEnabled), false)
+	viper.SetDefault(string(AuthEnabled), false)
+	viper.SetDefault(string(AuthAPIKeyHeader), "X-API-Key")
+	viper.SetDefault(string(AuthJWTRequireExpiry), true)
+	viper.SetDefault(string(AuthJWTIdentityClaim), "sub")
+	viper.SetDefault(string(AuthMTLSEnabled), false)
 	viper.SetDefault(string(FileWalletEnabled), true)
+	viper.SetDefault(string(FileWalletPriority), 0)
+	viper.SetDefault(string(DevWalletEnabled), false)
+	viper.SetDefault(string(DevWalletPriority), 0)
+	viper.SetDefault(string(HealthTimeout), "1s")
+	viper.SetDefault(string(LimitsAddressBurst), 10)
+	viper.SetDefault(string(LimitsIdentityBurst), 10)
+	viper.SetDefault(string(LimitsQuotaWindow), "24h")
+	viper.SetDefault(string(MetricsEnabled), false)
+	viper.SetDefault(string(MetricsPath), "/metrics")
+	viper.SetDefault(string(RawTransactionsValidate), true)
+	viper.SetDefault(string(RawTransactionsAllowUnprotected), true)
+	viper.SetDefault(string(RawTransactionsPolicy), false)
+	viper.SetDefault(string(SimulationEnabled), false)
+	viper.SetDefault(string(SimulationBlock), "pending")
+	viper.SetDefault(string(TrackingEnabled), false)
+	viper.SetDefault(string(TrackingConfirmations), 0)
+	viper.SetDefault(string(TrackingPollInterval), "5s")
+	viper.SetDefault(string(TrackingRetention), "24h")
+	viper.SetDefault(string(TrackingResubmit), false)
+	viper.SetDefault(string(TrackingMaxResubmits), 10)
+	viper.SetDefault(string(WebSocketWriteTimeout), "10s")
+	viper.SetDefault(string(WebSocketReadBufferSize), "16Kb")
+	viper.SetDefault(string(WebSocketWriteBufferSize), "16Kb")
 }
 
 func Reset() {
@@ -56,9 +268,62 @@ func Reset() {
 	httpserver.InitCORSConfig(CorsConfig)
 
 	BackendConfig = config.RootSection("backend")
-	wsclient.InitConfig(BackendConfig)
+	NonceManagerConfig = config.RootSection("nonceManager")
+	GasConfig = config.RootSection("gas")
+	initChainConfig(BackendConfig, NonceManagerConfig, GasConfig)
+
+	ChainsConfig = config.RootArray("chains")
+	ChainsConfig.AddKnownKey(ChainConfName)
+	initChainConfig(ChainsConfig.SubSection("backend"), ChainsConfig.SubSection("nonceManager"), ChainsConfig.SubSection("gas"))
 
 	FileWalletConfig = config.RootSection("fileWallet")
 	fswallet.InitConfig(FileWalletConfig)
 
+	DevWalletConfig = config.RootSection("devWallet")
+	devwallet.InitConfig(DevWalletConfig)
+
+	WalletsConfig = config.RootArray("wallets")
+	WalletsConfig.AddKnownKey(WalletConfName)
+	WalletsConfig.AddKnownKey(WalletConfType, WalletTypeFileWallet)
+	WalletsConfig.AddKnownKey(WalletConfPriority, 0)
+	fswallet.InitConfig(WalletsConfig.SubSection(WalletConfFileWallet))
+	hdwallet.InitConfig(WalletsConfig.SubSection(WalletConfHDWallet))
+	devwallet.InitConfig(WalletsConfig.SubSection(WalletConfDevWallet))
+	hsmwallet.InitConfig(WalletsConfig.SubSection(WalletConfHSMWallet))
+	kmswallet.InitConfig(WalletsConfig.SubSection(WalletConfKMSWallet))
+
+	AuditConfig = config.RootSection("audit")
+	audit.InitConfig(AuditConfig)
+
+	MetricsConfig = config.RootSection("metrics")
+	httpserver.InitHTTPConfig(MetricsConfig, 6000)
+
+	AdminConfig = config.RootSection("admin")
+	httpserver.InitHTTPConfig(AdminConfig, 5000)
+	AdminConfig.AddKnownKey(ffapi.ConfAPIRequestTimeout, "30s")
+
+}
+
+// initChainConfig adds the keys and defaults for the backend, nonce manager and gas sections of a chain
+func initChainConfig(backendConf, nonceManagerConf, gasConf config.Section) {
+	wsclient.InitConfig(backendConf)
+	backendConf.AddKnownKey(BackendConfChainID, -1)
+	backendConf.AddKnownKey(BackendConfTransport, "http")
+	backendConf.AddKnownKey(BackendConfURLs)
+	backendConf.AddKnownKey(BackendConfStrategy, "priority")
+	backendConf.AddKnownKey(BackendConfHealthCheckInterval, "10s")
+	backendConf.AddKnownKey(BackendConfHealthCheckTimeout, "5s")
+	backendConf.AddKnownKey(BackendConfHealthCheckMaxBlockLag, 5)
+	backendConf.AddKnownKey(BackendConfSenderPinTTL, "10m")
+
+	nonceManagerConf.AddKnownKey(NonceManagerConfCacheEnabled, true)
+	nonceManagerConf.AddKnownKey(NonceManagerConfJournalFile)
+
+	gasConf.AddKnownKey(GasConfAutoPopulate, true)
+	gasConf.AddKnownKey(GasConfEstimateMultiplier, 1.5)
+	gasConf.AddKnownKey(GasConfFeeHistoryBlocks, 20)
+	gasConf.AddKnownKey(GasConfPriorityFeePercentile, 50)
+	gasConf.AddKnownKey(GasConfMaxGasPrice)
+	gasConf.AddKnownKey(GasConfMaxFeePerGas)
+	gasConf.AddKnownKey(GasConfMaxPriorityFeePerGas)
 }
@@ -31,4 +31,25 @@ var (
 	APIBoolDescription    = ffm("api.bool", "A boolean. You can use a boolean or a string true/false as input")
 	APIFloatDescription   = ffm("api.float", "A floating point number, which will be converted to a fixed point number. You are recommended to use a JSON string. A JSON number can be used for values up to the safe maximum.")
 	APIHexDescription     = ffm("api.hex", "A hex encoded set of bytes, with an optional '0x' prefix")
+
+	APIEndpointsGetAccounts        = ffm("api.endpoints.get.accounts", "List the accounts in the wallet, with their metadata and whether the key is decrypted in the signer cache")
+	APIEndpointsGetAccount         = ffm("api.endpoints.get.account", "Get an account in the wallet, with its metadata and whether the key is decrypted in the signer cache")
+	APIEndpointsDeleteAccountCache = ffm("api.endpoints.delete.account.cache", "Evict the decrypted key of an account from the signer cache, so it is loaded and decrypted again on next use")
+	APIEndpointsPostRefresh        = ffm("api.endpoints.post.refresh", "Refresh the wallet to detect new keys immediately, and list the accounts")
+	APIEndpointsGetChains          = ffm("api.endpoints.get.chains", "List the chains served by the signer, with the detected chain ID and the status of the backend")
+
+	APIEndpointsGetApprovals = ffm("api.endpoints.get.approvals", "List the transactions held for approval, and recently decided")
+	APIEndpointsGetApproval  = ffm("api.endpoints.get.approval", "Get a transaction approval request")
+	APIEndpointsPostApprove  = ffm("api.endpoints.post.approval.approve", "Approve a pending transaction, which is then signed and submitted. Returns the approval request with the transaction hash, or the error")
+	APIEndpointsPostReject   = ffm("api.endpoints.post.approval.reject", "Reject a pending transaction, so it is not signed")
+
+	APIEndpointsGetTransactions = ffm("api.endpoints.get.transactions", "List the tracked transactions, most recently submitted first")
+	APIEndpointsGetTransaction  = ffm("api.endpoints.get.transaction", "Get the status of a tracked transaction, with its receipt once it is mined")
+
+	APIParamsAddress        = ffm("api.params.address", "The address of the account")
+	APIParamsApprovalID     = ffm("api.params.approval.id", "The ID of the approval request")
+	APIParamsApprovalStatus = ffm("api.params.approval.status", "Only return approval requests with this status")
+	APIParamsTxHash         = ffm("api.params.transaction.hash", "The hash of the transaction")
+	APIParamsTxStatus       = ffm("api.params.transaction.status", "Only return transactions with this status")
+	APIParamsTxFrom         = ffm("api.params.transaction.from", "Only return transactions signed by this address")
 )
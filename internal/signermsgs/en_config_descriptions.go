@@ -28,20 +28,27 @@ var ffc = func(key, translation, fieldType string) i18n.ConfigMessageKey {
 //revive:disable
 var (
 	ConfigFileWalletEnabled                      = ffc("config.fileWallet.enabled", "Whether the Keystore V3 filesystem wallet is enabled", "boolean")
-	ConfigFileWalletPath                         = ffc("config.fileWallet.path", "Path on the filesystem where the metadata files (and/or key files) are located", "string")
-	ConfigFileWalletFilenamesPrimaryBatchRegex   = ffc("config.fileWallet.filenames.primaryMatchRegex", "Regular expression run against key/metadata filenames to extract the address (takes precedence over primaryExt)", "regexp")
-	ConfigFileWalletFilenamesWith0xPrefix        = ffc("config.fileWallet.filenames.with0xPrefix", "When true and passwordExt is used, password filenames will be generated with an 0x prefix", "boolean")
-	ConfigFileWalletFilenamesPrimaryExt          = ffc("config.fileWallet.filenames.primaryExt", "Extension for key/metadata files named by <ADDRESS>.<EXT>", "string")
-	ConfigFileWalletFilenamesPasswordExt         = ffc("config.fileWallet.filenames.passwordExt", "Optional to use to look up password files, that sit next to the key files directly. Alternative to metadata when you have a password per keystore", "string")
-	ConfigFileWalletFilenamesPasswordPath        = ffc("config.fileWallet.filenames.passwordPath", "Optional directory in which to look for the password files, when passwordExt is configured. Default is the wallet directory", "string")
-	ConfigFileWalletFilenamesPasswordTrimSpace   = ffc("config.fileWallet.filenames.passwordTrimSpace", "Whether to trim leading/trailing whitespace (such as a newline) from the password when loaded from file", "boolean")
-	ConfigFileWalletDefaultPasswordFile          = ffc("config.fileWallet.defaultPasswordFile", "Optional default password file to use, if one is not specified individually for the key (via metadata, or file extension)", "string")
-	ConfigFileWalletDisableListener              = ffc("config.fileWallet.disableListener", "Disable the filesystem listener that automatically detects the creation of new keystore files", "boolean")
-	ConfigFileWalletSignerCacheSize              = ffc("config.fileWallet.signerCacheSize", "Maximum of signing keys to hold in memory", "number")
-	ConfigFileWalletSignerCacheTTL               = ffc("config.fileWallet.signerCacheTTL", "How long ot leave an unused signing key in memory", "duration")
-	ConfigFileWalletMetadataFormat               = ffc("config.fileWallet.metadata.format", "Set this if the primary key file is a metadata file. Supported formats: auto (from extension) / filename / toml / yaml / json (please quote \"0x...\" strings in YAML)", "string")
-	ConfigFileWalletMetadataKeyFileProperty      = ffc("config.fileWallet.metadata.keyFileProperty", "Go template to look up the key-file path from the metadata. Example: '{{ index .signing \"key-file\" }}'", "go-template")
-	ConfigFileWalletMetadataPasswordFileProperty = ffc("config.fileWallet.metadata.passwordFileProperty", "Go template to look up the password-file path from the metadata", "go-template")
+	ConfigFileWalletPriority                     = ffc("config.fileWallet.priority", "When wallets are also configured in the wallets array, the priority of this wallet if the same address is in more than one wallet. The wallet with the highest priority signs for the address", "int")
+	ConfigDevWalletEnabled                       = ffc("config.devWallet.enabled", "Whether the in-memory development wallet is enabled. Disable the fileWallet to use it instead of the filesystem wallet. The keys are generated from the seed, so must only be used for development and testing", "boolean")
+	ConfigDevWalletPriority                      = ffc("config.devWallet.priority", "When other wallets are also configured, the priority of this wallet if the same address is in more than one wallet. The wallet with the highest priority signs for the address", "int")
+	ConfigDevWalletSeed                          = ffc("config.global.devWallet.seed", "The seed the accounts are generated from. A BIP-39 mnemonic gives the same accounts as development chains such as Hardhat and Anvil, and any other string is hashed. The default is the Hardhat/Anvil mnemonic", "string")
+	ConfigDevWalletPath                          = ffc("config.global.devWallet.path", "The BIP-32 path of the parent of the accounts, which are generated with consecutive indexes under the path", "string")
+	ConfigDevWalletAccounts                      = ffc("config.global.devWallet.accounts", "The number of accounts to generate on startup", "int")
+	ConfigDevWalletBalance                       = ffc("config.global.devWallet.balance", "Optional balance in wei to pre-fund each account with on the development chain, returned in the balance property of the account metadata", "string")
+	ConfigFileWalletPath                         = ffc("config.global.fileWallet.path", "Path on the filesystem where the metadata files (and/or key files) are located", "string")
+	ConfigFileWalletFilenamesPrimaryBatchRegex   = ffc("config.global.fileWallet.filenames.primaryMatchRegex", "Regular expression run against key/metadata filenames to extract the address (takes precedence over primaryExt)", "regexp")
+	ConfigFileWalletFilenamesWith0xPrefix        = ffc("config.global.fileWallet.filenames.with0xPrefix", "When true and passwordExt is used, password filenames will be generated with an 0x prefix", "boolean")
+	ConfigFileWalletFilenamesPrimaryExt          = ffc("config.global.fileWallet.filenames.primaryExt", "Extension for key/metadata files named by <ADDRESS>.<EXT>", "string")
+	ConfigFileWalletFilenamesPasswordExt         = ffc("config.global.fileWallet.filenames.passwordExt", "Optional to use to look up password files, that sit next to the key files directly. Alternative to metadata when you have a password per keystore", "string")
+	ConfigFileWalletFilenamesPasswordPath        = ffc("config.global.fileWallet.filenames.passwordPath", "Optional directory in which to look for the password files, when passwordExt is configured. Default is the wallet directory", "string")
+	ConfigFileWalletFilenamesPasswordTrimSpace   = ffc("config.global.fileWallet.filenames.passwordTrimSpace", "Whether to trim leading/trailing whitespace (such as a newline) from the password when loaded from file", "boolean")
+	ConfigFileWalletDefaultPasswordFile          = ffc("config.global.fileWallet.defaultPasswordFile", "Optional default password file to use, if one is not specified individually for the key (via metadata, or file extension)", "string")
+	ConfigFileWalletDisableListener              = ffc("config.global.fileWallet.disableListener", "Disable the filesystem listener that automatically detects the creation of new keystore files", "boolean")
+	ConfigFileWalletSignerCacheSize              = ffc("config.global.fileWallet.signerCacheSize", "Maximum of signing keys to hold in memory", "number")
+	ConfigFileWalletSignerCacheTTL               = ffc("config.global.fileWallet.signerCacheTTL", "How long ot leave an unused signing key in memory", "duration")
+	ConfigFileWalletMetadataFormat               = ffc("config.global.fileWallet.metadata.format", "Set this if the primary key file is a metadata file. Supported formats: auto (from extension) / filename / toml / yaml / json (please quote \"0x...\" strings in YAML)", "string")
+	ConfigFileWalletMetadataKeyFileProperty      = ffc("config.global.fileWallet.metadata.keyFileProperty", "Go template to look up the key-file path from the metadata. Example: '{{ index .signing \"key-file\" }}'", "go-template")
+	ConfigFileWalletMetadataPasswordFileProperty = ffc("config.global.fileWallet.metadata.passwordFileProperty", "Go template to look up the password-file path from the metadata", "go-template")
 
 	ConfigServerAddress      = ffc("config.server.address", "Local address for the JSON/RPC server to listen on", "string")
 	ConfigServerPort         = ffc("config.server.port", "Port for the JSON/RPC server to listen on", "number")
@@ -50,7 +57,123 @@ var (
 	ConfigServerWriteTimeout = ffc("config.server.writeTimeout", "The maximum time to wait when writing to a HTTP connection", "duration")
 	ConfigAPIShutdownTimeout = ffc("config.server.shutdownTimeout", "The maximum amount of time to wait for any open HTTP requests to finish before shutting down the HTTP server", i18n.TimeDurationType)
 
-	ConfigBackendChainID  = ffc("config.backend.chainId", "Optionally set the Chain ID of the blockchain. Otherwise the Network ID will be queried, and used as the Chain ID in signing", "number")
-	ConfigBackendURL      = ffc("config.backend.url", "URL for the backend JSON/RPC server / blockchain node", "url")
-	ConfigBackendProxyURL = ffc("config.backend.proxy.url", "Optional HTTP proxy URL", "url")
+	ConfigBackendChainID                = ffc("config.global.backend.chainId", "Optionally set the Chain ID of the blockchain. Otherwise the Network ID will be queried, and used as the Chain ID in signing", "number")
+	ConfigBackendTransport              = ffc("config.global.backend.transport", "The transport used for all requests to the backend: http, or websocket. With websocket a single persistent connection is used, configured in the backend.ws section", "string")
+	ConfigBackendURLs                   = ffc("config.global.backend.urls", "Optional list of HTTP URLs for multiple backend nodes, used instead of url. Requests go to healthy nodes, and fail over to the next node on a connection error or 5xx response. Other HTTP settings are shared. WebSocket subscriptions connect to ws.url if set, or otherwise the first of the URLs", "string[]")
+	ConfigBackendStrategy               = ffc("config.global.backend.strategy", "When multiple urls are configured, whether to use healthy nodes in priority order (priority) or spread requests across them (roundRobin)", "string")
+	ConfigBackendSenderPinTTL           = ffc("config.global.backend.senderPinTTL", "When multiple urls are configured, eth_sendRawTransaction requests for each sender go to the same node until this long after the last one", i18n.TimeDurationType)
+	ConfigBackendHealthCheckInterval    = ffc("config.global.backend.healthCheck.interval", "When multiple urls are configured, how often to check each node with eth_blockNumber", i18n.TimeDurationType)
+	ConfigBackendHealthCheckTimeout     = ffc("config.global.backend.healthCheck.timeout", "The timeout for each health check", i18n.TimeDurationType)
+	ConfigBackendHealthCheckMaxBlockLag = ffc("config.global.backend.healthCheck.maxBlockLag", "The number of blocks a node can be behind the highest node before it is unhealthy. Zero disables lag detection", "int")
+	ConfigBackendURL                    = ffc("config.global.backend.url", "URL for the backend JSON/RPC server / blockchain node", "url")
+	ConfigBackendProxyURL               = ffc("config.global.backend.proxy.url", "Optional HTTP proxy URL", "url")
+
+	ConfigNonceManagerCacheEnabled = ffc("config.global.nonceManager.cacheEnabled", "Whether to cache the next nonce for each address in memory, after querying the pending transaction count from the chain. When disabled, the chain is queried for every transaction", "boolean")
+	ConfigNonceManagerJournalFile  = ffc("config.global.nonceManager.journalFile", "Optional file in which to record the next nonce for each address, so that nonces assigned before a restart are not re-used", "string")
+
+	ConfigGasAutoPopulate          = ffc("config.global.gas.autoPopulate", "Whether to fill in the gas limit and fees of transactions that do not include them, before signing", "boolean")
+	ConfigGasEstimateMultiplier    = ffc("config.global.gas.estimateMultiplier", "The factor to multiply the result of eth_estimateGas by, to calculate the gas limit", "float")
+	ConfigGasFeeHistoryBlocks      = ffc("config.global.gas.feeHistoryBlocks", "The number of recent blocks to query with eth_feeHistory, to calculate the EIP-1559 priority fee", "int")
+	ConfigGasPriorityFeePercentile = ffc("config.global.gas.priorityFeePercentile", "The percentile of priority fees paid in recent blocks to use for the EIP-1559 priority fee", "float")
+	ConfigGasMaxGasPrice           = ffc("config.global.gas.maxGasPrice", "Optional ceiling in wei for the calculated gasPrice of a legacy transaction", "string")
+	ConfigGasMaxFeePerGas          = ffc("config.global.gas.maxFeePerGas", "Optional ceiling in wei for the calculated maxFeePerGas of an EIP-1559 transaction", "string")
+	ConfigGasMaxPriorityFeePerGas  = ffc("config.global.gas.maxPriorityFeePerGas", "Optional ceiling in wei for the calculated maxPriorityFeePerGas of an EIP-1559 transaction", "string")
+
+	ConfigPolicyAllowedTo              = ffc("config.policy.allowedTo", "Optional list of addresses that transactions can be sent to. Contract deployments are rejected when set", "string[]")
+	ConfigPolicyMaxValue               = ffc("config.policy.maxValue", "Optional maximum value in wei for a transaction", "string")
+	ConfigPolicyMaxFeePerGas           = ffc("config.policy.maxFeePerGas", "Optional maximum gasPrice, or maxFeePerGas, in wei for a transaction", "string")
+	ConfigPolicyAllowedFunctions       = ffc("config.policy.allowedFunctions", "Optional list of destination contracts, each with a 'to' address and the ABI 'functions' that can be called on that contract", "object[]")
+	ConfigPolicyWalletMetadataProperty = ffc("config.policy.walletMetadataProperty", "Optional property in the wallet metadata file of each key, containing additional policy rules for that key. Uses the same structure as this policy section", "string")
+
+	ConfigAdminEnabled = ffc("config.admin.enabled", "Whether to serve the management REST API, with its OpenAPI documentation on /api, on a separate HTTP server configured in this section. When authentication is enabled, only identities in the auth section with 'admin' set can use it, and approval decisions are recorded against the identity. When authentication is disabled, admin.address must be a loopback address", "boolean")
+
+	ConfigAuthEnabled          = ffc("config.auth.enabled", "Whether clients must authenticate with an API key, JWT bearer token, or TLS client certificate. Each client can only use the accounts of its identity", "boolean")
+	ConfigAuthAPIKeyHeader     = ffc("config.auth.apiKeyHeader", "The HTTP header containing an API key", "string")
+	ConfigAuthIdentities       = ffc("config.auth.identities", "List of client identities, each with a 'name', the 'addresses' of the accounts it can use, optional 'apiKeys', and 'admin' set to true if it can use the admin API", "object[]")
+	ConfigAuthJWTHMACSecret    = ffc("config.auth.jwt.hmacSecret", "Optional secret to verify JWT bearer tokens signed with HS256, HS384 or HS512", "string")
+	ConfigAuthJWTPublicKeyFile = ffc("config.auth.jwt.publicKeyFile", "Optional PEM file containing the RSA, ECDSA or Ed25519 public key (or certificate) to verify JWT bearer tokens", "string")
+	ConfigAuthJWTIssuer        = ffc("config.auth.jwt.issuer", "Optional issuer that JWT bearer tokens must have in the 'iss' claim", "string")
+	ConfigAuthJWTAudience      = ffc("config.auth.jwt.audience", "Optional audience that JWT bearer tokens must include in the 'aud' claim", "string")
+	ConfigAuthJWTRequireExpiry = ffc("config.auth.jwt.requireExpiry", "Whether JWT bearer tokens must have an 'exp' claim. Tokens without an expiry can be used for as long as the key is trusted", "boolean")
+	ConfigAuthJWTIdentityClaim = ffc("config.auth.jwt.identityClaim", "The claim in JWT bearer tokens containing the name of the identity", "string")
+	ConfigAuthMTLSEnabled      = ffc("config.auth.mtls.enabled", "Whether the common name of a verified TLS client certificate is used as the name of the identity. Requires server.tls.clientAuth", "boolean")
+
+	ConfigApprovalEnabled           = ffc("config.approval.enabled", "Whether eth_sendTransaction requests that match any of the approval criteria are held until they are approved or rejected through the admin API. Held requests return code -32006 with the approval request in the error data, and can be queried with ffsigner_getApproval", "boolean")
+	ConfigApprovalValueThreshold    = ffc("config.approval.valueThreshold", "Optional value in wei. Transactions with a value over this require approval", "string")
+	ConfigApprovalKnownDestinations = ffc("config.approval.knownDestinations", "Optional list of known destination addresses. When set, transactions to any other address, and contract deployments, require approval", "string[]")
+	ConfigApprovalSelectors         = ffc("config.approval.selectors", "Optional list of 4 byte function selectors, such as 0xa9059cbb, for the functions that require approval", "string[]")
+	ConfigApprovalTimeout           = ffc("config.approval.timeout", "How long a transaction is held for approval, before the request expires", i18n.TimeDurationType)
+	ConfigApprovalWait              = ffc("config.approval.wait", "Whether eth_sendTransaction waits for the approval decision and returns the result of submitting the transaction, rather than returning the approval request ID immediately", "boolean")
+	ConfigApprovalRetention         = ffc("config.approval.retention", "How long approval requests are kept in memory after they are decided or expire, so they can be queried", i18n.TimeDurationType)
+
+	ConfigAuditEnabled  = ffc("config.audit.enabled", "Whether to write a tamper-evident audit log entry for every transaction, message and typed data signed. Signing fails if the entry cannot be written", "boolean")
+	ConfigAuditFile     = ffc("config.audit.file", "The path of the audit log file. Each line is a JSON entry containing the SHA-256 hash of the previous entry. Check it with 'ffsigner audit verify'", "string")
+	ConfigAuditMaxSize  = ffc("config.audit.maxSize", "The size at which the audit log file is rotated, by adding a .1 suffix to its name and renaming older rotated files to the next number up", i18n.ByteSizeType)
+	ConfigAuditMaxFiles = ffc("config.audit.maxFiles", "The number of rotated audit log files to keep. Zero keeps all of them, so the whole chain can be verified", "int")
+
+	ConfigHealthTimeout = ffc("config.health.timeout", "The maximum time for the checks of the wallet and each backend made by a readiness or status request. Set the timeout of the readiness probe to more than this", i18n.TimeDurationType)
+
+	ConfigLimitsAddressRate    = ffc("config.limits.address.rate", "Optional maximum number of signing operations per second for each address, across all chains. Requests over the limit are rejected with code -32005", "float")
+	ConfigLimitsAddressBurst   = ffc("config.limits.address.burst", "The number of signing operations an address can make at once, before the rate limit applies", "int")
+	ConfigLimitsIdentityRate   = ffc("config.limits.identity.rate", "Optional maximum number of signing operations per second for each client identity, when authentication is enabled. Requests over the limit are rejected with code -32005", "float")
+	ConfigLimitsIdentityBurst  = ffc("config.limits.identity.burst", "The number of signing operations an identity can make at once, before the rate limit applies", "int")
+	ConfigLimitsQuotaWindow    = ffc("config.limits.quota.window", "The rolling window that the spend quotas apply to", i18n.TimeDurationType)
+	ConfigLimitsQuotaMaxValue  = ffc("config.limits.quota.maxValue", "Optional maximum total value in wei of the transactions signed for each address on each chain in the window", "string")
+	ConfigLimitsQuotaMaxFee    = ffc("config.limits.quota.maxFee", "Optional maximum total fee in wei of the transactions signed for each address on each chain in the window. The fee of each transaction is its gas limit multiplied by its maxFeePerGas, or gasPrice", "string")
+	ConfigLimitsQuotaStateFile = ffc("config.limits.quota.stateFile", "Optional file to record the transactions counted against the spend quotas, so the quotas are not reset by a restart", "string")
+
+	ConfigMetricsEnabled = ffc("config.metrics.enabled", "Whether to serve Prometheus metrics for JSON/RPC requests, signing, backend requests and the wallet on a separate HTTP server", "boolean")
+	ConfigMetricsPath    = ffc("config.metrics.path", "The path on the metrics server to serve the metrics on", "string")
+
+	ConfigChainsName = ffc("config.chains[].name", "The name of the chain, which is served on the /chains/{name} path. Each chain has its own backend, gas and nonceManager sections, and shares the wallet and policy", "string")
+
+	ConfigWalletsName     = ffc("config.wallets[].name", "The name of the wallet, which must be unique across the wallets. The fileWallet and devWallet sections are named fileWallet and devWallet", "string")
+	ConfigWalletsType     = ffc("config.wallets[].type", "The type of the wallet, which is configured in the section of the same name: fileWallet, hdWallet, devWallet, hsmWallet, kmsWallet", "string")
+	ConfigWalletsPriority = ffc("config.wallets[].priority", "If the same address is in more than one wallet, the wallet with the highest priority signs for the address. Wallets with the same priority are used in the order they are configured, after the fileWallet and devWallet sections", "int")
+
+	ConfigWalletsHDWalletSeedFile          = ffc("config.wallets[].hdWallet.seedFile", "Keystore V3 file containing the encrypted BIP-39 seed, or BIP-32 extended private key, that all the accounts are derived from. Create it with the ffsigner hdwallet create command", "string")
+	ConfigWalletsHDWalletPasswordFile      = ffc("config.wallets[].hdWallet.passwordFile", "File containing the password to decrypt the seed file", "string")
+	ConfigWalletsHDWalletPasswordTrimSpace = ffc("config.wallets[].hdWallet.passwordTrimSpace", "Whether to trim leading/trailing whitespace (such as a newline) from the password when loaded from file", "boolean")
+	ConfigWalletsHDWalletPath              = ffc("config.wallets[].hdWallet.path", "The BIP-32 path of the parent of the range of accounts. The default is the BIP-44 path for Ethereum", "string")
+	ConfigWalletsHDWalletAccountsStart     = ffc("config.wallets[].hdWallet.accounts.start", "The index under the path of the first account", "int")
+	ConfigWalletsHDWalletAccountsCount     = ffc("config.wallets[].hdWallet.accounts.count", "The number of accounts to derive, with consecutive indexes under the path", "int")
+	ConfigWalletsHDWalletPaths             = ffc("config.wallets[].hdWallet.paths", "Optional list of the full BIP-32 paths of the accounts, such as m/44'/60'/1'/0/0, used instead of the range of accounts", "string[]")
+
+	ConfigWalletsHSMWalletLibrary    = ffc("config.wallets[].hsmWallet.library", "Path to the PKCS#11 module shared library of the HSM, such as /usr/lib/softhsm/libsofthsm2.so. Requires a build with the pkcs11 tag", "string")
+	ConfigWalletsHSMWalletTokenLabel = ffc("config.wallets[].hsmWallet.tokenLabel", "The label of the token containing the keys", "string")
+	ConfigWalletsHSMWalletPINFile    = ffc("config.wallets[].hsmWallet.pinFile", "File containing the user PIN to log in to the token. Leading/trailing whitespace is trimmed", "string")
+	ConfigWalletsHSMWalletKeysLabels = ffc("config.wallets[].hsmWallet.keys.labels", "Optional list of the labels of the secp256k1 keys to use. When no labels or IDs are set, all the secp256k1 keys on the token are used", "string[]")
+	ConfigWalletsHSMWalletKeysIDs    = ffc("config.wallets[].hsmWallet.keys.ids", "Optional list of the hex IDs (CKA_ID) of the secp256k1 keys to use", "string[]")
+
+	ConfigWalletsKMSWalletURL                            = ffc("config.wallets[].kmsWallet.url", "URL of the KMS API. The default is the AWS KMS endpoint of the region", "url")
+	ConfigWalletsKMSWalletRegion                         = ffc("config.wallets[].kmsWallet.region", "The region of the KMS, used to sign the requests", "string")
+	ConfigWalletsKMSWalletCredentialsAccessKeyID         = ffc("config.wallets[].kmsWallet.credentials.accessKeyId", "The access key ID to sign the requests with. When not set, the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables are used", "string")
+	ConfigWalletsKMSWalletCredentialsSecretAccessKeyFile = ffc("config.wallets[].kmsWallet.credentials.secretAccessKeyFile", "File containing the secret access key. Leading/trailing whitespace is trimmed", "string")
+	ConfigWalletsKMSWalletCredentialsSessionTokenFile    = ffc("config.wallets[].kmsWallet.credentials.sessionTokenFile", "Optional file containing the session token of temporary credentials", "string")
+	ConfigWalletsKMSWalletKeysIDs                        = ffc("config.wallets[].kmsWallet.keys.ids", "Optional list of the key IDs, ARNs or alias names of the ECC_SECG_P256K1 keys to use", "string[]")
+	ConfigWalletsKMSWalletKeysAliases                    = ffc("config.wallets[].kmsWallet.keys.aliases", "Optional list of the alias names of the keys to use, with or without the alias/ prefix", "string[]")
+	ConfigWalletsKMSWalletKeysAliasPrefix                = ffc("config.wallets[].kmsWallet.keys.aliasPrefix", "Optional prefix of the alias names of the keys to use. Keys that are not ECC_SECG_P256K1 keys are ignored", "string")
+	ConfigWalletsKMSWalletKeysTagKey                     = ffc("config.wallets[].kmsWallet.keys.tag.key", "Optional tag key of the keys to use. Keys that are not ECC_SECG_P256K1 keys are ignored", "string")
+	ConfigWalletsKMSWalletKeysTagValue                   = ffc("config.wallets[].kmsWallet.keys.tag.value", "The value of the tag of the keys to use. When not set, any key with the tag is used", "string")
+
+	ConfigRawTransactionsValidate         = ffc("config.rawTransactions.validate", "Whether to decode each eth_sendRawTransaction payload and recover the sender before passing it to the backend. Payloads that are malformed, or signed for a different chain ID, are rejected", "boolean")
+	ConfigRawTransactionsAllowUnprotected = ffc("config.rawTransactions.allowUnprotected", "Whether to accept legacy raw transactions signed without a chain ID (before EIP-155), which could be replayed on any chain", "boolean")
+	ConfigRawTransactionsPolicy           = ffc("config.rawTransactions.policy", "Whether to apply the allowedTo, maxValue, maxFeePerGas and allowedFunctions rules of the policy section to raw transactions. Rules from the wallet metadata are not applied, as the sender does not need to be in the wallet", "boolean")
+
+	ConfigSimulationEnabled = ffc("config.simulation.enabled", "Whether to simulate each transaction with eth_call before signing it. Transactions that revert are rejected with code 3, and the decoded revert reason, without using a nonce", "boolean")
+	ConfigSimulationBlock   = ffc("config.simulation.block", "The block tag, or hex block number, to simulate transactions against", "string")
+	ConfigSimulationErrors  = ffc("config.simulation.errors", "Optional ABI containing the custom errors to decode from the revert data of a simulation. Entries that are not errors are ignored. Error(string) and Panic(uint256) are always decoded", "object[]")
+
+	ConfigTrackingEnabled       = ffc("config.tracking.enabled", "Whether to keep a record of each transaction signed and submitted by eth_sendTransaction, and poll for its receipt. The status can be queried with ffsigner_getTransaction, or the admin API", "boolean")
+	ConfigTrackingConfirmations = ffc("config.tracking.confirmations", "The number of blocks after the block containing the transaction, before the transaction is confirmed or failed", "int")
+	ConfigTrackingPollInterval  = ffc("config.tracking.pollInterval", "How often to poll for the receipts of the tracked transactions", i18n.TimeDurationType)
+	ConfigTrackingRetention     = ffc("config.tracking.retention", "How long to keep the record of a transaction after it is confirmed, failed or dropped", i18n.TimeDurationType)
+	ConfigTrackingStateFile     = ffc("config.tracking.stateFile", "Optional file to record the tracked transactions in, so they continue to be tracked after a restart", "string")
+	ConfigTrackingResubmit      = ffc("config.tracking.resubmit", "Whether to resubmit the signed transaction if the node no longer knows about it, because it was dropped from the mempool", "boolean")
+	ConfigTrackingMaxResubmits  = ffc("config.tracking.maxResubmits", "The maximum number of times to resubmit a transaction, before it is reported as dropped", "int")
+
+	ConfigWebSocketEnabled         = ffc("config.websocket.enabled", "Whether to accept WebSocket connections on the JSON/RPC server path. Subscriptions made with eth_subscribe are proxied to the backend over a WebSocket connection, configured in the backend.ws section", "boolean")
+	ConfigWebSocketWriteTimeout    = ffc("config.websocket.writeTimeout", "The maximum time to wait when sending a message to a WebSocket client", i18n.TimeDurationType)
+	ConfigWebSocketReadBufferSize  = ffc("config.websocket.readBufferSize", "The read buffer size for WebSocket client connections", i18n.ByteSizeType)
+	ConfigWebSocketWriteBufferSize = ffc("config.websocket.writeBufferSize", "The write buffer size for WebSocket client connections", i18n.ByteSizeType)
 )
@@ -108,4 +108,115 @@ var (
 	MsgInvalidUint64PrecisionLoss  = ffe("FF22090", "String %s cannot be converted to a uint64 without losing precision")
 	MsgInvalidJSONTypeForBigInt    = ffe("FF22091", "JSON parsed '%T' cannot be converted to an integer")
 	MsgHexUintNegative             = ffe("FF22092", "Cannot convert negative integer %d to unsigned")
+	MsgRecoverSignatureFailed      = ffe("FF22093", "Failed to recover signer from signature: %s")
+	MsgWalletNotSupported          = ffe("FF22094", "The configured wallet does not support %s")
+	MsgInvalidMessageData          = ffe("FF22095", "Invalid message data for %s")
+	MsgNonceJournalReadFailed      = ffe("FF22096", "Failed to read nonce journal file '%s'")
+	MsgNonceLockCanceled           = ffe("FF22097", "Context canceled waiting to assign nonce for address '%s'")
+	MsgGasEstimateFailed           = ffe("FF22098", "Failed to estimate gas for transaction: %s")
+	MsgFeeQueryFailed              = ffe("FF22099", "Failed to query %s to calculate transaction fee: %s")
+	MsgInvalidFeeCeiling           = ffe("FF22100", "Invalid fee ceiling '%s' for '%s'")
+	MsgInvalidSigningPolicy        = ffe("FF22101", "Invalid signing policy in %s: %v")
+	MsgPolicyDeployNotAllowed      = ffe("FF22102", "Transaction rejected by signing policy rule '%s' from %s: contract deployment is not allowed")
+	MsgPolicyDestinationNotAllowed = ffe("FF22103", "Transaction rejected by signing policy rule '%s' from %s: destination %s is not allowed")
+	MsgPolicyValueExceeded         = ffe("FF22104", "Transaction rejected by signing policy rule '%s' from %s: value %s exceeds the maximum %s")
+	MsgPolicyFeeExceeded           = ffe("FF22105", "Transaction rejected by signing policy rule '%s' from %s: fee per gas %s exceeds the maximum %s")
+	MsgPolicyFunctionNotAllowed    = ffe("FF22106", "Transaction rejected by signing policy rule '%s' from %s: function selector %s is not allowed for destination %s")
+	MsgWebSocketClosed             = ffe("FF22107", "WebSocket connection closed")
+	MsgInvalidBackendTransport     = ffe("FF22108", "Invalid backend transport '%s' - must be 'http' or 'websocket'")
+	MsgNoBackendEndpoints          = ffe("FF22109", "At least one backend endpoint must be configured")
+	MsgInvalidEndpointStrategy     = ffe("FF22110", "Invalid backend endpoint strategy '%s' - must be 'priority' or 'roundRobin'")
+	MsgEndpointBlockLag            = ffe("FF22111", "Endpoint at block %d is lagging behind the highest block %d")
+	MsgMultiEndpointTransport      = ffe("FF22112", "Multiple backend URLs are only supported with the 'http' transport")
+	MsgInvalidChainName            = ffe("FF22113", "Invalid name for chain %d")
+	MsgDuplicateChainName          = ffe("FF22114", "Duplicate chain name '%s'")
+	MsgChainStartFailed            = ffe("FF22115", "Failed to start chain '%s'")
+	MsgAuthRequired                = ffe("FF22116", "Authentication required", 401)
+	MsgAuthInvalidAPIKey           = ffe("FF22117", "Invalid API key", 401)
+	MsgAuthUnknownIdentity         = ffe("FF22118", "Identity '%s' is not configured", 403)
+	MsgAccountNotAuthorized        = ffe("FF22119", "Identity '%s' is not authorized to use account %s", 403)
+	MsgInvalidAuthIdentities       = ffe("FF22120", "Invalid auth identities")
+	MsgDuplicateAuthIdentity       = ffe("FF22121", "Duplicate auth identity '%s'")
+	MsgDuplicateAPIKey             = ffe("FF22122", "Duplicate API key for identity '%s'")
+	MsgInvalidJWTPublicKey         = ffe("FF22123", "Invalid JWT public key file '%s'")
+	MsgJWTMalformed                = ffe("FF22124", "Malformed JWT", 401)
+	MsgJWTUnsupportedAlgorithm     = ffe("FF22125", "Unsupported JWT algorithm '%s'", 401)
+	MsgJWTInvalidSignature         = ffe("FF22126", "Invalid JWT signature", 401)
+	MsgJWTExpired                  = ffe("FF22127", "JWT has expired", 401)
+	MsgJWTNotYetValid              = ffe("FF22128", "JWT is not valid yet", 401)
+	MsgJWTInvalidIssuer            = ffe("FF22129", "JWT issuer '%s' does not match the configured issuer", 401)
+	MsgJWTInvalidAudience          = ffe("FF22130", "JWT audience does not include '%s'", 401)
+	MsgJWTMissingIdentity          = ffe("FF22131", "JWT does not contain the '%s' identity claim", 401)
+	MsgAuditLogFileRequired        = ffe("FF22132", "The audit log file must be set when the audit log is enabled")
+	MsgAuditLogOpenFailed          = ffe("FF22133", "Failed to read audit log '%s'")
+	MsgAuditLogWriteFailed         = ffe("FF22134", "Failed to write audit log '%s'")
+	MsgAuditLogInvalidEntry        = ffe("FF22135", "Invalid audit log entry on line %d of '%s'")
+	MsgAuditLogHashMismatch        = ffe("FF22136", "Audit log entry %d on line %d of '%s' has been modified - the hash does not match the content")
+	MsgAuditLogChainBroken         = ffe("FF22137", "Audit log entry %d on line %d of '%s' does not follow the previous entry - entries have been removed, inserted or re-ordered")
+	MsgAuditLogNoEntries           = ffe("FF22138", "No audit log entries found in '%s'")
+	MsgServerNotReady              = ffe("FF22139", "The server is starting, and is not ready to process requests", 503)
+	MsgWalletListenerStopped       = ffe("FF22140", "The filesystem listener that detects new keys has stopped")
+	MsgInvalidAccountAddress       = ffe("FF22141", "Invalid account address '%s'", 400)
+	MsgRateLimitExceeded           = ffe("FF22142", "Signing rate limit exceeded for %s '%s'", 429)
+	MsgQuotaExceeded               = ffe("FF22143", "Signing quota '%s' exceeded for '%s' on chain '%s': %s wei signed in the last %s, plus %s wei requested, is more than the limit of %s wei", 429)
+	MsgInvalidQuotaLimit           = ffe("FF22144", "Invalid spend quota '%s': %s")
+	MsgQuotaStateReadFailed        = ffe("FF22145", "Failed to read spend quota state file '%s'")
+	MsgInvalidSimulationErrors     = ffe("FF22146", "Invalid simulation error ABI: %s")
+	MsgSimulationReverted          = ffe("FF22147", "Transaction reverted in simulation: %s")
+	MsgSimulationFailed            = ffe("FF22148", "Transaction simulation failed: %s")
+	MsgInvalidApprovalConfig       = ffe("FF22149", "Invalid approval %s '%s'")
+	MsgApprovalPending             = ffe("FF22150", "Transaction requires approval - approval request '%s' is pending")
+	MsgApprovalNotFound            = ffe("FF22151", "Approval request '%s' not found", 404)
+	MsgApprovalNotPending          = ffe("FF22152", "Approval request '%s' is %s, not pending", 409)
+	MsgApprovalDeclined            = ffe("FF22153", "Approval request '%s' was %s")
+	MsgApprovalsNotEnabled         = ffe("FF22154", "Transaction approvals are not enabled", 404)
+	MsgInvalidApprovalID           = ffe("FF22155", "Invalid approval request ID '%s'", 400)
+	MsgInvalidRawTransaction       = ffe("FF22156", "Invalid raw transaction")
+	MsgUnprotectedRawTransaction   = ffe("FF22157", "Raw transaction from '%s' does not include a chain ID (EIP-155), and is not accepted for chain %d")
+	MsgTrackingNotEnabled          = ffe("FF22158", "Transaction tracking is not enabled", 404)
+	MsgTrackedTransactionNotFound  = ffe("FF22159", "Transaction '%s' not found", 404)
+	MsgInvalidTransactionHash      = ffe("FF22160", "Invalid transaction hash '%s'", 400)
+	MsgTransactionHashMismatch     = ffe("FF22161", "Node returned transaction hash '%s' for transaction '%s'")
+	MsgTrackingStateReadFailed     = ffe("FF22162", "Failed to read transaction tracking state file '%s'")
+	MsgInvalidWalletName           = ffe("FF22163", "Invalid name for wallet %d")
+	MsgDuplicateWalletName         = ffe("FF22164", "Duplicate wallet name '%s'")
+	MsgChildWalletFailed           = ffe("FF22165", "Wallet '%s' failed")
+	MsgInvalidWalletType           = ffe("FF22166", "Invalid type '%s' for wallet '%s'")
+	MsgInvalidMnemonic             = ffe("FF22167", "Invalid BIP-39 mnemonic: %s")
+	MsgInvalidEntropyLength        = ffe("FF22168", "Invalid BIP-39 entropy length %d - must be 16-32 bytes, in multiples of 4")
+	MsgInvalidExtendedKey          = ffe("FF22169", "Invalid BIP-32 extended private key: %s")
+	MsgInvalidSeedLength           = ffe("FF22170", "Invalid BIP-32 seed length %d - must be 16-64 bytes")
+	MsgInvalidDerivationPath       = ffe("FF22171", "Invalid BIP-32 derivation path '%s'")
+	MsgInvalidDerivedKey           = ffe("FF22172", "BIP-32 derivation of '%s' produced an invalid key - use a different index")
+	MsgSeedFileFailed              = ffe("FF22173", "Failed to read HD wallet seed file '%s'")
+	MsgNoHDWalletAccounts          = ffe("FF22174", "No accounts configured for the HD wallet - set a count, or a list of paths")
+	MsgHDWalletSecretConflict      = ffe("FF22175", "Only one of a mnemonic, or an extended private key, can be used to create a seed file")
+	MsgInvalidDevWalletConfig      = ffe("FF22176", "Invalid dev wallet %s '%v'")
+	MsgInvalidECDSASignature       = ffe("FF22177", "Invalid ECDSA signature from external signer: %s")
+	MsgSignatureRecoveryFailed     = ffe("FF22178", "Signature from external signer does not recover to the address %s")
+	MsgHSMNotSupported             = ffe("FF22179", "PKCS#11 support is not included in this build - build with the pkcs11 tag")
+	MsgHSMLibraryLoadFailed        = ffe("FF22180", "Failed to load PKCS#11 library '%s': %s")
+	MsgHSMOperationFailed          = ffe("FF22181", "PKCS#11 %s failed with error 0x%x")
+	MsgHSMTokenNotFound            = ffe("FF22182", "PKCS#11 token with label '%s' not found")
+	MsgHSMPINFileFailed            = ffe("FF22183", "Failed to read PKCS#11 PIN file '%s'")
+	MsgHSMInvalidKeyID             = ffe("FF22184", "Invalid HSM key ID '%s' - must be hex")
+	MsgHSMInvalidPublicKey         = ffe("FF22185", "Invalid secp256k1 public key for HSM key %s: %s")
+	MsgHSMKeyNotFound              = ffe("FF22186", "No secp256k1 key pair found in the HSM for %s")
+	MsgKMSRequestFailed            = ffe("FF22187", "KMS %s request failed: %s")
+	MsgKMSInvalidPublicKey         = ffe("FF22188", "Invalid secp256k1 public key for KMS key %s: %s")
+	MsgKMSAliasNotFound            = ffe("FF22189", "KMS alias '%s' not found")
+	MsgKMSNoKeysConfigured         = ffe("FF22190", "No KMS keys configured for the KMS wallet - set key IDs, aliases, an alias prefix or a tag")
+	MsgKMSMissingConfig            = ffe("FF22191", "KMS wallet %s not configured")
+	MsgKMSCredentialsFileFailed    = ffe("FF22192", "Failed to read KMS credentials file '%s'")
+	MsgPriorityFeeExceedsMaxFee    = ffe("FF22193", "maxPriorityFeePerGas %s exceeds the maxFeePerGas ceiling %s")
+	MsgAdminNotAuthorized          = ffe("FF22194", "Identity '%s' is not authorized to use the admin API", 403)
+	MsgAdminRequiresAuth           = ffe("FF22195", "The admin API can only listen on a loopback address when authentication is disabled - admin.address is '%s'")
+	MsgApprovalSelfApproval        = ffe("FF22196", "Identity '%s' requested the transaction in approval request '%s', so cannot approve it", 403)
+	MsgApprovalRequiresSend        = ffe("FF22197", "Transaction requires approval, so must be submitted with eth_sendTransaction - it matches the criteria %v")
+	MsgInvalidTransactionSignature = ffe("FF22198", "Invalid transaction signature: %s")
+	MsgHDWalletEntropyFailed       = ffe("FF22199", "Failed to generate entropy for a new mnemonic")
+	MsgJWTMissingExpiry            = ffe("FF22200", "JWT does not have an expiry in the 'exp' claim", 401)
+	MsgSeedFileExists              = ffe("FF22201", "Seed file '%s' already exists - it will not be overwritten")
+	MsgBackendChainIDMismatch      = ffe("FF22202", "Backend for chain '%s' returned chain ID %d, but the chain ID is %d")
+	MsgAuditLogPruned              = ffe("FF22203", "Audit log starts at entry %d in '%s', as older rotated files have been removed - the entries before it cannot be verified")
 )
@@ -48,8 +48,73 @@ var (
 	EIP712ResultR            = ffm("EIP712Result.r", "The R value of the ECDSA signature as a 32byte hex encoded array")
 	EIP712ResultS            = ffm("EIP712Result.s", "The S value of the ECDSA signature as a 32byte hex encoded array")
 
+	EIP191ResultHash         = ffm("EIP191Result.hash", "The keccak256 hash of the EIP-191 payload that was signed")
+	EIP191ResultSignatureRSV = ffm("EIP191Result.signatureRSV", "Hex encoded array of 65 bytes containing the R, S & V of the ECDSA signature, with a V value of 27 or 28. This is the format returned by personal_sign and eth_sign")
+	EIP191ResultV            = ffm("EIP191Result.v", "The V value of the ECDSA signature as a hex encoded integer")
+	EIP191ResultR            = ffm("EIP191Result.r", "The R value of the ECDSA signature as a 32byte hex encoded array")
+	EIP191ResultS            = ffm("EIP191Result.s", "The S value of the ECDSA signature as a 32byte hex encoded array")
+
 	TypedDataDomain      = ffm("TypedData.domain", "The data to encode into the EIP712Domain as part fo signing the transaction")
 	TypedDataMessage     = ffm("TypedData.message", "The data to encode into primaryType structure, with nested values for any sub-structures")
 	TypedDataTypes       = ffm("TypedData.types", "Array of types to use when encoding, which must include the primaryType and the EIP712Domain (noting the primary type can be EIP712Domain if the message is empty)")
 	TypedDataPrimaryType = ffm("TypedData.primaryType", "The primary type to begin encoding the EIP-712 hash from in the list of types, using the input message (unless set directly to EIP712Domain, in which case the message can be omitted)")
+
+	AdminAccountAddress  = ffm("AdminAccount.address", "The address of the account")
+	AdminAccountCached   = ffm("AdminAccount.cached", "Whether the key is currently decrypted in the signer cache. Omitted if the wallet does not cache keys")
+	AdminAccountMetadata = ffm("AdminAccount.metadata", "The metadata of the account, such as the content of the TOML/YAML/JSON descriptor file of the filesystem wallet")
+
+	ChainStatusName          = ffm("ChainStatus.name", "The name of the chain - 'default' for the chain served on the root path")
+	ChainStatusChainID       = ffm("ChainStatus.chainId", "The chain ID configured, or detected from the backend on startup")
+	ChainStatusHealthy       = ffm("ChainStatus.healthy", "Whether the backend answered the health check")
+	ChainStatusBlockNumber   = ffm("ChainStatus.blockNumber", "The current block number returned by the backend")
+	ChainStatusLastError     = ffm("ChainStatus.lastError", "The most recent error from a health check of the backend, which is still reported after it recovers")
+	ChainStatusLastErrorTime = ffm("ChainStatus.lastErrorTime", "The time of the most recent error")
+	ChainStatusEndpoints     = ffm("ChainStatus.endpoints", "The status of each endpoint, if the backend fails over between multiple URLs")
+
+	EndpointStatusURL         = ffm("EndpointStatus.url", "The URL of the endpoint")
+	EndpointStatusHealthy     = ffm("EndpointStatus.healthy", "Whether the endpoint passed its most recent health check")
+	EndpointStatusBlockNumber = ffm("EndpointStatus.blockNumber", "The block number returned by the most recent health check of the endpoint")
+	EndpointStatusLastError   = ffm("EndpointStatus.lastError", "The error from the most recent failed health check or request")
+
+	ApprovalID              = ffm("Approval.id", "The ID of the approval request")
+	ApprovalStatus          = ffm("Approval.status", "The status of the approval request - pending, approved while it is signed and submitted, submitted, failed, rejected or expired")
+	ApprovalChain           = ffm("Approval.chain", "The name of the chain the transaction is for")
+	ApprovalFrom            = ffm("Approval.from", "The address the transaction is signed by")
+	ApprovalTransaction     = ffm("Approval.transaction", "The transaction, with the gas and fees calculated when it was requested. The gas and fees are calculated again when it is approved")
+	ApprovalReasons         = ffm("Approval.reasons", "The approval criteria the transaction matched - value, destination or selector")
+	ApprovalIdentity        = ffm("Approval.identity", "The identity of the client that requested the transaction, when authentication is enabled")
+	ApprovalCreated         = ffm("Approval.created", "The time the transaction was requested")
+	ApprovalExpires         = ffm("Approval.expires", "The time the approval request expires, if it is not decided")
+	ApprovalDecided         = ffm("Approval.decided", "The time the approval request was approved, rejected, or expired")
+	ApprovalApprover        = ffm("Approval.approver", "The identity of the approver who made the decision")
+	ApprovalComment         = ffm("Approval.comment", "The comment supplied with the decision")
+	ApprovalTransactionHash = ffm("Approval.transactionHash", "The hash of the transaction, once it is submitted")
+	ApprovalError           = ffm("Approval.error", "The error if the transaction could not be signed or submitted after it was approved")
+
+	ApprovalDecisionApprover = ffm("ApprovalDecision.approver", "The name of the approver making the decision, when authentication is disabled. When authentication is enabled, the authenticated identity is always recorded as the approver")
+	ApprovalDecisionComment  = ffm("ApprovalDecision.comment", "An optional comment recorded with the decision")
+
+	TrackedTransactionHash          = ffm("TrackedTransaction.hash", "The hash of the transaction, calculated from the signed payload")
+	TrackedTransactionChain         = ffm("TrackedTransaction.chain", "The name of the chain the transaction was submitted to")
+	TrackedTransactionFrom          = ffm("TrackedTransaction.from", "The address the transaction is signed by")
+	TrackedTransactionNonce         = ffm("TrackedTransaction.nonce", "The nonce of the transaction")
+	TrackedTransactionTo            = ffm("TrackedTransaction.to", "The destination of the transaction, or empty for a contract deployment")
+	TrackedTransactionRaw           = ffm("TrackedTransaction.raw", "The signed transaction payload, which is resubmitted if the node drops the transaction")
+	TrackedTransactionStatus        = ffm("TrackedTransaction.status", "The status of the transaction - pending, mined while waiting for confirmations, confirmed, failed, or dropped")
+	TrackedTransactionSubmitted     = ffm("TrackedTransaction.submitted", "The time the transaction was submitted")
+	TrackedTransactionUpdated       = ffm("TrackedTransaction.updated", "The time the status of the transaction last changed")
+	TrackedTransactionBlockNumber   = ffm("TrackedTransaction.blockNumber", "The number of the block containing the transaction, once it is mined")
+	TrackedTransactionBlockHash     = ffm("TrackedTransaction.blockHash", "The hash of the block containing the transaction, once it is mined")
+	TrackedTransactionConfirmations = ffm("TrackedTransaction.confirmations", "The number of blocks after the block containing the transaction")
+	TrackedTransactionReceipt       = ffm("TrackedTransaction.receipt", "The receipt of the transaction, with the revert reason in errorMessage if it failed")
+	TrackedTransactionResubmits     = ffm("TrackedTransaction.resubmits", "The number of times the transaction was resubmitted, after the node dropped it")
+	TrackedTransactionError         = ffm("TrackedTransaction.error", "The last error submitting the transaction, or querying its status")
+
+	ReceiptExtraInfoContractAddress   = ffm("ReceiptExtraInfo.contractAddress", "The address of the contract deployed by the transaction, if any")
+	ReceiptExtraInfoCumulativeGasUsed = ffm("ReceiptExtraInfo.cumulativeGasUsed", "The total gas used in the block, up to and including this transaction")
+	ReceiptExtraInfoFrom              = ffm("ReceiptExtraInfo.from", "The address the transaction was sent from")
+	ReceiptExtraInfoTo                = ffm("ReceiptExtraInfo.to", "The destination of the transaction, or empty for a contract deployment")
+	ReceiptExtraInfoGasUsed           = ffm("ReceiptExtraInfo.gasUsed", "The gas used by the transaction")
+	ReceiptExtraInfoStatus            = ffm("ReceiptExtraInfo.status", "The status of the transaction - 1 for success, or 0 if it reverted")
+	ReceiptExtraInfoErrorMessage      = ffm("ReceiptExtraInfo.errorMessage", "The revert reason of a failed transaction")
 )
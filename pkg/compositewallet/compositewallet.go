@@ -0,0 +1,344 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compositewallet
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/eip712"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+)
+
+// Wallet combines a set of child wallets, such as multiple filesystem wallets and remote signers,
+// into a single wallet. The accounts are the union of the accounts of the children, and each
+// request is routed to the child that owns the address.
+type Wallet interface {
+	ethsigner.WalletTypedData
+	ethsigner.WalletEIP191
+	ethsigner.WalletMetadata
+	ethsigner.WalletHealth
+	ethsigner.WalletCache
+	ethsigner.WalletListener
+	// GetAccountWallet returns the name of the child wallet that owns the address
+	GetAccountWallet(ctx context.Context, addr ethtypes.Address0xHex) (string, error)
+}
+
+// Child is a wallet within the composite wallet. When the same address is in more than one
+// child, the child with the highest priority owns it. Children with the same priority are
+// preferred in the order they are supplied.
+type Child struct {
+	Name     string
+	Priority int
+	Wallet   ethsigner.Wallet
+}
+
+type compositeWallet struct {
+	children       []*Child // in priority order
+	listenerCancel context.CancelFunc
+	listenerDone   chan struct{}
+	childUpdates   chan ethtypes.Address0xHex
+	updateMux      sync.Mutex // only one update of the accounts at a time, so listeners are notified once
+
+	mux       sync.Mutex
+	owners    map[ethtypes.Address0xHex]*Child
+	accounts  []*ethtypes.Address0xHex // priority order of the children, then the order of each child
+	listeners []chan<- ethtypes.Address0xHex
+}
+
+func NewCompositeWallet(ctx context.Context, children []*Child, initialListeners ...chan<- ethtypes.Address0xHex) (Wallet, error) {
+	if len(children) == 0 {
+		return nil, i18n.NewError(ctx, signermsgs.MsgNoWalletEnabled)
+	}
+	names := make(map[string]bool)
+	for _, c := range children {
+		if names[c.Name] {
+			return nil, i18n.NewError(ctx, signermsgs.MsgDuplicateWalletName, c.Name)
+		}
+		names[c.Name] = true
+	}
+	w := &compositeWallet{
+		children:     make([]*Child, len(children)),
+		childUpdates: make(chan ethtypes.Address0xHex),
+		owners:       make(map[ethtypes.Address0xHex]*Child),
+		listeners:    initialListeners,
+	}
+	copy(w.children, children)
+	sort.SliceStable(w.children, func(i, j int) bool {
+		return w.children[i].Priority > w.children[j].Priority
+	})
+	return w, nil
+}
+
+// Initialize initializes each child in priority order, then builds the list of accounts.
+// Children that notify listeners of new accounts are watched, so accounts they detect later
+// are added without a refresh.
+func (w *compositeWallet) Initialize(ctx context.Context) error {
+	lCtx, lCancel := context.WithCancel(log.WithLogField(ctx, "wallet", "composite"))
+	w.listenerCancel = lCancel
+	w.listenerDone = make(chan struct{})
+	go w.childListener(lCtx)
+	for _, c := range w.children {
+		if lw, ok := c.Wallet.(ethsigner.WalletListener); ok {
+			lw.AddListener(w.childUpdates)
+		}
+		if err := c.Wallet.Initialize(ctx); err != nil {
+			return i18n.WrapError(ctx, err, signermsgs.MsgChildWalletFailed, c.Name)
+		}
+	}
+	_, err := w.GetAccounts(ctx)
+	return err
+}
+
+func (w *compositeWallet) childListener(ctx context.Context) {
+	defer close(w.listenerDone)
+	for {
+		select {
+		case <-ctx.Done():
+			log.L(ctx).Debugf("Composite wallet listener stopped")
+			return
+		case addr := <-w.childUpdates:
+			log.L(ctx).Debugf("Child wallet detected account %s", addr)
+			if _, err := w.GetAccounts(ctx); err != nil {
+				log.L(ctx).Errorf("Failed to update accounts after %s was detected: %s", addr, err)
+			}
+		}
+	}
+}
+
+// AddListener registers a listener that is notified of each account the first time it is
+// found in any of the children
+func (w *compositeWallet) AddListener(listener chan<- ethtypes.Address0xHex) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.listeners = append(w.listeners, listener)
+}
+
+// GetAccounts returns the union of the accounts of the children, with each address listed once
+func (w *compositeWallet) GetAccounts(ctx context.Context) ([]*ethtypes.Address0xHex, error) {
+	w.updateMux.Lock()
+	defer w.updateMux.Unlock()
+
+	owners := make(map[ethtypes.Address0xHex]*Child)
+	accounts := make([]*ethtypes.Address0xHex, 0)
+	for _, c := range w.children {
+		childAccounts, err := c.Wallet.GetAccounts(ctx)
+		if err != nil {
+			return nil, i18n.WrapError(ctx, err, signermsgs.MsgChildWalletFailed, c.Name)
+		}
+		for _, addr := range childAccounts {
+			if owner, exists := owners[*addr]; exists {
+				log.L(ctx).Tracef("Address %s is in wallet '%s' and wallet '%s' - using '%s'", addr, owner.Name, c.Name, owner.Name)
+				continue
+			}
+			owners[*addr] = c
+			accounts = append(accounts, addr)
+		}
+	}
+
+	w.mux.Lock()
+	newAccounts := make([]ethtypes.Address0xHex, 0)
+	for _, addr := range accounts {
+		if _, existing := w.owners[*addr]; !existing {
+			log.L(ctx).Debugf("Added address: %s (wallet=%s)", addr, owners[*addr].Name)
+			newAccounts = append(newAccounts, *addr)
+		}
+	}
+	w.owners = owners
+	w.accounts = accounts
+	listeners := make([]chan<- ethtypes.Address0xHex, len(w.listeners))
+	copy(listeners, w.listeners)
+	w.mux.Unlock()
+
+	if len(newAccounts) > 0 && len(listeners) > 0 {
+		// Avoid any blocking of this routine using a separate go-routine that will deliver async callbacks
+		go func() {
+			for _, l := range listeners {
+				for _, addr := range newAccounts {
+					l <- addr
+				}
+			}
+		}()
+	}
+
+	result := make([]*ethtypes.Address0xHex, len(accounts))
+	copy(result, accounts)
+	return result, nil
+}
+
+// Refresh refreshes each child, then updates the list of accounts
+func (w *compositeWallet) Refresh(ctx context.Context) error {
+	for _, c := range w.children {
+		if err := c.Wallet.Refresh(ctx); err != nil {
+			return i18n.WrapError(ctx, err, signermsgs.MsgChildWalletFailed, c.Name)
+		}
+	}
+	_, err := w.GetAccounts(ctx)
+	return err
+}
+
+// Close closes all the children, returning the first error
+func (w *compositeWallet) Close() (err error) {
+	if w.listenerCancel != nil {
+		w.listenerCancel()
+		<-w.listenerDone
+	}
+	for _, c := range w.children {
+		if closeErr := c.Wallet.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// CheckHealth returns the error of the first child that reports it is unhealthy
+func (w *compositeWallet) CheckHealth(ctx context.Context) error {
+	for _, c := range w.children {
+		if hw, ok := c.Wallet.(ethsigner.WalletHealth); ok {
+			if err := hw.CheckHealth(ctx); err != nil {
+				return i18n.WrapError(ctx, err, signermsgs.MsgChildWalletFailed, c.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// owner returns the child that owns the address. If the address is not known, the accounts are
+// updated in case a child has added it since the last update.
+func (w *compositeWallet) owner(ctx context.Context, addr ethtypes.Address0xHex) (*Child, error) {
+	w.mux.Lock()
+	c, ok := w.owners[addr]
+	w.mux.Unlock()
+	if ok {
+		return c, nil
+	}
+	if _, err := w.GetAccounts(ctx); err != nil {
+		return nil, err
+	}
+	w.mux.Lock()
+	c, ok = w.owners[addr]
+	w.mux.Unlock()
+	if !ok {
+		return nil, i18n.NewError(ctx, signermsgs.MsgWalletNotAvailable, addr)
+	}
+	return c, nil
+}
+
+func (w *compositeWallet) GetAccountWallet(ctx context.Context, addr ethtypes.Address0xHex) (string, error) {
+	c, err := w.owner(ctx, addr)
+	if err != nil {
+		return "", err
+	}
+	return c.Name, nil
+}
+
+func (w *compositeWallet) Sign(ctx context.Context, txn *ethsigner.Transaction, chainID int64) ([]byte, error) {
+	var from ethtypes.Address0xHex
+	if err := json.Unmarshal(txn.From, &from); err != nil {
+		return nil, err
+	}
+	c, err := w.owner(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	return c.Wallet.Sign(ctx, txn, chainID)
+}
+
+func (w *compositeWallet) SignTypedDataV4(ctx context.Context, from ethtypes.Address0xHex, payload *eip712.TypedData) (*ethsigner.EIP712Result, error) {
+	c, err := w.owner(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	tw, ok := c.Wallet.(ethsigner.WalletTypedData)
+	if !ok {
+		return nil, i18n.WrapError(ctx, i18n.NewError(ctx, signermsgs.MsgWalletNotSupported, "EIP-712 typed data signing"), signermsgs.MsgChildWalletFailed, c.Name)
+	}
+	return tw.SignTypedDataV4(ctx, from, payload)
+}
+
+func (w *compositeWallet) SignEIP191PersonalMessage(ctx context.Context, from ethtypes.Address0xHex, message []byte) (*ethsigner.EIP191Result, error) {
+	mw, err := w.eip191Wallet(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	return mw.SignEIP191PersonalMessage(ctx, from, message)
+}
+
+func (w *compositeWallet) SignEIP191IntendedValidator(ctx context.Context, from ethtypes.Address0xHex, validator ethtypes.Address0xHex, data []byte) (*ethsigner.EIP191Result, error) {
+	mw, err := w.eip191Wallet(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	return mw.SignEIP191IntendedValidator(ctx, from, validator, data)
+}
+
+func (w *compositeWallet) eip191Wallet(ctx context.Context, from ethtypes.Address0xHex) (ethsigner.WalletEIP191, error) {
+	c, err := w.owner(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	mw, ok := c.Wallet.(ethsigner.WalletEIP191)
+	if !ok {
+		return nil, i18n.WrapError(ctx, i18n.NewError(ctx, signermsgs.MsgWalletNotSupported, "EIP-191 message signing"), signermsgs.MsgChildWalletFailed, c.Name)
+	}
+	return mw, nil
+}
+
+// GetAccountMetadata returns the metadata from the child that owns the address, or nil if
+// that child does not store metadata
+func (w *compositeWallet) GetAccountMetadata(ctx context.Context, addr ethtypes.Address0xHex) (map[string]interface{}, error) {
+	c, err := w.owner(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if mw, ok := c.Wallet.(ethsigner.WalletMetadata); ok {
+		return mw.GetAccountMetadata(ctx, addr)
+	}
+	return nil, nil
+}
+
+// IsKeyCached returns whether the child that owns the address has the key cached. Children that
+// do not cache keys always return false.
+func (w *compositeWallet) IsKeyCached(ctx context.Context, addr ethtypes.Address0xHex) bool {
+	w.mux.Lock()
+	c, ok := w.owners[addr]
+	w.mux.Unlock()
+	if ok {
+		if cw, ok := c.Wallet.(ethsigner.WalletCache); ok {
+			return cw.IsKeyCached(ctx, addr)
+		}
+	}
+	return false
+}
+
+func (w *compositeWallet) EvictKey(ctx context.Context, addr ethtypes.Address0xHex) bool {
+	w.mux.Lock()
+	c, ok := w.owners[addr]
+	w.mux.Unlock()
+	if ok {
+		if cw, ok := c.Wallet.(ethsigner.WalletCache); ok {
+			return cw.EvictKey(ctx, addr)
+		}
+	}
+	return false
+}
@@ -0,0 +1,323 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compositewallet
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/mocks/ethsignermocks"
+	"github.com/hyperledger/firefly-signer/pkg/eip712"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	testAddr1 = ethtypes.MustNewAddress("0x1f185718734552d08278aa70f804580bab5fd2b4")
+	testAddr2 = ethtypes.MustNewAddress("0x497eedc4299dea2f2a364be10025d0ad0f702de3")
+	testAddr3 = ethtypes.MustNewAddress("0x5d093e9b41911be5f5c4cf91b108bac5d130fa83")
+)
+
+func newTestCompositeWallet(t *testing.T, children ...*Child) (context.Context, *compositeWallet) {
+	ctx := context.Background()
+	w, err := NewCompositeWallet(ctx, children)
+	assert.NoError(t, err)
+	return ctx, w.(*compositeWallet)
+}
+
+func testTxnFrom(addr *ethtypes.Address0xHex) *ethsigner.Transaction {
+	return &ethsigner.Transaction{From: []byte(fmt.Sprintf(`"%s"`, addr))}
+}
+
+func TestNewCompositeWalletNoChildren(t *testing.T) {
+	_, err := NewCompositeWallet(context.Background(), nil)
+	assert.Regexp(t, "FF22017", err)
+}
+
+func TestNewCompositeWalletDuplicateName(t *testing.T) {
+	_, err := NewCompositeWallet(context.Background(), []*Child{
+		{Name: "wallet1", Wallet: &ethsignermocks.Wallet{}},
+		{Name: "wallet1", Wallet: &ethsignermocks.Wallet{}},
+	})
+	assert.Regexp(t, "FF22164.*wallet1", err)
+}
+
+func TestCompositeWalletRoutesByPriority(t *testing.T) {
+	w1 := &ethsignermocks.Wallet{}
+	w2 := &ethsignermocks.WalletTypedData{}
+	w3 := &ethsignermocks.Wallet{}
+	ctx, w := newTestCompositeWallet(t,
+		&Child{Name: "low", Priority: -1, Wallet: w1},
+		&Child{Name: "default", Wallet: w3},
+		&Child{Name: "high", Priority: 10, Wallet: w2},
+	)
+	w1.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr1, testAddr2}, nil)
+	w2.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr2}, nil)
+	w3.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr3, testAddr1}, nil)
+
+	accounts, err := w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{testAddr2, testAddr3, testAddr1}, accounts)
+
+	for addr, expected := range map[*ethtypes.Address0xHex]string{testAddr1: "default", testAddr2: "high", testAddr3: "default"} {
+		owner, err := w.GetAccountWallet(ctx, *addr)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, owner)
+	}
+
+	w2.On("Sign", mock.Anything, mock.Anything, int64(1001)).Return([]byte("signed by high"), nil)
+	signed, err := w.Sign(ctx, testTxnFrom(testAddr2), 1001)
+	assert.NoError(t, err)
+	assert.Equal(t, "signed by high", string(signed))
+
+	w3.On("Sign", mock.Anything, mock.Anything, int64(1001)).Return([]byte("signed by default"), nil)
+	signed, err = w.Sign(ctx, testTxnFrom(testAddr1), 1001)
+	assert.NoError(t, err)
+	assert.Equal(t, "signed by default", string(signed))
+
+	w2.On("SignTypedDataV4", mock.Anything, *testAddr2, mock.Anything).Return(&ethsigner.EIP712Result{V: *ethtypes.NewHexInteger64(27)}, nil)
+	result, err := w.SignTypedDataV4(ctx, *testAddr2, &eip712.TypedData{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(27), result.V.Int64())
+
+	_, err = w.SignTypedDataV4(ctx, *testAddr3, &eip712.TypedData{})
+	assert.Regexp(t, "FF22165.*default.*FF22094", err)
+
+	w1.AssertNotCalled(t, "Sign", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCompositeWalletSignUnknownAddressUpdatesAccounts(t *testing.T) {
+	w1 := &ethsignermocks.Wallet{}
+	ctx, w := newTestCompositeWallet(t, &Child{Name: "wallet1", Wallet: w1})
+	w1.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr1}, nil).Once()
+	w1.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr1, testAddr2}, nil).Once()
+	w1.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr1, testAddr2}, nil).Once()
+
+	_, err := w.GetAccounts(ctx)
+	assert.NoError(t, err)
+
+	// Added to the child since the last update
+	w1.On("Sign", mock.Anything, mock.Anything, int64(1001)).Return([]byte("signed"), nil)
+	_, err = w.Sign(ctx, testTxnFrom(testAddr2), 1001)
+	assert.NoError(t, err)
+
+	_, err = w.Sign(ctx, testTxnFrom(testAddr3), 1001)
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.Sign(ctx, &ethsigner.Transaction{From: []byte(`"wrong"`)}, 1001)
+	assert.Error(t, err)
+
+	w1.AssertExpectations(t)
+}
+
+func TestCompositeWalletGetAccountsFail(t *testing.T) {
+	w1 := &ethsignermocks.Wallet{}
+	ctx, w := newTestCompositeWallet(t, &Child{Name: "wallet1", Wallet: w1})
+	w1.On("GetAccounts", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := w.GetAccounts(ctx)
+	assert.Regexp(t, "FF22165.*wallet1.*pop", err)
+
+	_, err = w.Sign(ctx, testTxnFrom(testAddr1), 1001)
+	assert.Regexp(t, "pop", err)
+
+	_, err = w.GetAccountWallet(ctx, *testAddr1)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestCompositeWalletInitializeListenersAndClose(t *testing.T) {
+	w1 := &ethsignermocks.WalletListener{}
+	w2 := &ethsignermocks.Wallet{}
+	ctx := context.Background()
+	initialListener := make(chan ethtypes.Address0xHex, 10)
+	cw, err := NewCompositeWallet(ctx, []*Child{
+		{Name: "wallet1", Wallet: w1},
+		{Name: "wallet2", Wallet: w2},
+	}, initialListener)
+	assert.NoError(t, err)
+	addedListener := make(chan ethtypes.Address0xHex, 10)
+	cw.AddListener(addedListener)
+
+	var childListener chan<- ethtypes.Address0xHex
+	w1.On("AddListener", mock.Anything).Run(func(args mock.Arguments) {
+		childListener = args[0].(chan<- ethtypes.Address0xHex)
+	})
+	w1.On("Initialize", mock.Anything).Return(nil)
+	w2.On("Initialize", mock.Anything).Return(nil)
+	w1.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr1}, nil).Once()
+	w2.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr1, testAddr2}, nil)
+	err = cw.Initialize(ctx)
+	assert.NoError(t, err)
+
+	// Each address is notified once, even though it is in both wallets
+	for _, l := range []chan ethtypes.Address0xHex{initialListener, addedListener} {
+		assert.Equal(t, *testAddr1, <-l)
+		assert.Equal(t, *testAddr2, <-l)
+	}
+
+	// A new account detected by a child is added, and notified
+	w1.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr1, testAddr3}, nil)
+	childListener <- *testAddr3
+	assert.Equal(t, *testAddr3, <-initialListener)
+	assert.Equal(t, *testAddr3, <-addedListener)
+	accounts, err := cw.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{testAddr1, testAddr3, testAddr2}, accounts)
+
+	w1.On("Close").Return(fmt.Errorf("pop1"))
+	w2.On("Close").Return(fmt.Errorf("pop2"))
+	err = cw.Close()
+	assert.Regexp(t, "pop1", err)
+	w1.AssertExpectations(t)
+	w2.AssertExpectations(t)
+}
+
+func TestCompositeWalletListenerUpdateFail(t *testing.T) {
+	w1 := &ethsignermocks.WalletListener{}
+	ctx, w := newTestCompositeWallet(t, &Child{Name: "wallet1", Wallet: w1})
+
+	w1.On("AddListener", mock.Anything)
+	w1.On("Initialize", mock.Anything).Return(nil)
+	w1.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr1}, nil).Once()
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	updated := make(chan struct{})
+	w1.On("GetAccounts", mock.Anything).Run(func(args mock.Arguments) {
+		close(updated)
+	}).Return(nil, fmt.Errorf("pop")).Once()
+	w.childUpdates <- *testAddr2
+	<-updated
+
+	w1.On("Close").Return(nil)
+	err = w.Close()
+	assert.NoError(t, err)
+}
+
+func TestCompositeWalletInitializeFail(t *testing.T) {
+	w1 := &ethsignermocks.Wallet{}
+	ctx, w := newTestCompositeWallet(t, &Child{Name: "wallet1", Wallet: w1})
+	w1.On("Initialize", mock.Anything).Return(fmt.Errorf("pop"))
+	err := w.Initialize(ctx)
+	assert.Regexp(t, "FF22165.*wallet1.*pop", err)
+}
+
+func TestCompositeWalletRefresh(t *testing.T) {
+	w1 := &ethsignermocks.Wallet{}
+	w2 := &ethsignermocks.Wallet{}
+	ctx, w := newTestCompositeWallet(t, &Child{Name: "wallet1", Wallet: w1}, &Child{Name: "wallet2", Wallet: w2})
+	w1.On("Refresh", mock.Anything).Return(nil)
+	w2.On("Refresh", mock.Anything).Return(nil).Once()
+	w1.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr1}, nil)
+	w2.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr2}, nil)
+	err := w.Refresh(ctx)
+	assert.NoError(t, err)
+
+	w2.On("Refresh", mock.Anything).Return(fmt.Errorf("pop"))
+	err = w.Refresh(ctx)
+	assert.Regexp(t, "FF22165.*wallet2.*pop", err)
+
+	// Close before initialize
+	w1.On("Close").Return(nil)
+	w2.On("Close").Return(nil)
+	err = w.Close()
+	assert.NoError(t, err)
+}
+
+func TestCompositeWalletCheckHealth(t *testing.T) {
+	w1 := &ethsignermocks.Wallet{}
+	w2 := &ethsignermocks.WalletHealth{}
+	ctx, w := newTestCompositeWallet(t, &Child{Name: "wallet1", Wallet: w1}, &Child{Name: "wallet2", Wallet: w2})
+	w2.On("CheckHealth", mock.Anything).Return(nil).Once()
+	err := w.CheckHealth(ctx)
+	assert.NoError(t, err)
+
+	w2.On("CheckHealth", mock.Anything).Return(fmt.Errorf("pop"))
+	err = w.CheckHealth(ctx)
+	assert.Regexp(t, "FF22165.*wallet2.*pop", err)
+}
+
+func TestCompositeWalletEIP191(t *testing.T) {
+	w1 := &ethsignermocks.WalletEIP191{}
+	w2 := &ethsignermocks.Wallet{}
+	ctx, w := newTestCompositeWallet(t, &Child{Name: "wallet1", Wallet: w1}, &Child{Name: "wallet2", Wallet: w2})
+	w1.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr1}, nil)
+	w2.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr2}, nil)
+
+	w1.On("SignEIP191PersonalMessage", mock.Anything, *testAddr1, []byte("hello")).Return(&ethsigner.EIP191Result{V: *ethtypes.NewHexInteger64(27)}, nil)
+	result, err := w.SignEIP191PersonalMessage(ctx, *testAddr1, []byte("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(27), result.V.Int64())
+
+	w1.On("SignEIP191IntendedValidator", mock.Anything, *testAddr1, *testAddr3, []byte("hello")).Return(&ethsigner.EIP191Result{V: *ethtypes.NewHexInteger64(28)}, nil)
+	result, err = w.SignEIP191IntendedValidator(ctx, *testAddr1, *testAddr3, []byte("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(28), result.V.Int64())
+
+	_, err = w.SignEIP191PersonalMessage(ctx, *testAddr2, []byte("hello"))
+	assert.Regexp(t, "FF22165.*wallet2.*FF22094", err)
+	_, err = w.SignEIP191IntendedValidator(ctx, *testAddr2, *testAddr3, []byte("hello"))
+	assert.Regexp(t, "FF22165.*wallet2.*FF22094", err)
+	_, err = w.SignEIP191PersonalMessage(ctx, *testAddr3, []byte("hello"))
+	assert.Regexp(t, "FF22014", err)
+	_, err = w.SignTypedDataV4(ctx, *testAddr3, &eip712.TypedData{})
+	assert.Regexp(t, "FF22014", err)
+}
+
+func TestCompositeWalletMetadata(t *testing.T) {
+	w1 := &ethsignermocks.WalletMetadata{}
+	w2 := &ethsignermocks.Wallet{}
+	ctx, w := newTestCompositeWallet(t, &Child{Name: "wallet1", Wallet: w1}, &Child{Name: "wallet2", Wallet: w2})
+	w1.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr1}, nil)
+	w2.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr2}, nil)
+
+	w1.On("GetAccountMetadata", mock.Anything, *testAddr1).Return(map[string]interface{}{"team": "a"}, nil)
+	metadata, err := w.GetAccountMetadata(ctx, *testAddr1)
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.JSONObject{"team": "a"}, fftypes.JSONObject(metadata))
+
+	metadata, err = w.GetAccountMetadata(ctx, *testAddr2)
+	assert.NoError(t, err)
+	assert.Nil(t, metadata)
+
+	_, err = w.GetAccountMetadata(ctx, *testAddr3)
+	assert.Regexp(t, "FF22014", err)
+}
+
+func TestCompositeWalletCache(t *testing.T) {
+	w1 := &ethsignermocks.WalletCache{}
+	w2 := &ethsignermocks.Wallet{}
+	ctx, w := newTestCompositeWallet(t, &Child{Name: "wallet1", Wallet: w1}, &Child{Name: "wallet2", Wallet: w2})
+	w1.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr1}, nil)
+	w2.On("GetAccounts", mock.Anything).Return([]*ethtypes.Address0xHex{testAddr2}, nil)
+	_, err := w.GetAccounts(ctx)
+	assert.NoError(t, err)
+
+	w1.On("IsKeyCached", mock.Anything, *testAddr1).Return(true)
+	w1.On("EvictKey", mock.Anything, *testAddr1).Return(true)
+	assert.True(t, w.IsKeyCached(ctx, *testAddr1))
+	assert.True(t, w.EvictKey(ctx, *testAddr1))
+
+	assert.False(t, w.IsKeyCached(ctx, *testAddr2))
+	assert.False(t, w.EvictKey(ctx, *testAddr2))
+	assert.False(t, w.IsKeyCached(ctx, *testAddr3))
+	assert.False(t, w.EvictKey(ctx, *testAddr3))
+}
@@ -0,0 +1,60 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devwallet
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-signer/pkg/hdwallet"
+)
+
+const (
+	// ConfigSeed the seed string the accounts are generated from - a BIP-39 mnemonic, or any other string
+	ConfigSeed = "seed"
+	// ConfigPath the BIP-32 path of the parent key of the accounts
+	ConfigPath = "path"
+	// ConfigAccounts the number of accounts to generate on initialization
+	ConfigAccounts = "accounts"
+	// ConfigBalance optional balance in wei to pre-fund each account with, included in the account metadata
+	ConfigBalance = "balance"
+)
+
+// DefaultSeed is the well known development mnemonic of Hardhat and Anvil, so the accounts
+// match the accounts funded by those development chains. Never use it for real funds.
+const DefaultSeed = "test test test test test test test test test test test junk"
+
+type Config struct {
+	Seed     string
+	Path     string
+	Accounts int
+	Balance  string
+}
+
+func InitConfig(section config.Section) {
+	section.AddKnownKey(ConfigSeed, DefaultSeed)
+	section.AddKnownKey(ConfigPath, hdwallet.DefaultPath)
+	section.AddKnownKey(ConfigAccounts, 10)
+	section.AddKnownKey(ConfigBalance)
+}
+
+func ReadConfig(section config.Section) *Config {
+	return &Config{
+		Seed:     section.GetString(ConfigSeed),
+		Path:     section.GetString(ConfigPath),
+		Accounts: section.GetInt(ConfigAccounts),
+		Balance:  section.GetString(ConfigBalance),
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devwallet
+
+import (
+	"context"
+	"crypto/sha512"
+	"math/big"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/hdwallet"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+)
+
+const (
+	// MetadataPath is the metadata property containing the BIP-32 path of the account
+	MetadataPath = "path"
+	// MetadataBalance is the metadata property containing the pre-funding balance of the account, when configured
+	MetadataBalance = "balance"
+)
+
+// Wallet is an in-memory development wallet, where the accounts are generated deterministically
+// from a seed string - in the same way as development chains such as Hardhat, Anvil and Ganache.
+// Nothing is written to disk, and the keys are only as secret as the seed, so it must only
+// be used for development and testing.
+//
+// Further accounts can be added at runtime, and listeners are notified of each account.
+type Wallet interface {
+	ethsigner.WalletTypedData
+	ethsigner.WalletEIP191
+	ethsigner.WalletMetadata
+	ethsigner.WalletListener
+	AddAccounts(ctx context.Context, count int) ([]*ethtypes.Address0xHex, error)
+}
+
+type devAccount struct {
+	path    hdwallet.DerivationPath
+	keypair *secp256k1.KeyPair
+}
+
+type devWallet struct {
+	*ethsigner.KeySigner
+	*ethsigner.Listeners
+	conf    Config
+	master  *hdwallet.ExtendedKey
+	parent  hdwallet.DerivationPath
+	balance *ethtypes.HexInteger
+
+	mux         sync.Mutex
+	accounts    map[ethtypes.Address0xHex]*devAccount
+	addressList []*ethtypes.Address0xHex // in index order
+}
+
+// NewDevWallet creates the master key from the seed. When the seed is a valid BIP-39 mnemonic
+// the accounts match any other BIP-39 wallet with the same path, otherwise the seed is hashed.
+func NewDevWallet(ctx context.Context, conf *Config, initialListeners ...chan<- ethtypes.Address0xHex) (Wallet, error) {
+	w := &devWallet{
+		Listeners: ethsigner.NewListeners(initialListeners...),
+		conf:      *conf,
+		accounts:  make(map[ethtypes.Address0xHex]*devAccount),
+	}
+	w.KeySigner = ethsigner.NewKeySigner(w.getSignerForAddr)
+	if conf.Accounts < 0 {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidDevWalletConfig, ConfigAccounts, conf.Accounts)
+	}
+	if conf.Balance != "" {
+		balance, ok := new(big.Int).SetString(conf.Balance, 0)
+		if !ok || balance.Sign() < 0 {
+			return nil, i18n.NewError(ctx, signermsgs.MsgInvalidDevWalletConfig, ConfigBalance, conf.Balance)
+		}
+		w.balance = ethtypes.NewHexInteger(balance)
+	}
+	var err error
+	if w.parent, err = hdwallet.ParseDerivationPath(ctx, conf.Path); err != nil {
+		return nil, err
+	}
+	seed, err := hdwallet.MnemonicToSeed(ctx, conf.Seed, "")
+	if err != nil {
+		log.L(ctx).Infof("Dev wallet seed is not a BIP-39 mnemonic (%s) - using a hash of the seed", err)
+		hash := sha512.Sum512([]byte(conf.Seed))
+		seed = hash[:]
+	}
+	if w.master, err = hdwallet.NewMasterKey(ctx, seed); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Initialize generates the configured number of accounts, if they have not already been generated
+func (w *devWallet) Initialize(ctx context.Context) error {
+	w.mux.Lock()
+	count := w.conf.Accounts - len(w.addressList)
+	w.mux.Unlock()
+	if count > 0 {
+		if _, err := w.AddAccounts(ctx, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddAccounts generates the next accounts, after the highest index generated so far,
+// and notifies the listeners of each new account
+func (w *devWallet) AddAccounts(ctx context.Context, count int) ([]*ethtypes.Address0xHex, error) {
+	if count < 0 {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidDevWalletConfig, ConfigAccounts, count)
+	}
+	w.mux.Lock()
+	newAccounts := make([]*ethtypes.Address0xHex, 0, count)
+	for i := 0; i < count; i++ {
+		path := w.parent.Append(uint32(len(w.addressList)))
+		key, err := w.master.Derive(ctx, path)
+		if err != nil {
+			w.mux.Unlock()
+			return nil, err
+		}
+		keypair := key.KeyPair()
+		addr := keypair.Address
+		w.accounts[addr] = &devAccount{path: path, keypair: keypair}
+		w.addressList = append(w.addressList, &addr)
+		newAccounts = append(newAccounts, &addr)
+	}
+	w.mux.Unlock()
+
+	log.L(ctx).Infof("Generated %d dev wallet accounts", len(newAccounts))
+	w.Notify(newAccounts)
+	return newAccounts, nil
+}
+
+// GetAccounts returns the generated accounts, in index order
+func (w *devWallet) GetAccounts(_ context.Context) ([]*ethtypes.Address0xHex, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	accounts := make([]*ethtypes.Address0xHex, len(w.addressList))
+	copy(accounts, w.addressList)
+	return accounts, nil
+}
+
+// GetAccountMetadata returns the path of the account, and the pre-funding balance if configured
+func (w *devWallet) GetAccountMetadata(ctx context.Context, addr ethtypes.Address0xHex) (map[string]interface{}, error) {
+	account, err := w.getAccount(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	metadata := map[string]interface{}{
+		MetadataPath: account.path.String(),
+	}
+	if w.balance != nil {
+		metadata[MetadataBalance] = w.balance.String()
+	}
+	return metadata, nil
+}
+
+// Refresh does nothing, as accounts are only added by AddAccounts
+func (w *devWallet) Refresh(_ context.Context) error {
+	return nil
+}
+
+// Close does nothing, as the accounts are kept until the wallet is discarded
+func (w *devWallet) Close() error {
+	return nil
+}
+
+func (w *devWallet) getAccount(ctx context.Context, addr ethtypes.Address0xHex) (*devAccount, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	account, ok := w.accounts[addr]
+	if !ok {
+		return nil, i18n.NewError(ctx, signermsgs.MsgWalletNotAvailable, addr)
+	}
+	return account, nil
+}
+
+func (w *devWallet) getSignerForAddr(ctx context.Context, addr ethtypes.Address0xHex) (secp256k1.SignerDirect, error) {
+	account, err := w.getAccount(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	return account.keypair, nil
+}
@@ -0,0 +1,214 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devwallet
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-signer/pkg/eip712"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/hdwallet"
+	"github.com/stretchr/testify/assert"
+)
+
+// The first accounts of Hardhat and Anvil, from the default seed
+var (
+	testAccount0 = ethtypes.MustNewAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	testAccount1 = ethtypes.MustNewAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	testAccount2 = ethtypes.MustNewAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+)
+
+func newTestDevWallet(t *testing.T, conf *Config, listeners ...chan<- ethtypes.Address0xHex) (context.Context, Wallet) {
+	ctx := context.Background()
+	w, err := NewDevWallet(ctx, conf, listeners...)
+	assert.NoError(t, err)
+	err = w.Initialize(ctx)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = w.Close() })
+	return ctx, w
+}
+
+func testDefaultConfig() *Config {
+	config.RootConfigReset()
+	section := config.RootSection("devWallet")
+	InitConfig(section)
+	section.Set(ConfigAccounts, 2)
+	return ReadConfig(section)
+}
+
+func TestConfig(t *testing.T) {
+	assert.Equal(t, &Config{
+		Seed:     DefaultSeed,
+		Path:     hdwallet.DefaultPath,
+		Accounts: 2,
+	}, testDefaultConfig())
+}
+
+func TestDefaultSeedAccounts(t *testing.T) {
+	listener := make(chan ethtypes.Address0xHex, 3)
+	ctx, w := newTestDevWallet(t, testDefaultConfig(), listener)
+
+	accounts, err := w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{testAccount0, testAccount1}, accounts)
+	assert.Equal(t, *testAccount0, <-listener)
+	assert.Equal(t, *testAccount1, <-listener)
+
+	// Initialize again does not generate more accounts
+	err = w.Initialize(ctx)
+	assert.NoError(t, err)
+	err = w.Refresh(ctx)
+	assert.NoError(t, err)
+	accounts, err = w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 2)
+
+	metadata, err := w.GetAccountMetadata(ctx, *testAccount1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"path": "m/44'/60'/0'/0/1"}, metadata)
+}
+
+func TestAddAccounts(t *testing.T) {
+	ctx, w := newTestDevWallet(t, &Config{
+		Seed:     DefaultSeed,
+		Path:     hdwallet.DefaultPath,
+		Accounts: 1,
+		Balance:  "10000000000000000000000",
+	})
+	listener := make(chan ethtypes.Address0xHex, 2)
+	w.AddListener(listener)
+
+	added, err := w.AddAccounts(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{testAccount1, testAccount2}, added)
+	assert.Equal(t, *testAccount1, <-listener)
+	assert.Equal(t, *testAccount2, <-listener)
+
+	accounts, err := w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{testAccount0, testAccount1, testAccount2}, accounts)
+
+	metadata, err := w.GetAccountMetadata(ctx, *testAccount2)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"path":    "m/44'/60'/0'/0/2",
+		"balance": "0x21e19e0c9bab2400000",
+	}, metadata)
+
+	added, err = w.AddAccounts(ctx, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, added)
+
+	_, err = w.AddAccounts(ctx, -1)
+	assert.Regexp(t, "FF22176", err)
+}
+
+func TestNonMnemonicSeed(t *testing.T) {
+	conf := &Config{Seed: "my integration test", Path: "m/0", Accounts: 3}
+	ctx, w1 := newTestDevWallet(t, conf)
+	_, w2 := newTestDevWallet(t, conf)
+
+	accounts1, err := w1.GetAccounts(ctx)
+	assert.NoError(t, err)
+	accounts2, err := w2.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, accounts1, 3)
+	assert.Equal(t, accounts1, accounts2)
+	assert.NotEqual(t, testAccount0, accounts1[0])
+}
+
+func TestNewDevWalletBadConfig(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewDevWallet(ctx, &Config{Seed: DefaultSeed, Path: hdwallet.DefaultPath, Accounts: -1})
+	assert.Regexp(t, "FF22176.*accounts", err)
+
+	_, err = NewDevWallet(ctx, &Config{Seed: DefaultSeed, Path: hdwallet.DefaultPath, Balance: "lots"})
+	assert.Regexp(t, "FF22176.*balance", err)
+
+	_, err = NewDevWallet(ctx, &Config{Seed: DefaultSeed, Path: hdwallet.DefaultPath, Balance: "-1"})
+	assert.Regexp(t, "FF22176.*balance", err)
+
+	_, err = NewDevWallet(ctx, &Config{Seed: DefaultSeed, Path: "bad"})
+	assert.Regexp(t, "FF22171", err)
+}
+
+func TestSignOK(t *testing.T) {
+	ctx, w := newTestDevWallet(t, testDefaultConfig())
+
+	b, err := w.Sign(ctx, &ethsigner.Transaction{
+		From:     json.RawMessage(`"0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"`),
+		Nonce:    ethtypes.NewHexInteger64(0),
+		GasLimit: ethtypes.NewHexInteger64(21000),
+	}, 31337)
+	assert.NoError(t, err)
+
+	from, _, err := ethsigner.RecoverRawTransaction(ctx, b, 31337)
+	assert.NoError(t, err)
+	assert.Equal(t, testAccount0, from)
+}
+
+func TestSignTypedDataOK(t *testing.T) {
+	ctx, w := newTestDevWallet(t, testDefaultConfig())
+
+	res, err := w.SignTypedDataV4(ctx, *testAccount0, &eip712.TypedData{
+		PrimaryType: eip712.EIP712Domain,
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, res)
+}
+
+func TestSignEIP191OK(t *testing.T) {
+	ctx, w := newTestDevWallet(t, testDefaultConfig())
+
+	res, err := w.SignEIP191PersonalMessage(ctx, *testAccount0, []byte("Hello World"))
+	assert.NoError(t, err)
+	ok, err := ethsigner.VerifyEIP191PersonalMessage(ctx, []byte("Hello World"), res.SignatureRSV, *testAccount0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	res, err = w.SignEIP191IntendedValidator(ctx, *testAccount1, *testAccount0, []byte("some data"))
+	assert.NoError(t, err)
+	ok, err = ethsigner.VerifyEIP191IntendedValidator(ctx, *testAccount0, []byte("some data"), res.SignatureRSV, *testAccount1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestSignNotFound(t *testing.T) {
+	ctx, w := newTestDevWallet(t, testDefaultConfig())
+
+	_, err := w.Sign(ctx, &ethsigner.Transaction{From: json.RawMessage(`"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"`)}, 31337)
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.Sign(ctx, &ethsigner.Transaction{From: json.RawMessage(`"bad address"`)}, 31337)
+	assert.Regexp(t, "bad address", err)
+
+	_, err = w.SignTypedDataV4(ctx, *testAccount2, &eip712.TypedData{})
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.SignEIP191PersonalMessage(ctx, *testAccount2, []byte("Hello World"))
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.SignEIP191IntendedValidator(ctx, *testAccount2, *testAccount0, []byte("Hello World"))
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.GetAccountMetadata(ctx, *testAccount2)
+	assert.Regexp(t, "FF22014", err)
+}
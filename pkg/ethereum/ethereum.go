@@ -43,13 +43,13 @@ type TXReceiptJSONRPC struct {
 // - We omit fields already in the standardized cross-blockchain section
 // - We format numbers as decimals
 type ReceiptExtraInfo struct {
-	ContractAddress   *ethtypes.Address0xHex `json:"contractAddress"`
-	CumulativeGasUsed *fftypes.FFBigInt      `json:"cumulativeGasUsed"`
-	From              *ethtypes.Address0xHex `json:"from"`
-	To                *ethtypes.Address0xHex `json:"to"`
-	GasUsed           *fftypes.FFBigInt      `json:"gasUsed"`
-	Status            *fftypes.FFBigInt      `json:"status"`
-	ErrorMessage      *string                `json:"errorMessage"`
+	ContractAddress   *ethtypes.Address0xHex `ffstruct:"ReceiptExtraInfo" json:"contractAddress"`
+	CumulativeGasUsed *fftypes.FFBigInt      `ffstruct:"ReceiptExtraInfo" json:"cumulativeGasUsed"`
+	From              *ethtypes.Address0xHex `ffstruct:"ReceiptExtraInfo" json:"from"`
+	To                *ethtypes.Address0xHex `ffstruct:"ReceiptExtraInfo" json:"to"`
+	GasUsed           *fftypes.FFBigInt      `ffstruct:"ReceiptExtraInfo" json:"gasUsed"`
+	Status            *fftypes.FFBigInt      `ffstruct:"ReceiptExtraInfo" json:"status"`
+	ErrorMessage      *string                `ffstruct:"ReceiptExtraInfo" json:"errorMessage"`
 }
 
 // txInfoJSONRPC is the transaction info obtained over JSON/RPC from the ethereum client, with input data
@@ -0,0 +1,149 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethsigner
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	EIP191VersionIntendedValidator byte = 0x00
+	EIP191VersionStructuredData    byte = 0x01 // EIP-712 - see SignTypedDataV4
+	EIP191VersionPersonalSign      byte = 0x45 // "E" for "Ethereum Signed Message"
+)
+
+type EIP191Result struct {
+	Hash         ethtypes.HexBytes0xPrefix `ffstruct:"EIP191Result" json:"hash"`
+	SignatureRSV ethtypes.HexBytes0xPrefix `ffstruct:"EIP191Result" json:"signatureRSV"`
+	V            ethtypes.HexInteger       `ffstruct:"EIP191Result" json:"v"`
+	R            ethtypes.HexBytes0xPrefix `ffstruct:"EIP191Result" json:"r"`
+	S            ethtypes.HexBytes0xPrefix `ffstruct:"EIP191Result" json:"s"`
+}
+
+// EIP191PersonalSignPayload returns the version 0x45 payload for a message, as used by
+// personal_sign and eth_sign in Ethereum clients:
+//
+//	0x19 || "Ethereum Signed Message:\n" || len(message) || message
+func EIP191PersonalSignPayload(message []byte) []byte {
+	prefix := "\x19Ethereum Signed Message:\n" + strconv.Itoa(len(message))
+	return append([]byte(prefix), message...)
+}
+
+// EIP191IntendedValidatorPayload returns the version 0x00 payload, where the signed data
+// is only intended to be validated by the specified validator address (usually a contract):
+//
+//	0x19 || 0x00 || validator || data
+func EIP191IntendedValidatorPayload(validator ethtypes.Address0xHex, data []byte) []byte {
+	payload := make([]byte, 0, 22+len(data))
+	payload = append(payload, 0x19, EIP191VersionIntendedValidator)
+	payload = append(payload, validator[:]...)
+	return append(payload, data...)
+}
+
+// EIP191PersonalSignHash returns the keccak256 hash of the version 0x45 payload
+func EIP191PersonalSignHash(message []byte) ethtypes.HexBytes0xPrefix {
+	return keccak256(EIP191PersonalSignPayload(message))
+}
+
+// EIP191IntendedValidatorHash returns the keccak256 hash of the version 0x00 payload
+func EIP191IntendedValidatorHash(validator ethtypes.Address0xHex, data []byte) ethtypes.HexBytes0xPrefix {
+	return keccak256(EIP191IntendedValidatorPayload(validator, data))
+}
+
+// SignEIP191PersonalMessage signs a message with the version 0x45 "Ethereum Signed Message" prefix
+func SignEIP191PersonalMessage(ctx context.Context, signer secp256k1.Signer, message []byte) (*EIP191Result, error) {
+	return signEIP191Payload(ctx, signer, EIP191PersonalSignPayload(message))
+}
+
+// SignEIP191IntendedValidator signs data with the version 0x00 intended validator prefix
+func SignEIP191IntendedValidator(ctx context.Context, signer secp256k1.Signer, validator ethtypes.Address0xHex, data []byte) (*EIP191Result, error) {
+	return signEIP191Payload(ctx, signer, EIP191IntendedValidatorPayload(validator, data))
+}
+
+func signEIP191Payload(ctx context.Context, signer secp256k1.Signer, payload []byte) (*EIP191Result, error) {
+	if signer == nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidSigner)
+	}
+	// Note that signer.Sign performs the hash, and returns the legacy 27/28 V value
+	// that is the convention for EIP-191 signatures
+	sig, err := signer.Sign(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &EIP191Result{
+		Hash:         keccak256(payload),
+		V:            ethtypes.HexInteger(*sig.V),
+		R:            sig.R.FillBytes(make([]byte, 32)),
+		S:            sig.S.FillBytes(make([]byte, 32)),
+		SignatureRSV: sig.CompactRSV(),
+	}, nil
+}
+
+// RecoverEIP191PersonalMessage recovers the address that signed a message with the version 0x45
+// prefix, from the 65 byte R,S,V signature (V can be 0/1 or 27/28)
+func RecoverEIP191PersonalMessage(ctx context.Context, message []byte, signatureRSV []byte) (*ethtypes.Address0xHex, error) {
+	return recoverEIP191Payload(ctx, EIP191PersonalSignPayload(message), signatureRSV)
+}
+
+// RecoverEIP191IntendedValidator recovers the address that signed data with the version 0x00 prefix
+func RecoverEIP191IntendedValidator(ctx context.Context, validator ethtypes.Address0xHex, data []byte, signatureRSV []byte) (*ethtypes.Address0xHex, error) {
+	return recoverEIP191Payload(ctx, EIP191IntendedValidatorPayload(validator, data), signatureRSV)
+}
+
+// VerifyEIP191PersonalMessage checks the message was signed by the expected address
+func VerifyEIP191PersonalMessage(ctx context.Context, message []byte, signatureRSV []byte, expected ethtypes.Address0xHex) (bool, error) {
+	addr, err := RecoverEIP191PersonalMessage(ctx, message, signatureRSV)
+	if err != nil {
+		return false, err
+	}
+	return *addr == expected, nil
+}
+
+// VerifyEIP191IntendedValidator checks the intended validator data was signed by the expected address
+func VerifyEIP191IntendedValidator(ctx context.Context, validator ethtypes.Address0xHex, data []byte, signatureRSV []byte, expected ethtypes.Address0xHex) (bool, error) {
+	addr, err := RecoverEIP191IntendedValidator(ctx, validator, data, signatureRSV)
+	if err != nil {
+		return false, err
+	}
+	return *addr == expected, nil
+}
+
+func recoverEIP191Payload(ctx context.Context, payload []byte, signatureRSV []byte) (*ethtypes.Address0xHex, error) {
+	sig, err := secp256k1.DecodeCompactRSV(ctx, signatureRSV)
+	if err != nil {
+		return nil, err
+	}
+	// There is no chain ID involved in EIP-191 signatures
+	addr, err := sig.Recover(payload, 0)
+	if err != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgRecoverSignatureFailed, err)
+	}
+	return addr, nil
+}
+
+func keccak256(b []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(b)
+	return hash.Sum(nil)
+}
@@ -0,0 +1,128 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethsigner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-signer/mocks/secp256k1mocks"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEIP191PersonalSignHash(t *testing.T) {
+	// Matches ethers.js hashMessage("Hello World")
+	assert.Equal(t, "0xa1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2", EIP191PersonalSignHash([]byte("Hello World")).String())
+}
+
+func TestEIP191IntendedValidatorPayload(t *testing.T) {
+	validator := ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248")
+	payload := EIP191IntendedValidatorPayload(*validator, []byte{0x01, 0x02})
+	assert.Equal(t, "0x1900fb075bb99f2aa4c49955bf703509a227d7a122480102", ethtypes.HexBytes0xPrefix(payload).String())
+	assert.Equal(t, ethtypes.HexBytes0xPrefix(keccak256(payload)), EIP191IntendedValidatorHash(*validator, []byte{0x01, 0x02}))
+}
+
+func TestSignRecoverEIP191PersonalMessage(t *testing.T) {
+	ctx := context.Background()
+	keypair, err := secp256k1.GenerateSecp256k1KeyPair()
+	assert.NoError(t, err)
+
+	result, err := SignEIP191PersonalMessage(ctx, keypair, []byte("Hello World"))
+	assert.NoError(t, err)
+	assert.Equal(t, EIP191PersonalSignHash([]byte("Hello World")), result.Hash)
+	assert.Len(t, result.SignatureRSV, 65)
+	v := result.SignatureRSV[64]
+	assert.True(t, v == 27 || v == 28)
+	assert.Equal(t, int64(v), result.V.Int64())
+	assert.Equal(t, ethtypes.HexBytes0xPrefix(result.SignatureRSV[0:32]), result.R)
+	assert.Equal(t, ethtypes.HexBytes0xPrefix(result.SignatureRSV[32:64]), result.S)
+
+	addr, err := RecoverEIP191PersonalMessage(ctx, []byte("Hello World"), result.SignatureRSV)
+	assert.NoError(t, err)
+	assert.Equal(t, keypair.Address, *addr)
+
+	ok, err := VerifyEIP191PersonalMessage(ctx, []byte("Hello World"), result.SignatureRSV, keypair.Address)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = VerifyEIP191PersonalMessage(ctx, []byte("Goodbye World"), result.SignatureRSV, keypair.Address)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// 0/1 V values are accepted for recovery too
+	sig01 := make([]byte, 65)
+	copy(sig01, result.SignatureRSV)
+	sig01[64] -= 27
+	addr, err = RecoverEIP191PersonalMessage(ctx, []byte("Hello World"), sig01)
+	assert.NoError(t, err)
+	assert.Equal(t, keypair.Address, *addr)
+}
+
+func TestSignRecoverEIP191IntendedValidator(t *testing.T) {
+	ctx := context.Background()
+	keypair, err := secp256k1.GenerateSecp256k1KeyPair()
+	assert.NoError(t, err)
+	validator := ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248")
+
+	result, err := SignEIP191IntendedValidator(ctx, keypair, *validator, []byte("some data"))
+	assert.NoError(t, err)
+
+	ok, err := VerifyEIP191IntendedValidator(ctx, *validator, []byte("some data"), result.SignatureRSV, keypair.Address)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = VerifyEIP191IntendedValidator(ctx, keypair.Address, []byte("some data"), result.SignatureRSV, keypair.Address)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestSignEIP191NilSigner(t *testing.T) {
+	_, err := SignEIP191PersonalMessage(context.Background(), nil, []byte("Hello World"))
+	assert.Regexp(t, "FF22064", err)
+}
+
+func TestSignEIP191SignFail(t *testing.T) {
+	msn := &secp256k1mocks.Signer{}
+	msn.On("Sign", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := SignEIP191PersonalMessage(context.Background(), msn, []byte("Hello World"))
+	assert.Regexp(t, "pop", err)
+}
+
+func TestRecoverEIP191BadSignatureLength(t *testing.T) {
+	_, err := RecoverEIP191PersonalMessage(context.Background(), []byte("Hello World"), []byte{0x00})
+	assert.Regexp(t, "FF22087", err)
+
+	_, err = VerifyEIP191IntendedValidator(context.Background(), ethtypes.Address0xHex{}, []byte("Hello World"), []byte{0x00}, ethtypes.Address0xHex{})
+	assert.Regexp(t, "FF22087", err)
+}
+
+func TestRecoverEIP191BadSignature(t *testing.T) {
+	sig := make([]byte, 65)
+	sig[64] = 27
+	_, err := VerifyEIP191PersonalMessage(context.Background(), []byte("Hello World"), sig, ethtypes.Address0xHex{})
+	assert.Regexp(t, "FF22093", err)
+}
+
+func TestEIP191ResultDocumented(t *testing.T) {
+	ffapi.CheckObjectDocumented(&EIP191Result{})
+}
@@ -0,0 +1,113 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethsigner
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/hyperledger/firefly-signer/pkg/eip712"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+)
+
+// SignerLookup returns the signer for the key of an address. Signers that call a remote service,
+// such as an HSM or a KMS, must use the context for those calls.
+type SignerLookup func(ctx context.Context, addr ethtypes.Address0xHex) (secp256k1.SignerDirect, error)
+
+// KeySigner implements the signing functions of a wallet, for wallets that can look up a
+// secp256k1 signer for each of their accounts
+type KeySigner struct {
+	getSigner SignerLookup
+}
+
+func NewKeySigner(getSigner SignerLookup) *KeySigner {
+	return &KeySigner{getSigner: getSigner}
+}
+
+func (ks *KeySigner) Sign(ctx context.Context, txn *Transaction, chainID int64) ([]byte, error) {
+	// We require an ethereum address in the "from" field
+	var from ethtypes.Address0xHex
+	if err := json.Unmarshal(txn.From, &from); err != nil {
+		return nil, err
+	}
+	signer, err := ks.getSigner(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	return txn.Sign(signer, chainID)
+}
+
+func (ks *KeySigner) SignTypedDataV4(ctx context.Context, from ethtypes.Address0xHex, payload *eip712.TypedData) (*EIP712Result, error) {
+	signer, err := ks.getSigner(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	return SignTypedDataV4(ctx, signer, payload)
+}
+
+func (ks *KeySigner) SignEIP191PersonalMessage(ctx context.Context, from ethtypes.Address0xHex, message []byte) (*EIP191Result, error) {
+	signer, err := ks.getSigner(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	return SignEIP191PersonalMessage(ctx, signer, message)
+}
+
+func (ks *KeySigner) SignEIP191IntendedValidator(ctx context.Context, from ethtypes.Address0xHex, validator ethtypes.Address0xHex, data []byte) (*EIP191Result, error) {
+	signer, err := ks.getSigner(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	return SignEIP191IntendedValidator(ctx, signer, validator, data)
+}
+
+// Listeners implements AddListener for a WalletListener, and notifies the listeners of new accounts
+type Listeners struct {
+	mux       sync.Mutex
+	listeners []chan<- ethtypes.Address0xHex
+}
+
+func NewListeners(initialListeners ...chan<- ethtypes.Address0xHex) *Listeners {
+	return &Listeners{listeners: initialListeners}
+}
+
+// AddListener registers a listener to be notified asynchronously of each new account
+func (l *Listeners) AddListener(listener chan<- ethtypes.Address0xHex) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.listeners = append(l.listeners, listener)
+}
+
+// Notify sends each address to each listener, without blocking the caller
+func (l *Listeners) Notify(addrs []*ethtypes.Address0xHex) {
+	l.mux.Lock()
+	listeners := make([]chan<- ethtypes.Address0xHex, len(l.listeners))
+	copy(listeners, l.listeners)
+	l.mux.Unlock()
+
+	if len(listeners) > 0 && len(addrs) > 0 {
+		go func() {
+			for _, listener := range listeners {
+				for _, addr := range addrs {
+					listener <- *addr
+				}
+			}
+		}()
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethsigner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-signer/pkg/eip712"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+	"github.com/stretchr/testify/assert"
+)
+
+type testKeyCtxKey struct{}
+
+func newTestKeySigner(t *testing.T) (*KeySigner, *secp256k1.KeyPair) {
+	keypair, err := secp256k1.GenerateSecp256k1KeyPair()
+	assert.NoError(t, err)
+	return NewKeySigner(func(ctx context.Context, addr ethtypes.Address0xHex) (secp256k1.SignerDirect, error) {
+		assert.Equal(t, "caller", ctx.Value(testKeyCtxKey{}))
+		if addr != keypair.Address {
+			return nil, fmt.Errorf("pop")
+		}
+		return keypair, nil
+	}), keypair
+}
+
+func TestKeySigner(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testKeyCtxKey{}, "caller")
+	ks, keypair := newTestKeySigner(t)
+	from := keypair.Address
+
+	raw, err := ks.Sign(ctx, &Transaction{
+		From:     json.RawMessage(fmt.Sprintf(`"%s"`, from)),
+		Nonce:    ethtypes.NewHexInteger64(3),
+		GasLimit: ethtypes.NewHexInteger64(21000),
+		GasPrice: ethtypes.NewHexInteger64(100),
+	}, 1001)
+	assert.NoError(t, err)
+	signer, _, err := RecoverRawTransaction(ctx, raw, 1001)
+	assert.NoError(t, err)
+	assert.Equal(t, from, *signer)
+
+	typedData, err := ks.SignTypedDataV4(ctx, from, &eip712.TypedData{
+		Types:       eip712.TypeSet{eip712.EIP712Domain: {}},
+		PrimaryType: eip712.EIP712Domain,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, typedData.SignatureRSV, 65)
+
+	personal, err := ks.SignEIP191PersonalMessage(ctx, from, []byte("Hello World"))
+	assert.NoError(t, err)
+	ok, err := VerifyEIP191PersonalMessage(ctx, []byte("Hello World"), personal.SignatureRSV, from)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	validator := ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248")
+	intended, err := ks.SignEIP191IntendedValidator(ctx, from, *validator, []byte{0x01})
+	assert.NoError(t, err)
+	ok, err = VerifyEIP191IntendedValidator(ctx, *validator, []byte{0x01}, intended.SignatureRSV, from)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestKeySignerErrors(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testKeyCtxKey{}, "caller")
+	ks, _ := newTestKeySigner(t)
+	other := *ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248")
+
+	_, err := ks.Sign(ctx, &Transaction{From: json.RawMessage(`"bad"`)}, 1001)
+	assert.Error(t, err)
+
+	_, err = ks.Sign(ctx, &Transaction{From: json.RawMessage(fmt.Sprintf(`"%s"`, other))}, 1001)
+	assert.Regexp(t, "pop", err)
+
+	_, err = ks.SignTypedDataV4(ctx, other, &eip712.TypedData{})
+	assert.Regexp(t, "pop", err)
+
+	_, err = ks.SignEIP191PersonalMessage(ctx, other, []byte{})
+	assert.Regexp(t, "pop", err)
+
+	_, err = ks.SignEIP191IntendedValidator(ctx, other, other, []byte{})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestListeners(t *testing.T) {
+	l1 := make(chan ethtypes.Address0xHex, 2)
+	l2 := make(chan ethtypes.Address0xHex, 2)
+	l := NewListeners(l1)
+	l.AddListener(l2)
+
+	// Nothing to notify
+	l.Notify(nil)
+
+	addr1 := ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248")
+	addr2 := ethtypes.MustNewAddress("0x497eedc4299dea2f2a364be10025d0ad0f702de3")
+	l.Notify([]*ethtypes.Address0xHex{addr1, addr2})
+	for _, listener := range []chan ethtypes.Address0xHex{l1, l2} {
+		assert.Equal(t, *addr1, <-listener)
+		assert.Equal(t, *addr2, <-listener)
+	}
+}
@@ -253,7 +253,7 @@ func RecoverLegacyRawTransaction(ctx context.Context, rawTx ethtypes.HexBytes0xP
 		Data:     ethtypes.HexBytes0xPrefix(rlpList[5].ToData()),
 	}
 
-	vValue := rlpList[6].ToData().Int().Int64()
+	vValue := rlpList[6].ToData().IntOrZero().Int64()
 	rValue := rlpList[7].ToData().BytesNotNil()
 	sValue := rlpList[8].ToData().BytesNotNil()
 
@@ -274,11 +274,15 @@ func RecoverLegacyRawTransaction(ctx context.Context, rawTx ethtypes.HexBytes0xP
 		message = (rlpList[0:6]).Encode()
 	}
 
-	return recoverCommon(tx, message, chainID, vValue, rValue, sValue)
+	return recoverCommon(ctx, tx, message, chainID, vValue, rValue, sValue)
 
 }
 
-func recoverCommon(tx *Transaction, message []byte, chainID int64, v int64, r, s []byte) (*ethtypes.Address0xHex, *TransactionWithOriginalPayload, error) {
+func recoverCommon(ctx context.Context, tx *Transaction, message []byte, chainID int64, v int64, r, s []byte) (*ethtypes.Address0xHex, *TransactionWithOriginalPayload, error) {
+	// R and S are each encoded in 32 bytes of the compact signature, so must not be longer
+	if len(r) > 32 || len(s) > 32 {
+		return nil, nil, i18n.NewError(ctx, signermsgs.MsgInvalidTransactionSignature, "r or s longer than 32 bytes")
+	}
 	foundSig := &secp256k1.SignatureData{
 		V: new(big.Int),
 		R: new(big.Int),
@@ -290,7 +294,7 @@ func recoverCommon(tx *Transaction, message []byte, chainID int64, v int64, r, s
 
 	signer, err := foundSig.Recover(message, chainID)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, i18n.NewError(ctx, signermsgs.MsgInvalidTransactionSignature, err)
 	}
 
 	return signer, &TransactionWithOriginalPayload{
@@ -310,9 +314,8 @@ func decodeEIP1559SignaturePayload(ctx context.Context, rawTx ethtypes.HexBytes0
 		log.L(ctx).Errorf("Invalid EIP-1559 transaction data '%s': %s", rawTx, err)
 		return nil, nil, i18n.NewError(ctx, signermsgs.MsgInvalidEIP1559Transaction, err)
 	}
-	rlpList := decoded.(rlp.List)
-
-	if len(rlpList) < rlpMinLen {
+	rlpList, ok := decoded.(rlp.List)
+	if !ok || len(rlpList) < rlpMinLen {
 		log.L(ctx).Errorf("Invalid EIP-1559 transaction data (%d RLP elements)", rlpList)
 		return nil, nil, i18n.NewError(ctx, signermsgs.MsgInvalidEIP1559Transaction, "EOF")
 	}
@@ -344,10 +347,10 @@ func RecoverEIP1559Transaction(ctx context.Context, rawTx ethtypes.HexBytes0xPre
 		return nil, nil, err
 	}
 
-	return recoverCommon(tx,
+	return recoverCommon(ctx, tx,
 		append([]byte{TransactionType1559}, (rlpList[0:9]).Encode()...),
 		chainID,
-		rlpList[9].ToData().Int().Int64(),
+		rlpList[9].ToData().IntOrZero().Int64(),
 		rlpList[10].ToData().BytesNotNil(),
 		rlpList[11].ToData().BytesNotNil(),
 	)
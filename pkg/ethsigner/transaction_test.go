@@ -295,6 +295,58 @@ func TestRecoverEIP1559BadStructure(t *testing.T) {
 	assert.Regexp(t, "FF22084.*EOF", err)
 }
 
+func TestRecoverEIP1559NotList(t *testing.T) {
+	_, _, err := RecoverEIP1559Transaction(context.Background(), append([]byte{TransactionType1559}, rlp.WrapString("not a list").Encode()...), 1001)
+	assert.Regexp(t, "FF22084.*EOF", err)
+}
+
+func TestRecoverEIP1559NestedSignatureV(t *testing.T) {
+	rlpList := make(rlp.List, 12)
+	for i := range rlpList {
+		rlpList[i] = rlp.WrapInt(big.NewInt(1))
+	}
+	rlpList[0] = rlp.WrapInt(big.NewInt(1001))
+	rlpList[8] = rlp.List{}
+	rlpList[9] = rlp.List{}
+	assert.NotPanics(t, func() {
+		_, _, _ = RecoverEIP1559Transaction(context.Background(), append([]byte{TransactionType1559}, rlpList.Encode()...), 1001)
+	})
+}
+
+func TestRecoverLegacyNestedSignatureV(t *testing.T) {
+	rlpList := make(rlp.List, 9)
+	for i := range rlpList {
+		rlpList[i] = rlp.WrapInt(big.NewInt(1))
+	}
+	rlpList[6] = rlp.List{}
+	_, _, err := RecoverLegacyRawTransaction(context.Background(), rlpList.Encode(), 1001)
+	assert.Regexp(t, "FF22085", err)
+}
+
+func TestRecoverEIP1559SignatureOutOfRange(t *testing.T) {
+	rlpList := make(rlp.List, 12)
+	for i := range rlpList {
+		rlpList[i] = rlp.WrapInt(big.NewInt(1))
+	}
+	rlpList[0] = rlp.WrapInt(big.NewInt(1001))
+	rlpList[8] = rlp.List{}
+	rlpList[9] = rlp.WrapInt(big.NewInt(0))
+	for _, rs := range [][2]rlp.Data{
+		{rlp.WrapInt(big.NewInt(0)), rlp.WrapInt(big.NewInt(1))},
+		{rlp.WrapInt(big.NewInt(1)), rlp.WrapInt(secp256k1Order())},
+		{make(rlp.Data, 33), rlp.WrapInt(big.NewInt(1))},
+	} {
+		rlpList[10], rlpList[11] = rs[0], rs[1]
+		_, _, err := RecoverEIP1559Transaction(context.Background(), append([]byte{TransactionType1559}, rlpList.Encode()...), 1001)
+		assert.Regexp(t, "FF22198", err)
+	}
+}
+
+func secp256k1Order() *big.Int {
+	n, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	return n
+}
+
 func TestDecodeEIP1559SignaturePayloadEmpty(t *testing.T) {
 	_, err := DecodeEIP1559SignaturePayload(context.Background(), []byte{}, 1001)
 	assert.Regexp(t, "FF22084.*TransactionType", err)
@@ -1,4 +1,4 @@
-// Copyright © 2024 Kaleido, Inc.
+// Copyright © 2026 Kaleido, Inc.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -37,3 +37,39 @@ type WalletTypedData interface {
 	Wallet
 	SignTypedDataV4(ctx context.Context, from ethtypes.Address0xHex, payload *eip712.TypedData) (*EIP712Result, error)
 }
+
+// WalletEIP191 is implemented by wallets that can sign arbitrary messages using EIP-191
+type WalletEIP191 interface {
+	Wallet
+	SignEIP191PersonalMessage(ctx context.Context, from ethtypes.Address0xHex, message []byte) (*EIP191Result, error)
+	SignEIP191IntendedValidator(ctx context.Context, from ethtypes.Address0xHex, validator ethtypes.Address0xHex, data []byte) (*EIP191Result, error)
+}
+
+// WalletMetadata is implemented by wallets that store additional metadata for each key,
+// such as the TOML/YAML/JSON metadata files of the filesystem wallet
+type WalletMetadata interface {
+	Wallet
+	GetAccountMetadata(ctx context.Context, addr ethtypes.Address0xHex) (map[string]interface{}, error)
+}
+
+// WalletHealth is implemented by wallets that can report whether they are still able to
+// detect and load keys, such as the filesystem listener of the filesystem wallet
+type WalletHealth interface {
+	Wallet
+	CheckHealth(ctx context.Context) error
+}
+
+// WalletCache is implemented by wallets that keep decrypted keys in memory, such as the
+// signer cache of the filesystem wallet
+type WalletCache interface {
+	Wallet
+	IsKeyCached(ctx context.Context, addr ethtypes.Address0xHex) bool
+	EvictKey(ctx context.Context, addr ethtypes.Address0xHex) bool
+}
+
+// WalletListener is implemented by wallets that notify listeners of each account as it is
+// detected, such as the filesystem listener of the filesystem wallet
+type WalletListener interface {
+	Wallet
+	AddListener(listener chan<- ethtypes.Address0xHex)
+}
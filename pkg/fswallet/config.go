@@ -62,6 +62,8 @@ type Config struct {
 	DisableListener     bool
 	Filenames           FilenamesConfig
 	Metadata            MetadataConfig
+	// Metrics is optionally set by the application (not read from the config section)
+	Metrics Metrics
 }
 
 type ConfigGeneric struct {
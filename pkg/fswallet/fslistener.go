@@ -56,17 +56,23 @@ func (w *fsWallet) fsListenerLoop(ctx context.Context, done func(), events chan
 			log.L(ctx).Infof("File listener exiting")
 			return
 		case event, ok := <-events:
-			if ok {
-				log.L(ctx).Tracef("FSEvent [%s]: %s", event.Op, event.Name)
-				fi, err := os.Stat(event.Name)
-				if err == nil {
-					_ = w.notifyNewFiles(ctx, fi)
-				}
+			if !ok {
+				log.L(ctx).Errorf("File listener stopped: event channel closed")
+				w.fsListenerStopped.Store(true)
+				return
+			}
+			log.L(ctx).Tracef("FSEvent [%s]: %s", event.Op, event.Name)
+			fi, err := os.Stat(event.Name)
+			if err == nil {
+				_ = w.notifyNewFiles(ctx, fi)
 			}
 		case err, ok := <-errors:
-			if ok {
-				log.L(ctx).Errorf("FSEvent error: %s", err)
+			if !ok {
+				log.L(ctx).Errorf("File listener stopped: error channel closed")
+				w.fsListenerStopped.Store(true)
+				return
 			}
+			log.L(ctx).Errorf("FSEvent error: %s", err)
 		}
 	}
 }
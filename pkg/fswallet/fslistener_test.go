@@ -117,3 +117,36 @@ func TestFileListenerRemoveDirWhileListening(t *testing.T) {
 	f.fsListenerLoop(ctx, func() {}, make(chan fsnotify.Event), errs)
 
 }
+
+func TestFileListenerStoppedEventsClosed(t *testing.T) {
+
+	ctx, ew, _, done := newEmptyWalletTestDir(t, true)
+	defer done()
+
+	err := ew.CheckHealth(ctx)
+	assert.NoError(t, err)
+
+	events := make(chan fsnotify.Event)
+	close(events)
+	f := ew.gw.(*fsWallet)
+	f.fsListenerLoop(ctx, func() {}, events, make(chan error))
+
+	err = ew.CheckHealth(ctx)
+	assert.Regexp(t, "FF22140", err)
+
+}
+
+func TestFileListenerStoppedErrorsClosed(t *testing.T) {
+
+	ctx, ew, _, done := newEmptyWalletTestDir(t, true)
+	defer done()
+
+	errs := make(chan error)
+	close(errs)
+	f := ew.gw.(*fsWallet)
+	f.fsListenerLoop(ctx, func() {}, make(chan fsnotify.Event), errs)
+
+	err := ew.CheckHealth(ctx)
+	assert.Regexp(t, "FF22140", err)
+
+}
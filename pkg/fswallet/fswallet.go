@@ -25,6 +25,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"text/template"
 	"time"
 
@@ -47,13 +48,25 @@ type WalletGeneric interface {
 	Initialize(ctx context.Context) error
 	Refresh(ctx context.Context) error
 	Close() error
+	CheckHealth(ctx context.Context) error
 
 	GetAccounts(ctx context.Context) ([]string, error)
 	GetWalletFile(ctx context.Context, addr string) (keystorev3.WalletFile, error)
+	GetMetadata(ctx context.Context, addr string) (map[string]interface{}, error)
+	IsCached(ctx context.Context, addr string) bool
+	EvictCached(ctx context.Context, addr string) bool
 	SetSyncCallback(SyncCallback)
 	AddListener(listener chan<- string)
 }
 
+// Metrics is notified of signing key lookups, and the number of accounts in the wallet
+type Metrics interface {
+	SignerCacheHit(ctx context.Context)
+	SignerCacheMiss(ctx context.Context)
+	KeyDecrypted(ctx context.Context, addr string, duration time.Duration, err error)
+	AccountsLoaded(ctx context.Context, count int)
+}
+
 func NewFilesystemWalletGeneric(ctx context.Context, conf *ConfigGeneric, initialListeners ...chan<- string) (ww WalletGeneric, err error) {
 	w := &fsWallet{
 		conf:             *conf,
@@ -111,6 +124,7 @@ type fsWallet struct {
 	fsListenerCancel  context.CancelFunc
 	fsListenerStarted chan error
 	fsListenerDone    chan struct{}
+	fsListenerStopped atomic.Bool
 }
 
 func (w *fsWallet) Initialize(ctx context.Context) error {
@@ -230,6 +244,9 @@ func (w *fsWallet) processNewFiles(ctx context.Context, files ...fs.FileInfo) (l
 			}
 		}
 	}
+	if w.conf.Metrics != nil {
+		w.conf.Metrics.AccountsLoaded(ctx, len(w.addressList))
+	}
 	listeners = make([]chan<- string, len(w.listeners))
 	copy(listeners, w.listeners)
 	log.L(ctx).Debugf("Processed %d files. Found %d new addresses", len(files), len(newAddresses))
@@ -277,13 +294,27 @@ func (w *fsWallet) Close() error {
 	return nil
 }
 
+// CheckHealth returns an error if the filesystem listener has stopped, so new keys are no longer detected
+func (w *fsWallet) CheckHealth(ctx context.Context) error {
+	if w.fsListenerStopped.Load() {
+		return i18n.NewError(ctx, signermsgs.MsgWalletListenerStopped)
+	}
+	return nil
+}
+
 func (w *fsWallet) GetWalletFile(ctx context.Context, addrString string) (keystorev3.WalletFile, error) {
 
 	cached := w.signerCache.Get(addrString)
 	if cached != nil {
 		cached.Extend(w.signerCacheTTL)
+		if w.conf.Metrics != nil {
+			w.conf.Metrics.SignerCacheHit(ctx)
+		}
 		return cached.Value().(keystorev3.WalletFile), nil
 	}
+	if w.conf.Metrics != nil {
+		w.conf.Metrics.SignerCacheMiss(ctx)
+	}
 
 	w.mux.Lock()
 	primaryFilename, ok := w.addressToFileMap[addrString]
@@ -292,19 +323,35 @@ func (w *fsWallet) GetWalletFile(ctx context.Context, addrString string) (keysto
 		return nil, i18n.NewError(ctx, signermsgs.MsgWalletNotAvailable, addrString)
 	}
 
+	startTime := time.Now()
 	kv3, err := w.loadWalletFile(ctx, addrString, path.Join(w.conf.Path, primaryFilename))
+	if err == nil && w.conf.WalletFileValidator != nil {
+		err = w.conf.WalletFileValidator(ctx, addrString, kv3)
+	}
+	if w.conf.Metrics != nil {
+		w.conf.Metrics.KeyDecrypted(ctx, addrString, time.Since(startTime), err)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if w.conf.WalletFileValidator != nil {
-		if err := w.conf.WalletFileValidator(ctx, addrString, kv3); err != nil {
-			return nil, err
-		}
-	}
-
 	w.signerCache.Set(addrString, kv3, w.signerCacheTTL)
-	return kv3, err
+	return kv3, nil
+}
+
+// IsCached returns whether the decrypted key for the address is in the signer cache
+func (w *fsWallet) IsCached(_ context.Context, addrString string) bool {
+	return w.signerCache.Get(addrString) != nil
+}
+
+// EvictCached removes the decrypted key for the address from the signer cache, so it is loaded
+// and decrypted again on next use. Returns whether the key was in the cache.
+func (w *fsWallet) EvictCached(ctx context.Context, addrString string) bool {
+	evicted := w.signerCache.Delete(addrString)
+	if evicted {
+		log.L(ctx).Infof("Evicted key for '%s' from the signer cache", addrString)
+	}
+	return evicted
 }
 
 func (w *fsWallet) loadWalletFile(ctx context.Context, addr string, primaryFilename string) (keystorev3.WalletFile, error) {
@@ -364,12 +411,31 @@ func (w *fsWallet) loadWalletFile(ctx context.Context, addr string, primaryFilen
 
 }
 
-func (w *fsWallet) getKeyAndPasswordFiles(ctx context.Context, addr string, primaryFilename string, primaryFile []byte) (kf string, pf string, err error) {
+// GetMetadata returns the parsed metadata file for the address, or nil if the wallet is configured
+// without metadata files (format "filename")
+func (w *fsWallet) GetMetadata(ctx context.Context, addrString string) (map[string]interface{}, error) {
+	w.mux.Lock()
+	primaryFilename, ok := w.addressToFileMap[addrString]
+	w.mux.Unlock()
+	if !ok {
+		return nil, i18n.NewError(ctx, signermsgs.MsgWalletNotAvailable, addrString)
+	}
+
+	primaryFilename = path.Join(w.conf.Path, primaryFilename)
+	b, err := os.ReadFile(primaryFilename)
+	if err != nil {
+		log.L(ctx).Errorf("Failed to read '%s': %s", primaryFilename, err)
+		return nil, i18n.NewError(ctx, signermsgs.MsgWalletFailed, addrString)
+	}
+	metadata, _, err := w.parseMetadata(ctx, addrString, primaryFilename, b)
+	return metadata, err
+}
+
+func (w *fsWallet) parseMetadata(ctx context.Context, addr string, primaryFilename string, primaryFile []byte) (metadata map[string]interface{}, hasMetadata bool, err error) {
 	if strings.ToLower(w.conf.Metadata.Format) == "auto" {
 		w.conf.Metadata.Format = strings.TrimPrefix(w.conf.Filenames.PrimaryExt, ".")
 	}
 
-	var metadata map[string]interface{}
 	switch w.conf.Metadata.Format {
 	case "toml", "tml":
 		err = toml.Unmarshal(primaryFile, &metadata)
@@ -378,6 +444,21 @@ func (w *fsWallet) getKeyAndPasswordFiles(ctx context.Context, addr string, prim
 	case "yaml", "yml":
 		err = yaml.Unmarshal(primaryFile, &metadata)
 	default:
+		return nil, false, nil
+	}
+	if err != nil {
+		log.L(ctx).Errorf("Failed to parse '%s' as %s: %s", primaryFilename, w.conf.Metadata.Format, err)
+		return nil, true, i18n.NewError(ctx, signermsgs.MsgWalletFailed, addr)
+	}
+	return metadata, true, nil
+}
+
+func (w *fsWallet) getKeyAndPasswordFiles(ctx context.Context, addr string, primaryFilename string, primaryFile []byte) (kf string, pf string, err error) {
+	metadata, hasMetadata, err := w.parseMetadata(ctx, addr, primaryFilename, primaryFile)
+	if err != nil {
+		return "", "", err
+	}
+	if !hasMetadata {
 		// No separate metadata file - we just use the default password file extension instead
 		passwordPath := w.conf.Filenames.PasswordPath
 		if passwordPath == "" {
@@ -390,10 +471,6 @@ func (w *fsWallet) getKeyAndPasswordFiles(ctx context.Context, addr string, prim
 		passwordFilename += w.conf.Filenames.PasswordExt
 		return primaryFilename, path.Join(passwordPath, passwordFilename), nil
 	}
-	if err != nil {
-		log.L(ctx).Errorf("Failed to parse '%s' as %s: %s", primaryFilename, w.conf.Metadata.Format, err)
-		return "", "", i18n.NewError(ctx, signermsgs.MsgWalletFailed, addr)
-	}
 
 	kf, err = w.goTemplateToString(ctx, primaryFilename, metadata, w.metadataKeyFileProperty)
 	if err == nil {
@@ -1,4 +1,4 @@
-// Copyright © 2023 Kaleido, Inc.
+// Copyright © 2026 Kaleido, Inc.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -22,7 +22,6 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-signer/internal/signermsgs"
-	"github.com/hyperledger/firefly-signer/pkg/eip712"
 	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
 	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
 	"github.com/hyperledger/firefly-signer/pkg/keystorev3"
@@ -38,12 +37,17 @@ type SyncAddressCallback func(context.Context, ethtypes.Address0xHex) error
 // of an address more generic.
 type Wallet interface {
 	ethsigner.WalletTypedData
+	ethsigner.WalletEIP191
+	ethsigner.WalletMetadata
+	ethsigner.WalletHealth
+	ethsigner.WalletCache
 	GetWalletFile(ctx context.Context, addr ethtypes.Address0xHex) (keystorev3.WalletFile, error)
 	SetSyncAddressCallback(SyncAddressCallback)
 	AddListener(listener chan<- ethtypes.Address0xHex)
 }
 
 type walletEthAddr struct {
+	*ethsigner.KeySigner
 	gw WalletGeneric
 }
 
@@ -71,9 +75,11 @@ func NewFilesystemWallet(ctx context.Context, conf *Config, initialListeners ...
 	if err != nil {
 		return nil, err
 	}
-	return &walletEthAddr{
+	e := &walletEthAddr{
 		gw: gw,
-	}, nil
+	}
+	e.KeySigner = ethsigner.NewKeySigner(e.getSignerForAddr)
+	return e, nil
 }
 
 func ethProxyListeners(listeners ...chan<- ethtypes.Address0xHex) []chan<- string {
@@ -101,6 +107,10 @@ func (e *walletEthAddr) Close() error {
 	return e.gw.Close()
 }
 
+func (e *walletEthAddr) CheckHealth(ctx context.Context) error {
+	return e.gw.CheckHealth(ctx)
+}
+
 func (e *walletEthAddr) GetAccounts(ctx context.Context) (addrs []*ethtypes.Address0xHex, err error) {
 	addrStrs, err := e.gw.GetAccounts(ctx)
 	if err == nil {
@@ -118,6 +128,18 @@ func (e *walletEthAddr) GetWalletFile(ctx context.Context, addr ethtypes.Address
 	return e.gw.GetWalletFile(ctx, addr.String())
 }
 
+func (e *walletEthAddr) GetAccountMetadata(ctx context.Context, addr ethtypes.Address0xHex) (map[string]interface{}, error) {
+	return e.gw.GetMetadata(ctx, addr.String())
+}
+
+func (e *walletEthAddr) IsKeyCached(ctx context.Context, addr ethtypes.Address0xHex) bool {
+	return e.gw.IsCached(ctx, addr.String())
+}
+
+func (e *walletEthAddr) EvictKey(ctx context.Context, addr ethtypes.Address0xHex) bool {
+	return e.gw.EvictCached(ctx, addr.String())
+}
+
 func (e *walletEthAddr) Initialize(ctx context.Context) error {
 	return e.gw.Initialize(ctx)
 }
@@ -136,7 +158,7 @@ func (e *walletEthAddr) SetSyncAddressCallback(cb SyncAddressCallback) {
 	})
 }
 
-func (e *walletEthAddr) getSignerForJSONAccount(ctx context.Context, rawAddrJSON json.RawMessage) (*secp256k1.KeyPair, error) {
+func (e *walletEthAddr) getSignerForJSONAccount(ctx context.Context, rawAddrJSON json.RawMessage) (secp256k1.SignerDirect, error) {
 
 	// We require an ethereum address in the "from" field
 	var from ethtypes.Address0xHex
@@ -147,7 +169,7 @@ func (e *walletEthAddr) getSignerForJSONAccount(ctx context.Context, rawAddrJSON
 	return e.getSignerForAddr(ctx, from)
 }
 
-func (e *walletEthAddr) getSignerForAddr(ctx context.Context, from ethtypes.Address0xHex) (*secp256k1.KeyPair, error) {
+func (e *walletEthAddr) getSignerForAddr(ctx context.Context, from ethtypes.Address0xHex) (secp256k1.SignerDirect, error) {
 
 	wf, err := e.GetWalletFile(ctx, from)
 	if err != nil {
@@ -156,19 +178,3 @@ func (e *walletEthAddr) getSignerForAddr(ctx context.Context, from ethtypes.Addr
 	return wf.KeyPair(), nil
 
 }
-
-func (e *walletEthAddr) Sign(ctx context.Context, txn *ethsigner.Transaction, chainID int64) ([]byte, error) {
-	keypair, err := e.getSignerForJSONAccount(ctx, txn.From)
-	if err != nil {
-		return nil, err
-	}
-	return txn.Sign(keypair, chainID)
-}
-
-func (e *walletEthAddr) SignTypedDataV4(ctx context.Context, from ethtypes.Address0xHex, payload *eip712.TypedData) (*ethsigner.EIP712Result, error) {
-	keypair, err := e.getSignerForAddr(ctx, from)
-	if err != nil {
-		return nil, err
-	}
-	return ethsigner.SignTypedDataV4(ctx, keypair, payload)
-}
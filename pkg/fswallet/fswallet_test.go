@@ -23,6 +23,7 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-signer/pkg/eip712"
@@ -234,6 +235,51 @@ func TestSignTypedDataOK(t *testing.T) {
 
 }
 
+func TestSignEIP191PersonalMessageOK(t *testing.T) {
+
+	ctx, f, done := newTestTOMLMetadataWallet(t, true)
+	defer done()
+
+	addr := *ethtypes.MustNewAddress(`0x1f185718734552d08278aa70f804580bab5fd2b4`)
+	res, err := f.SignEIP191PersonalMessage(ctx, addr, []byte("Hello World"))
+	assert.NoError(t, err)
+
+	ok, err := ethsigner.VerifyEIP191PersonalMessage(ctx, []byte("Hello World"), res.SignatureRSV, addr)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+}
+
+func TestSignEIP191IntendedValidatorOK(t *testing.T) {
+
+	ctx, f, done := newTestTOMLMetadataWallet(t, true)
+	defer done()
+
+	addr := *ethtypes.MustNewAddress(`0x1f185718734552d08278aa70f804580bab5fd2b4`)
+	validator := *ethtypes.MustNewAddress(`0x497eedc4299dea2f2a364be10025d0ad0f702de3`)
+	res, err := f.SignEIP191IntendedValidator(ctx, addr, validator, []byte("some data"))
+	assert.NoError(t, err)
+
+	ok, err := ethsigner.VerifyEIP191IntendedValidator(ctx, validator, []byte("some data"), res.SignatureRSV, addr)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+}
+
+func TestSignEIP191NotFound(t *testing.T) {
+
+	ctx, f, done := newTestTOMLMetadataWallet(t, true)
+	defer done()
+
+	addr := *ethtypes.MustNewAddress(`0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF`)
+	_, err := f.SignEIP191PersonalMessage(ctx, addr, []byte("Hello World"))
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = f.SignEIP191IntendedValidator(ctx, addr, addr, []byte("Hello World"))
+	assert.Regexp(t, "FF22014", err)
+
+}
+
 func TestSignNotFound(t *testing.T) {
 
 	ctx, f, done := newTestTOMLMetadataWallet(t, true)
@@ -274,6 +320,24 @@ func TestGetAccountCached(t *testing.T) {
 
 }
 
+func TestKeyCacheEvict(t *testing.T) {
+
+	ctx, f, done := newTestTOMLMetadataWallet(t, true)
+	defer done()
+
+	addr := *ethtypes.MustNewAddress("0x1f185718734552d08278aa70f804580bab5fd2b4")
+	assert.False(t, f.IsKeyCached(ctx, addr))
+	assert.False(t, f.EvictKey(ctx, addr))
+
+	_, err := f.GetWalletFile(ctx, addr)
+	assert.NoError(t, err)
+	assert.True(t, f.IsKeyCached(ctx, addr))
+
+	assert.True(t, f.EvictKey(ctx, addr))
+	assert.False(t, f.IsKeyCached(ctx, addr))
+
+}
+
 func TestGetAccountBadYAML(t *testing.T) {
 
 	ctx, f, done := newTestTOMLMetadataWallet(t, true)
@@ -506,3 +570,114 @@ func TestSyncInitCallbackError(t *testing.T) {
 	err = ff.Initialize(ctx)
 	assert.Regexp(t, "pop", err)
 }
+
+func TestGetAccountMetadataTOML(t *testing.T) {
+
+	ctx, f, done := newTestTOMLMetadataWallet(t, true)
+	defer done()
+
+	metadata, err := f.GetAccountMetadata(ctx, *ethtypes.MustNewAddress("0x1f185718734552d08278aa70f804580bab5fd2b4"))
+	assert.NoError(t, err)
+	assert.Equal(t, "1000000000000000000", metadata["policy"].(map[string]interface{})["maxValue"])
+
+}
+
+func TestGetAccountMetadataFilenameOnly(t *testing.T) {
+
+	ctx, f, done := newTestRegexpFilenameOnlyWallet(t, true)
+	defer done()
+
+	metadata, err := f.GetAccountMetadata(ctx, *ethtypes.MustNewAddress("0x1f185718734552d08278aa70f804580bab5fd2b4"))
+	assert.NoError(t, err)
+	assert.Nil(t, metadata)
+
+}
+
+func TestGetAccountMetadataNotFound(t *testing.T) {
+
+	ctx, f, done := newTestTOMLMetadataWallet(t, true)
+	defer done()
+
+	_, err := f.GetAccountMetadata(ctx, *ethtypes.MustNewAddress("0xabcd1234abcd1234abcd1234abcd1234abcd1234"))
+	assert.Regexp(t, "FF22014", err)
+
+}
+
+func TestGetAccountMetadataBadFormat(t *testing.T) {
+
+	ctx, f, done := newTestTOMLMetadataWallet(t, true)
+	defer done()
+	conf := &f.gw.(*fsWallet).conf
+	conf.Metadata.Format = "json"
+
+	_, err := f.GetAccountMetadata(ctx, *ethtypes.MustNewAddress("0x1f185718734552d08278aa70f804580bab5fd2b4"))
+	assert.Regexp(t, "FF22015", err)
+
+}
+
+func TestGetAccountMetadataReadFail(t *testing.T) {
+
+	ctx, f, done := newTestTOMLMetadataWallet(t, true)
+	defer done()
+	fw := f.gw.(*fsWallet)
+	fw.addressToFileMap["0xabcd1234abcd1234abcd1234abcd1234abcd1234"] = "missing.toml"
+
+	_, err := f.GetAccountMetadata(ctx, *ethtypes.MustNewAddress("0xabcd1234abcd1234abcd1234abcd1234abcd1234"))
+	assert.Regexp(t, "FF22015", err)
+
+}
+
+type testMetrics struct {
+	hits, misses  int
+	decrypted     []string
+	decryptErrors int
+	accounts      int
+}
+
+func (tm *testMetrics) SignerCacheHit(ctx context.Context)  { tm.hits++ }
+func (tm *testMetrics) SignerCacheMiss(ctx context.Context) { tm.misses++ }
+func (tm *testMetrics) KeyDecrypted(ctx context.Context, addr string, duration time.Duration, err error) {
+	tm.decrypted = append(tm.decrypted, addr)
+	if err != nil {
+		tm.decryptErrors++
+	}
+}
+func (tm *testMetrics) AccountsLoaded(ctx context.Context, count int) { tm.accounts = count }
+
+func TestGetAccountMetrics(t *testing.T) {
+
+	ctx, f, done := newTestTOMLMetadataWallet(t, false)
+	defer done()
+	tm := &testMetrics{}
+	f.gw.(*fsWallet).conf.Metrics = tm
+	err := f.Initialize(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, tm.accounts)
+
+	for i := 0; i < 2; i++ {
+		_, err = f.getSignerForJSONAccount(ctx, json.RawMessage(`"0x1f185718734552d08278aa70f804580bab5fd2b4"`))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, tm.hits)
+	assert.Equal(t, 1, tm.misses)
+	assert.Equal(t, []string{"0x1f185718734552d08278aa70f804580bab5fd2b4"}, tm.decrypted)
+	assert.Zero(t, tm.decryptErrors)
+
+}
+
+func TestGetAccountMetricsDecryptFail(t *testing.T) {
+
+	ctx, f, done := newTestRegexpFilenameOnlyWallet(t, false)
+	defer done()
+	tm := &testMetrics{}
+	f.gw.(*fsWallet).conf.Metrics = tm
+	f.gw.(*fsWallet).conf.Filenames.PasswordExt = ".wrong"
+	err := f.Initialize(ctx)
+	assert.NoError(t, err)
+
+	_, err = f.getSignerForJSONAccount(ctx, json.RawMessage(`"0x1f185718734552d08278aa70f804580bab5fd2b4"`))
+	assert.Regexp(t, "FF22015", err)
+	assert.Equal(t, 1, tm.misses)
+	assert.Equal(t, 1, tm.decryptErrors)
+
+}
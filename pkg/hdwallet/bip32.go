@@ -0,0 +1,298 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hdwallet
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	btcec "github.com/btcsuite/btcd/btcec/v2" // ISC licensed
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // HASH160 is required for BIP-32 fingerprints
+)
+
+// HardenedOffset is added to the index of a hardened child key, shown with a ' in a path
+const HardenedOffset uint32 = 0x80000000
+
+// serializedKeyLen is the length of a serialized extended key, before the base58 checksum
+const serializedKeyLen = 78
+
+var (
+	versionMainnetPrivate = []byte{0x04, 0x88, 0xad, 0xe4} // xprv
+	versionTestnetPrivate = []byte{0x04, 0x35, 0x83, 0x94} // tprv
+	masterKeyHMACKey      = []byte("Bitcoin seed")
+)
+
+// ExtendedKey is a BIP-32 extended private key - a secp256k1 private key, with the chain code
+// used to derive its children
+type ExtendedKey struct {
+	version           []byte
+	depth             byte
+	parentFingerprint []byte
+	childNumber       uint32
+	chainCode         []byte
+	key               []byte
+}
+
+// DerivationPath is a list of child indexes from the master key, with hardened indexes
+// including the HardenedOffset
+type DerivationPath []uint32
+
+// NewMasterKey derives the master extended key from a 16-64 byte seed, such as the seed from
+// a BIP-39 mnemonic
+func NewMasterKey(ctx context.Context, seed []byte) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidSeedLength, len(seed))
+	}
+	mac := hmac.New(sha512.New, masterKeyHMACKey)
+	mac.Write(seed)
+	i := mac.Sum(nil)
+	if !validPrivateKey(i[:32]) {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidDerivedKey, "m")
+	}
+	return &ExtendedKey{
+		version:           versionMainnetPrivate,
+		parentFingerprint: []byte{0, 0, 0, 0},
+		chainCode:         i[32:],
+		key:               i[:32],
+	}, nil
+}
+
+// ParseExtendedKey parses a base58 encoded xprv (or tprv) extended private key
+func ParseExtendedKey(ctx context.Context, encoded string) (*ExtendedKey, error) {
+	b, err := base58Decode(encoded)
+	if err != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidExtendedKey, err)
+	}
+	if len(b) != serializedKeyLen+4 {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidExtendedKey, fmt.Sprintf("length %d", len(b)))
+	}
+	checksum := doubleSHA256(b[:serializedKeyLen])
+	if !bytes.Equal(checksum[:4], b[serializedKeyLen:]) {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidExtendedKey, "checksum mismatch")
+	}
+	return DeserializeExtendedKey(ctx, b[:serializedKeyLen])
+}
+
+// DeserializeExtendedKey parses the 78 byte serialized form of an extended private key
+func DeserializeExtendedKey(ctx context.Context, b []byte) (*ExtendedKey, error) {
+	if len(b) != serializedKeyLen {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidExtendedKey, fmt.Sprintf("length %d", len(b)))
+	}
+	version := b[0:4]
+	if !bytes.Equal(version, versionMainnetPrivate) && !bytes.Equal(version, versionTestnetPrivate) {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidExtendedKey, "not a private key")
+	}
+	if b[45] != 0x00 || !validPrivateKey(b[46:78]) {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidExtendedKey, "invalid key data")
+	}
+	return &ExtendedKey{
+		version:           append([]byte{}, version...),
+		depth:             b[4],
+		parentFingerprint: append([]byte{}, b[5:9]...),
+		childNumber:       binary.BigEndian.Uint32(b[9:13]),
+		chainCode:         append([]byte{}, b[13:45]...),
+		key:               append([]byte{}, b[46:78]...),
+	}, nil
+}
+
+// Serialize returns the 78 byte serialized form of the extended key
+func (k *ExtendedKey) Serialize() []byte {
+	b := make([]byte, 0, serializedKeyLen)
+	b = append(b, k.version...)
+	b = append(b, k.depth)
+	b = append(b, k.parentFingerprint...)
+	b = binary.BigEndian.AppendUint32(b, k.childNumber)
+	b = append(b, k.chainCode...)
+	b = append(b, 0x00)
+	return append(b, k.key...)
+}
+
+// String returns the base58 encoded xprv (or tprv) form of the extended key
+func (k *ExtendedKey) String() string {
+	b := k.Serialize()
+	checksum := doubleSHA256(b)
+	return base58Encode(append(b, checksum[:4]...))
+}
+
+// KeyPair returns the secp256k1 key pair for signing with the extended key
+func (k *ExtendedKey) KeyPair() *secp256k1.KeyPair {
+	return secp256k1.KeyPairFromBytes(k.key)
+}
+
+func (k *ExtendedKey) compressedPublicKey() []byte {
+	_, pubKey := btcec.PrivKeyFromBytes(k.key)
+	return pubKey.SerializeCompressed()
+}
+
+// Child derives the child private key at the index, which is hardened if it includes the HardenedOffset
+func (k *ExtendedKey) Child(ctx context.Context, index uint32) (*ExtendedKey, error) {
+	parentPublicKey := k.compressedPublicKey()
+	data := make([]byte, 0, 37)
+	if index >= HardenedOffset {
+		data = append(append(data, 0x00), k.key...)
+	} else {
+		data = append(data, parentPublicKey...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	i := mac.Sum(nil)
+
+	// The child key is the parent key plus the left half of the HMAC, mod n
+	var il, childKey btcec.ModNScalar
+	if overflow := il.SetByteSlice(i[:32]); overflow {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidDerivedKey, formatIndex(index))
+	}
+	childKey.SetByteSlice(k.key)
+	childKey.Add(&il)
+	if childKey.IsZero() {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidDerivedKey, formatIndex(index))
+	}
+	childKeyBytes := childKey.Bytes()
+
+	fingerprint := hash160(parentPublicKey)
+	return &ExtendedKey{
+		version:           k.version,
+		depth:             k.depth + 1,
+		parentFingerprint: fingerprint[:4],
+		childNumber:       index,
+		chainCode:         i[32:],
+		key:               childKeyBytes[:],
+	}, nil
+}
+
+// Derive derives the private key at the path, relative to this key
+func (k *ExtendedKey) Derive(ctx context.Context, path DerivationPath) (key *ExtendedKey, err error) {
+	key = k
+	for _, index := range path {
+		if key, err = key.Child(ctx, index); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// ParseDerivationPath parses a path such as m/44'/60'/0'/0/0, where a ' (or h/H) suffix is a hardened index
+func ParseDerivationPath(ctx context.Context, s string) (DerivationPath, error) {
+	segments := strings.Split(strings.TrimSpace(s), "/")
+	if segments[0] != "m" {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidDerivationPath, s)
+	}
+	path := make(DerivationPath, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		var offset uint32
+		if trimmed := strings.TrimRight(segment, "'hH"); len(trimmed) == len(segment)-1 {
+			segment, offset = trimmed, HardenedOffset
+		}
+		index, err := strconv.ParseUint(segment, 10, 31)
+		if err != nil {
+			return nil, i18n.NewError(ctx, signermsgs.MsgInvalidDerivationPath, s)
+		}
+		path = append(path, uint32(index)+offset)
+	}
+	return path, nil
+}
+
+// Append returns a new path with the indexes added to the end
+func (p DerivationPath) Append(indexes ...uint32) DerivationPath {
+	return append(append(make(DerivationPath, 0, len(p)+len(indexes)), p...), indexes...)
+}
+
+func (p DerivationPath) String() string {
+	var buff strings.Builder
+	buff.WriteString("m")
+	for _, index := range p {
+		buff.WriteString("/")
+		buff.WriteString(formatIndex(index))
+	}
+	return buff.String()
+}
+
+func formatIndex(index uint32) string {
+	if index >= HardenedOffset {
+		return strconv.FormatUint(uint64(index-HardenedOffset), 10) + "'"
+	}
+	return strconv.FormatUint(uint64(index), 10)
+}
+
+func validPrivateKey(b []byte) bool {
+	var k btcec.ModNScalar
+	overflow := k.SetByteSlice(b)
+	return !overflow && !k.IsZero()
+}
+
+func doubleSHA256(b []byte) [32]byte {
+	h := sha256.Sum256(b)
+	return sha256.Sum256(h[:])
+}
+
+func hash160(b []byte) []byte {
+	h := sha256.Sum256(b)
+	r := ripemd160.New()
+	r.Write(h[:])
+	return r.Sum(nil)
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var bigRadix = big.NewInt(58)
+
+func base58Encode(b []byte) string {
+	x := new(big.Int).SetBytes(b)
+	mod := new(big.Int)
+	encoded := make([]byte, 0, len(b)*138/100+1)
+	for x.Sign() > 0 {
+		x.DivMod(x, bigRadix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	// Leading zero bytes are each encoded as a leading 1
+	for i := 0; i < len(b) && b[i] == 0; i++ {
+		encoded = append(encoded, base58Alphabet[0])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+func base58Decode(s string) ([]byte, error) {
+	x := new(big.Int)
+	for _, c := range []byte(s) {
+		digit := strings.IndexByte(base58Alphabet, c)
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character '%c'", c)
+		}
+		x.Mul(x, bigRadix)
+		x.Add(x, big.NewInt(int64(digit)))
+	}
+	leadingZeros := 0
+	for leadingZeros < len(s) && s[leadingZeros] == base58Alphabet[0] {
+		leadingZeros++
+	}
+	return append(make([]byte, leadingZeros), x.Bytes()...), nil
+}
@@ -0,0 +1,146 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hdwallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bip32TestVector struct {
+	path string
+	xprv string
+}
+
+// Test vectors 1 and 3 from BIP-32
+func TestBIP32Vectors(t *testing.T) {
+	ctx := context.Background()
+	for seed, vectors := range map[string][]bip32TestVector{
+		"000102030405060708090a0b0c0d0e0f": {
+			{"m", "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"},
+			{"m/0'", "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7"},
+			{"m/0'/1", "xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs"},
+			{"m/0'/1/2'", "xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM"},
+			{"m/0'/1/2'/2", "xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334"},
+			{"m/0H/1/2H/2/1000000000", "xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76"},
+		},
+		"4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be": {
+			{"m", "xprv9s21ZrQH143K25QhxbucbDDuQ4naNntJRi4KUfWT7xo4EKsHt2QJDu7KXp1A3u7Bi1j8ph3EGsZ9Xvz9dGuVrtHHs7pXeTzjuxBrCmmhgC6"},
+			{"m/0h", "xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L"},
+		},
+	} {
+		seedBytes, _ := hex.DecodeString(seed)
+		master, err := NewMasterKey(ctx, seedBytes)
+		assert.NoError(t, err)
+		for _, v := range vectors {
+			path, err := ParseDerivationPath(ctx, v.path)
+			assert.NoError(t, err)
+			key, err := master.Derive(ctx, path)
+			assert.NoError(t, err)
+			assert.Equal(t, v.xprv, key.String(), v.path)
+
+			parsed, err := ParseExtendedKey(ctx, v.xprv)
+			assert.NoError(t, err)
+			assert.Equal(t, key, parsed)
+		}
+	}
+}
+
+func TestNewMasterKeyBadSeed(t *testing.T) {
+	_, err := NewMasterKey(context.Background(), make([]byte, 15))
+	assert.Regexp(t, "FF22170", err)
+	_, err = NewMasterKey(context.Background(), make([]byte, 65))
+	assert.Regexp(t, "FF22170", err)
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	ctx := context.Background()
+	path, err := ParseDerivationPath(ctx, "m/44'/60'/0'/0/7")
+	assert.NoError(t, err)
+	assert.Equal(t, DerivationPath{HardenedOffset + 44, HardenedOffset + 60, HardenedOffset, 0, 7}, path)
+	assert.Equal(t, "m/44'/60'/0'/0/7", path.String())
+	assert.Equal(t, "m/44'/60'/0'/0/7/8'", path.Append(HardenedOffset+8).String())
+
+	path, err = ParseDerivationPath(ctx, "m")
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+
+	for _, bad := range []string{"", "44'/60'", "m/", "m/x", "m/0''", "m/h", "m/2147483648", "m/-1"} {
+		_, err = ParseDerivationPath(ctx, bad)
+		assert.Regexp(t, "FF22171", err, bad)
+	}
+}
+
+func TestParseExtendedKeyErrors(t *testing.T) {
+	ctx := context.Background()
+	xprv := "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"
+	key, err := ParseExtendedKey(ctx, xprv)
+	assert.NoError(t, err)
+
+	_, err = ParseExtendedKey(ctx, "xprv0")
+	assert.Regexp(t, "FF22169.*base58", err)
+
+	_, err = ParseExtendedKey(ctx, "xprv9s21ZrQH143K")
+	assert.Regexp(t, "FF22169.*length", err)
+
+	_, err = ParseExtendedKey(ctx, xprv[:len(xprv)-1]+"j")
+	assert.Regexp(t, "FF22169.*checksum", err)
+
+	// Public extended key (xpub) from BIP-32 test vector 1
+	_, err = ParseExtendedKey(ctx, "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8")
+	assert.Regexp(t, "FF22169.*not a private key", err)
+
+	b := key.Serialize()
+	b[45] = 0x02
+	_, err = DeserializeExtendedKey(ctx, b)
+	assert.Regexp(t, "FF22169.*invalid key data", err)
+
+	_, err = DeserializeExtendedKey(ctx, b[1:])
+	assert.Regexp(t, "FF22169.*length", err)
+}
+
+func TestChildOfMaxKey(t *testing.T) {
+	ctx := context.Background()
+	key, err := NewMasterKey(ctx, make([]byte, 16))
+	assert.NoError(t, err)
+
+	// The child key wraps around the curve order n, when the parent key is n-1
+	key.key, _ = hex.DecodeString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140")
+	for i := uint32(0); i < 10; i++ {
+		child, err := key.Child(ctx, i)
+		assert.NoError(t, err)
+		assert.True(t, validPrivateKey(child.key))
+	}
+}
+
+func TestValidPrivateKey(t *testing.T) {
+	assert.False(t, validPrivateKey(make([]byte, 32)))
+	assert.False(t, validPrivateKey(bytes.Repeat([]byte{0xff}, 32)))
+	assert.True(t, validPrivateKey(append(make([]byte, 31), 0x01)))
+}
+
+func TestBase58LeadingZeros(t *testing.T) {
+	encoded := base58Encode([]byte{0, 0, 1})
+	assert.Equal(t, "112", encoded)
+	decoded, err := base58Decode(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 1}, decoded)
+}
@@ -0,0 +1,108 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hdwallet
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// The BIP-39 English wordlist - https://github.com/bitcoin/bips/blob/master/bip-0039/english.txt
+//
+//go:embed wordlist_english.txt
+var englishWordlist string
+
+var (
+	wordList    = strings.Fields(englishWordlist)
+	wordIndexes = func() map[string]int {
+		m := make(map[string]int, len(wordList))
+		for i, w := range wordList {
+			m[w] = i
+		}
+		return m
+	}()
+)
+
+// NewMnemonic returns the BIP-39 mnemonic for 16-32 bytes of entropy, in multiples of 4 bytes,
+// giving 12-24 words
+func NewMnemonic(ctx context.Context, entropy []byte) (string, error) {
+	if len(entropy) < 16 || len(entropy) > 32 || len(entropy)%4 != 0 {
+		return "", i18n.NewError(ctx, signermsgs.MsgInvalidEntropyLength, len(entropy))
+	}
+	checksum := sha256.Sum256(entropy)
+	// The checksum is the first len(entropy)/4 bits of the hash, appended to the entropy
+	bits := append(append([]byte{}, entropy...), checksum[0])
+	wordCount := (len(entropy)*8 + len(entropy)/4) / 11
+	words := make([]string, wordCount)
+	for i := range words {
+		index := 0
+		for b := i * 11; b < (i+1)*11; b++ {
+			index = index<<1 | int(bits[b/8]>>(7-b%8)&1)
+		}
+		words[i] = wordList[index]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy validates the words and checksum of a BIP-39 mnemonic, and returns the entropy
+func MnemonicToEntropy(ctx context.Context, mnemonic string) ([]byte, error) {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidMnemonic, "word count must be 12, 15, 18, 21 or 24")
+	}
+	bits := make([]byte, (len(words)*11+7)/8)
+	for i, w := range words {
+		index, ok := wordIndexes[w]
+		if !ok {
+			return nil, i18n.NewError(ctx, signermsgs.MsgInvalidMnemonic, "word "+w+" is not in the wordlist")
+		}
+		for b := 0; b < 11; b++ {
+			if index&(1<<(10-b)) != 0 {
+				pos := i*11 + b
+				bits[pos/8] |= 1 << (7 - pos%8)
+			}
+		}
+	}
+	checksumBits := len(words) / 3
+	entropy := bits[:(len(words)*11-checksumBits)/8]
+	checksum := sha256.Sum256(entropy)
+	if bits[len(entropy)]>>(8-checksumBits) != checksum[0]>>(8-checksumBits) {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidMnemonic, "checksum mismatch")
+	}
+	return entropy, nil
+}
+
+// MnemonicToSeed validates a BIP-39 mnemonic, and returns the 64 byte seed for the mnemonic and
+// passphrase. The passphrase is optional.
+func MnemonicToSeed(ctx context.Context, mnemonic, passphrase string) ([]byte, error) {
+	if _, err := MnemonicToEntropy(ctx, mnemonic); err != nil {
+		return nil, err
+	}
+	normalized := strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+	return pbkdf2.Key([]byte(normalized), []byte(salt), 2048, 64, sha512.New), nil
+}
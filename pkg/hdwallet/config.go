@@ -0,0 +1,79 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hdwallet
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+)
+
+const (
+	// ConfigSeedFile the Keystore V3 file containing the encrypted seed, or extended private key
+	ConfigSeedFile = "seedFile"
+	// ConfigPasswordFile the file containing the password to decrypt the seed file
+	ConfigPasswordFile = "passwordFile"
+	// ConfigPasswordTrimSpace whether to trim whitespace from the password loaded from the file (such as trailing newline characters)
+	ConfigPasswordTrimSpace = "passwordTrimSpace"
+	// ConfigPath the BIP-32 path of the parent key of the range of accounts
+	ConfigPath = "path"
+	// ConfigAccountsStart the index of the first account in the range, under the path
+	ConfigAccountsStart = "accounts.start"
+	// ConfigAccountsCount the number of accounts in the range
+	ConfigAccountsCount = "accounts.count"
+	// ConfigPaths an explicit list of BIP-32 paths of the accounts, used instead of the range
+	ConfigPaths = "paths"
+)
+
+// DefaultPath is the BIP-44 path of the external chain of the first Ethereum account
+const DefaultPath = "m/44'/60'/0'/0"
+
+type Config struct {
+	SeedFile          string
+	PasswordFile      string
+	PasswordTrimSpace bool
+	Path              string
+	Accounts          AccountsConfig
+	Paths             []string
+}
+
+type AccountsConfig struct {
+	Start int
+	Count int
+}
+
+func InitConfig(section config.Section) {
+	section.AddKnownKey(ConfigSeedFile)
+	section.AddKnownKey(ConfigPasswordFile)
+	section.AddKnownKey(ConfigPasswordTrimSpace, true)
+	section.AddKnownKey(ConfigPath, DefaultPath)
+	section.AddKnownKey(ConfigAccountsStart, 0)
+	section.AddKnownKey(ConfigAccountsCount, 10)
+	section.AddKnownKey(ConfigPaths)
+}
+
+func ReadConfig(section config.Section) *Config {
+	return &Config{
+		SeedFile:          section.GetString(ConfigSeedFile),
+		PasswordFile:      section.GetString(ConfigPasswordFile),
+		PasswordTrimSpace: section.GetBool(ConfigPasswordTrimSpace),
+		Path:              section.GetString(ConfigPath),
+		Accounts: AccountsConfig{
+			Start: section.GetInt(ConfigAccountsStart),
+			Count: section.GetInt(ConfigAccountsCount),
+		},
+		Paths: section.GetStringSlice(ConfigPaths),
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hdwallet
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/keystorev3"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+)
+
+// Wallet is a hierarchical deterministic wallet, where every account is derived with BIP-32
+// from a single secret - the seed of a BIP-39 mnemonic, or an extended private key.
+//
+// The secret is stored encrypted in a Keystore V3 seed file, where the private key is either
+// the 16-64 byte seed, or the 78 byte serialized form of the extended private key.
+type Wallet interface {
+	ethsigner.WalletTypedData
+	ethsigner.WalletEIP191
+	ethsigner.WalletListener
+	GetAccountPath(ctx context.Context, addr ethtypes.Address0xHex) (DerivationPath, error)
+}
+
+type hdAccount struct {
+	path    DerivationPath
+	keypair *secp256k1.KeyPair
+}
+
+type hdWallet struct {
+	*ethsigner.KeySigner
+	*ethsigner.Listeners
+	conf  Config
+	paths []DerivationPath
+
+	mux         sync.Mutex
+	accounts    map[ethtypes.Address0xHex]*hdAccount
+	addressList []*ethtypes.Address0xHex // in the order of the paths
+}
+
+// NewHDWallet validates the configured paths of the accounts. The seed file is read on Initialize.
+func NewHDWallet(ctx context.Context, conf *Config, initialListeners ...chan<- ethtypes.Address0xHex) (Wallet, error) {
+	w := &hdWallet{
+		Listeners: ethsigner.NewListeners(initialListeners...),
+		conf:      *conf,
+		accounts:  make(map[ethtypes.Address0xHex]*hdAccount),
+	}
+	w.KeySigner = ethsigner.NewKeySigner(w.getSignerForAddr)
+	if len(conf.Paths) > 0 {
+		for _, p := range conf.Paths {
+			path, err := ParseDerivationPath(ctx, p)
+			if err != nil {
+				return nil, err
+			}
+			w.paths = append(w.paths, path)
+		}
+	} else {
+		parent, err := ParseDerivationPath(ctx, conf.Path)
+		if err != nil {
+			return nil, err
+		}
+		if conf.Accounts.Start < 0 || conf.Accounts.Start+conf.Accounts.Count > int(HardenedOffset) {
+			return nil, i18n.NewError(ctx, signermsgs.MsgInvalidDerivationPath, fmt.Sprintf("%s/%d", conf.Path, conf.Accounts.Start))
+		}
+		for i := 0; i < conf.Accounts.Count; i++ {
+			w.paths = append(w.paths, parent.Append(uint32(conf.Accounts.Start+i)))
+		}
+	}
+	if len(w.paths) == 0 {
+		return nil, i18n.NewError(ctx, signermsgs.MsgNoHDWalletAccounts)
+	}
+	return w, nil
+}
+
+// Initialize decrypts the seed file, and derives all the accounts
+func (w *hdWallet) Initialize(ctx context.Context) error {
+	master, err := w.readSeedFile(ctx)
+	if err != nil {
+		return err
+	}
+
+	accounts := make(map[ethtypes.Address0xHex]*hdAccount, len(w.paths))
+	addressList := make([]*ethtypes.Address0xHex, 0, len(w.paths))
+	for _, path := range w.paths {
+		key, err := master.Derive(ctx, path)
+		if err != nil {
+			return err
+		}
+		keypair := key.KeyPair()
+		if _, exists := accounts[keypair.Address]; exists {
+			log.L(ctx).Warnf("Ignoring duplicate HD wallet path %s for address %s", path, keypair.Address)
+			continue
+		}
+		accounts[keypair.Address] = &hdAccount{path: path, keypair: keypair}
+		addr := keypair.Address
+		addressList = append(addressList, &addr)
+	}
+	log.L(ctx).Infof("Derived %d accounts from HD wallet seed file %s", len(addressList), w.conf.SeedFile)
+
+	w.mux.Lock()
+	w.accounts = accounts
+	w.addressList = addressList
+	w.mux.Unlock()
+
+	// Listeners are notified asynchronously of each account
+	w.Notify(addressList)
+	return nil
+}
+
+func (w *hdWallet) readSeedFile(ctx context.Context) (*ExtendedKey, error) {
+	password, err := os.ReadFile(w.conf.PasswordFile)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, signermsgs.MsgSeedFileFailed, w.conf.SeedFile)
+	}
+	if w.conf.PasswordTrimSpace {
+		password = []byte(strings.TrimSpace(string(password)))
+	}
+	seedFile, err := os.ReadFile(w.conf.SeedFile)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, signermsgs.MsgSeedFileFailed, w.conf.SeedFile)
+	}
+	kv3, err := keystorev3.ReadWalletFile(seedFile, password)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, signermsgs.MsgSeedFileFailed, w.conf.SeedFile)
+	}
+	// A serialized extended key is 78 bytes, so cannot be confused with a seed of 16-64 bytes
+	secret := kv3.PrivateKey()
+	if len(secret) == serializedKeyLen {
+		return DeserializeExtendedKey(ctx, secret)
+	}
+	return NewMasterKey(ctx, secret)
+}
+
+// GetAccounts returns the derived accounts, in the order of the configured paths
+func (w *hdWallet) GetAccounts(_ context.Context) ([]*ethtypes.Address0xHex, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	accounts := make([]*ethtypes.Address0xHex, len(w.addressList))
+	copy(accounts, w.addressList)
+	return accounts, nil
+}
+
+// GetAccountPath returns the BIP-32 path the account was derived from
+func (w *hdWallet) GetAccountPath(ctx context.Context, addr ethtypes.Address0xHex) (DerivationPath, error) {
+	account, err := w.getAccount(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	return account.path.Append(), nil
+}
+
+// Refresh does nothing, as the accounts are fixed by the configuration
+func (w *hdWallet) Refresh(_ context.Context) error {
+	return nil
+}
+
+// Close discards the derived keys
+func (w *hdWallet) Close() error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.accounts = make(map[ethtypes.Address0xHex]*hdAccount)
+	w.addressList = nil
+	return nil
+}
+
+func (w *hdWallet) getAccount(ctx context.Context, addr ethtypes.Address0xHex) (*hdAccount, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	account, ok := w.accounts[addr]
+	if !ok {
+		return nil, i18n.NewError(ctx, signermsgs.MsgWalletNotAvailable, addr)
+	}
+	return account, nil
+}
+
+func (w *hdWallet) getSignerForAddr(ctx context.Context, addr ethtypes.Address0xHex) (secp256k1.SignerDirect, error) {
+	account, err := w.getAccount(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	return account.keypair, nil
+}
@@ -0,0 +1,295 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hdwallet
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-signer/pkg/eip712"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/keystorev3"
+	"github.com/stretchr/testify/assert"
+)
+
+// The default development mnemonic of Hardhat and Anvil, with well known accounts
+const testMnemonic = "test test test test test test test test test test test junk"
+
+var (
+	testAccount0 = ethtypes.MustNewAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	testAccount1 = ethtypes.MustNewAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	testAccount2 = ethtypes.MustNewAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+)
+
+func writeTestSeedFile(t *testing.T, secret []byte) *Config {
+	dir := t.TempDir()
+	conf := &Config{
+		SeedFile:          filepath.Join(dir, "seed.json"),
+		PasswordFile:      filepath.Join(dir, "seed.pwd"),
+		PasswordTrimSpace: true,
+		Path:              DefaultPath,
+		Accounts:          AccountsConfig{Count: 3},
+	}
+	err := os.WriteFile(conf.PasswordFile, []byte("correcthorsebatterystaple\n"), 0600)
+	assert.NoError(t, err)
+	if secret != nil {
+		kv3 := keystorev3.NewWalletFileCustomBytesLight("correcthorsebatterystaple", secret)
+		err = os.WriteFile(conf.SeedFile, kv3.JSON(), 0600)
+		assert.NoError(t, err)
+	}
+	return conf
+}
+
+func newTestMnemonicWallet(t *testing.T, modifyConf ...func(conf *Config)) (context.Context, Wallet) {
+	ctx := context.Background()
+	seed, err := MnemonicToSeed(ctx, testMnemonic, "")
+	assert.NoError(t, err)
+	conf := writeTestSeedFile(t, seed)
+	for _, fn := range modifyConf {
+		fn(conf)
+	}
+	w, err := NewHDWallet(ctx, conf)
+	assert.NoError(t, err)
+	err = w.Initialize(ctx)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = w.Close() })
+	return ctx, w
+}
+
+func TestConfig(t *testing.T) {
+	config.RootConfigReset()
+	section := config.RootSection("hdWallet")
+	InitConfig(section)
+	section.Set(ConfigSeedFile, "seed.json")
+	section.Set(ConfigPasswordFile, "seed.pwd")
+	section.Set(ConfigPaths, []string{"m/44'/60'/0'/0/5"})
+	conf := ReadConfig(section)
+	assert.Equal(t, &Config{
+		SeedFile:          "seed.json",
+		PasswordFile:      "seed.pwd",
+		PasswordTrimSpace: true,
+		Path:              DefaultPath,
+		Accounts:          AccountsConfig{Start: 0, Count: 10},
+		Paths:             []string{"m/44'/60'/0'/0/5"},
+	}, conf)
+}
+
+func TestMnemonicAccountRange(t *testing.T) {
+	listener := make(chan ethtypes.Address0xHex, 3)
+	ctx, w := newTestMnemonicWallet(t)
+	w.AddListener(listener)
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	accounts, err := w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{testAccount0, testAccount1, testAccount2}, accounts)
+	assert.Equal(t, *testAccount0, <-listener)
+	assert.Equal(t, *testAccount1, <-listener)
+	assert.Equal(t, *testAccount2, <-listener)
+
+	path, err := w.GetAccountPath(ctx, *testAccount2)
+	assert.NoError(t, err)
+	assert.Equal(t, "m/44'/60'/0'/0/2", path.String())
+
+	err = w.Refresh(ctx)
+	assert.NoError(t, err)
+}
+
+func TestMnemonicAccountRangeStart(t *testing.T) {
+	listener := make(chan ethtypes.Address0xHex, 1)
+	ctx := context.Background()
+	seed, err := MnemonicToSeed(ctx, testMnemonic, "")
+	assert.NoError(t, err)
+	conf := writeTestSeedFile(t, seed)
+	conf.Accounts = AccountsConfig{Start: 2, Count: 1}
+	w, err := NewHDWallet(ctx, conf, listener)
+	assert.NoError(t, err)
+	err = w.Initialize(ctx)
+	assert.NoError(t, err)
+	defer w.Close()
+
+	accounts, err := w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{testAccount2}, accounts)
+	assert.Equal(t, *testAccount2, <-listener)
+}
+
+func TestMnemonicExplicitPaths(t *testing.T) {
+	ctx, w := newTestMnemonicWallet(t, func(conf *Config) {
+		conf.Paths = []string{"m/44'/60'/0'/0/1", "m/44h/60h/0h/0/1", "m/44'/60'/0'/0/0"}
+	})
+
+	accounts, err := w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{testAccount1, testAccount0}, accounts)
+}
+
+func TestExtendedKeySeedFile(t *testing.T) {
+	ctx := context.Background()
+	seed, err := MnemonicToSeed(ctx, testMnemonic, "")
+	assert.NoError(t, err)
+	master, err := NewMasterKey(ctx, seed)
+	assert.NoError(t, err)
+	// The extended key for the parent of the accounts can be stored, rather than the master
+	parentPath, err := ParseDerivationPath(ctx, DefaultPath)
+	assert.NoError(t, err)
+	parent, err := master.Derive(ctx, parentPath)
+	assert.NoError(t, err)
+
+	conf := writeTestSeedFile(t, parent.Serialize())
+	conf.Path = "m"
+	w, err := NewHDWallet(ctx, conf)
+	assert.NoError(t, err)
+	err = w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	accounts, err := w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{testAccount0, testAccount1, testAccount2}, accounts)
+
+	err = w.Close()
+	assert.NoError(t, err)
+	accounts, err = w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, accounts)
+}
+
+func TestNewHDWalletBadConfig(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewHDWallet(ctx, &Config{Paths: []string{"m/0", "bad"}})
+	assert.Regexp(t, "FF22171.*bad", err)
+
+	_, err = NewHDWallet(ctx, &Config{Path: "bad", Accounts: AccountsConfig{Count: 1}})
+	assert.Regexp(t, "FF22171.*bad", err)
+
+	_, err = NewHDWallet(ctx, &Config{Path: DefaultPath, Accounts: AccountsConfig{Start: -1, Count: 1}})
+	assert.Regexp(t, "FF22171", err)
+
+	_, err = NewHDWallet(ctx, &Config{Path: DefaultPath, Accounts: AccountsConfig{Start: int(HardenedOffset) - 1, Count: 2}})
+	assert.Regexp(t, "FF22171", err)
+
+	_, err = NewHDWallet(ctx, &Config{Path: DefaultPath})
+	assert.Regexp(t, "FF22174", err)
+}
+
+func TestInitializeSeedFileErrors(t *testing.T) {
+	ctx := context.Background()
+
+	conf := writeTestSeedFile(t, nil)
+	w, err := NewHDWallet(ctx, conf)
+	assert.NoError(t, err)
+	err = w.Initialize(ctx)
+	assert.Regexp(t, "FF22173", err)
+
+	conf = writeTestSeedFile(t, make([]byte, 16))
+	conf.PasswordFile = filepath.Join(t.TempDir(), "missing")
+	w, err = NewHDWallet(ctx, conf)
+	assert.NoError(t, err)
+	err = w.Initialize(ctx)
+	assert.Regexp(t, "FF22173", err)
+
+	conf = writeTestSeedFile(t, make([]byte, 16))
+	conf.PasswordTrimSpace = false
+	w, err = NewHDWallet(ctx, conf)
+	assert.NoError(t, err)
+	err = w.Initialize(ctx)
+	assert.Regexp(t, "FF22173", err)
+
+	conf = writeTestSeedFile(t, make([]byte, 8))
+	w, err = NewHDWallet(ctx, conf)
+	assert.NoError(t, err)
+	err = w.Initialize(ctx)
+	assert.Regexp(t, "FF22170", err)
+
+	conf = writeTestSeedFile(t, make([]byte, serializedKeyLen))
+	w, err = NewHDWallet(ctx, conf)
+	assert.NoError(t, err)
+	err = w.Initialize(ctx)
+	assert.Regexp(t, "FF22169", err)
+}
+
+func TestSignOK(t *testing.T) {
+	ctx, w := newTestMnemonicWallet(t)
+
+	txn := &ethsigner.Transaction{
+		From:     json.RawMessage(`"0x70997970c51812dc3a010c7d01b50e0d17dc79c8"`),
+		Nonce:    ethtypes.NewHexInteger64(0),
+		GasPrice: ethtypes.NewHexInteger64(1000000000),
+		GasLimit: ethtypes.NewHexInteger64(21000),
+	}
+	b, err := w.Sign(ctx, txn, 1337)
+	assert.NoError(t, err)
+
+	from, _, err := ethsigner.RecoverRawTransaction(ctx, b, 1337)
+	assert.NoError(t, err)
+	assert.Equal(t, testAccount1, from)
+}
+
+func TestSignTypedDataOK(t *testing.T) {
+	ctx, w := newTestMnemonicWallet(t)
+
+	res, err := w.SignTypedDataV4(ctx, *testAccount0, &eip712.TypedData{
+		PrimaryType: eip712.EIP712Domain,
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, res)
+}
+
+func TestSignEIP191OK(t *testing.T) {
+	ctx, w := newTestMnemonicWallet(t)
+
+	res, err := w.SignEIP191PersonalMessage(ctx, *testAccount0, []byte("Hello World"))
+	assert.NoError(t, err)
+	ok, err := ethsigner.VerifyEIP191PersonalMessage(ctx, []byte("Hello World"), res.SignatureRSV, *testAccount0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	res, err = w.SignEIP191IntendedValidator(ctx, *testAccount1, *testAccount2, []byte("some data"))
+	assert.NoError(t, err)
+	ok, err = ethsigner.VerifyEIP191IntendedValidator(ctx, *testAccount2, []byte("some data"), res.SignatureRSV, *testAccount1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestSignNotFound(t *testing.T) {
+	ctx, w := newTestMnemonicWallet(t)
+	unknown := *ethtypes.MustNewAddress("0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF")
+
+	_, err := w.Sign(ctx, &ethsigner.Transaction{From: json.RawMessage(`"0xFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF"`)}, 1337)
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.Sign(ctx, &ethsigner.Transaction{From: json.RawMessage(`"bad address"`)}, 1337)
+	assert.Regexp(t, "bad address", err)
+
+	_, err = w.SignTypedDataV4(ctx, unknown, &eip712.TypedData{})
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.SignEIP191PersonalMessage(ctx, unknown, []byte("Hello World"))
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.SignEIP191IntendedValidator(ctx, unknown, unknown, []byte("Hello World"))
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.GetAccountPath(ctx, unknown)
+	assert.Regexp(t, "FF22014", err)
+}
@@ -0,0 +1,68 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hsmwallet
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+)
+
+const (
+	// ConfigLibrary the path to the PKCS#11 module shared library of the HSM
+	ConfigLibrary = "library"
+	// ConfigTokenLabel the label of the token containing the keys
+	ConfigTokenLabel = "tokenLabel"
+	// ConfigPINFile the file containing the user PIN to log in to the token
+	ConfigPINFile = "pinFile"
+	// ConfigKeysLabels optional list of the labels of the keys to use
+	ConfigKeysLabels = "keys.labels"
+	// ConfigKeysIDs optional list of the hex IDs of the keys to use
+	ConfigKeysIDs = "keys.ids"
+)
+
+type Config struct {
+	Library    string
+	TokenLabel string
+	PINFile    string
+	Keys       KeysConfig
+}
+
+// KeysConfig selects the keys by label or ID. When neither is set, all the secp256k1 keys on
+// the token are used.
+type KeysConfig struct {
+	Labels []string
+	IDs    []string
+}
+
+func InitConfig(section config.Section) {
+	section.AddKnownKey(ConfigLibrary)
+	section.AddKnownKey(ConfigTokenLabel)
+	section.AddKnownKey(ConfigPINFile)
+	section.AddKnownKey(ConfigKeysLabels)
+	section.AddKnownKey(ConfigKeysIDs)
+}
+
+func ReadConfig(section config.Section) *Config {
+	return &Config{
+		Library:    section.GetString(ConfigLibrary),
+		TokenLabel: section.GetString(ConfigTokenLabel),
+		PINFile:    section.GetString(ConfigPINFile),
+		Keys: KeysConfig{
+			Labels: section.GetStringSlice(ConfigKeysLabels),
+			IDs:    section.GetStringSlice(ConfigKeysIDs),
+		},
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hsmwallet
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+	"golang.org/x/crypto/sha3"
+)
+
+// Wallet is a wallet where the secp256k1 keys are held in an HSM, and used through PKCS#11.
+// The private keys never leave the HSM - digests are signed with CKM_ECDSA, and the signatures
+// are converted to Ethereum signatures.
+type Wallet interface {
+	ethsigner.WalletTypedData
+	ethsigner.WalletEIP191
+	ethsigner.WalletHealth
+	ethsigner.WalletListener
+	// Signer returns a signer for the key of the address, for use with any of the signing functions.
+	// Signing is not started once the context has ended.
+	Signer(ctx context.Context, addr ethtypes.Address0xHex) (secp256k1.SignerDirect, error)
+}
+
+// hsmKey is a key pair in the HSM, which implements secp256k1.SignerDirect.
+// The signing functions have no context, so each signer is bound to the context of the request.
+type hsmKey struct {
+	w          *hsmWallet
+	ctx        context.Context
+	address    ethtypes.Address0xHex
+	privateKey ObjectHandle
+}
+
+type hsmWallet struct {
+	*ethsigner.KeySigner
+	*ethsigner.Listeners
+	conf   Config
+	ids    [][]byte
+	opener SessionOpener
+
+	mux         sync.Mutex
+	session     Session
+	keys        map[ethtypes.Address0xHex]*hsmKey
+	addressList []*ethtypes.Address0xHex // in the order they are found
+}
+
+// NewHSMWallet validates the configuration. The session to the HSM is opened on Initialize.
+func NewHSMWallet(ctx context.Context, conf *Config, initialListeners ...chan<- ethtypes.Address0xHex) (Wallet, error) {
+	return newHSMWallet(ctx, conf, OpenPKCS11Session, initialListeners...)
+}
+
+func newHSMWallet(ctx context.Context, conf *Config, opener SessionOpener, initialListeners ...chan<- ethtypes.Address0xHex) (*hsmWallet, error) {
+	w := &hsmWallet{
+		Listeners: ethsigner.NewListeners(initialListeners...),
+		conf:      *conf,
+		opener:    opener,
+		keys:      make(map[ethtypes.Address0xHex]*hsmKey),
+	}
+	w.KeySigner = ethsigner.NewKeySigner(w.Signer)
+	for _, id := range conf.Keys.IDs {
+		b, err := hex.DecodeString(strings.TrimPrefix(id, "0x"))
+		if err != nil || len(b) == 0 {
+			return nil, i18n.NewError(ctx, signermsgs.MsgHSMInvalidKeyID, id)
+		}
+		w.ids = append(w.ids, b)
+	}
+	return w, nil
+}
+
+// Initialize logs in to the token with the PIN from the PIN file, and finds the keys
+func (w *hsmWallet) Initialize(ctx context.Context) error {
+	pin, err := os.ReadFile(w.conf.PINFile)
+	if err != nil {
+		return i18n.WrapError(ctx, err, signermsgs.MsgHSMPINFileFailed, w.conf.PINFile)
+	}
+	session, err := w.opener(ctx, &w.conf, strings.TrimSpace(string(pin)))
+	if err != nil {
+		return err
+	}
+	w.mux.Lock()
+	w.session = session
+	w.mux.Unlock()
+	return w.Refresh(ctx)
+}
+
+// Refresh finds the configured keys in the HSM - or all the secp256k1 keys when none are
+// configured - and notifies the listeners of any new addresses
+func (w *hsmWallet) Refresh(ctx context.Context) error {
+	session, err := w.getSession(ctx)
+	if err != nil {
+		return err
+	}
+
+	var found []*hsmKey
+	if len(w.conf.Keys.Labels) == 0 && len(w.ids) == 0 {
+		if found, err = w.findKeys(ctx, session, nil); err != nil {
+			return err
+		}
+	}
+	for _, label := range w.conf.Keys.Labels {
+		keys, err := w.findKeys(ctx, session, &Attribute{Type: ckaLabel, Value: label})
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			return i18n.NewError(ctx, signermsgs.MsgHSMKeyNotFound, "label="+label)
+		}
+		found = append(found, keys...)
+	}
+	for _, id := range w.ids {
+		keys, err := w.findKeys(ctx, session, &Attribute{Type: ckaID, Value: id})
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			return i18n.NewError(ctx, signermsgs.MsgHSMKeyNotFound, "id="+hex.EncodeToString(id))
+		}
+		found = append(found, keys...)
+	}
+
+	w.mux.Lock()
+	var newAddresses []*ethtypes.Address0xHex
+	for _, key := range found {
+		if _, exists := w.keys[key.address]; !exists {
+			addr := key.address
+			newAddresses = append(newAddresses, &addr)
+			w.addressList = append(w.addressList, &addr)
+		}
+		// Handles can change when the session is re-opened, so are always updated
+		w.keys[key.address] = key
+	}
+	w.mux.Unlock()
+
+	log.L(ctx).Infof("Found %d keys in HSM token '%s' (%d new)", len(found), w.conf.TokenLabel, len(newAddresses))
+	w.Notify(newAddresses)
+	return nil
+}
+
+// findKeys finds the secp256k1 public keys matching the optional attribute, and the private key for each
+func (w *hsmWallet) findKeys(ctx context.Context, session Session, match *Attribute) ([]*hsmKey, error) {
+	template := []*Attribute{
+		{Type: ckaClass, Value: ckoPublicKey},
+		{Type: ckaKeyType, Value: ckkEC},
+	}
+	if match != nil {
+		template = append(template, match)
+	}
+	publicKeys, err := session.FindObjects(template)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*hsmKey, 0, len(publicKeys))
+	for _, pub := range publicKeys {
+		values, err := session.GetAttributeValues(pub, ckaECParams, ckaECPoint, ckaID, ckaLabel)
+		if err != nil {
+			return nil, err
+		}
+		ecParams, ecPoint, id, label := values[0], values[1], values[2], values[3]
+		if !isSecp256k1(ecParams) {
+			log.L(ctx).Debugf("Ignoring HSM key with label '%s' as it is not secp256k1", label)
+			continue
+		}
+		pubKey, err := parseECPoint(ecPoint)
+		if err != nil {
+			return nil, i18n.NewError(ctx, signermsgs.MsgHSMInvalidPublicKey, keyName(id, label), err)
+		}
+		// The private key is matched to the public key by ID, or by label for keys without an ID
+		privTemplate := []*Attribute{
+			{Type: ckaClass, Value: ckoPrivateKey},
+			{Type: ckaKeyType, Value: ckkEC},
+		}
+		if len(id) > 0 {
+			privTemplate = append(privTemplate, &Attribute{Type: ckaID, Value: id})
+		} else {
+			privTemplate = append(privTemplate, &Attribute{Type: ckaLabel, Value: label})
+		}
+		privateKeys, err := session.FindObjects(privTemplate)
+		if err != nil {
+			return nil, err
+		}
+		if len(privateKeys) == 0 {
+			log.L(ctx).Warnf("Ignoring HSM public key %s with no private key", keyName(id, label))
+			continue
+		}
+		key := &hsmKey{
+			w:          w,
+			address:    *secp256k1.PublicKeyToAddress(pubKey),
+			privateKey: privateKeys[0],
+		}
+		log.L(ctx).Debugf("HSM key %s has address %s", keyName(id, label), key.address)
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func keyName(id, label []byte) string {
+	if len(id) > 0 {
+		return "id=" + hex.EncodeToString(id)
+	}
+	return "label=" + string(label)
+}
+
+func (w *hsmWallet) getSession(ctx context.Context) (Session, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if w.session == nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgWalletNotAvailable, w.conf.TokenLabel)
+	}
+	return w.session, nil
+}
+
+// GetAccounts returns the addresses of the keys, in the order they were found
+func (w *hsmWallet) GetAccounts(_ context.Context) ([]*ethtypes.Address0xHex, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	accounts := make([]*ethtypes.Address0xHex, len(w.addressList))
+	copy(accounts, w.addressList)
+	return accounts, nil
+}
+
+// CheckHealth checks the session to the HSM is still usable
+func (w *hsmWallet) CheckHealth(ctx context.Context) error {
+	session, err := w.getSession(ctx)
+	if err != nil {
+		return err
+	}
+	return session.CheckSession()
+}
+
+// Close closes the session to the HSM
+func (w *hsmWallet) Close() error {
+	w.mux.Lock()
+	session := w.session
+	w.session = nil
+	w.mux.Unlock()
+	if session != nil {
+		return session.Close()
+	}
+	return nil
+}
+
+func (w *hsmWallet) getKey(ctx context.Context, addr ethtypes.Address0xHex) (*hsmKey, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	key, ok := w.keys[addr]
+	if !ok {
+		return nil, i18n.NewError(ctx, signermsgs.MsgWalletNotAvailable, addr)
+	}
+	return key, nil
+}
+
+// Signer returns a signer for the key, bound to the context
+func (w *hsmWallet) Signer(ctx context.Context, addr ethtypes.Address0xHex) (secp256k1.SignerDirect, error) {
+	key, err := w.getKey(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	bound := *key
+	bound.ctx = ctx
+	return &bound, nil
+}
+
+// Sign hashes the message with keccak256, and signs the digest in the HSM
+func (k *hsmKey) Sign(message []byte) (*secp256k1.SignatureData, error) {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(message)
+	return k.SignDirect(hash.Sum(nil))
+}
+
+// SignDirect signs the digest in the HSM with CKM_ECDSA, and converts the signature to an Ethereum
+// signature with a low S value and the recovery ID
+func (k *hsmKey) SignDirect(digest []byte) (*secp256k1.SignatureData, error) {
+	ctx := k.ctx
+	// PKCS#11 calls cannot be canceled, so we do not start one for a request that has already ended
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	session, err := k.w.getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := session.Sign(ckmECDSA, k.privateKey, digest)
+	if err != nil {
+		return nil, err
+	}
+	r, s, err := secp256k1.ParseECDSASignature(ctx, sig)
+	if err != nil {
+		return nil, err
+	}
+	return secp256k1.NewSignatureDataFromRS(ctx, digest, r, s, k.address)
+}
@@ -0,0 +1,531 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hsmwallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-signer/pkg/eip712"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+	"github.com/stretchr/testify/assert"
+)
+
+// The first accounts of Hardhat and Anvil
+var (
+	testKey0 = secp256k1.KeyPairFromBytes(mustHex("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"))
+	testKey1 = secp256k1.KeyPairFromBytes(mustHex("59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"))
+)
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// fakeObject is an object in the fake token, with its attributes and the key for private keys
+type fakeObject struct {
+	attrs map[uint][]byte
+	key   *secp256k1.KeyPair
+}
+
+// fakeSession is an in-memory PKCS#11 session, that matches templates on the exact bytes of the values
+type fakeSession struct {
+	mux       sync.Mutex
+	objects   []*fakeObject
+	rawSigs   bool
+	findErr   error
+	attrErr   error
+	signErr   error
+	signBad   bool
+	healthErr error
+	closed    bool
+}
+
+func fakeAttrBytes(value interface{}) []byte {
+	switch v := value.(type) {
+	case []byte:
+		return v
+	case string:
+		return []byte(v)
+	default:
+		return []byte(fmt.Sprintf("%d", v))
+	}
+}
+
+func (s *fakeSession) addKeyPair(key *secp256k1.KeyPair, id []byte, label string, ecParams, ecPoint []byte) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if ecParams == nil {
+		ecParams = secp256k1ECParams
+	}
+	if ecPoint == nil {
+		ecPoint, _ = asn1.Marshal(key.PublicKey.SerializeUncompressed())
+	}
+	pubAttrs := map[uint][]byte{
+		ckaClass:    fakeAttrBytes(ckoPublicKey),
+		ckaKeyType:  fakeAttrBytes(ckkEC),
+		ckaLabel:    []byte(label),
+		ckaECParams: ecParams,
+		ckaECPoint:  ecPoint,
+	}
+	privAttrs := map[uint][]byte{
+		ckaClass:   fakeAttrBytes(ckoPrivateKey),
+		ckaKeyType: fakeAttrBytes(ckkEC),
+		ckaLabel:   []byte(label),
+	}
+	if id != nil {
+		pubAttrs[ckaID] = id
+		privAttrs[ckaID] = id
+	}
+	s.objects = append(s.objects, &fakeObject{attrs: pubAttrs})
+	if key != nil {
+		s.objects = append(s.objects, &fakeObject{attrs: privAttrs, key: key})
+	}
+}
+
+func (s *fakeSession) FindObjects(template []*Attribute) ([]ObjectHandle, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.findErr != nil {
+		return nil, s.findErr
+	}
+	var handles []ObjectHandle
+	for i, o := range s.objects {
+		match := true
+		for _, attr := range template {
+			if v, ok := o.attrs[attr.Type]; !ok || !bytes.Equal(v, fakeAttrBytes(attr.Value)) {
+				match = false
+			}
+		}
+		if match {
+			handles = append(handles, ObjectHandle(i))
+		}
+	}
+	return handles, nil
+}
+
+func (s *fakeSession) GetAttributeValues(object ObjectHandle, types ...uint) ([][]byte, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.attrErr != nil {
+		return nil, s.attrErr
+	}
+	values := make([][]byte, len(types))
+	for i, t := range types {
+		values[i] = s.objects[object].attrs[t]
+	}
+	return values, nil
+}
+
+func (s *fakeSession) Sign(mechanism uint, key ObjectHandle, data []byte) ([]byte, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.signErr != nil {
+		return nil, s.signErr
+	}
+	if s.signBad {
+		return []byte{0x30, 0x00}, nil
+	}
+	if mechanism != ckmECDSA || len(data) != 32 {
+		return nil, fmt.Errorf("bad sign request")
+	}
+	privateKey := s.objects[key].key.PrivateKey
+	if s.rawSigs {
+		return ecdsa.SignCompact(privateKey, data, false)[1:], nil
+	}
+	return ecdsa.Sign(privateKey, data).Serialize(), nil
+}
+
+func (s *fakeSession) CheckSession() error {
+	return s.healthErr
+}
+
+func (s *fakeSession) Close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.closed = true
+	return nil
+}
+
+func writePINFile(t *testing.T) string {
+	pinFile := path.Join(t.TempDir(), "pin")
+	err := os.WriteFile(pinFile, []byte("1234\n"), 0600)
+	assert.NoError(t, err)
+	return pinFile
+}
+
+func newTestHSMWallet(t *testing.T, conf *Config, session Session, listeners ...chan<- ethtypes.Address0xHex) (context.Context, *hsmWallet) {
+	ctx := context.Background()
+	if conf.PINFile == "" {
+		conf.PINFile = writePINFile(t)
+	}
+	w, err := newHSMWallet(ctx, conf, func(_ context.Context, _ *Config, pin string) (Session, error) {
+		assert.Equal(t, "1234", pin)
+		return session, nil
+	}, listeners...)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = w.Close() })
+	return ctx, w
+}
+
+func TestConfig(t *testing.T) {
+	config.RootConfigReset()
+	section := config.RootSection("hsmWallet")
+	InitConfig(section)
+	section.Set(ConfigLibrary, "/usr/lib/softhsm/libsofthsm2.so")
+	section.Set(ConfigTokenLabel, "signer")
+	section.Set(ConfigPINFile, "/secrets/pin")
+	section.Set(ConfigKeysLabels, []string{"key1"})
+	section.Set(ConfigKeysIDs, []string{"0a0b"})
+	assert.Equal(t, &Config{
+		Library:    "/usr/lib/softhsm/libsofthsm2.so",
+		TokenLabel: "signer",
+		PINFile:    "/secrets/pin",
+		Keys: KeysConfig{
+			Labels: []string{"key1"},
+			IDs:    []string{"0a0b"},
+		},
+	}, ReadConfig(section))
+}
+
+func TestInitializeAllKeys(t *testing.T) {
+	session := &fakeSession{}
+	session.addKeyPair(testKey0, []byte{0x01}, "key0", nil, nil)
+	// A raw EC point, with no ID so the private key is found by label
+	session.addKeyPair(testKey1, nil, "key1", nil, testKey1.PublicKey.SerializeUncompressed())
+	// A P-256 key, and a public key without a private key, are both ignored
+	session.addKeyPair(testKey1, []byte{0x02}, "p256", []byte{0x06, 0x08, 0x2a, 0x86, 0x48, 0xce, 0x3d, 0x03, 0x01, 0x07}, nil)
+	session.addKeyPair(nil, []byte{0x03}, "nopriv", nil, testKey1.PublicKey.SerializeUncompressed())
+
+	listener := make(chan ethtypes.Address0xHex, 2)
+	ctx, w := newTestHSMWallet(t, &Config{TokenLabel: "signer"}, session, listener)
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	accounts, err := w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{&testKey0.Address, &testKey1.Address}, accounts)
+	assert.Equal(t, testKey0.Address, <-listener)
+	assert.Equal(t, testKey1.Address, <-listener)
+
+	// Refresh finds no new keys
+	err = w.Refresh(ctx)
+	assert.NoError(t, err)
+	accounts, err = w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 2)
+
+	err = w.CheckHealth(ctx)
+	assert.NoError(t, err)
+
+	err = w.Close()
+	assert.NoError(t, err)
+	assert.True(t, session.closed)
+	err = w.CheckHealth(ctx)
+	assert.Regexp(t, "FF22014", err)
+	err = w.Refresh(ctx)
+	assert.Regexp(t, "FF22014", err)
+	err = w.Close()
+	assert.NoError(t, err)
+}
+
+func TestInitializeByLabelAndID(t *testing.T) {
+	session := &fakeSession{}
+	session.addKeyPair(testKey0, []byte{0x0a, 0x0b}, "key0", nil, nil)
+	session.addKeyPair(testKey1, []byte{0x0c}, "key1", nil, nil)
+	ctx, w := newTestHSMWallet(t, &Config{
+		Keys: KeysConfig{Labels: []string{"key1"}, IDs: []string{"0x0a0b"}},
+	}, session)
+	listener := make(chan ethtypes.Address0xHex, 2)
+	w.AddListener(listener)
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	accounts, err := w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{&testKey1.Address, &testKey0.Address}, accounts)
+	assert.Equal(t, testKey1.Address, <-listener)
+	assert.Equal(t, testKey0.Address, <-listener)
+}
+
+func TestInitializeKeyNotFound(t *testing.T) {
+	session := &fakeSession{}
+	session.addKeyPair(testKey0, []byte{0x01}, "key0", nil, nil)
+
+	ctx, w := newTestHSMWallet(t, &Config{Keys: KeysConfig{Labels: []string{"missing"}}}, session)
+	err := w.Initialize(ctx)
+	assert.Regexp(t, "FF22186.*label=missing", err)
+
+	ctx, w = newTestHSMWallet(t, &Config{Keys: KeysConfig{IDs: []string{"02"}}}, session)
+	err = w.Initialize(ctx)
+	assert.Regexp(t, "FF22186.*id=02", err)
+}
+
+func TestInitializeInvalidPublicKey(t *testing.T) {
+	session := &fakeSession{}
+	session.addKeyPair(testKey0, []byte{0x01}, "key0", nil, []byte{0x04, 0x01, 0x00})
+	ctx, w := newTestHSMWallet(t, &Config{}, session)
+	err := w.Initialize(ctx)
+	assert.Regexp(t, "FF22185.*id=01", err)
+
+	session = &fakeSession{}
+	session.addKeyPair(testKey0, nil, "key0", nil, []byte{0xff})
+	ctx, w = newTestHSMWallet(t, &Config{}, session)
+	err = w.Initialize(ctx)
+	assert.Regexp(t, "FF22185.*label=key0", err)
+}
+
+func TestInitializeSessionErrors(t *testing.T) {
+	ctx := context.Background()
+	w, err := newHSMWallet(ctx, &Config{PINFile: path.Join(t.TempDir(), "missing")}, nil)
+	assert.NoError(t, err)
+	err = w.Initialize(ctx)
+	assert.Regexp(t, "FF22183", err)
+
+	w, err = newHSMWallet(ctx, &Config{PINFile: writePINFile(t)}, func(_ context.Context, _ *Config, _ string) (Session, error) {
+		return nil, fmt.Errorf("pop")
+	})
+	assert.NoError(t, err)
+	err = w.Initialize(ctx)
+	assert.Regexp(t, "pop", err)
+
+	_, err = NewHSMWallet(ctx, &Config{Keys: KeysConfig{IDs: []string{"not hex"}}})
+	assert.Regexp(t, "FF22184", err)
+	_, err = NewHSMWallet(ctx, &Config{Keys: KeysConfig{IDs: []string{"0x"}}})
+	assert.Regexp(t, "FF22184", err)
+}
+
+func TestInitializeFindErrors(t *testing.T) {
+	for _, conf := range []*Config{{}, {Keys: KeysConfig{Labels: []string{"key0"}}}, {Keys: KeysConfig{IDs: []string{"01"}}}} {
+		session := &fakeSession{findErr: fmt.Errorf("pop")}
+		ctx, w := newTestHSMWallet(t, conf, session)
+		err := w.Initialize(ctx)
+		assert.Regexp(t, "pop", err)
+	}
+
+	session := &fakeSession{attrErr: fmt.Errorf("pop")}
+	session.addKeyPair(testKey0, []byte{0x01}, "key0", nil, nil)
+	ctx, w := newTestHSMWallet(t, &Config{}, session)
+	err := w.Initialize(ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestInitializePrivateKeyFindError(t *testing.T) {
+	session := &fakeSession{}
+	session.addKeyPair(testKey0, []byte{0x01}, "key0", nil, nil)
+	ctx, w := newTestHSMWallet(t, &Config{}, &findOnceSession{fakeSession: session})
+	err := w.Initialize(ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+// findOnceSession fails all but the first FindObjects, so the private key lookup fails
+type findOnceSession struct {
+	*fakeSession
+	found bool
+}
+
+func (s *findOnceSession) FindObjects(template []*Attribute) ([]ObjectHandle, error) {
+	if s.found {
+		return nil, fmt.Errorf("pop")
+	}
+	s.found = true
+	return s.fakeSession.FindObjects(template)
+}
+
+func TestSignOK(t *testing.T) {
+	for _, rawSigs := range []bool{false, true} {
+		session := &fakeSession{rawSigs: rawSigs}
+		session.addKeyPair(testKey0, []byte{0x01}, "key0", nil, nil)
+		ctx, w := newTestHSMWallet(t, &Config{}, session)
+		err := w.Initialize(ctx)
+		assert.NoError(t, err)
+
+		b, err := w.Sign(ctx, &ethsigner.Transaction{
+			From:     json.RawMessage(`"0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"`),
+			Nonce:    ethtypes.NewHexInteger64(0),
+			GasLimit: ethtypes.NewHexInteger64(21000),
+		}, 31337)
+		assert.NoError(t, err)
+
+		from, _, err := ethsigner.RecoverRawTransaction(ctx, b, 31337)
+		assert.NoError(t, err)
+		assert.Equal(t, testKey0.Address, *from)
+	}
+}
+
+func TestSignerMatchesKeyPair(t *testing.T) {
+	session := &fakeSession{}
+	session.addKeyPair(testKey0, []byte{0x01}, "key0", nil, nil)
+	ctx, w := newTestHSMWallet(t, &Config{}, session)
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	signer, err := w.Signer(ctx, testKey0.Address)
+	assert.NoError(t, err)
+	for _, message := range []string{"message 1", "message 2", "message 3"} {
+		// RFC6979 signatures are deterministic, so match exactly
+		expected, err := testKey0.Sign([]byte(message))
+		assert.NoError(t, err)
+		sig, err := signer.Sign([]byte(message))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, sig)
+	}
+}
+
+func TestSignTypedDataOK(t *testing.T) {
+	session := &fakeSession{}
+	session.addKeyPair(testKey0, []byte{0x01}, "key0", nil, nil)
+	ctx, w := newTestHSMWallet(t, &Config{}, session)
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	res, err := w.SignTypedDataV4(ctx, testKey0.Address, &eip712.TypedData{
+		PrimaryType: eip712.EIP712Domain,
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, res)
+}
+
+func TestSignEIP191OK(t *testing.T) {
+	session := &fakeSession{}
+	session.addKeyPair(testKey0, []byte{0x01}, "key0", nil, nil)
+	ctx, w := newTestHSMWallet(t, &Config{}, session)
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	res, err := w.SignEIP191PersonalMessage(ctx, testKey0.Address, []byte("Hello World"))
+	assert.NoError(t, err)
+	ok, err := ethsigner.VerifyEIP191PersonalMessage(ctx, []byte("Hello World"), res.SignatureRSV, testKey0.Address)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	res, err = w.SignEIP191IntendedValidator(ctx, testKey0.Address, testKey1.Address, []byte("some data"))
+	assert.NoError(t, err)
+	ok, err = ethsigner.VerifyEIP191IntendedValidator(ctx, testKey1.Address, []byte("some data"), res.SignatureRSV, testKey0.Address)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestSignNotFound(t *testing.T) {
+	session := &fakeSession{}
+	session.addKeyPair(testKey0, []byte{0x01}, "key0", nil, nil)
+	ctx, w := newTestHSMWallet(t, &Config{}, session)
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	_, err = w.Sign(ctx, &ethsigner.Transaction{From: json.RawMessage(`"0x70997970C51812dc3A010C7d01b50e0d17dc79C8"`)}, 31337)
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.Sign(ctx, &ethsigner.Transaction{From: json.RawMessage(`"bad address"`)}, 31337)
+	assert.Regexp(t, "bad address", err)
+
+	_, err = w.SignTypedDataV4(ctx, testKey1.Address, &eip712.TypedData{})
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.SignEIP191PersonalMessage(ctx, testKey1.Address, []byte("Hello World"))
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.SignEIP191IntendedValidator(ctx, testKey1.Address, testKey0.Address, []byte("Hello World"))
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.Signer(ctx, testKey1.Address)
+	assert.Regexp(t, "FF22014", err)
+}
+
+func TestSignDirectErrors(t *testing.T) {
+	session := &fakeSession{}
+	session.addKeyPair(testKey0, []byte{0x01}, "key0", nil, nil)
+	ctx, w := newTestHSMWallet(t, &Config{}, session)
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+	signer, err := w.Signer(ctx, testKey0.Address)
+	assert.NoError(t, err)
+
+	session.signErr = fmt.Errorf("pop")
+	_, err = signer.Sign([]byte("Hello World"))
+	assert.Regexp(t, "pop", err)
+
+	session.signErr = nil
+	session.signBad = true
+	_, err = signer.Sign([]byte("Hello World"))
+	assert.Regexp(t, "FF22177", err)
+
+	session.healthErr = fmt.Errorf("session closed")
+	err = w.CheckHealth(ctx)
+	assert.Regexp(t, "session closed", err)
+
+	err = w.Close()
+	assert.NoError(t, err)
+	_, err = signer.Sign([]byte("Hello World"))
+	assert.Regexp(t, "FF22014", err)
+}
+
+func TestSignDirectContextCanceled(t *testing.T) {
+	session := &fakeSession{}
+	session.addKeyPair(testKey0, []byte{0x01}, "key0", nil, nil)
+	ctx, w := newTestHSMWallet(t, &Config{}, session)
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = w.SignEIP191PersonalMessage(cancelCtx, testKey0.Address, []byte("Hello World"))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSignWrongKey(t *testing.T) {
+	// The public key does not match the private key, so the signature does not recover to the address
+	session := &fakeSession{}
+	session.addKeyPair(testKey0, []byte{0x01}, "key0", nil, nil)
+	session.objects[1].key = testKey1
+	ctx, w := newTestHSMWallet(t, &Config{}, session)
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	_, err = w.SignEIP191PersonalMessage(ctx, testKey0.Address, []byte("Hello World"))
+	assert.Regexp(t, "FF22178", err)
+}
+
+func TestParseECPoint(t *testing.T) {
+	der, err := asn1.Marshal(testKey0.PublicKey.SerializeUncompressed())
+	assert.NoError(t, err)
+	pubKey, err := parseECPoint(der)
+	assert.NoError(t, err)
+	assert.True(t, testKey0.PublicKey.IsEqual(pubKey))
+
+	pubKey, err = parseECPoint(testKey0.PublicKey.SerializeUncompressed())
+	assert.NoError(t, err)
+	assert.True(t, testKey0.PublicKey.IsEqual(pubKey))
+
+	_, err = parseECPoint([]byte{0x04})
+	assert.Error(t, err)
+}
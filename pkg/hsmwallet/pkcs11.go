@@ -0,0 +1,89 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hsmwallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/asn1"
+
+	btcec "github.com/btcsuite/btcd/btcec/v2" // ISC licensed
+)
+
+// PKCS#11 constants used by the wallet, from the OASIS PKCS#11 specification
+const (
+	ckaClass    uint = 0x0000 // CKA_CLASS
+	ckaLabel    uint = 0x0003 // CKA_LABEL
+	ckaKeyType  uint = 0x0100 // CKA_KEY_TYPE
+	ckaID       uint = 0x0102 // CKA_ID
+	ckaECParams uint = 0x0180 // CKA_EC_PARAMS
+	ckaECPoint  uint = 0x0181 // CKA_EC_POINT
+
+	ckoPublicKey  uint = 0x0002 // CKO_PUBLIC_KEY
+	ckoPrivateKey uint = 0x0003 // CKO_PRIVATE_KEY
+
+	ckkEC uint = 0x0003 // CKK_EC
+
+	ckmECDSA uint = 0x1041 // CKM_ECDSA
+)
+
+// ObjectHandle is the handle of an object, such as a key, in a PKCS#11 session
+type ObjectHandle uint
+
+// Attribute is a PKCS#11 attribute in a search template. The value is a []byte, a string,
+// or a uint for CK_ULONG attributes such as CKA_CLASS.
+type Attribute struct {
+	Type  uint
+	Value interface{}
+}
+
+// Session is the subset of PKCS#11 used by the wallet, on a session that is logged in to the token.
+// Implementations must be safe to call concurrently.
+type Session interface {
+	// FindObjects returns the handles of all the objects matching the template
+	FindObjects(template []*Attribute) ([]ObjectHandle, error)
+	// GetAttributeValues returns the values of the attributes of the object, in the same order as
+	// the types, with nil for any attribute the object does not have
+	GetAttributeValues(object ObjectHandle, types ...uint) ([][]byte, error)
+	// Sign signs the data with the key and mechanism, such as CKM_ECDSA (0x1041) to sign a digest
+	Sign(mechanism uint, key ObjectHandle, data []byte) ([]byte, error)
+	// CheckSession returns an error if the session is no longer usable
+	CheckSession() error
+	Close() error
+}
+
+// SessionOpener opens a session to the configured token, and logs in with the PIN
+type SessionOpener func(ctx context.Context, conf *Config, pin string) (Session, error)
+
+// secp256k1ECParams is the DER encoded named curve OID 1.3.132.0.10, in the CKA_EC_PARAMS of secp256k1 keys
+var secp256k1ECParams = []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}
+
+func isSecp256k1(ecParams []byte) bool {
+	return bytes.Equal(ecParams, secp256k1ECParams)
+}
+
+// parseECPoint parses the CKA_EC_POINT of a public key, which the specification defines as a DER
+// encoded OCTET STRING containing the uncompressed point - although some HSMs return the raw point
+func parseECPoint(ecPoint []byte) (*btcec.PublicKey, error) {
+	point := ecPoint
+	if len(ecPoint) != 65 {
+		if _, err := asn1.Unmarshal(ecPoint, &point); err != nil {
+			return nil, err
+		}
+	}
+	return btcec.ParsePubKey(point)
+}
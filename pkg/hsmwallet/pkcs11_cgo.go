@@ -0,0 +1,484 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build pkcs11 && cgo
+
+package hsmwallet
+
+/*
+#cgo linux LDFLAGS: -ldl
+
+#include <stdlib.h>
+#include <dlfcn.h>
+
+// The subset of the PKCS#11 v2.40 types used by the wallet. Only the functions we call are typed
+// in the function list, and the structures use the native packing of Unix platforms.
+
+typedef unsigned long CK_ULONG;
+typedef unsigned char CK_BYTE;
+typedef CK_ULONG CK_RV;
+typedef CK_ULONG CK_SLOT_ID;
+typedef CK_ULONG CK_SESSION_HANDLE;
+typedef CK_ULONG CK_OBJECT_HANDLE;
+
+typedef struct { CK_BYTE major; CK_BYTE minor; } CK_VERSION;
+
+typedef struct {
+	CK_ULONG type;
+	void *pValue;
+	CK_ULONG ulValueLen;
+} CK_ATTRIBUTE;
+
+typedef struct {
+	CK_ULONG mechanism;
+	void *pParameter;
+	CK_ULONG ulParameterLen;
+} CK_MECHANISM;
+
+typedef struct {
+	void *CreateMutex;
+	void *DestroyMutex;
+	void *LockMutex;
+	void *UnlockMutex;
+	CK_ULONG flags;
+	void *pReserved;
+} CK_C_INITIALIZE_ARGS;
+
+typedef struct {
+	CK_BYTE label[32];
+	CK_BYTE manufacturerID[32];
+	CK_BYTE model[16];
+	CK_BYTE serialNumber[16];
+	CK_ULONG flags;
+	CK_ULONG ulMaxSessionCount;
+	CK_ULONG ulSessionCount;
+	CK_ULONG ulMaxRwSessionCount;
+	CK_ULONG ulRwSessionCount;
+	CK_ULONG ulMaxPinLen;
+	CK_ULONG ulMinPinLen;
+	CK_ULONG ulTotalPublicMemory;
+	CK_ULONG ulFreePublicMemory;
+	CK_ULONG ulTotalPrivateMemory;
+	CK_ULONG ulFreePrivateMemory;
+	CK_VERSION hardwareVersion;
+	CK_VERSION firmwareVersion;
+	CK_BYTE utcTime[16];
+} CK_TOKEN_INFO;
+
+typedef struct {
+	CK_SLOT_ID slotID;
+	CK_ULONG state;
+	CK_ULONG flags;
+	CK_ULONG ulDeviceError;
+} CK_SESSION_INFO;
+
+typedef struct {
+	CK_VERSION version;
+	CK_RV (*C_Initialize)(void *);
+	void *C_Finalize;
+	void *C_GetInfo;
+	void *C_GetFunctionList;
+	CK_RV (*C_GetSlotList)(CK_BYTE, CK_SLOT_ID *, CK_ULONG *);
+	void *C_GetSlotInfo;
+	CK_RV (*C_GetTokenInfo)(CK_SLOT_ID, CK_TOKEN_INFO *);
+	void *C_GetMechanismList;
+	void *C_GetMechanismInfo;
+	void *C_InitToken;
+	void *C_InitPIN;
+	void *C_SetPIN;
+	CK_RV (*C_OpenSession)(CK_SLOT_ID, CK_ULONG, void *, void *, CK_SESSION_HANDLE *);
+	CK_RV (*C_CloseSession)(CK_SESSION_HANDLE);
+	void *C_CloseAllSessions;
+	CK_RV (*C_GetSessionInfo)(CK_SESSION_HANDLE, CK_SESSION_INFO *);
+	void *C_GetOperationState;
+	void *C_SetOperationState;
+	CK_RV (*C_Login)(CK_SESSION_HANDLE, CK_ULONG, CK_BYTE *, CK_ULONG);
+	void *C_Logout;
+	void *C_CreateObject;
+	void *C_CopyObject;
+	void *C_DestroyObject;
+	void *C_GetObjectSize;
+	CK_RV (*C_GetAttributeValue)(CK_SESSION_HANDLE, CK_OBJECT_HANDLE, CK_ATTRIBUTE *, CK_ULONG);
+	void *C_SetAttributeValue;
+	CK_RV (*C_FindObjectsInit)(CK_SESSION_HANDLE, CK_ATTRIBUTE *, CK_ULONG);
+	CK_RV (*C_FindObjects)(CK_SESSION_HANDLE, CK_OBJECT_HANDLE *, CK_ULONG, CK_ULONG *);
+	CK_RV (*C_FindObjectsFinal)(CK_SESSION_HANDLE);
+	void *C_EncryptInit;
+	void *C_Encrypt;
+	void *C_EncryptUpdate;
+	void *C_EncryptFinal;
+	void *C_DecryptInit;
+	void *C_Decrypt;
+	void *C_DecryptUpdate;
+	void *C_DecryptFinal;
+	void *C_DigestInit;
+	void *C_Digest;
+	void *C_DigestUpdate;
+	void *C_DigestKey;
+	void *C_DigestFinal;
+	CK_RV (*C_SignInit)(CK_SESSION_HANDLE, CK_MECHANISM *, CK_OBJECT_HANDLE);
+	CK_RV (*C_Sign)(CK_SESSION_HANDLE, CK_BYTE *, CK_ULONG, CK_BYTE *, CK_ULONG *);
+} CK_FUNCTION_LIST;
+
+typedef CK_RV (*CK_C_GetFunctionList)(CK_FUNCTION_LIST **);
+
+static const char *ck_load(const char *path, CK_FUNCTION_LIST **fl) {
+	void *lib = dlopen(path, RTLD_NOW | RTLD_LOCAL);
+	if (lib == NULL) {
+		return dlerror();
+	}
+	CK_C_GetFunctionList getFunctionList = (CK_C_GetFunctionList)dlsym(lib, "C_GetFunctionList");
+	if (getFunctionList == NULL) {
+		const char *err = dlerror();
+		dlclose(lib);
+		return err;
+	}
+	if (getFunctionList(fl) != 0 || *fl == NULL) {
+		dlclose(lib);
+		return "C_GetFunctionList failed";
+	}
+	return NULL;
+}
+
+static CK_RV ck_initialize(CK_FUNCTION_LIST *fl) {
+	CK_C_INITIALIZE_ARGS args = {0};
+	args.flags = 0x2; // CKF_OS_LOCKING_OK
+	return fl->C_Initialize(&args);
+}
+
+static CK_RV ck_get_slot_list(CK_FUNCTION_LIST *fl, CK_SLOT_ID *slots, CK_ULONG *count) {
+	return fl->C_GetSlotList(1, slots, count);
+}
+
+static CK_RV ck_get_token_info(CK_FUNCTION_LIST *fl, CK_SLOT_ID slot, CK_TOKEN_INFO *info) {
+	return fl->C_GetTokenInfo(slot, info);
+}
+
+static CK_RV ck_open_session(CK_FUNCTION_LIST *fl, CK_SLOT_ID slot, CK_SESSION_HANDLE *session) {
+	return fl->C_OpenSession(slot, 0x4 | 0x2, NULL, NULL, session); // CKF_SERIAL_SESSION | CKF_RW_SESSION
+}
+
+static CK_RV ck_close_session(CK_FUNCTION_LIST *fl, CK_SESSION_HANDLE session) {
+	return fl->C_CloseSession(session);
+}
+
+static CK_RV ck_get_session_info(CK_FUNCTION_LIST *fl, CK_SESSION_HANDLE session) {
+	CK_SESSION_INFO info;
+	return fl->C_GetSessionInfo(session, &info);
+}
+
+static CK_RV ck_login(CK_FUNCTION_LIST *fl, CK_SESSION_HANDLE session, CK_BYTE *pin, CK_ULONG pinLen) {
+	return fl->C_Login(session, 1, pin, pinLen); // CKU_USER
+}
+
+static CK_RV ck_get_attribute_value(CK_FUNCTION_LIST *fl, CK_SESSION_HANDLE session, CK_OBJECT_HANDLE object, CK_ATTRIBUTE *template, CK_ULONG count) {
+	return fl->C_GetAttributeValue(session, object, template, count);
+}
+
+static CK_RV ck_find_objects_init(CK_FUNCTION_LIST *fl, CK_SESSION_HANDLE session, CK_ATTRIBUTE *template, CK_ULONG count) {
+	return fl->C_FindObjectsInit(session, template, count);
+}
+
+static CK_RV ck_find_objects(CK_FUNCTION_LIST *fl, CK_SESSION_HANDLE session, CK_OBJECT_HANDLE *objects, CK_ULONG max, CK_ULONG *count) {
+	return fl->C_FindObjects(session, objects, max, count);
+}
+
+static CK_RV ck_find_objects_final(CK_FUNCTION_LIST *fl, CK_SESSION_HANDLE session) {
+	return fl->C_FindObjectsFinal(session);
+}
+
+static CK_RV ck_sign(CK_FUNCTION_LIST *fl, CK_SESSION_HANDLE session, CK_ULONG mechanism, CK_OBJECT_HANDLE key, CK_BYTE *data, CK_ULONG dataLen, CK_BYTE *sig, CK_ULONG *sigLen) {
+	CK_MECHANISM mech = {mechanism, NULL, 0};
+	CK_RV rv = fl->C_SignInit(session, &mech, key);
+	if (rv != 0) {
+		return rv;
+	}
+	return fl->C_Sign(session, data, dataLen, sig, sigLen);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+)
+
+const (
+	ckrOK                       = 0x000
+	ckrAttributeSensitive       = 0x011
+	ckrAttributeTypeInvalid     = 0x012
+	ckrUserAlreadyLoggedIn      = 0x100
+	ckrCryptokiAlreadyInitiated = 0x191
+	ckUnavailableInformation    = ^C.CK_ULONG(0)
+	findObjectsBatch            = 32
+	maxECDSASignatureLen        = 128
+)
+
+// The library is loaded and initialized once per process, and shared by all the sessions
+var (
+	libraryMux sync.Mutex
+	libraries  = map[string]*C.CK_FUNCTION_LIST{}
+)
+
+type pkcs11Session struct {
+	ctx     context.Context
+	mux     sync.Mutex
+	fl      *C.CK_FUNCTION_LIST
+	session C.CK_SESSION_HANDLE
+}
+
+// OpenPKCS11Session loads the PKCS#11 library, and opens a session logged in to the token with the configured label
+func OpenPKCS11Session(ctx context.Context, conf *Config, pin string) (Session, error) {
+	fl, err := loadLibrary(ctx, conf.Library)
+	if err != nil {
+		return nil, err
+	}
+	slot, err := findTokenSlot(ctx, fl, conf.TokenLabel)
+	if err != nil {
+		return nil, err
+	}
+	s := &pkcs11Session{ctx: ctx, fl: fl}
+	if rv := C.ck_open_session(fl, slot, &s.session); rv != ckrOK {
+		return nil, s.opError("C_OpenSession", rv)
+	}
+	cPIN := C.CBytes([]byte(pin))
+	defer C.free(cPIN)
+	rv := C.ck_login(fl, s.session, (*C.CK_BYTE)(cPIN), C.CK_ULONG(len(pin)))
+	if rv != ckrOK && rv != ckrUserAlreadyLoggedIn {
+		_ = s.Close()
+		return nil, s.opError("C_Login", rv)
+	}
+	log.L(ctx).Infof("Opened PKCS#11 session to token '%s' in slot %d", conf.TokenLabel, slot)
+	return s, nil
+}
+
+func loadLibrary(ctx context.Context, library string) (*C.CK_FUNCTION_LIST, error) {
+	libraryMux.Lock()
+	defer libraryMux.Unlock()
+	if fl, ok := libraries[library]; ok {
+		return fl, nil
+	}
+	cLibrary := C.CString(library)
+	defer C.free(unsafe.Pointer(cLibrary))
+	var fl *C.CK_FUNCTION_LIST
+	if errMsg := C.ck_load(cLibrary, &fl); errMsg != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgHSMLibraryLoadFailed, library, C.GoString(errMsg))
+	}
+	if rv := C.ck_initialize(fl); rv != ckrOK && rv != ckrCryptokiAlreadyInitiated {
+		return nil, i18n.NewError(ctx, signermsgs.MsgHSMOperationFailed, "C_Initialize", uint(rv))
+	}
+	libraries[library] = fl
+	return fl, nil
+}
+
+func findTokenSlot(ctx context.Context, fl *C.CK_FUNCTION_LIST, tokenLabel string) (C.CK_SLOT_ID, error) {
+	var count C.CK_ULONG
+	if rv := C.ck_get_slot_list(fl, nil, &count); rv != ckrOK {
+		return 0, i18n.NewError(ctx, signermsgs.MsgHSMOperationFailed, "C_GetSlotList", uint(rv))
+	}
+	if count > 0 {
+		slots := make([]C.CK_SLOT_ID, count)
+		if rv := C.ck_get_slot_list(fl, &slots[0], &count); rv != ckrOK {
+			return 0, i18n.NewError(ctx, signermsgs.MsgHSMOperationFailed, "C_GetSlotList", uint(rv))
+		}
+		for _, slot := range slots[:count] {
+			var info C.CK_TOKEN_INFO
+			if rv := C.ck_get_token_info(fl, slot, &info); rv != ckrOK {
+				return 0, i18n.NewError(ctx, signermsgs.MsgHSMOperationFailed, "C_GetTokenInfo", uint(rv))
+			}
+			// Token labels are padded with spaces to 32 bytes
+			label := C.GoBytes(unsafe.Pointer(&info.label[0]), C.int(len(info.label)))
+			if strings.TrimRight(string(label), " \x00") == tokenLabel {
+				return slot, nil
+			}
+		}
+	}
+	return 0, i18n.NewError(ctx, signermsgs.MsgHSMTokenNotFound, tokenLabel)
+}
+
+func (s *pkcs11Session) opError(op string, rv C.CK_RV) error {
+	return i18n.NewError(s.ctx, signermsgs.MsgHSMOperationFailed, op, uint(rv))
+}
+
+// cTemplate is an attribute template allocated in C memory, as the library may keep
+// pointers to it for the duration of the call
+type cTemplate struct {
+	attrs  *C.CK_ATTRIBUTE
+	count  C.CK_ULONG
+	values []unsafe.Pointer
+}
+
+func newCTemplate(count int) *cTemplate {
+	t := &cTemplate{count: C.CK_ULONG(count)}
+	if count > 0 {
+		t.attrs = (*C.CK_ATTRIBUTE)(C.calloc(C.size_t(count), C.size_t(unsafe.Sizeof(C.CK_ATTRIBUTE{}))))
+	}
+	return t
+}
+
+func (t *cTemplate) slice() []C.CK_ATTRIBUTE {
+	if t.attrs == nil {
+		return nil
+	}
+	return unsafe.Slice(t.attrs, t.count)
+}
+
+func (t *cTemplate) setValue(i int, attrType uint, value []byte) {
+	attr := &t.slice()[i]
+	attr._type = C.CK_ULONG(attrType)
+	attr.ulValueLen = C.CK_ULONG(len(value))
+	if len(value) > 0 {
+		attr.pValue = C.CBytes(value)
+		t.values = append(t.values, attr.pValue)
+	}
+}
+
+func (t *cTemplate) allocValue(i int) {
+	attr := &t.slice()[i]
+	attr.pValue = C.malloc(C.size_t(attr.ulValueLen))
+	t.values = append(t.values, attr.pValue)
+}
+
+func (t *cTemplate) free() {
+	for _, v := range t.values {
+		C.free(v)
+	}
+	if t.attrs != nil {
+		C.free(unsafe.Pointer(t.attrs))
+	}
+}
+
+func attributeBytes(value interface{}) []byte {
+	switch v := value.(type) {
+	case []byte:
+		return v
+	case string:
+		return []byte(v)
+	case uint:
+		ul := C.CK_ULONG(v)
+		return C.GoBytes(unsafe.Pointer(&ul), C.int(unsafe.Sizeof(ul)))
+	default:
+		return nil
+	}
+}
+
+func (s *pkcs11Session) FindObjects(template []*Attribute) ([]ObjectHandle, error) {
+	t := newCTemplate(len(template))
+	defer t.free()
+	for i, attr := range template {
+		t.setValue(i, attr.Type, attributeBytes(attr.Value))
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if rv := C.ck_find_objects_init(s.fl, s.session, t.attrs, t.count); rv != ckrOK {
+		return nil, s.opError("C_FindObjectsInit", rv)
+	}
+	var handles []ObjectHandle
+	batch := (*C.CK_OBJECT_HANDLE)(C.malloc(C.size_t(findObjectsBatch * unsafe.Sizeof(C.CK_OBJECT_HANDLE(0)))))
+	defer C.free(unsafe.Pointer(batch))
+	for {
+		var count C.CK_ULONG
+		if rv := C.ck_find_objects(s.fl, s.session, batch, findObjectsBatch, &count); rv != ckrOK {
+			_ = C.ck_find_objects_final(s.fl, s.session)
+			return nil, s.opError("C_FindObjects", rv)
+		}
+		if count == 0 {
+			break
+		}
+		for _, h := range unsafe.Slice(batch, count) {
+			handles = append(handles, ObjectHandle(h))
+		}
+	}
+	if rv := C.ck_find_objects_final(s.fl, s.session); rv != ckrOK {
+		return nil, s.opError("C_FindObjectsFinal", rv)
+	}
+	return handles, nil
+}
+
+func (s *pkcs11Session) GetAttributeValues(object ObjectHandle, types ...uint) ([][]byte, error) {
+	t := newCTemplate(len(types))
+	defer t.free()
+	for i, attrType := range types {
+		t.setValue(i, attrType, nil)
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	// The first call gets the lengths of the values, and the second gets the values
+	for pass := 0; pass < 2; pass++ {
+		rv := C.ck_get_attribute_value(s.fl, s.session, C.CK_OBJECT_HANDLE(object), t.attrs, t.count)
+		if rv != ckrOK && rv != ckrAttributeSensitive && rv != ckrAttributeTypeInvalid {
+			return nil, s.opError("C_GetAttributeValue", rv)
+		}
+		if pass == 0 {
+			for i, attr := range t.slice() {
+				if attr.ulValueLen != ckUnavailableInformation && attr.ulValueLen > 0 {
+					t.allocValue(i)
+				}
+			}
+		}
+	}
+	values := make([][]byte, len(types))
+	for i, attr := range t.slice() {
+		if attr.pValue != nil && attr.ulValueLen != ckUnavailableInformation {
+			values[i] = C.GoBytes(attr.pValue, C.int(attr.ulValueLen))
+		}
+	}
+	return values, nil
+}
+
+func (s *pkcs11Session) Sign(mechanism uint, key ObjectHandle, data []byte) ([]byte, error) {
+	cData := C.CBytes(data)
+	defer C.free(cData)
+	cSig := C.malloc(maxECDSASignatureLen)
+	defer C.free(cSig)
+	sigLen := C.CK_ULONG(maxECDSASignatureLen)
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	rv := C.ck_sign(s.fl, s.session, C.CK_ULONG(mechanism), C.CK_OBJECT_HANDLE(key),
+		(*C.CK_BYTE)(cData), C.CK_ULONG(len(data)), (*C.CK_BYTE)(cSig), &sigLen)
+	if rv != ckrOK {
+		return nil, s.opError("C_Sign", rv)
+	}
+	return C.GoBytes(cSig, C.int(sigLen)), nil
+}
+
+func (s *pkcs11Session) CheckSession() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if rv := C.ck_get_session_info(s.fl, s.session); rv != ckrOK {
+		return s.opError("C_GetSessionInfo", rv)
+	}
+	return nil
+}
+
+// Close closes the session. The library stays initialized, as it is shared by all the sessions in the process.
+func (s *pkcs11Session) Close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if rv := C.ck_close_session(s.fl, s.session); rv != ckrOK {
+		return s.opError("C_CloseSession", rv)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build pkcs11 && cgo
+
+package hsmwallet
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/hyperledger/firefly-signer/pkg/eip712"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSoftHSM runs against a SoftHSMv2 token (or any other PKCS#11 token) with secp256k1 keys, for example:
+//
+//	softhsm2-util --init-token --free --label signer --pin 1234 --so-pin 5678
+//	pkcs11-tool --module /usr/lib/softhsm/libsofthsm2.so --token-label signer --login --pin 1234 \
+//	  --keypairgen --key-type EC:secp256k1 --id 01 --label key1
+//	HSM_TEST_LIBRARY=/usr/lib/softhsm/libsofthsm2.so HSM_TEST_TOKEN=signer HSM_TEST_PIN=1234 \
+//	  go test -tags pkcs11 ./pkg/hsmwallet
+func TestSoftHSM(t *testing.T) {
+	library := os.Getenv("HSM_TEST_LIBRARY")
+	if library == "" {
+		t.Skip("HSM_TEST_LIBRARY not set")
+	}
+	pinFile := path.Join(t.TempDir(), "pin")
+	err := os.WriteFile(pinFile, []byte(os.Getenv("HSM_TEST_PIN")), 0600)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	w, err := NewHSMWallet(ctx, &Config{
+		Library:    library,
+		TokenLabel: os.Getenv("HSM_TEST_TOKEN"),
+		PINFile:    pinFile,
+	})
+	assert.NoError(t, err)
+	err = w.Initialize(ctx)
+	assert.NoError(t, err)
+	defer w.Close()
+
+	err = w.CheckHealth(ctx)
+	assert.NoError(t, err)
+
+	accounts, err := w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, accounts)
+	for _, addr := range accounts {
+		from, _ := json.Marshal(addr)
+		b, err := w.Sign(ctx, &ethsigner.Transaction{
+			From:     from,
+			Nonce:    ethtypes.NewHexInteger64(0),
+			GasLimit: ethtypes.NewHexInteger64(21000),
+		}, 1337)
+		assert.NoError(t, err)
+		signer, _, err := ethsigner.RecoverRawTransaction(ctx, b, 1337)
+		assert.NoError(t, err)
+		assert.Equal(t, addr, signer)
+
+		_, err = w.SignTypedDataV4(ctx, *addr, &eip712.TypedData{PrimaryType: eip712.EIP712Domain})
+		assert.NoError(t, err)
+	}
+}
+
+func TestOpenPKCS11SessionBadLibrary(t *testing.T) {
+	_, err := OpenPKCS11Session(context.Background(), &Config{Library: "/does/not/exist.so"}, "1234")
+	assert.Regexp(t, "FF22180", err)
+}
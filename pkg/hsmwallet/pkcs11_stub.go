@@ -0,0 +1,31 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !pkcs11 || !cgo
+
+package hsmwallet
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+)
+
+// OpenPKCS11Session returns an error, as the PKCS#11 binding requires cgo and the pkcs11 build tag
+func OpenPKCS11Session(ctx context.Context, _ *Config, _ string) (Session, error) {
+	return nil, i18n.NewError(ctx, signermsgs.MsgHSMNotSupported)
+}
@@ -0,0 +1,34 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !pkcs11 || !cgo
+
+package hsmwallet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenPKCS11SessionNotSupported(t *testing.T) {
+	ctx := context.Background()
+	w, err := NewHSMWallet(ctx, &Config{PINFile: writePINFile(t)})
+	assert.NoError(t, err)
+	err = w.Initialize(ctx)
+	assert.Regexp(t, "FF22179", err)
+}
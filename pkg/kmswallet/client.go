@@ -0,0 +1,251 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmswallet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+)
+
+const (
+	// KeySpecSecp256k1 is the key spec of secp256k1 keys
+	KeySpecSecp256k1 = "ECC_SECG_P256K1"
+	// SigningAlgorithmECDSASHA256 is the signing algorithm of secp256k1 keys. Ethereum signs keccak256
+	// digests, so the message type is always DIGEST and the KMS does not hash the message again.
+	SigningAlgorithmECDSASHA256 = "ECDSA_SHA_256"
+	messageTypeDigest           = "DIGEST"
+	listLimit                   = 100
+	defaultRequestTimeout       = 30 * time.Second
+)
+
+// The subset of the KMS JSON API used by the wallet
+
+type getPublicKeyRequest struct {
+	KeyID string `json:"KeyId"`
+}
+
+type getPublicKeyResponse struct {
+	KeyID     string `json:"KeyId"`
+	KeySpec   string `json:"KeySpec"`
+	KeyUsage  string `json:"KeyUsage"`
+	PublicKey []byte `json:"PublicKey"` // base64 DER SubjectPublicKeyInfo
+}
+
+type signRequest struct {
+	KeyID            string `json:"KeyId"`
+	Message          []byte `json:"Message"`
+	MessageType      string `json:"MessageType"`
+	SigningAlgorithm string `json:"SigningAlgorithm"`
+}
+
+type signResponse struct {
+	KeyID     string `json:"KeyId"`
+	Signature []byte `json:"Signature"` // base64 DER ECDSA signature
+}
+
+type listRequest struct {
+	KeyID  string `json:"KeyId,omitempty"`
+	Limit  int    `json:"Limit"`
+	Marker string `json:"Marker,omitempty"`
+}
+
+type listResponse struct {
+	NextMarker string `json:"NextMarker"`
+	Truncated  bool   `json:"Truncated"`
+}
+
+type keyListEntry struct {
+	KeyID  string `json:"KeyId"`
+	KeyArn string `json:"KeyArn"`
+}
+
+type listKeysResponse struct {
+	listResponse
+	Keys []*keyListEntry `json:"Keys"`
+}
+
+type aliasListEntry struct {
+	AliasName   string `json:"AliasName"`
+	AliasArn    string `json:"AliasArn"`
+	TargetKeyID string `json:"TargetKeyId"`
+}
+
+type listAliasesResponse struct {
+	listResponse
+	Aliases []*aliasListEntry `json:"Aliases"`
+}
+
+type tag struct {
+	TagKey   string `json:"TagKey"`
+	TagValue string `json:"TagValue"`
+}
+
+type listResourceTagsResponse struct {
+	listResponse
+	Tags []*tag `json:"Tags"`
+}
+
+type errorResponse struct {
+	Type        string `json:"__type"`
+	Message     string `json:"message"`
+	MessageCaps string `json:"Message"`
+}
+
+// kmsClient calls the KMS JSON API over HTTP, signing each request with the credentials
+type kmsClient struct {
+	client  *resty.Client
+	region  string
+	host    string
+	path    string
+	timeout time.Duration
+	now     func() time.Time
+
+	mux   sync.Mutex
+	creds *credentials
+}
+
+func newKMSClient(ctx context.Context, client *resty.Client, region string, timeout time.Duration) (*kmsClient, error) {
+	if client.BaseURL == "" {
+		client.SetBaseURL(fmt.Sprintf("https://kms.%s.amazonaws.com", region))
+	}
+	u, err := url.Parse(client.BaseURL)
+	if err != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgKMSMissingConfig, "url")
+	}
+	if timeout <= 0 {
+		timeout = defaultRequestTimeout
+	}
+	return &kmsClient{
+		client:  client,
+		region:  region,
+		host:    u.Host,
+		path:    u.Path,
+		timeout: timeout,
+		now:     time.Now,
+	}, nil
+}
+
+func (c *kmsClient) setCredentials(creds *credentials) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.creds = creds
+}
+
+func (c *kmsClient) call(ctx context.Context, operation string, input, output interface{}) error {
+	c.mux.Lock()
+	creds := c.creds
+	c.mux.Unlock()
+	if creds == nil {
+		return i18n.NewError(ctx, signermsgs.MsgKMSMissingConfig, "credentials")
+	}
+	// Every request has a deadline, as a request to sign may be bound to a context that never ends
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	body, _ := json.Marshal(input)
+	var errRes errorResponse
+	res, err := c.client.R().
+		SetContext(ctx).
+		SetHeaders(signedHeaders(creds, c.region, c.host, c.path, operation, body, c.now())).
+		SetBody(body).
+		SetResult(output).
+		SetError(&errRes).
+		Post("")
+	if err != nil {
+		return i18n.WrapError(ctx, err, signermsgs.MsgKMSRequestFailed, operation, err)
+	}
+	if res.IsError() {
+		message := errRes.Message
+		if message == "" {
+			message = errRes.MessageCaps
+		}
+		return i18n.NewError(ctx, signermsgs.MsgKMSRequestFailed, operation, fmt.Sprintf("[%d] %s %s", res.StatusCode(), errRes.Type, message))
+	}
+	return nil
+}
+
+func (c *kmsClient) getPublicKey(ctx context.Context, keyID string) (*getPublicKeyResponse, error) {
+	var res getPublicKeyResponse
+	err := c.call(ctx, "GetPublicKey", &getPublicKeyRequest{KeyID: keyID}, &res)
+	return &res, err
+}
+
+func (c *kmsClient) sign(ctx context.Context, keyID string, digest []byte) ([]byte, error) {
+	var res signResponse
+	err := c.call(ctx, "Sign", &signRequest{
+		KeyID:            keyID,
+		Message:          digest,
+		MessageType:      messageTypeDigest,
+		SigningAlgorithm: SigningAlgorithmECDSASHA256,
+	}, &res)
+	return res.Signature, err
+}
+
+func (c *kmsClient) listKeys(ctx context.Context) ([]*keyListEntry, error) {
+	var keys []*keyListEntry
+	marker := ""
+	for {
+		var res listKeysResponse
+		if err := c.call(ctx, "ListKeys", &listRequest{Limit: listLimit, Marker: marker}, &res); err != nil {
+			return nil, err
+		}
+		keys = append(keys, res.Keys...)
+		if !res.Truncated {
+			return keys, nil
+		}
+		marker = res.NextMarker
+	}
+}
+
+func (c *kmsClient) listAliases(ctx context.Context) ([]*aliasListEntry, error) {
+	var aliases []*aliasListEntry
+	marker := ""
+	for {
+		var res listAliasesResponse
+		if err := c.call(ctx, "ListAliases", &listRequest{Limit: listLimit, Marker: marker}, &res); err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, res.Aliases...)
+		if !res.Truncated {
+			return aliases, nil
+		}
+		marker = res.NextMarker
+	}
+}
+
+func (c *kmsClient) listResourceTags(ctx context.Context, keyID string) ([]*tag, error) {
+	var tags []*tag
+	marker := ""
+	for {
+		var res listResourceTagsResponse
+		if err := c.call(ctx, "ListResourceTags", &listRequest{KeyID: keyID, Limit: listLimit, Marker: marker}, &res); err != nil {
+			return nil, err
+		}
+		tags = append(tags, res.Tags...)
+		if !res.Truncated {
+			return tags, nil
+		}
+		marker = res.NextMarker
+	}
+}
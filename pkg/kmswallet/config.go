@@ -0,0 +1,108 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmswallet
+
+import (
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+)
+
+const (
+	// ConfigRegion the region of the KMS, used to sign requests and for the default URL
+	ConfigRegion = "region"
+	// ConfigCredentialsAccessKeyID the access key ID - the AWS_ACCESS_KEY_ID environment variable is used if not set
+	ConfigCredentialsAccessKeyID = "credentials.accessKeyId"
+	// ConfigCredentialsSecretAccessKeyFile the file containing the secret access key
+	ConfigCredentialsSecretAccessKeyFile = "credentials.secretAccessKeyFile"
+	// ConfigCredentialsSessionTokenFile optional file containing the session token of temporary credentials
+	ConfigCredentialsSessionTokenFile = "credentials.sessionTokenFile"
+	// ConfigKeysIDs optional list of the IDs, ARNs or alias names of the keys to use
+	ConfigKeysIDs = "keys.ids"
+	// ConfigKeysAliases optional list of the alias names of the keys to use
+	ConfigKeysAliases = "keys.aliases"
+	// ConfigKeysAliasPrefix optional prefix of the alias names of the keys to use
+	ConfigKeysAliasPrefix = "keys.aliasPrefix"
+	// ConfigKeysTagKey optional tag key of the keys to use
+	ConfigKeysTagKey = "keys.tag.key"
+	// ConfigKeysTagValue the value of the tag of the keys to use
+	ConfigKeysTagValue = "keys.tag.value"
+)
+
+type Config struct {
+	Region         string
+	RequestTimeout time.Duration
+	Credentials    CredentialsConfig
+	Keys           KeysConfig
+}
+
+// CredentialsConfig are the credentials to sign requests with. When the access key ID is not configured,
+// the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables are used.
+type CredentialsConfig struct {
+	AccessKeyID         string
+	SecretAccessKeyFile string
+	SessionTokenFile    string
+}
+
+// KeysConfig selects the keys. The keys are the union of all the keys selected by each option.
+type KeysConfig struct {
+	IDs         []string
+	Aliases     []string
+	AliasPrefix string
+	Tag         TagConfig
+}
+
+type TagConfig struct {
+	Key   string
+	Value string
+}
+
+// InitConfig adds the wallet keys, and the HTTP client keys for the connection to the KMS
+func InitConfig(section config.Section) {
+	ffresty.InitConfig(section)
+	section.AddKnownKey(ConfigRegion)
+	section.AddKnownKey(ConfigCredentialsAccessKeyID)
+	section.AddKnownKey(ConfigCredentialsSecretAccessKeyFile)
+	section.AddKnownKey(ConfigCredentialsSessionTokenFile)
+	section.AddKnownKey(ConfigKeysIDs)
+	section.AddKnownKey(ConfigKeysAliases)
+	section.AddKnownKey(ConfigKeysAliasPrefix)
+	section.AddKnownKey(ConfigKeysTagKey)
+	section.AddKnownKey(ConfigKeysTagValue)
+}
+
+func ReadConfig(section config.Section) *Config {
+	return &Config{
+		Region:         section.GetString(ConfigRegion),
+		RequestTimeout: section.GetDuration(ffresty.HTTPConfigRequestTimeout),
+		Credentials: CredentialsConfig{
+			AccessKeyID:         section.GetString(ConfigCredentialsAccessKeyID),
+			SecretAccessKeyFile: section.GetString(ConfigCredentialsSecretAccessKeyFile),
+			SessionTokenFile:    section.GetString(ConfigCredentialsSessionTokenFile),
+		},
+		Keys: KeysConfig{
+			IDs:         section.GetStringSlice(ConfigKeysIDs),
+			Aliases:     section.GetStringSlice(ConfigKeysAliases),
+			AliasPrefix: section.GetString(ConfigKeysAliasPrefix),
+			Tag: TagConfig{
+				Key:   section.GetString(ConfigKeysTagKey),
+				Value: section.GetString(ConfigKeysTagValue),
+			},
+		},
+	}
+}
@@ -0,0 +1,394 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmswallet
+
+import (
+	"context"
+	"encoding/asn1"
+	"os"
+	"strings"
+	"sync"
+
+	btcec "github.com/btcsuite/btcd/btcec/v2" // ISC licensed
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// MetadataKeyID is the metadata property containing the ARN of the KMS key of the account
+	MetadataKeyID = "keyId"
+
+	aliasPrefix = "alias/"
+)
+
+// Wallet is a wallet where the secp256k1 (ECC_SECG_P256K1) keys are held in a cloud KMS, such as
+// AWS KMS, and used through the KMS JSON API. The private keys never leave the KMS - digests are
+// signed with ECDSA_SHA_256, and the signatures are converted to Ethereum signatures.
+type Wallet interface {
+	ethsigner.WalletTypedData
+	ethsigner.WalletEIP191
+	ethsigner.WalletMetadata
+	ethsigner.WalletListener
+	// Signer returns a signer for the key of the address, for use with any of the signing functions.
+	// The requests to the KMS end with the context.
+	Signer(ctx context.Context, addr ethtypes.Address0xHex) (secp256k1.SignerDirect, error)
+}
+
+// kmsKey is a key in the KMS, which implements secp256k1.SignerDirect.
+// The signing functions have no context, so each signer is bound to the context of the request.
+type kmsKey struct {
+	w       *kmsWallet
+	ctx     context.Context
+	keyArn  string
+	address ethtypes.Address0xHex
+}
+
+// subjectPublicKeyInfo is the DER encoded EC public key returned by GetPublicKey
+type subjectPublicKeyInfo struct {
+	Algorithm struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.ObjectIdentifier
+	}
+	PublicKey asn1.BitString
+}
+
+var oidSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+type kmsWallet struct {
+	*ethsigner.KeySigner
+	*ethsigner.Listeners
+	conf   Config
+	client *kmsClient
+
+	mux         sync.Mutex
+	keysByID    map[string]*kmsKey // cache of the keys by the ID we looked them up with, nil for ignored keys
+	keys        map[ethtypes.Address0xHex]*kmsKey
+	addressList []*ethtypes.Address0xHex // in the order they are found
+}
+
+// NewKMSWallet validates the configuration, and uses the HTTP client to call the KMS. When the
+// client does not have a base URL, the endpoint of the region is used.
+// The credentials are read on Initialize.
+func NewKMSWallet(ctx context.Context, conf *Config, httpClient *resty.Client, initialListeners ...chan<- ethtypes.Address0xHex) (Wallet, error) {
+	return newKMSWallet(ctx, conf, httpClient, initialListeners...)
+}
+
+func newKMSWallet(ctx context.Context, conf *Config, httpClient *resty.Client, initialListeners ...chan<- ethtypes.Address0xHex) (*kmsWallet, error) {
+	if conf.Region == "" {
+		return nil, i18n.NewError(ctx, signermsgs.MsgKMSMissingConfig, ConfigRegion)
+	}
+	keys := conf.Keys
+	if len(keys.IDs) == 0 && len(keys.Aliases) == 0 && keys.AliasPrefix == "" && keys.Tag.Key == "" {
+		return nil, i18n.NewError(ctx, signermsgs.MsgKMSNoKeysConfigured)
+	}
+	client, err := newKMSClient(ctx, httpClient, conf.Region, conf.RequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+	w := &kmsWallet{
+		Listeners: ethsigner.NewListeners(initialListeners...),
+		conf:      *conf,
+		client:    client,
+		keysByID:  make(map[string]*kmsKey),
+		keys:      make(map[ethtypes.Address0xHex]*kmsKey),
+	}
+	w.KeySigner = ethsigner.NewKeySigner(w.Signer)
+	return w, nil
+}
+
+// Initialize reads the credentials, and finds the keys
+func (w *kmsWallet) Initialize(ctx context.Context) error {
+	creds, err := w.readCredentials(ctx)
+	if err != nil {
+		return err
+	}
+	w.client.setCredentials(creds)
+	return w.Refresh(ctx)
+}
+
+func (w *kmsWallet) readCredentials(ctx context.Context) (*credentials, error) {
+	conf := w.conf.Credentials
+	if conf.AccessKeyID == "" {
+		creds := &credentials{
+			accessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+		if creds.accessKeyID == "" || creds.secretAccessKey == "" {
+			return nil, i18n.NewError(ctx, signermsgs.MsgKMSMissingConfig, "credentials")
+		}
+		return creds, nil
+	}
+	creds := &credentials{accessKeyID: conf.AccessKeyID}
+	var err error
+	if creds.secretAccessKey, err = readTrimmedFile(ctx, conf.SecretAccessKeyFile); err != nil {
+		return nil, err
+	}
+	if conf.SessionTokenFile != "" {
+		if creds.sessionToken, err = readTrimmedFile(ctx, conf.SessionTokenFile); err != nil {
+			return nil, err
+		}
+	}
+	return creds, nil
+}
+
+func readTrimmedFile(ctx context.Context, filename string) (string, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return "", i18n.WrapError(ctx, err, signermsgs.MsgKMSCredentialsFileFailed, filename)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+func normalizeAlias(alias string) string {
+	if !strings.HasPrefix(alias, aliasPrefix) {
+		return aliasPrefix + alias
+	}
+	return alias
+}
+
+// Refresh finds the keys selected by ID, alias and tag, and notifies the listeners of any new addresses.
+// Keys selected by alias prefix or tag that are not secp256k1 keys are ignored.
+func (w *kmsWallet) Refresh(ctx context.Context) error {
+	keyIDs, explicit, err := w.selectKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	var found []*kmsKey
+	for _, keyID := range keyIDs {
+		key, err := w.getKeyByID(ctx, keyID, explicit[keyID])
+		if err != nil {
+			return err
+		}
+		if key != nil {
+			found = append(found, key)
+		}
+	}
+
+	w.mux.Lock()
+	var newAddresses []*ethtypes.Address0xHex
+	for _, key := range found {
+		if _, exists := w.keys[key.address]; !exists {
+			addr := key.address
+			newAddresses = append(newAddresses, &addr)
+			w.addressList = append(w.addressList, &addr)
+			w.keys[key.address] = key
+		}
+	}
+	w.mux.Unlock()
+
+	log.L(ctx).Infof("Found %d keys in KMS region %s (%d new)", len(found), w.conf.Region, len(newAddresses))
+	w.Notify(newAddresses)
+	return nil
+}
+
+// selectKeys returns the IDs of all the selected keys, and which of them were explicitly configured
+func (w *kmsWallet) selectKeys(ctx context.Context) ([]string, map[string]bool, error) {
+	keys := w.conf.Keys
+	var keyIDs []string
+	explicit := make(map[string]bool)
+	add := func(keyID string, isExplicit bool) {
+		if _, exists := explicit[keyID]; !exists {
+			keyIDs = append(keyIDs, keyID)
+		}
+		explicit[keyID] = explicit[keyID] || isExplicit
+	}
+
+	for _, keyID := range keys.IDs {
+		add(keyID, true)
+	}
+
+	if len(keys.Aliases) > 0 || keys.AliasPrefix != "" {
+		aliases, err := w.client.listAliases(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		targets := make(map[string]string, len(aliases))
+		for _, alias := range aliases {
+			// Aliases that are not associated with a key have no target
+			if alias.TargetKeyID != "" {
+				targets[alias.AliasName] = alias.TargetKeyID
+			}
+		}
+		for _, name := range keys.Aliases {
+			keyID, ok := targets[normalizeAlias(name)]
+			if !ok {
+				return nil, nil, i18n.NewError(ctx, signermsgs.MsgKMSAliasNotFound, name)
+			}
+			add(keyID, true)
+		}
+		if keys.AliasPrefix != "" {
+			prefix := normalizeAlias(keys.AliasPrefix)
+			for _, alias := range aliases {
+				if alias.TargetKeyID != "" && strings.HasPrefix(alias.AliasName, prefix) {
+					add(alias.TargetKeyID, false)
+				}
+			}
+		}
+	}
+
+	if keys.Tag.Key != "" {
+		kmsKeys, err := w.client.listKeys(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, key := range kmsKeys {
+			tags, err := w.client.listResourceTags(ctx, key.KeyID)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, t := range tags {
+				if t.TagKey == keys.Tag.Key && (keys.Tag.Value == "" || t.TagValue == keys.Tag.Value) {
+					add(key.KeyID, false)
+					break
+				}
+			}
+		}
+	}
+	return keyIDs, explicit, nil
+}
+
+// getKeyByID gets the public key of the key, unless it is already cached. Keys that are not
+// secp256k1 keys are an error when explicitly configured, and otherwise ignored.
+func (w *kmsWallet) getKeyByID(ctx context.Context, keyID string, explicit bool) (*kmsKey, error) {
+	w.mux.Lock()
+	key, ok := w.keysByID[keyID]
+	w.mux.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	res, err := w.client.getPublicKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if res.KeySpec != KeySpecSecp256k1 {
+		if explicit {
+			return nil, i18n.NewError(ctx, signermsgs.MsgKMSInvalidPublicKey, keyID, res.KeySpec)
+		}
+		log.L(ctx).Debugf("Ignoring KMS key %s with key spec %s", keyID, res.KeySpec)
+		w.mux.Lock()
+		w.keysByID[keyID] = nil
+		w.mux.Unlock()
+		return nil, nil
+	}
+	pubKey, err := parsePublicKey(ctx, keyID, res.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	key = &kmsKey{
+		w:       w,
+		keyArn:  res.KeyID,
+		address: *secp256k1.PublicKeyToAddress(pubKey),
+	}
+	log.L(ctx).Debugf("KMS key %s has address %s", key.keyArn, key.address)
+
+	w.mux.Lock()
+	w.keysByID[keyID] = key
+	w.mux.Unlock()
+	return key, nil
+}
+
+func parsePublicKey(ctx context.Context, keyID string, der []byte) (*btcec.PublicKey, error) {
+	var spki subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(der, &spki); err != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgKMSInvalidPublicKey, keyID, err)
+	}
+	if !spki.Algorithm.Parameters.Equal(oidSecp256k1) {
+		return nil, i18n.NewError(ctx, signermsgs.MsgKMSInvalidPublicKey, keyID, spki.Algorithm.Parameters)
+	}
+	pubKey, err := btcec.ParsePubKey(spki.PublicKey.Bytes)
+	if err != nil {
+		return nil, i18n.NewError(ctx, signermsgs.MsgKMSInvalidPublicKey, keyID, err)
+	}
+	return pubKey, nil
+}
+
+// GetAccounts returns the addresses of the keys, in the order they were found
+func (w *kmsWallet) GetAccounts(_ context.Context) ([]*ethtypes.Address0xHex, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	accounts := make([]*ethtypes.Address0xHex, len(w.addressList))
+	copy(accounts, w.addressList)
+	return accounts, nil
+}
+
+// GetAccountMetadata returns the ARN of the KMS key of the account
+func (w *kmsWallet) GetAccountMetadata(ctx context.Context, addr ethtypes.Address0xHex) (map[string]interface{}, error) {
+	key, err := w.getKey(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		MetadataKeyID: key.keyArn,
+	}, nil
+}
+
+// Close does nothing, as each request to the KMS is independent
+func (w *kmsWallet) Close() error {
+	return nil
+}
+
+func (w *kmsWallet) getKey(ctx context.Context, addr ethtypes.Address0xHex) (*kmsKey, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	key, ok := w.keys[addr]
+	if !ok {
+		return nil, i18n.NewError(ctx, signermsgs.MsgWalletNotAvailable, addr)
+	}
+	return key, nil
+}
+
+// Signer returns a signer for the key, bound to the context
+func (w *kmsWallet) Signer(ctx context.Context, addr ethtypes.Address0xHex) (secp256k1.SignerDirect, error) {
+	key, err := w.getKey(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	bound := *key
+	bound.ctx = ctx
+	return &bound, nil
+}
+
+// Sign hashes the message with keccak256, and signs the digest in the KMS
+func (k *kmsKey) Sign(message []byte) (*secp256k1.SignatureData, error) {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(message)
+	return k.SignDirect(hash.Sum(nil))
+}
+
+// SignDirect signs the digest in the KMS, and converts the DER signature to an Ethereum
+// signature with a low S value and the recovery ID
+func (k *kmsKey) SignDirect(digest []byte) (*secp256k1.SignatureData, error) {
+	ctx := k.ctx
+	sig, err := k.w.client.sign(ctx, k.keyArn, digest)
+	if err != nil {
+		return nil, err
+	}
+	r, s, err := secp256k1.ParseECDSASignature(ctx, sig)
+	if err != nil {
+		return nil, err
+	}
+	return secp256k1.NewSignatureDataFromRS(ctx, digest, r, s, k.address)
+}
@@ -0,0 +1,631 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmswallet
+
+import (
+	"context"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	btcec "github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-signer/pkg/eip712"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+	"github.com/stretchr/testify/assert"
+)
+
+// The first accounts of Hardhat and Anvil
+var (
+	testKey0 = secp256k1.KeyPairFromBytes(mustHex("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"))
+	testKey1 = secp256k1.KeyPairFromBytes(mustHex("59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"))
+	testKey2 = secp256k1.KeyPairFromBytes(mustHex("5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a"))
+)
+
+var oidECPublicKey = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func testSPKI(key *secp256k1.KeyPair) []byte {
+	var spki subjectPublicKeyInfo
+	spki.Algorithm.Algorithm = oidECPublicKey
+	spki.Algorithm.Parameters = oidSecp256k1
+	point := key.PublicKey.SerializeUncompressed()
+	spki.PublicKey = asn1.BitString{Bytes: point, BitLength: len(point) * 8}
+	b, _ := asn1.Marshal(spki)
+	return b
+}
+
+type mockKey struct {
+	arn       string
+	keySpec   string
+	publicKey []byte
+	keypair   *secp256k1.KeyPair
+	tags      []*tag
+}
+
+// mockKMS is a local KMS server, implementing the JSON API used by the wallet with one item per page
+type mockKMS struct {
+	t        *testing.T
+	mux      sync.Mutex
+	keys     map[string]*mockKey // by key ID
+	keyOrder []string
+	aliases  []*aliasListEntry
+	highS    bool
+	failOps  map[string]int
+	badSig   bool
+	delay    time.Duration
+	calls    map[string]int
+}
+
+func newMockKMS(t *testing.T) (*mockKMS, *httptest.Server) {
+	m := &mockKMS{
+		t:       t,
+		keys:    make(map[string]*mockKey),
+		failOps: make(map[string]int),
+		calls:   make(map[string]int),
+	}
+	server := httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	t.Cleanup(server.Close)
+	return m, server
+}
+
+func (m *mockKMS) addKey(keyID string, keypair *secp256k1.KeyPair, aliases []string, tags map[string]string) {
+	key := &mockKey{
+		arn:     "arn:aws:kms:us-east-1:111122223333:key/" + keyID,
+		keySpec: KeySpecSecp256k1,
+		keypair: keypair,
+	}
+	if keypair != nil {
+		key.publicKey = testSPKI(keypair)
+	} else {
+		key.keySpec = "SYMMETRIC_DEFAULT"
+	}
+	for k, v := range tags {
+		key.tags = append(key.tags, &tag{TagKey: k, TagValue: v})
+	}
+	m.keys[keyID] = key
+	m.keyOrder = append(m.keyOrder, keyID)
+	for _, alias := range aliases {
+		m.aliases = append(m.aliases, &aliasListEntry{AliasName: alias, TargetKeyID: keyID})
+	}
+}
+
+func (m *mockKMS) lookupKey(keyID string) *mockKey {
+	if key, ok := m.keys[keyID]; ok {
+		return key
+	}
+	for _, key := range m.keys {
+		if key.arn == keyID {
+			return key
+		}
+	}
+	for _, alias := range m.aliases {
+		if alias.AliasName == keyID {
+			return m.keys[alias.TargetKeyID]
+		}
+	}
+	return nil
+}
+
+// page returns the item at the marker, with the marker of the next item
+func page(marker string, count int) (int, listResponse) {
+	i, _ := strconv.Atoi(marker)
+	if i+1 < count {
+		return i, listResponse{Truncated: true, NextMarker: strconv.Itoa(i + 1)}
+	}
+	return i, listResponse{}
+}
+
+func (m *mockKMS) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mux.Lock()
+	delay := m.delay
+	m.mux.Unlock()
+	time.Sleep(delay)
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	assert.Equal(m.t, http.MethodPost, r.Method)
+	assert.Equal(m.t, amzJSONContent, r.Header.Get("Content-Type"))
+	assert.Regexp(m.t, "^AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/[0-9]{8}/us-east-1/kms/aws4_request, SignedHeaders=content-type;host;x-amz-date;(x-amz-security-token;)?x-amz-target, Signature=[0-9a-f]{64}$", r.Header.Get("Authorization"))
+	operation := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), kmsTargetPrefix)
+	m.calls[operation]++
+
+	writeJSON := func(status int, body interface{}) {
+		w.Header().Set("Content-Type", amzJSONContent)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(body)
+	}
+	if status, ok := m.failOps[operation]; ok {
+		writeJSON(status, map[string]string{"__type": "KMSInternalException", "message": "pop"})
+		return
+	}
+	var req struct {
+		KeyID            string `json:"KeyId"`
+		Marker           string `json:"Marker"`
+		Message          []byte `json:"Message"`
+		MessageType      string `json:"MessageType"`
+		SigningAlgorithm string `json:"SigningAlgorithm"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	assert.NoError(m.t, err)
+	keyID := req.KeyID
+
+	switch operation {
+	case "GetPublicKey":
+		key := m.lookupKey(keyID)
+		if key == nil {
+			writeJSON(400, map[string]string{"__type": "NotFoundException", "Message": "key not found"})
+			return
+		}
+		writeJSON(200, &getPublicKeyResponse{KeyID: key.arn, KeySpec: key.keySpec, KeyUsage: "SIGN_VERIFY", PublicKey: key.publicKey})
+	case "Sign":
+		key := m.lookupKey(keyID)
+		assert.Equal(m.t, messageTypeDigest, req.MessageType)
+		assert.Equal(m.t, SigningAlgorithmECDSASHA256, req.SigningAlgorithm)
+		assert.Len(m.t, req.Message, 32)
+		sig := ecdsa.Sign(key.keypair.PrivateKey, req.Message)
+		der := sig.Serialize()
+		if m.highS {
+			// Re-encode with the high S value, that a KMS can return
+			var parsed struct{ R, S *big.Int }
+			_, _ = asn1.Unmarshal(der, &parsed)
+			parsed.S = new(big.Int).Sub(btcec.S256().N, parsed.S)
+			der, _ = asn1.Marshal(parsed)
+		}
+		if m.badSig {
+			der = []byte{0x30, 0x00}
+		}
+		writeJSON(200, &signResponse{KeyID: key.arn, Signature: der})
+	case "ListKeys":
+		i, res := page(req.Marker, len(m.keyOrder))
+		keyID := m.keyOrder[i]
+		writeJSON(200, &listKeysResponse{listResponse: res, Keys: []*keyListEntry{{KeyID: keyID, KeyArn: m.keys[keyID].arn}}})
+	case "ListAliases":
+		res := &listAliasesResponse{}
+		if len(m.aliases) > 0 {
+			var i int
+			i, res.listResponse = page(req.Marker, len(m.aliases))
+			res.Aliases = m.aliases[i : i+1]
+		}
+		writeJSON(200, res)
+	case "ListResourceTags":
+		tags := m.keys[keyID].tags
+		res := &listResourceTagsResponse{}
+		if len(tags) > 0 {
+			var i int
+			i, res.listResponse = page(req.Marker, len(tags))
+			res.Tags = tags[i : i+1]
+		}
+		writeJSON(200, res)
+	default:
+		writeJSON(400, map[string]string{"__type": "UnknownOperationException"})
+	}
+}
+
+func writeTestFile(t *testing.T, name, content string) string {
+	filename := path.Join(t.TempDir(), name)
+	err := os.WriteFile(filename, []byte(content), 0600)
+	assert.NoError(t, err)
+	return filename
+}
+
+func testConfig(t *testing.T, keys KeysConfig) *Config {
+	return &Config{
+		Region: "us-east-1",
+		Credentials: CredentialsConfig{
+			AccessKeyID:         "AKIDEXAMPLE",
+			SecretAccessKeyFile: writeTestFile(t, "secret", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY\n"),
+		},
+		Keys: keys,
+	}
+}
+
+func newTestKMSWallet(t *testing.T, server *httptest.Server, conf *Config, listeners ...chan<- ethtypes.Address0xHex) (context.Context, *kmsWallet) {
+	ctx := context.Background()
+	w, err := newKMSWallet(ctx, conf, resty.New().SetBaseURL(server.URL), listeners...)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = w.Close() })
+	return ctx, w
+}
+
+func TestConfig(t *testing.T) {
+	config.RootConfigReset()
+	section := config.RootSection("kmsWallet")
+	InitConfig(section)
+	section.Set(ffresty.HTTPConfigURL, "http://localhost:4566")
+	section.Set(ffresty.HTTPConfigRequestTimeout, "10s")
+	section.Set(ConfigRegion, "eu-west-1")
+	section.Set(ConfigCredentialsAccessKeyID, "AKIDEXAMPLE")
+	section.Set(ConfigCredentialsSecretAccessKeyFile, "/secrets/secret")
+	section.Set(ConfigCredentialsSessionTokenFile, "/secrets/token")
+	section.Set(ConfigKeysIDs, []string{"key1"})
+	section.Set(ConfigKeysAliases, []string{"alias/signer"})
+	section.Set(ConfigKeysAliasPrefix, "signer-")
+	section.Set(ConfigKeysTagKey, "purpose")
+	section.Set(ConfigKeysTagValue, "signing")
+	assert.Equal(t, &Config{
+		Region:         "eu-west-1",
+		RequestTimeout: 10 * time.Second,
+		Credentials: CredentialsConfig{
+			AccessKeyID:         "AKIDEXAMPLE",
+			SecretAccessKeyFile: "/secrets/secret",
+			SessionTokenFile:    "/secrets/token",
+		},
+		Keys: KeysConfig{
+			IDs:         []string{"key1"},
+			Aliases:     []string{"alias/signer"},
+			AliasPrefix: "signer-",
+			Tag:         TagConfig{Key: "purpose", Value: "signing"},
+		},
+	}, ReadConfig(section))
+
+	client, err := ffresty.New(context.Background(), section)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:4566", client.BaseURL)
+}
+
+func TestInitializeByIDAliasAndTag(t *testing.T) {
+	m, server := newMockKMS(t)
+	m.addKey("key0", testKey0, []string{"alias/signer-0"}, nil)
+	m.addKey("key1", testKey1, []string{"alias/signer-1", "alias/other"}, map[string]string{"team": "a"})
+	m.addKey("key2", testKey2, nil, map[string]string{"env": "dev", "purpose": "signing"})
+	// Keys selected by prefix or tag that are not secp256k1 are ignored
+	m.addKey("key3", nil, []string{"alias/signer-3"}, map[string]string{"purpose": "signing"})
+	m.aliases = append(m.aliases, &aliasListEntry{AliasName: "alias/signer-unused"})
+
+	listener := make(chan ethtypes.Address0xHex, 3)
+	ctx, w := newTestKMSWallet(t, server, testConfig(t, KeysConfig{
+		IDs:         []string{"key0"},
+		Aliases:     []string{"other"},
+		AliasPrefix: "signer-",
+		Tag:         TagConfig{Key: "purpose", Value: "signing"},
+	}), listener)
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	accounts, err := w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{&testKey0.Address, &testKey1.Address, &testKey2.Address}, accounts)
+	assert.Equal(t, testKey0.Address, <-listener)
+	assert.Equal(t, testKey1.Address, <-listener)
+	assert.Equal(t, testKey2.Address, <-listener)
+
+	metadata, err := w.GetAccountMetadata(ctx, testKey1.Address)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{MetadataKeyID: "arn:aws:kms:us-east-1:111122223333:key/key1"}, metadata)
+
+	// Refresh uses the cached public keys, and finds new keys
+	assert.Equal(t, 4, m.calls["GetPublicKey"])
+	m.addKey("key4", testKey1, []string{"alias/signer-4"}, nil)
+	err = w.Refresh(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, m.calls["GetPublicKey"])
+	accounts, err = w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 3)
+}
+
+func TestInitializeTagAnyValue(t *testing.T) {
+	m, server := newMockKMS(t)
+	m.addKey("key0", testKey0, nil, map[string]string{"ethereum": "true"})
+	m.addKey("key1", testKey1, nil, nil)
+	m.addKey("key2", testKey2, nil, map[string]string{"ethereum": "yes"})
+
+	ctx, w := newTestKMSWallet(t, server, testConfig(t, KeysConfig{Tag: TagConfig{Key: "ethereum"}}))
+	listener := make(chan ethtypes.Address0xHex, 2)
+	w.AddListener(listener)
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+	accounts, err := w.GetAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*ethtypes.Address0xHex{&testKey0.Address, &testKey2.Address}, accounts)
+	assert.Equal(t, testKey0.Address, <-listener)
+	assert.Equal(t, testKey2.Address, <-listener)
+}
+
+func TestInitializeConfigErrors(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewKMSWallet(ctx, &Config{Keys: KeysConfig{IDs: []string{"key0"}}}, resty.New())
+	assert.Regexp(t, "FF22191.*region", err)
+
+	_, err = NewKMSWallet(ctx, &Config{Region: "us-east-1"}, resty.New())
+	assert.Regexp(t, "FF22190", err)
+
+	_, err = NewKMSWallet(ctx, &Config{Region: "us-east-1", Keys: KeysConfig{IDs: []string{"key0"}}}, resty.New().SetBaseURL("://bad"))
+	assert.Regexp(t, "FF22191.*url", err)
+}
+
+func TestDefaultURL(t *testing.T) {
+	client := resty.New()
+	w, err := newKMSWallet(context.Background(), &Config{Region: "eu-west-2", Keys: KeysConfig{IDs: []string{"key0"}}}, client)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://kms.eu-west-2.amazonaws.com", client.BaseURL)
+	assert.Equal(t, "kms.eu-west-2.amazonaws.com", w.client.host)
+}
+
+func TestInitializeCredentials(t *testing.T) {
+	m, server := newMockKMS(t)
+	m.addKey("key0", testKey0, nil, nil)
+
+	// Environment variables
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "token")
+	ctx, w := newTestKMSWallet(t, server, &Config{Region: "us-east-1", Keys: KeysConfig{IDs: []string{"key0"}}})
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &credentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "secret", sessionToken: "token"}, w.client.creds)
+
+	// Files
+	conf := testConfig(t, KeysConfig{IDs: []string{"key0"}})
+	conf.Credentials.SessionTokenFile = writeTestFile(t, "token", "file-token\n")
+	ctx, w = newTestKMSWallet(t, server, conf)
+	err = w.Initialize(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "file-token", w.client.creds.sessionToken)
+
+	conf.Credentials.SessionTokenFile = path.Join(t.TempDir(), "missing")
+	ctx, w = newTestKMSWallet(t, server, conf)
+	err = w.Initialize(ctx)
+	assert.Regexp(t, "FF22192", err)
+
+	conf.Credentials.SecretAccessKeyFile = path.Join(t.TempDir(), "missing")
+	ctx, w = newTestKMSWallet(t, server, conf)
+	err = w.Initialize(ctx)
+	assert.Regexp(t, "FF22192", err)
+
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	ctx, w = newTestKMSWallet(t, server, &Config{Region: "us-east-1", Keys: KeysConfig{IDs: []string{"key0"}}})
+	err = w.Initialize(ctx)
+	assert.Regexp(t, "FF22191.*credentials", err)
+
+	// Requests fail until the credentials are loaded
+	err = w.Refresh(ctx)
+	assert.Regexp(t, "FF22191.*credentials", err)
+}
+
+func TestInitializeKeyErrors(t *testing.T) {
+	m, server := newMockKMS(t)
+	m.addKey("key0", testKey0, nil, nil)
+	m.addKey("symmetric", nil, nil, nil)
+	m.addKey("badkey", testKey1, nil, nil)
+	m.keys["badkey"].publicKey = []byte{0xff}
+	m.addKey("p256", testKey1, nil, nil)
+	var spki subjectPublicKeyInfo
+	_, _ = asn1.Unmarshal(m.keys["p256"].publicKey, &spki)
+	spki.Algorithm.Parameters = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	m.keys["p256"].publicKey, _ = asn1.Marshal(spki)
+	m.addKey("badpoint", testKey1, nil, nil)
+	spki.Algorithm.Parameters = oidSecp256k1
+	spki.PublicKey = asn1.BitString{Bytes: []byte{0x04, 0x01}, BitLength: 16}
+	m.keys["badpoint"].publicKey, _ = asn1.Marshal(spki)
+
+	for keyID, errRegexp := range map[string]string{
+		"missing":   "FF22187.*GetPublicKey.*400.*NotFoundException key not found",
+		"symmetric": "FF22188.*symmetric.*SYMMETRIC_DEFAULT",
+		"badkey":    "FF22188.*badkey",
+		"p256":      "FF22188.*p256.*1.2.840.10045.3.1.7",
+		"badpoint":  "FF22188.*badpoint",
+	} {
+		ctx, w := newTestKMSWallet(t, server, testConfig(t, KeysConfig{IDs: []string{keyID}}))
+		err := w.Initialize(ctx)
+		assert.Regexp(t, errRegexp, err)
+	}
+
+	ctx, w := newTestKMSWallet(t, server, testConfig(t, KeysConfig{Aliases: []string{"alias/missing"}}))
+	err := w.Initialize(ctx)
+	assert.Regexp(t, "FF22189.*alias/missing", err)
+}
+
+func TestInitializeListErrors(t *testing.T) {
+	m, server := newMockKMS(t)
+	m.addKey("key0", testKey0, []string{"alias/signer-0"}, map[string]string{"purpose": "signing"})
+
+	for op, keys := range map[string]KeysConfig{
+		"ListAliases":      {AliasPrefix: "signer-"},
+		"ListKeys":         {Tag: TagConfig{Key: "purpose"}},
+		"ListResourceTags": {Tag: TagConfig{Key: "purpose"}},
+	} {
+		m.failOps = map[string]int{op: 500}
+		ctx, w := newTestKMSWallet(t, server, testConfig(t, keys))
+		err := w.Initialize(ctx)
+		assert.Regexp(t, fmt.Sprintf("FF22187.*%s.*500.*KMSInternalException pop", op), err)
+	}
+}
+
+func TestRequestFailed(t *testing.T) {
+	_, server := newMockKMS(t)
+	ctx, w := newTestKMSWallet(t, server, testConfig(t, KeysConfig{IDs: []string{"key0"}}))
+	server.Close()
+	err := w.Initialize(ctx)
+	assert.Regexp(t, "FF22187.*GetPublicKey", err)
+}
+
+func TestSignOK(t *testing.T) {
+	for _, highS := range []bool{false, true} {
+		m, server := newMockKMS(t)
+		m.addKey("key0", testKey0, nil, nil)
+		m.highS = highS
+		ctx, w := newTestKMSWallet(t, server, testConfig(t, KeysConfig{IDs: []string{"key0"}}))
+		err := w.Initialize(ctx)
+		assert.NoError(t, err)
+
+		b, err := w.Sign(ctx, &ethsigner.Transaction{
+			From:     json.RawMessage(`"0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"`),
+			Nonce:    ethtypes.NewHexInteger64(0),
+			GasLimit: ethtypes.NewHexInteger64(21000),
+		}, 31337)
+		assert.NoError(t, err)
+
+		from, _, err := ethsigner.RecoverRawTransaction(ctx, b, 31337)
+		assert.NoError(t, err)
+		assert.Equal(t, testKey0.Address, *from)
+
+		// RFC6979 signatures are deterministic, so match the local key exactly after normalization
+		signer, err := w.Signer(ctx, testKey0.Address)
+		assert.NoError(t, err)
+		expected, err := testKey0.Sign([]byte("Hello World"))
+		assert.NoError(t, err)
+		sig, err := signer.Sign([]byte("Hello World"))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, sig)
+	}
+}
+
+func TestSignTypedDataOK(t *testing.T) {
+	m, server := newMockKMS(t)
+	m.addKey("key0", testKey0, nil, nil)
+	ctx, w := newTestKMSWallet(t, server, testConfig(t, KeysConfig{IDs: []string{"key0"}}))
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	res, err := w.SignTypedDataV4(ctx, testKey0.Address, &eip712.TypedData{
+		PrimaryType: eip712.EIP712Domain,
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, res)
+}
+
+func TestSignEIP191OK(t *testing.T) {
+	m, server := newMockKMS(t)
+	m.addKey("key0", testKey0, nil, nil)
+	ctx, w := newTestKMSWallet(t, server, testConfig(t, KeysConfig{IDs: []string{"key0"}}))
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	res, err := w.SignEIP191PersonalMessage(ctx, testKey0.Address, []byte("Hello World"))
+	assert.NoError(t, err)
+	ok, err := ethsigner.VerifyEIP191PersonalMessage(ctx, []byte("Hello World"), res.SignatureRSV, testKey0.Address)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	res, err = w.SignEIP191IntendedValidator(ctx, testKey0.Address, testKey1.Address, []byte("some data"))
+	assert.NoError(t, err)
+	ok, err = ethsigner.VerifyEIP191IntendedValidator(ctx, testKey1.Address, []byte("some data"), res.SignatureRSV, testKey0.Address)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestSignNotFound(t *testing.T) {
+	m, server := newMockKMS(t)
+	m.addKey("key0", testKey0, nil, nil)
+	ctx, w := newTestKMSWallet(t, server, testConfig(t, KeysConfig{IDs: []string{"key0"}}))
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	_, err = w.Sign(ctx, &ethsigner.Transaction{From: json.RawMessage(`"0x70997970C51812dc3A010C7d01b50e0d17dc79C8"`)}, 31337)
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.Sign(ctx, &ethsigner.Transaction{From: json.RawMessage(`"bad address"`)}, 31337)
+	assert.Regexp(t, "bad address", err)
+
+	_, err = w.SignTypedDataV4(ctx, testKey1.Address, &eip712.TypedData{})
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.SignEIP191PersonalMessage(ctx, testKey1.Address, []byte("Hello World"))
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.SignEIP191IntendedValidator(ctx, testKey1.Address, testKey0.Address, []byte("Hello World"))
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.GetAccountMetadata(ctx, testKey1.Address)
+	assert.Regexp(t, "FF22014", err)
+
+	_, err = w.Signer(ctx, testKey1.Address)
+	assert.Regexp(t, "FF22014", err)
+}
+
+func TestSignErrors(t *testing.T) {
+	m, server := newMockKMS(t)
+	m.addKey("key0", testKey0, nil, nil)
+	ctx, w := newTestKMSWallet(t, server, testConfig(t, KeysConfig{IDs: []string{"key0"}}))
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	m.badSig = true
+	_, err = w.SignEIP191PersonalMessage(ctx, testKey0.Address, []byte("Hello World"))
+	assert.Regexp(t, "FF22177", err)
+
+	m.failOps["Sign"] = 400
+	_, err = w.SignEIP191PersonalMessage(ctx, testKey0.Address, []byte("Hello World"))
+	assert.Regexp(t, "FF22187.*Sign", err)
+}
+
+func TestSignWrongKey(t *testing.T) {
+	// The public key does not match the signing key, so the signature does not recover to the address
+	m, server := newMockKMS(t)
+	m.addKey("key0", testKey0, nil, nil)
+	m.keys["key0"].keypair = testKey1
+	ctx, w := newTestKMSWallet(t, server, testConfig(t, KeysConfig{IDs: []string{"key0"}}))
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	_, err = w.SignEIP191PersonalMessage(ctx, testKey0.Address, []byte("Hello World"))
+	assert.Regexp(t, "FF22178", err)
+}
+
+func TestSignContextCanceled(t *testing.T) {
+	m, server := newMockKMS(t)
+	m.addKey("key0", testKey0, nil, nil)
+	ctx, w := newTestKMSWallet(t, server, testConfig(t, KeysConfig{IDs: []string{"key0"}}))
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = w.SignEIP191PersonalMessage(canceledCtx, testKey0.Address, []byte("Hello World"))
+	assert.Regexp(t, "FF22187.*Sign.*context canceled", err)
+	assert.Zero(t, m.calls["Sign"])
+}
+
+func TestSignTimeout(t *testing.T) {
+	m, server := newMockKMS(t)
+	m.addKey("key0", testKey0, nil, nil)
+	conf := testConfig(t, KeysConfig{IDs: []string{"key0"}})
+	conf.RequestTimeout = 50 * time.Millisecond
+	ctx, w := newTestKMSWallet(t, server, conf)
+	err := w.Initialize(ctx)
+	assert.NoError(t, err)
+
+	m.mux.Lock()
+	m.delay = 250 * time.Millisecond
+	m.mux.Unlock()
+	_, err = w.SignEIP191PersonalMessage(ctx, testKey0.Address, []byte("Hello World"))
+	assert.Regexp(t, "FF22187.*Sign.*context deadline exceeded", err)
+}
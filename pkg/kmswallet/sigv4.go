@@ -0,0 +1,118 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmswallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4Service    = "kms"
+	amzDateFormat   = "20060102T150405Z"
+	amzJSONContent  = "application/x-amz-json-1.1"
+	kmsTargetPrefix = "TrentService."
+)
+
+type credentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// signedHeaders returns the headers of a KMS JSON request, including the AWS Signature Version 4
+// Authorization header over the host, path, target operation and body of the request
+func signedHeaders(creds *credentials, region, host, path, operation string, body []byte, now time.Time) map[string]string {
+	headers := map[string]string{
+		"content-type": amzJSONContent,
+		"host":         host,
+		"x-amz-date":   now.UTC().Format(amzDateFormat),
+		"x-amz-target": kmsTargetPrefix + operation,
+	}
+	if creds.sessionToken != "" {
+		headers["x-amz-security-token"] = creds.sessionToken
+	}
+	authorization := sigV4Authorization(creds, "POST", path, region, sigV4Service, headers, body)
+
+	// The host header is set by the HTTP client from the URL
+	delete(headers, "host")
+	headers["authorization"] = authorization
+	return headers
+}
+
+// sigV4Authorization returns the AWS Signature Version 4 Authorization header of a request without
+// a query string, signing all the headers. The header names must be lower case, and include the
+// host and the x-amz-date of the request.
+func sigV4Authorization(creds *credentials, method, path, region, service string, headers map[string]string, body []byte) string {
+	amzDate := headers["x-amz-date"]
+	date := amzDate[0:8]
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaderNames := strings.Join(names, ";")
+	if path == "" {
+		path = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		path,
+		"", // no query string
+		canonicalHeaders.String(),
+		signedHeaderNames,
+		sha256Hex(body),
+	}, "\n")
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, region, service)
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, creds.accessKeyID, scope, signedHeaderNames, signature)
+}
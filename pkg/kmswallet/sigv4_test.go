@@ -0,0 +1,87 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmswallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The requests and signatures of the AWS Signature Version 4 test suite, which all sign with the
+// example credentials for the service "service" in us-east-1
+func TestSigV4AuthorizationTestSuite(t *testing.T) {
+	creds := &credentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	tests := []struct {
+		name      string
+		method    string
+		headers   map[string]string
+		body      string
+		signature string
+	}{
+		{
+			name:      "get-vanilla",
+			method:    "GET",
+			headers:   map[string]string{"host": "example.amazonaws.com", "x-amz-date": "20150830T123600Z"},
+			signature: "SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:      "post-vanilla",
+			method:    "POST",
+			headers:   map[string]string{"host": "example.amazonaws.com", "x-amz-date": "20150830T123600Z"},
+			signature: "SignedHeaders=host;x-amz-date, Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+		{
+			name:   "post-x-www-form-urlencoded",
+			method: "POST",
+			headers: map[string]string{
+				"content-type": "application/x-www-form-urlencoded",
+				"host":         "example.amazonaws.com",
+				"x-amz-date":   "20150830T123600Z",
+			},
+			body:      "Param1=value1",
+			signature: "SignedHeaders=content-type;host;x-amz-date, Signature=ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			authorization := sigV4Authorization(creds, test.method, "/", "us-east-1", "service", test.headers, []byte(test.body))
+			assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+test.signature, authorization)
+		})
+	}
+}
+
+// The signing is checked against the test suite above, so this checks the headers of a KMS request
+func TestSignedHeaders(t *testing.T) {
+	creds := &credentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	headers := signedHeaders(creds, "us-east-1", "kms.us-east-1.amazonaws.com", "", "GetPublicKey", []byte(`{"KeyId":"alias/test"}`), now)
+	assert.Equal(t, map[string]string{
+		"content-type": "application/x-amz-json-1.1",
+		"x-amz-date":   "20150830T123600Z",
+		"x-amz-target": "TrentService.GetPublicKey",
+		"authorization": "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/kms/aws4_request, " +
+			"SignedHeaders=content-type;host;x-amz-date;x-amz-target, " +
+			"Signature=d0f27571c05eaddcd2c77c1c45c0093c4a3690dbd5fc42a5a809394c5eabe5ff",
+	}, headers)
+
+	creds.sessionToken = "token"
+	headers = signedHeaders(creds, "us-east-1", "kms.us-east-1.amazonaws.com", "/", "GetPublicKey", []byte(`{"KeyId":"alias/test"}`), now)
+	assert.Equal(t, "token", headers["x-amz-security-token"])
+	assert.Regexp(t, "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target,", headers["authorization"])
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"sync/atomic"
 	"time"
 
@@ -50,6 +51,13 @@ type Backend interface {
 	SyncRequest(ctx context.Context, rpcReq *RPCRequest) (rpcRes *RPCResponse, err error)
 }
 
+// RPCClientMetrics is notified of the outcome of each request sent to the backend, with the
+// JSON/RPC error code of the response (0 on success), or unavailable if the backend could not
+// be reached
+type RPCClientMetrics interface {
+	RequestCompleted(ctx context.Context, method string, duration time.Duration, errorCode RPCCode, unavailable bool)
+}
+
 // NewRPCClient Constructor
 func NewRPCClient(client *resty.Client) Backend {
 	return NewRPCClientWithOption(client, RPCClientOptions{})
@@ -58,7 +66,8 @@ func NewRPCClient(client *resty.Client) Backend {
 // NewRPCClientWithOption Constructor
 func NewRPCClientWithOption(client *resty.Client, options RPCClientOptions) Backend {
 	rpcClient := &RPCClient{
-		client: client,
+		client:  client,
+		metrics: options.Metrics,
 	}
 
 	if options.MaxConcurrentRequest > 0 {
@@ -72,10 +81,12 @@ type RPCClient struct {
 	client           *resty.Client
 	concurrencySlots chan bool
 	requestCounter   int64
+	metrics          RPCClientMetrics
 }
 
 type RPCClientOptions struct {
 	MaxConcurrentRequest int64
+	Metrics              RPCClientMetrics
 }
 
 type RPCRequest struct {
@@ -142,19 +153,27 @@ func (rc *RPCClient) CallRPC(ctx context.Context, result interface{}, method str
 	return nil
 }
 
-// SyncRequest sends an individual RPC request to the backend (always over HTTP currently),
+// SyncRequest sends an individual RPC request to the backend over HTTP,
 // and waits synchronously for the response, or an error.
 //
 // In all return paths *including error paths* the RPCResponse is populated
 // so the caller has an RPC structure to send back to the front-end caller.
 func (rc *RPCClient) SyncRequest(ctx context.Context, rpcReq *RPCRequest) (rpcRes *RPCResponse, err error) {
+	rpcRes, _, err = rc.syncRequest(ctx, rpcReq)
+	return rpcRes, err
+}
+
+// syncRequest additionally returns whether the failure was in reaching the backend (a connection
+// error, or a 5xx status), as opposed to an error response from the node, so the request could
+// be retried on another backend
+func (rc *RPCClient) syncRequest(ctx context.Context, rpcReq *RPCRequest) (rpcRes *RPCResponse, unavailable bool, err error) {
 	if rc.concurrencySlots != nil {
 		select {
 		case rc.concurrencySlots <- true:
 			// wait for the concurrency slot and continue
 		case <-ctx.Done():
 			err := i18n.NewError(ctx, signermsgs.MsgRequestCanceledContext, rpcReq.ID)
-			return RPCErrorResponse(err, rpcReq.ID, RPCCodeInternalError), err
+			return RPCErrorResponse(err, rpcReq.ID, RPCCodeInternalError), false, err
 		}
 		defer func() {
 			<-rc.concurrencySlots
@@ -179,6 +198,18 @@ func (rc *RPCClient) SyncRequest(ctx context.Context, rpcReq *RPCRequest) (rpcRe
 		log.L(ctx).Tracef("RPC[%s] INPUT: %s", rpcTraceID, jsonInput)
 	}
 	rpcStartTime := time.Now()
+	if rc.metrics != nil {
+		defer func() {
+			var code RPCCode
+			if rpcRes.Error != nil {
+				code = RPCCode(rpcRes.Error.Code)
+			}
+			if err != nil && code == 0 {
+				code = RPCCodeInternalError
+			}
+			rc.metrics.RequestCompleted(ctx, rpcReq.Method, time.Since(rpcStartTime), code, unavailable)
+		}()
+	}
 	res, err := rc.client.R().
 		SetContext(ctx).
 		SetBody(beReq).
@@ -192,7 +223,7 @@ func (rc *RPCClient) SyncRequest(ctx context.Context, rpcReq *RPCRequest) (rpcRe
 		err := i18n.NewError(ctx, signermsgs.MsgRPCRequestFailed, err)
 		log.L(ctx).Errorf("RPC[%s] <-- ERROR: %s", rpcTraceID, err)
 		rpcRes = RPCErrorResponse(err, rpcReq.ID, RPCCodeInternalError)
-		return rpcRes, err
+		return rpcRes, true, err
 	}
 	if logrus.IsLevelEnabled(logrus.TraceLevel) {
 		jsonOutput, _ := json.Marshal(rpcRes)
@@ -210,14 +241,14 @@ func (rc *RPCClient) SyncRequest(ctx context.Context, rpcReq *RPCRequest) (rpcRe
 		}
 		log.L(ctx).Errorf("RPC[%s] <-- [%d]: %s", rpcTraceID, res.StatusCode(), errLog)
 		err := errors.New(rpcMsg)
-		return rpcRes, err
+		return rpcRes, res.StatusCode() >= http.StatusInternalServerError, err
 	}
 	log.L(ctx).Infof("RPC[%s] <-- %s [%d] OK (%.2fms)", rpcTraceID, rpcReq.Method, res.StatusCode(), float64(time.Since(rpcStartTime))/float64(time.Millisecond))
 	if rpcRes.Result == nil {
 		// We don't want a result for errors, but a null success response needs to go in there
 		rpcRes.Result = fftypes.JSONAnyPtr(fftypes.NullString)
 	}
-	return rpcRes, nil
+	return rpcRes, false, nil
 }
 
 func RPCErrorResponse(err error, id *fftypes.JSONAny, code RPCCode) *RPCResponse {
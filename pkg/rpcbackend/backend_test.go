@@ -24,7 +24,9 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/go-resty/resty/v2"
 	"github.com/hyperledger/firefly-common/pkg/ffresty"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-signer/internal/signerconfig"
@@ -310,3 +312,87 @@ func TestSyncRequestConcurrency(t *testing.T) {
 	<-bgDone
 
 }
+
+type testMetricsCall struct {
+	method      string
+	errorCode   RPCCode
+	unavailable bool
+}
+
+type testMetrics struct {
+	calls []*testMetricsCall
+}
+
+func (tm *testMetrics) RequestCompleted(ctx context.Context, method string, duration time.Duration, errorCode RPCCode, unavailable bool) {
+	tm.calls = append(tm.calls, &testMetricsCall{method: method, errorCode: errorCode, unavailable: unavailable})
+}
+
+func TestSyncRequestMetricsOK(t *testing.T) {
+
+	ctx, rb, done := newTestServer(t, func(rpcReq *RPCRequest) (status int, rpcRes *RPCResponse) {
+		return 200, &RPCResponse{
+			JSONRpc: "2.0",
+			ID:      rpcReq.ID,
+			Result:  fftypes.JSONAnyPtr(`"0x1"`),
+		}
+	})
+	defer done()
+	tm := &testMetrics{}
+	rb.metrics = tm
+
+	var txCount ethtypes.HexInteger
+	err := rb.CallRPC(ctx, &txCount, "eth_getTransactionCount", ethtypes.MustNewAddress("0xfb075bb99f2aa4c49955bf703509a227d7a12248"), "pending")
+	assert.Nil(t, err)
+	assert.Equal(t, []*testMetricsCall{{method: "eth_getTransactionCount"}}, tm.calls)
+}
+
+func TestSyncRequestMetricsErrorCode(t *testing.T) {
+
+	ctx, rb, done := newTestServer(t, func(rpcReq *RPCRequest) (status int, rpcRes *RPCResponse) {
+		return 200, &RPCResponse{
+			JSONRpc: "2.0",
+			ID:      rpcReq.ID,
+			Error: &RPCError{
+				Code:    -32000,
+				Message: "nonce too low",
+			},
+		}
+	})
+	defer done()
+	tm := &testMetrics{}
+	rb.metrics = tm
+
+	_, err := rb.SyncRequest(ctx, &RPCRequest{Method: "eth_sendRawTransaction"})
+	assert.Regexp(t, "nonce too low", err)
+	assert.Equal(t, []*testMetricsCall{{method: "eth_sendRawTransaction", errorCode: -32000}}, tm.calls)
+}
+
+func TestSyncRequestMetricsErrorStatusNoCode(t *testing.T) {
+
+	ctx, rb, done := newTestServer(t, func(rpcReq *RPCRequest) (status int, rpcRes *RPCResponse) { return 502, nil })
+	defer done()
+	tm := &testMetrics{}
+	rb.metrics = tm
+
+	_, err := rb.SyncRequest(ctx, &RPCRequest{Method: "eth_chainId"})
+	assert.Regexp(t, "FF22012", err)
+	assert.Equal(t, []*testMetricsCall{{method: "eth_chainId", errorCode: RPCCodeInternalError, unavailable: true}}, tm.calls)
+}
+
+func TestSyncRequestMetricsServerDown(t *testing.T) {
+
+	_, rb, done := newTestServer(t, func(rpcReq *RPCRequest) (status int, rpcRes *RPCResponse) { return 500, nil })
+	done()
+	tm := &testMetrics{}
+	rb.metrics = tm
+
+	_, err := rb.SyncRequest(context.Background(), &RPCRequest{Method: "net_version"})
+	assert.Regexp(t, "FF22012", err)
+	assert.Equal(t, []*testMetricsCall{{method: "net_version", errorCode: RPCCodeInternalError, unavailable: true}}, tm.calls)
+}
+
+func TestNewRPCClientWithMetrics(t *testing.T) {
+	tm := &testMetrics{}
+	rb := NewRPCClientWithOption(resty.New(), RPCClientOptions{Metrics: tm}).(*RPCClient)
+	assert.Equal(t, tm, rb.metrics)
+}
@@ -0,0 +1,375 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcbackend
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/rlp"
+)
+
+// EndpointStrategy determines the order in which healthy endpoints are used
+type EndpointStrategy string
+
+const (
+	// EndpointStrategyPriority sends every request to the first healthy endpoint in the list
+	EndpointStrategyPriority EndpointStrategy = "priority"
+	// EndpointStrategyRoundRobin spreads requests across all healthy endpoints
+	EndpointStrategyRoundRobin EndpointStrategy = "roundRobin"
+)
+
+// MultiEndpointBackend is a Backend that sends requests to one of a list of endpoints
+//
+// - Health checks each endpoint with eth_blockNumber, and marks endpoints that lag behind the others as unhealthy
+// - Sends requests to healthy endpoints, in priority or round-robin order
+// - Fails over to the next endpoint on a connection error, or a 5xx response
+// - Pins eth_sendRawTransaction for each sender to one endpoint, so transactions from a sender arrive in nonce order
+type MultiEndpointBackend interface {
+	Backend
+	EndpointStatus() []*EndpointStatus
+	Close()
+}
+
+type MultiEndpointOptions struct {
+	Strategy            EndpointStrategy
+	HealthCheckInterval time.Duration
+	HealthCheckTimeout  time.Duration
+	MaxBlockLag         uint64 // zero disables lag detection
+	SenderPinTTL        time.Duration
+	RPCClientOptions    RPCClientOptions // applied to each endpoint
+}
+
+// EndpointStatus is the result of the most recent health check, or failed request, for an endpoint
+type EndpointStatus struct {
+	URL         string `ffstruct:"EndpointStatus" json:"url"`
+	Healthy     bool   `ffstruct:"EndpointStatus" json:"healthy"`
+	BlockNumber uint64 `ffstruct:"EndpointStatus" json:"blockNumber"`
+	LastError   string `ffstruct:"EndpointStatus" json:"lastError,omitempty"`
+}
+
+// NewMultiEndpointBackend Constructor - health checking runs in the background until Close is called
+func NewMultiEndpointBackend(ctx context.Context, clients []*resty.Client, options MultiEndpointOptions) (MultiEndpointBackend, error) {
+	if len(clients) == 0 {
+		return nil, i18n.NewError(ctx, signermsgs.MsgNoBackendEndpoints)
+	}
+	switch options.Strategy {
+	case "":
+		options.Strategy = EndpointStrategyPriority
+	case EndpointStrategyPriority, EndpointStrategyRoundRobin:
+	default:
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidEndpointStrategy, options.Strategy)
+	}
+	if options.HealthCheckInterval <= 0 {
+		options.HealthCheckInterval = 10 * time.Second
+	}
+	if options.HealthCheckTimeout <= 0 {
+		options.HealthCheckTimeout = options.HealthCheckInterval
+	}
+	if options.SenderPinTTL <= 0 {
+		options.SenderPinTTL = 10 * time.Minute
+	}
+
+	mb := &multiEndpointBackend{
+		options:   options,
+		endpoints: make([]*endpoint, len(clients)),
+		pins:      make(map[ethtypes.Address0xHex]*senderPin),
+		done:      make(chan struct{}),
+	}
+	for i, c := range clients {
+		mb.endpoints[i] = &endpoint{
+			url:     c.BaseURL,
+			client:  NewRPCClientWithOption(c, options.RPCClientOptions).(*RPCClient),
+			healthy: true, // until the first health check tells us otherwise
+		}
+	}
+	mb.ctx, mb.cancelCtx = context.WithCancel(log.WithLogField(ctx, "role", "rpc_multi_endpoint"))
+	go mb.healthCheckLoop()
+	return mb, nil
+}
+
+type multiEndpointBackend struct {
+	ctx              context.Context
+	cancelCtx        context.CancelFunc
+	options          MultiEndpointOptions
+	endpoints        []*endpoint
+	roundRobinCursor uint64
+	mux              sync.Mutex
+	pins             map[ethtypes.Address0xHex]*senderPin
+	done             chan struct{}
+}
+
+type endpoint struct {
+	url         string
+	client      *RPCClient
+	healthy     bool
+	blockNumber uint64
+	lastError   string
+}
+
+type senderPin struct {
+	endpoint *endpoint
+	lastUsed time.Time
+}
+
+func (mb *multiEndpointBackend) Close() {
+	mb.cancelCtx()
+	<-mb.done
+}
+
+func (mb *multiEndpointBackend) EndpointStatus() []*EndpointStatus {
+	mb.mux.Lock()
+	defer mb.mux.Unlock()
+	status := make([]*EndpointStatus, len(mb.endpoints))
+	for i, ep := range mb.endpoints {
+		status[i] = &EndpointStatus{
+			URL:         ep.url,
+			Healthy:     ep.healthy,
+			BlockNumber: ep.blockNumber,
+			LastError:   ep.lastError,
+		}
+	}
+	return status
+}
+
+func (mb *multiEndpointBackend) CallRPC(ctx context.Context, result interface{}, method string, params ...interface{}) *RPCError {
+	rpcReq, rpcErr := buildRequest(ctx, method, params)
+	if rpcErr != nil {
+		return rpcErr
+	}
+	res, err := mb.SyncRequest(ctx, rpcReq)
+	if err != nil {
+		if res != nil && res.Error != nil && res.Error.Code != 0 {
+			return res.Error
+		}
+		return &RPCError{Code: int64(RPCCodeInternalError), Message: err.Error()}
+	}
+	err = json.Unmarshal(res.Result.Bytes(), &result)
+	if err != nil {
+		err = i18n.NewError(ctx, signermsgs.MsgResultParseFailed, result, err)
+		return &RPCError{Code: int64(RPCCodeParseError), Message: err.Error()}
+	}
+	return nil
+}
+
+// SyncRequest sends the request to each candidate endpoint in turn, until one is reachable.
+// An error response from a node is returned to the caller, rather than retried elsewhere.
+func (mb *multiEndpointBackend) SyncRequest(ctx context.Context, rpcReq *RPCRequest) (rpcRes *RPCResponse, err error) {
+	var sender *ethtypes.Address0xHex
+	if rpcReq.Method == "eth_sendRawTransaction" {
+		sender = rawTransactionSender(ctx, rpcReq)
+	}
+
+	for _, ep := range mb.candidates(sender) {
+		var unavailable bool
+		rpcRes, unavailable, err = ep.client.syncRequest(ctx, rpcReq)
+		if !unavailable {
+			if sender != nil {
+				mb.pinSender(*sender, ep)
+			}
+			return rpcRes, err
+		}
+		if ctx.Err() != nil {
+			// The caller gave up, which tells us nothing about the endpoint
+			break
+		}
+		log.L(ctx).Warnf("Backend endpoint %s unavailable: %s", ep.url, err)
+		mb.markUnavailable(ep, err)
+	}
+	return rpcRes, err
+}
+
+// candidates returns the endpoints to try in order - the healthy ones according to the strategy,
+// with any pinned endpoint for the sender first. If none are healthy, we try them all.
+func (mb *multiEndpointBackend) candidates(sender *ethtypes.Address0xHex) []*endpoint {
+	mb.mux.Lock()
+	defer mb.mux.Unlock()
+
+	healthy := make([]*endpoint, 0, len(mb.endpoints))
+	for _, ep := range mb.endpoints {
+		if ep.healthy {
+			healthy = append(healthy, ep)
+		}
+	}
+	if len(healthy) == 0 {
+		return append([]*endpoint{}, mb.endpoints...)
+	}
+
+	if mb.options.Strategy == EndpointStrategyRoundRobin {
+		start := int(mb.roundRobinCursor % uint64(len(healthy)))
+		mb.roundRobinCursor++
+		rotated := make([]*endpoint, 0, len(healthy))
+		rotated = append(rotated, healthy[start:]...)
+		healthy = append(rotated, healthy[:start]...)
+	}
+
+	if sender != nil {
+		if pin := mb.pins[*sender]; pin != nil && pin.endpoint.healthy {
+			ordered := []*endpoint{pin.endpoint}
+			for _, ep := range healthy {
+				if ep != pin.endpoint {
+					ordered = append(ordered, ep)
+				}
+			}
+			return ordered
+		}
+	}
+	return healthy
+}
+
+func (mb *multiEndpointBackend) pinSender(sender ethtypes.Address0xHex, ep *endpoint) {
+	mb.mux.Lock()
+	defer mb.mux.Unlock()
+	if pin := mb.pins[sender]; pin == nil || pin.endpoint != ep {
+		log.L(mb.ctx).Debugf("Pinned transactions from %s to %s", sender, ep.url)
+	}
+	mb.pins[sender] = &senderPin{endpoint: ep, lastUsed: time.Now()}
+}
+
+func (mb *multiEndpointBackend) markUnavailable(ep *endpoint, err error) {
+	mb.mux.Lock()
+	defer mb.mux.Unlock()
+	ep.healthy = false
+	ep.lastError = err.Error()
+}
+
+func (mb *multiEndpointBackend) healthCheckLoop() {
+	defer close(mb.done)
+	ticker := time.NewTicker(mb.options.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		mb.healthCheck()
+		select {
+		case <-ticker.C:
+		case <-mb.ctx.Done():
+			log.L(mb.ctx).Debugf("Health check loop ended")
+			return
+		}
+	}
+}
+
+func (mb *multiEndpointBackend) healthCheck() {
+	type checkResult struct {
+		blockNumber uint64
+		err         error
+	}
+	results := make([]checkResult, len(mb.endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range mb.endpoints {
+		wg.Add(1)
+		go func(i int, ep *endpoint) {
+			defer wg.Done()
+			ctx, cancelCtx := context.WithTimeout(mb.ctx, mb.options.HealthCheckTimeout)
+			defer cancelCtx()
+			var blockNumber ethtypes.HexUint64
+			if rpcErr := ep.client.CallRPC(ctx, &blockNumber, "eth_blockNumber"); rpcErr != nil {
+				results[i].err = rpcErr.Error()
+			}
+			results[i].blockNumber = blockNumber.Uint64()
+		}(i, ep)
+	}
+	wg.Wait()
+
+	var highest uint64
+	for _, r := range results {
+		if r.err == nil && r.blockNumber > highest {
+			highest = r.blockNumber
+		}
+	}
+
+	mb.mux.Lock()
+	defer mb.mux.Unlock()
+	for i, ep := range mb.endpoints {
+		r := results[i]
+		wasHealthy := ep.healthy
+		switch {
+		case r.err != nil:
+			ep.healthy = false
+			ep.lastError = r.err.Error()
+		case mb.options.MaxBlockLag > 0 && highest-r.blockNumber > mb.options.MaxBlockLag:
+			ep.healthy = false
+			ep.blockNumber = r.blockNumber
+			ep.lastError = i18n.NewError(mb.ctx, signermsgs.MsgEndpointBlockLag, r.blockNumber, highest).Error()
+		default:
+			ep.healthy = true
+			ep.blockNumber = r.blockNumber
+			ep.lastError = ""
+		}
+		if wasHealthy != ep.healthy {
+			if ep.healthy {
+				log.L(mb.ctx).Infof("Backend endpoint %s is healthy at block %d", ep.url, ep.blockNumber)
+			} else {
+				log.L(mb.ctx).Warnf("Backend endpoint %s is unhealthy: %s", ep.url, ep.lastError)
+			}
+		}
+	}
+
+	for sender, pin := range mb.pins {
+		if time.Since(pin.lastUsed) > mb.options.SenderPinTTL {
+			delete(mb.pins, sender)
+		}
+	}
+}
+
+// rawTransactionSender recovers the sender of the transaction in an eth_sendRawTransaction request,
+// using the chain ID in the transaction itself. Returns nil if the transaction cannot be decoded,
+// as the node can return a better error than we can.
+func rawTransactionSender(ctx context.Context, rpcReq *RPCRequest) *ethtypes.Address0xHex {
+	if len(rpcReq.Params) < 1 {
+		return nil
+	}
+	var rawTx ethtypes.HexBytes0xPrefix
+	if err := json.Unmarshal(rpcReq.Params[0].Bytes(), &rawTx); err != nil || len(rawTx) == 0 {
+		return nil
+	}
+
+	var chainID int64
+	if rawTx[0] >= 0xc0 {
+		// Legacy transaction, with the chain ID in the V value for EIP-155
+		decoded, _, err := rlp.Decode(rawTx)
+		list, ok := decoded.(rlp.List)
+		if err != nil || !ok || len(list) < 9 {
+			return nil
+		}
+		if v := list[6].ToData().IntOrZero().Int64(); v >= 35 {
+			chainID = (v - 35) / 2
+		}
+	} else {
+		// Typed transaction, with the chain ID as the first element
+		decoded, _, err := rlp.Decode(rawTx[1:])
+		list, ok := decoded.(rlp.List)
+		if err != nil || !ok || len(list) == 0 {
+			return nil
+		}
+		chainID = list[0].ToData().IntOrZero().Int64()
+	}
+
+	sender, _, err := ethsigner.RecoverRawTransaction(ctx, rawTx, chainID)
+	if err != nil {
+		log.L(ctx).Debugf("Unable to recover sender of raw transaction: %s", err)
+		return nil
+	}
+	return sender
+}
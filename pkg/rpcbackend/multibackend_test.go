@@ -0,0 +1,379 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcbackend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/pkg/ethsigner"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+	"github.com/stretchr/testify/assert"
+)
+
+type testEndpoint struct {
+	server      *httptest.Server
+	requests    int64
+	blockNumber int64
+	handler     func(rpcReq *RPCRequest) (int, *RPCResponse)
+}
+
+func newTestEndpoint(t *testing.T, blockNumber int64) *testEndpoint {
+	te := &testEndpoint{blockNumber: blockNumber}
+	te.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var rpcReq *RPCRequest
+		err := json.NewDecoder(r.Body).Decode(&rpcReq)
+		assert.NoError(t, err)
+
+		status, rpcRes := http.StatusOK, &RPCResponse{JSONRpc: "2.0", ID: rpcReq.ID}
+		if rpcReq.Method == "eth_blockNumber" {
+			rpcRes.Result = fftypes.JSONAnyPtr(`"` + ethtypes.NewHexInteger64(atomic.LoadInt64(&te.blockNumber)).String() + `"`)
+		} else {
+			atomic.AddInt64(&te.requests, 1)
+			if te.handler != nil {
+				status, rpcRes = te.handler(rpcReq)
+				rpcRes.ID = rpcReq.ID
+			} else {
+				rpcRes.Result = fftypes.JSONAnyPtr(`"` + te.server.URL + `"`)
+			}
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(rpcRes)
+	}))
+	return te
+}
+
+func newTestMultiEndpointBackend(t *testing.T, options MultiEndpointOptions, endpoints ...*testEndpoint) (context.Context, *multiEndpointBackend, func()) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	clients := make([]*resty.Client, len(endpoints))
+	for i, te := range endpoints {
+		clients[i] = resty.New().SetBaseURL(te.server.URL)
+	}
+	if options.HealthCheckInterval == 0 {
+		// Tests drive the health checks themselves, after the initial one
+		options.HealthCheckInterval = 1 * time.Hour
+	}
+	mb, err := NewMultiEndpointBackend(ctx, clients, options)
+	assert.NoError(t, err)
+	// Wait for the initial health check, so it cannot overwrite the state of the endpoints in the test
+	assert.Eventually(t, func() bool {
+		for _, status := range mb.(*multiEndpointBackend).EndpointStatus() {
+			if status.BlockNumber == 0 && status.LastError == "" {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, time.Millisecond)
+	return ctx, mb.(*multiEndpointBackend), func() {
+		mb.Close()
+		cancelCtx()
+		for _, te := range endpoints {
+			te.server.Close()
+		}
+	}
+}
+
+func testSendBlockNumber(t *testing.T, ctx context.Context, mb *multiEndpointBackend) string {
+	var url string
+	rpcErr := mb.CallRPC(ctx, &url, "eth_chainId")
+	assert.Nil(t, rpcErr)
+	return url
+}
+
+func testSignedRawTransaction(t *testing.T, eip1559 bool) (*ethtypes.Address0xHex, ethtypes.HexBytes0xPrefix) {
+	kp, err := secp256k1.GenerateSecp256k1KeyPair()
+	assert.NoError(t, err)
+	txn := &ethsigner.Transaction{
+		Nonce:    ethtypes.NewHexInteger64(0),
+		GasLimit: ethtypes.NewHexInteger64(21000),
+	}
+	if eip1559 {
+		txn.MaxFeePerGas = ethtypes.NewHexInteger64(100)
+		txn.MaxPriorityFeePerGas = ethtypes.NewHexInteger64(1)
+	} else {
+		txn.GasPrice = ethtypes.NewHexInteger64(100)
+	}
+	raw, err := txn.Sign(kp, 1337)
+	assert.NoError(t, err)
+	return &kp.Address, raw
+}
+
+func TestMultiEndpointPriorityFailover(t *testing.T) {
+	te1, te2 := newTestEndpoint(t, 100), newTestEndpoint(t, 100)
+	ctx, mb, done := newTestMultiEndpointBackend(t, MultiEndpointOptions{}, te1, te2)
+	defer done()
+
+	assert.Equal(t, te1.server.URL, testSendBlockNumber(t, ctx, mb))
+	assert.Equal(t, te1.server.URL, testSendBlockNumber(t, ctx, mb))
+
+	te1.handler = func(rpcReq *RPCRequest) (int, *RPCResponse) {
+		return http.StatusBadGateway, &RPCResponse{}
+	}
+	assert.Equal(t, te2.server.URL, testSendBlockNumber(t, ctx, mb))
+	assert.Equal(t, int64(3), te1.requests)
+
+	status := mb.EndpointStatus()
+	assert.False(t, status[0].Healthy)
+	assert.Regexp(t, "FF22012.*502", status[0].LastError)
+	assert.True(t, status[1].Healthy)
+
+	// Unhealthy endpoints are not used until the next health check
+	assert.Equal(t, te2.server.URL, testSendBlockNumber(t, ctx, mb))
+	assert.Equal(t, int64(3), te1.requests)
+
+	te1.handler = nil
+	mb.healthCheck()
+	assert.True(t, mb.EndpointStatus()[0].Healthy)
+	assert.Equal(t, te1.server.URL, testSendBlockNumber(t, ctx, mb))
+}
+
+func TestMultiEndpointConnectionFailover(t *testing.T) {
+	te1, te2 := newTestEndpoint(t, 100), newTestEndpoint(t, 100)
+	ctx, mb, done := newTestMultiEndpointBackend(t, MultiEndpointOptions{}, te1, te2)
+	defer done()
+
+	te1.server.Close()
+	assert.Equal(t, te2.server.URL, testSendBlockNumber(t, ctx, mb))
+	assert.False(t, mb.EndpointStatus()[0].Healthy)
+}
+
+func TestMultiEndpointNoFailoverOnRPCError(t *testing.T) {
+	te1, te2 := newTestEndpoint(t, 100), newTestEndpoint(t, 100)
+	ctx, mb, done := newTestMultiEndpointBackend(t, MultiEndpointOptions{}, te1, te2)
+	defer done()
+
+	te1.handler = func(rpcReq *RPCRequest) (int, *RPCResponse) {
+		return http.StatusOK, &RPCResponse{Error: &RPCError{Code: -32000, Message: "execution reverted"}}
+	}
+	rpcRes, err := mb.SyncRequest(ctx, &RPCRequest{ID: fftypes.JSONAnyPtr("1"), Method: "eth_call"})
+	assert.Regexp(t, "execution reverted", err)
+	assert.Equal(t, int64(-32000), rpcRes.Error.Code)
+	assert.Equal(t, "1", rpcRes.ID.String())
+	assert.Zero(t, te2.requests)
+	assert.True(t, mb.EndpointStatus()[0].Healthy)
+
+	rpcErr := mb.CallRPC(ctx, nil, "eth_call")
+	assert.Regexp(t, "execution reverted", rpcErr.Message)
+}
+
+func TestMultiEndpointAllUnavailable(t *testing.T) {
+	te1, te2 := newTestEndpoint(t, 100), newTestEndpoint(t, 100)
+	ctx, mb, done := newTestMultiEndpointBackend(t, MultiEndpointOptions{}, te1, te2)
+	defer done()
+
+	unavailable := func(rpcReq *RPCRequest) (int, *RPCResponse) {
+		return http.StatusServiceUnavailable, &RPCResponse{}
+	}
+	te1.handler, te2.handler = unavailable, unavailable
+	rpcErr := mb.CallRPC(ctx, nil, "eth_chainId")
+	assert.Regexp(t, "FF22012", rpcErr.Message)
+	assert.Equal(t, int64(1), te1.requests)
+	assert.Equal(t, int64(1), te2.requests)
+
+	// When nothing is healthy we still try everything
+	te2.handler = nil
+	assert.Equal(t, te2.server.URL, testSendBlockNumber(t, ctx, mb))
+	assert.Equal(t, int64(2), te1.requests)
+}
+
+func TestMultiEndpointRoundRobin(t *testing.T) {
+	te1, te2, te3 := newTestEndpoint(t, 100), newTestEndpoint(t, 100), newTestEndpoint(t, 100)
+	ctx, mb, done := newTestMultiEndpointBackend(t, MultiEndpointOptions{
+		Strategy: EndpointStrategyRoundRobin,
+	}, te1, te2, te3)
+	defer done()
+
+	assert.Equal(t, te1.server.URL, testSendBlockNumber(t, ctx, mb))
+	assert.Equal(t, te2.server.URL, testSendBlockNumber(t, ctx, mb))
+	assert.Equal(t, te3.server.URL, testSendBlockNumber(t, ctx, mb))
+	assert.Equal(t, te1.server.URL, testSendBlockNumber(t, ctx, mb))
+}
+
+func TestMultiEndpointHealthCheckLag(t *testing.T) {
+	te1, te2, te3 := newTestEndpoint(t, 90), newTestEndpoint(t, 100), newTestEndpoint(t, 97)
+	ctx, mb, done := newTestMultiEndpointBackend(t, MultiEndpointOptions{
+		MaxBlockLag: 5,
+	}, te1, te2, te3)
+	defer done()
+
+	mb.healthCheck()
+	status := mb.EndpointStatus()
+	assert.False(t, status[0].Healthy)
+	assert.Equal(t, uint64(90), status[0].BlockNumber)
+	assert.Regexp(t, "FF22111.*90.*100", status[0].LastError)
+	assert.True(t, status[1].Healthy)
+	assert.True(t, status[2].Healthy)
+	assert.Equal(t, te2.server.URL, testSendBlockNumber(t, ctx, mb))
+
+	// Catches up
+	atomic.StoreInt64(&te1.blockNumber, 99)
+	mb.healthCheck()
+	assert.True(t, mb.EndpointStatus()[0].Healthy)
+	assert.Empty(t, mb.EndpointStatus()[0].LastError)
+	assert.Equal(t, te1.server.URL, testSendBlockNumber(t, ctx, mb))
+}
+
+func TestMultiEndpointHealthCheckFail(t *testing.T) {
+	te1, te2 := newTestEndpoint(t, 100), newTestEndpoint(t, 100)
+	ctx, mb, done := newTestMultiEndpointBackend(t, MultiEndpointOptions{}, te1, te2)
+	defer done()
+
+	te1.server.Close()
+	mb.healthCheck()
+	assert.False(t, mb.EndpointStatus()[0].Healthy)
+	assert.Equal(t, te2.server.URL, testSendBlockNumber(t, ctx, mb))
+}
+
+func TestMultiEndpointHealthCheckLoop(t *testing.T) {
+	te1 := newTestEndpoint(t, 100)
+	_, mb, done := newTestMultiEndpointBackend(t, MultiEndpointOptions{
+		HealthCheckInterval: 10 * time.Millisecond,
+	}, te1)
+	defer done()
+
+	for mb.EndpointStatus()[0].BlockNumber != 100 {
+		time.Sleep(1 * time.Millisecond)
+	}
+	atomic.StoreInt64(&te1.blockNumber, 101)
+	for mb.EndpointStatus()[0].BlockNumber != 101 {
+		time.Sleep(1 * time.Millisecond)
+	}
+}
+
+func TestMultiEndpointSenderPinning(t *testing.T) {
+	te1, te2 := newTestEndpoint(t, 100), newTestEndpoint(t, 100)
+	ctx, mb, done := newTestMultiEndpointBackend(t, MultiEndpointOptions{
+		Strategy:     EndpointStrategyRoundRobin,
+		SenderPinTTL: 1 * time.Hour,
+	}, te1, te2)
+	defer done()
+
+	for _, eip1559 := range []bool{false, true} {
+		sender, raw := testSignedRawTransaction(t, eip1559)
+		var firstURL string
+		for i := 0; i < 4; i++ {
+			var url string
+			rpcErr := mb.CallRPC(ctx, &url, "eth_sendRawTransaction", raw)
+			assert.Nil(t, rpcErr)
+			if i == 0 {
+				firstURL = url
+			}
+			assert.Equal(t, firstURL, url)
+		}
+		assert.Contains(t, mb.pins, *sender)
+	}
+
+	// Other requests still round-robin
+	assert.NotEqual(t, testSendBlockNumber(t, ctx, mb), testSendBlockNumber(t, ctx, mb))
+}
+
+func TestMultiEndpointSenderPinFailover(t *testing.T) {
+	te1, te2 := newTestEndpoint(t, 100), newTestEndpoint(t, 100)
+	ctx, mb, done := newTestMultiEndpointBackend(t, MultiEndpointOptions{}, te1, te2)
+	defer done()
+
+	sender, raw := testSignedRawTransaction(t, false)
+	var url string
+	rpcErr := mb.CallRPC(ctx, &url, "eth_sendRawTransaction", raw)
+	assert.Nil(t, rpcErr)
+	assert.Equal(t, te1.server.URL, url)
+
+	// The pin moves with the failover
+	te1.server.Close()
+	rpcErr = mb.CallRPC(ctx, &url, "eth_sendRawTransaction", raw)
+	assert.Nil(t, rpcErr)
+	assert.Equal(t, te2.server.URL, url)
+	assert.Equal(t, te2.server.URL, mb.pins[*sender].endpoint.url)
+}
+
+func TestMultiEndpointSenderPinExpiry(t *testing.T) {
+	te1 := newTestEndpoint(t, 100)
+	ctx, mb, done := newTestMultiEndpointBackend(t, MultiEndpointOptions{
+		SenderPinTTL: 1 * time.Hour,
+	}, te1)
+	defer done()
+
+	sender, raw := testSignedRawTransaction(t, true)
+	rpcErr := mb.CallRPC(ctx, nil, "eth_sendRawTransaction", raw)
+	assert.Nil(t, rpcErr)
+
+	mb.healthCheck()
+	assert.Contains(t, mb.pins, *sender)
+
+	mb.pins[*sender].lastUsed = time.Now().Add(-2 * time.Hour)
+	mb.healthCheck()
+	assert.NotContains(t, mb.pins, *sender)
+}
+
+func TestMultiEndpointCancelledContext(t *testing.T) {
+	te1, te2 := newTestEndpoint(t, 100), newTestEndpoint(t, 100)
+	_, mb, done := newTestMultiEndpointBackend(t, MultiEndpointOptions{}, te1, te2)
+	defer done()
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	cancelCtx()
+	_, err := mb.SyncRequest(ctx, &RPCRequest{Method: "eth_chainId"})
+	assert.Error(t, err)
+	assert.True(t, mb.EndpointStatus()[0].Healthy)
+	assert.Zero(t, te2.requests)
+}
+
+func TestMultiEndpointCallRPCBadInputAndResult(t *testing.T) {
+	te1 := newTestEndpoint(t, 100)
+	ctx, mb, done := newTestMultiEndpointBackend(t, MultiEndpointOptions{}, te1)
+	defer done()
+
+	rpcErr := mb.CallRPC(ctx, nil, "eth_call", map[bool]bool{false: true})
+	assert.Regexp(t, "FF22011", rpcErr.Message)
+
+	var wrong int
+	rpcErr = mb.CallRPC(ctx, &wrong, "eth_chainId")
+	assert.Regexp(t, "FF22065", rpcErr.Message)
+}
+
+func TestMultiEndpointBadOptions(t *testing.T) {
+	_, err := NewMultiEndpointBackend(context.Background(), []*resty.Client{}, MultiEndpointOptions{})
+	assert.Regexp(t, "FF22109", err)
+
+	_, err = NewMultiEndpointBackend(context.Background(), []*resty.Client{resty.New()}, MultiEndpointOptions{
+		Strategy: "random",
+	})
+	assert.Regexp(t, "FF22110.*random", err)
+}
+
+func TestRawTransactionSenderInvalid(t *testing.T) {
+	ctx := context.Background()
+	for _, params := range [][]*fftypes.JSONAny{
+		{},
+		{fftypes.JSONAnyPtr(`false`)},
+		{fftypes.JSONAnyPtr(`"0x"`)},
+		{fftypes.JSONAnyPtr(`"0xc0"`)},
+		{fftypes.JSONAnyPtr(`"0x02"`)},
+		{fftypes.JSONAnyPtr(`"0x01c0"`)},
+	} {
+		assert.Nil(t, rawTransactionSender(ctx, &RPCRequest{Params: params}))
+	}
+}
@@ -36,8 +36,9 @@ import (
 // - Manages websocket connect/reconnect with keepalive etc.
 // - Manages subscriptions with a local ID, so they re-established automatically after reconnect
 // - Allows synchronous exchange over the WebSocket so you don't have to maintain a separate HTTP connection too
+// - Implements Backend, so it can be used in place of the HTTP client to proxy requests
 type WebSocketRPCClient interface {
-	RPC
+	Backend
 	Subscribe(ctx context.Context, params ...interface{}) (sub Subscription, error *RPCError)
 	Subscriptions() []Subscription
 	UnsubscribeAll(ctx context.Context) (error *RPCError)
@@ -47,8 +48,14 @@ type WebSocketRPCClient interface {
 
 // NewRPCClient Constructor
 func NewWSRPCClient(wsConf *wsclient.WSConfig) WebSocketRPCClient {
+	return NewWSRPCClientWithOption(wsConf, WSRPCClientOptions{})
+}
+
+// NewWSRPCClientWithOption Constructor
+func NewWSRPCClientWithOption(wsConf *wsclient.WSConfig, options WSRPCClientOptions) WebSocketRPCClient {
 	return &wsRPCClient{
 		wsConf:             *wsConf,
+		metrics:            options.Metrics,
 		calls:              make(map[string]chan *RPCResponse),
 		configuredSubs:     make(map[fftypes.UUID]*sub),
 		pendingSubsByReqID: make(map[string]*sub),
@@ -56,6 +63,10 @@ func NewWSRPCClient(wsConf *wsclient.WSConfig) WebSocketRPCClient {
 	}
 }
 
+type WSRPCClientOptions struct {
+	Metrics RPCClientMetrics
+}
+
 type Subscription interface {
 	LocalID() *fftypes.UUID // does not change through reconnects
 	Notifications() chan *RPCSubscriptionNotification
@@ -77,6 +88,7 @@ type wsRPCClient struct {
 	configuredSubs     map[fftypes.UUID]*sub
 	pendingSubsByReqID map[string]*sub
 	activeSubsBySubID  map[string]*sub
+	metrics            RPCClientMetrics
 }
 
 type sub struct {
@@ -375,6 +387,68 @@ func (rc *wsRPCClient) CallRPC(ctx context.Context, result interface{}, method s
 	return rc.waitResponse(ctx, result, reqID, rpcReq, rpcStartTime, resChannel)
 }
 
+// SyncRequest sends an individual RPC request over the WebSocket, and waits for the response, or an error.
+//
+// As with the HTTP client, the request ID is replaced with our own for the backend (so requests from
+// multiple front-end clients cannot clash), and the original ID is restored in the response.
+// In all return paths *including error paths* the RPCResponse is populated.
+func (rc *wsRPCClient) SyncRequest(ctx context.Context, rpcReq *RPCRequest) (rpcRes *RPCResponse, err error) {
+	var beReq = *rpcReq
+	beReq.JSONRpc = "2.0"
+	reqID, resChannel := rc.addInflightRequest(&beReq)
+	defer rc.removeInflightRequest(reqID)
+	rpcTraceID := reqID
+	if rpcReq.ID != nil {
+		// We're proxying a request with front-end RPC ID - log that as well
+		rpcTraceID = fmt.Sprintf("%s->%s", rpcReq.ID, reqID)
+	}
+
+	rpcStartTime := time.Now()
+	unavailable := false
+	if rc.metrics != nil {
+		defer func() {
+			var code RPCCode
+			if rpcRes.Error != nil {
+				code = RPCCode(rpcRes.Error.Code)
+			}
+			if err != nil && code == 0 {
+				code = RPCCodeInternalError
+			}
+			rc.metrics.RequestCompleted(ctx, rpcReq.Method, time.Since(rpcStartTime), code, unavailable)
+		}()
+	}
+	if rpcErr := rc.sendRPC(ctx, rpcTraceID, &beReq); rpcErr != nil {
+		unavailable = true
+		return &RPCResponse{JSONRpc: "2.0", ID: rpcReq.ID, Error: rpcErr}, rpcErr.Error()
+	}
+
+	select {
+	case rpcRes = <-resChannel:
+	case <-ctx.Done():
+		err := i18n.NewError(ctx, signermsgs.MsgRequestCanceledContext, rpcTraceID)
+		log.L(ctx).Errorf("RPC[%s] <-- ERROR: %s", rpcTraceID, err)
+		unavailable = true
+		return RPCErrorResponse(err, rpcReq.ID, RPCCodeInternalError), err
+	}
+
+	// Restore the original ID
+	rpcRes.ID = rpcReq.ID
+	if logrus.IsLevelEnabled(logrus.TraceLevel) {
+		jsonOutput, _ := json.Marshal(rpcRes)
+		log.L(ctx).Tracef("RPC[%s] OUTPUT: %s", rpcTraceID, jsonOutput)
+	}
+	if rpcRes.Error != nil && rpcRes.Error.Code != 0 {
+		log.L(ctx).Errorf("RPC[%s] <-- ERROR: %s", rpcTraceID, rpcRes.Message())
+		return rpcRes, rpcRes.Error.Error()
+	}
+	log.L(ctx).Infof("RPC[%s] <-- %s OK (%.2fms)", rpcTraceID, rpcReq.Method, float64(time.Since(rpcStartTime))/float64(time.Millisecond))
+	if rpcRes.Result == nil {
+		// We don't want a result for errors, but a null success response needs to go in there
+		rpcRes.Result = fftypes.JSONAnyPtr(fftypes.NullString)
+	}
+	return rpcRes, nil
+}
+
 func (rc *wsRPCClient) waitResponse(ctx context.Context, result interface{}, reqID string, rpcReq *RPCRequest, rpcStartTime time.Time, resChannel chan *RPCResponse) *RPCError {
 	var rpcRes *RPCResponse
 	select {
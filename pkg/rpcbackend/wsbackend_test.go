@@ -250,6 +250,180 @@ func TestCallRPC(t *testing.T) {
 	done()
 }
 
+func TestWSRPCSyncRequest(t *testing.T) {
+	ctx, rc, toServer, fromServer, done := newTestWSRPC(t)
+	defer done()
+
+	err := rc.Connect(ctx)
+	assert.NoError(t, err)
+
+	go func() {
+		msg := <-toServer
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":"000000001","method":"eth_getTransactionCount","params":["0xfb075bb99f2aa4c49955bf703509a227d7a12248","pending"]}`, msg)
+		fromServer <- `{"jsonrpc":"2.0","id":"000000001","result":"0x26"}`
+	}()
+
+	rpcRes, err := rc.SyncRequest(ctx, &RPCRequest{
+		ID:     fftypes.JSONAnyPtr(`"client-id"`),
+		Method: "eth_getTransactionCount",
+		Params: []*fftypes.JSONAny{
+			fftypes.JSONAnyPtr(`"0xfb075bb99f2aa4c49955bf703509a227d7a12248"`),
+			fftypes.JSONAnyPtr(`"pending"`),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `"client-id"`, rpcRes.ID.String())
+	assert.Equal(t, `"0x26"`, rpcRes.Result.String())
+	assert.Empty(t, rc.calls)
+}
+
+func TestWSRPCSyncRequestNullResult(t *testing.T) {
+	ctx, rc, toServer, fromServer, done := newTestWSRPC(t)
+	defer done()
+
+	err := rc.Connect(ctx)
+	assert.NoError(t, err)
+
+	go func() {
+		<-toServer
+		fromServer <- `{"jsonrpc":"2.0","id":"000000001"}`
+	}()
+
+	rpcRes, err := rc.SyncRequest(ctx, &RPCRequest{
+		ID:     fftypes.JSONAnyPtr(`1`),
+		Method: "eth_getTransactionByHash",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `1`, rpcRes.ID.String())
+	assert.Equal(t, fftypes.NullString, rpcRes.Result.String())
+}
+
+func TestWSRPCSyncRequestErrorResponse(t *testing.T) {
+	ctx, rc, toServer, fromServer, done := newTestWSRPC(t)
+	defer done()
+
+	err := rc.Connect(ctx)
+	assert.NoError(t, err)
+
+	go func() {
+		<-toServer
+		fromServer <- `{"jsonrpc":"2.0","id":"000000001","error":{"code":-32000,"message":"nonce too low","data":"0x01"}}`
+	}()
+
+	rpcRes, err := rc.SyncRequest(ctx, &RPCRequest{
+		ID:     fftypes.JSONAnyPtr(`1`),
+		Method: "eth_sendRawTransaction",
+	})
+	assert.Regexp(t, "nonce too low", err)
+	assert.Equal(t, `1`, rpcRes.ID.String())
+	assert.Equal(t, int64(-32000), rpcRes.Error.Code)
+	assert.Equal(t, `"0x01"`, rpcRes.Error.Data.String())
+}
+
+func TestWSRPCSyncRequestSendFail(t *testing.T) {
+	ctx, rc, _, _, done := newTestWSRPC(t)
+
+	err := rc.Connect(ctx)
+	assert.NoError(t, err)
+	done()
+
+	rpcRes, err := rc.SyncRequest(context.Background(), &RPCRequest{
+		ID:     fftypes.JSONAnyPtr(`1`),
+		Method: "net_version",
+	})
+	assert.Regexp(t, "FF22012", err)
+	assert.Equal(t, `1`, rpcRes.ID.String())
+	assert.Equal(t, int64(RPCCodeInternalError), rpcRes.Error.Code)
+}
+
+func TestWSRPCSyncRequestClosedContext(t *testing.T) {
+	ctx, rc, toServer, _, done := newTestWSRPC(t)
+	defer done()
+
+	err := rc.Connect(ctx)
+	assert.NoError(t, err)
+
+	reqCtx, cancelReqCtx := context.WithCancel(ctx)
+	go func() {
+		<-toServer
+		cancelReqCtx()
+	}()
+
+	rpcRes, err := rc.SyncRequest(reqCtx, &RPCRequest{
+		ID:     fftypes.JSONAnyPtr(`1`),
+		Method: "net_version",
+	})
+	assert.Regexp(t, "FF22063", err)
+	assert.Equal(t, `1`, rpcRes.ID.String())
+	assert.Empty(t, rc.calls)
+}
+
+func TestWSRPCSyncRequestMetrics(t *testing.T) {
+	ctx, rc, toServer, fromServer, done := newTestWSRPC(t)
+	defer done()
+	tm := &testMetrics{}
+	rc.metrics = tm
+
+	err := rc.Connect(ctx)
+	assert.NoError(t, err)
+
+	go func() {
+		<-toServer
+		fromServer <- `{"jsonrpc":"2.0","id":"000000001","result":"0x26"}`
+		<-toServer
+		fromServer <- `{"jsonrpc":"2.0","id":"000000002","error":{"code":-32000,"message":"nonce too low"}}`
+	}()
+
+	_, err = rc.SyncRequest(ctx, &RPCRequest{Method: "eth_getTransactionCount"})
+	assert.NoError(t, err)
+	_, err = rc.SyncRequest(ctx, &RPCRequest{Method: "eth_sendRawTransaction"})
+	assert.Regexp(t, "nonce too low", err)
+	assert.Equal(t, []*testMetricsCall{
+		{method: "eth_getTransactionCount"},
+		{method: "eth_sendRawTransaction", errorCode: -32000},
+	}, tm.calls)
+}
+
+func TestWSRPCSyncRequestMetricsSendFail(t *testing.T) {
+	ctx, rc, _, _, done := newTestWSRPC(t)
+	tm := &testMetrics{}
+	rc.metrics = tm
+
+	err := rc.Connect(ctx)
+	assert.NoError(t, err)
+	done()
+
+	_, err = rc.SyncRequest(context.Background(), &RPCRequest{Method: "net_version"})
+	assert.Regexp(t, "FF22012", err)
+	assert.Equal(t, []*testMetricsCall{{method: "net_version", errorCode: RPCCodeInternalError, unavailable: true}}, tm.calls)
+}
+
+func TestWSRPCSyncRequestMetricsClosedContext(t *testing.T) {
+	ctx, rc, toServer, _, done := newTestWSRPC(t)
+	defer done()
+	tm := &testMetrics{}
+	rc.metrics = tm
+
+	err := rc.Connect(ctx)
+	assert.NoError(t, err)
+
+	reqCtx, cancelReqCtx := context.WithCancel(ctx)
+	go func() {
+		<-toServer
+		cancelReqCtx()
+	}()
+
+	_, err = rc.SyncRequest(reqCtx, &RPCRequest{Method: "net_version"})
+	assert.Regexp(t, "FF22063", err)
+	assert.Equal(t, []*testMetricsCall{{method: "net_version", errorCode: RPCCodeInternalError, unavailable: true}}, tm.calls)
+}
+
+func TestNewWSRPCClientWithOption(t *testing.T) {
+	tm := &testMetrics{}
+	rc := NewWSRPCClientWithOption(generateConfig(), WSRPCClientOptions{Metrics: tm})
+	assert.Equal(t, tm, rc.(*wsRPCClient).metrics)
+}
+
 func TestWaitResponseClosedContext(t *testing.T) {
 	ctx, rc, _, _, done := newTestWSRPC(t)
 
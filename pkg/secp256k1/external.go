@@ -0,0 +1,78 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secp256k1
+
+import (
+	"context"
+	"encoding/asn1"
+	"math/big"
+
+	btcec "github.com/btcsuite/btcd/btcec/v2" // ISC licensed
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/internal/signermsgs"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+)
+
+var (
+	curveOrder     = btcec.S256().N
+	halfCurveOrder = new(big.Int).Rsh(curveOrder, 1)
+)
+
+type derSignature struct {
+	R *big.Int
+	S *big.Int
+}
+
+// ParseECDSASignature parses the signature returned by an external signer such as an HSM or
+// cloud KMS, which is either ASN.1 DER encoded, or the raw 64 byte r||s form used by PKCS#11
+func ParseECDSASignature(ctx context.Context, sig []byte) (r, s *big.Int, err error) {
+	if len(sig) == 64 {
+		return new(big.Int).SetBytes(sig[0:32]), new(big.Int).SetBytes(sig[32:64]), nil
+	}
+	var der derSignature
+	rest, err := asn1.Unmarshal(sig, &der)
+	if err != nil {
+		return nil, nil, i18n.NewError(ctx, signermsgs.MsgInvalidECDSASignature, err)
+	}
+	if len(rest) > 0 {
+		return nil, nil, i18n.NewError(ctx, signermsgs.MsgInvalidECDSASignature, "trailing data")
+	}
+	return der.R, der.S, nil
+}
+
+// NewSignatureDataFromRS builds the Ethereum signature for the r and s values of an ECDSA signature
+// of a digest, from an external signer that does not return the recovery ID.
+//
+// S is normalized to the lower half of the curve order, as required by Ethereum, and V is found by
+// trying each recovery ID until the signature recovers to the address of the signing key.
+// V is the legacy 27/28 value, like SignDirect.
+func NewSignatureDataFromRS(ctx context.Context, digest []byte, r, s *big.Int, signer ethtypes.Address0xHex) (*SignatureData, error) {
+	if r.Sign() <= 0 || r.Cmp(curveOrder) >= 0 || s.Sign() <= 0 || s.Cmp(curveOrder) >= 0 {
+		return nil, i18n.NewError(ctx, signermsgs.MsgInvalidECDSASignature, "r or s out of range")
+	}
+	if s.Cmp(halfCurveOrder) > 0 {
+		s = new(big.Int).Sub(curveOrder, s)
+	}
+	for _, v := range []int64{27, 28} {
+		sig := &SignatureData{V: big.NewInt(v), R: r, S: s}
+		addr, err := sig.RecoverDirect(digest, 0)
+		if err == nil && *addr == signer {
+			return sig, nil
+		}
+	}
+	return nil, i18n.NewError(ctx, signermsgs.MsgSignatureRecoveryFailed, signer)
+}
@@ -0,0 +1,105 @@
+// Copyright © 2026 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secp256k1
+
+import (
+	"context"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/sha3"
+)
+
+func testDigest(message string) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(message))
+	return hash.Sum(nil)
+}
+
+func TestNewSignatureDataFromRSDER(t *testing.T) {
+	ctx := context.Background()
+	keypair := testKeyPair(t)
+	for _, message := range []string{"message 1", "message 2", "message 3", "message 4"} {
+		digest := testDigest(message)
+		expected, err := keypair.SignDirect(digest)
+		assert.NoError(t, err)
+
+		// External signers can return either S value, so check both are normalized
+		for _, s := range []*big.Int{expected.S, new(big.Int).Sub(curveOrder, expected.S)} {
+			der, err := asn1.Marshal(derSignature{R: expected.R, S: s})
+			assert.NoError(t, err)
+			r, s, err := ParseECDSASignature(ctx, der)
+			assert.NoError(t, err)
+
+			sig, err := NewSignatureDataFromRS(ctx, digest, r, s, keypair.Address)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, sig)
+		}
+	}
+}
+
+func TestNewSignatureDataFromRSRaw(t *testing.T) {
+	ctx := context.Background()
+	keypair := testKeyPair(t)
+	digest := testDigest("raw")
+	expected, err := keypair.SignDirect(digest)
+	assert.NoError(t, err)
+
+	raw := make([]byte, 64)
+	expected.R.FillBytes(raw[0:32])
+	expected.S.FillBytes(raw[32:64])
+	r, s, err := ParseECDSASignature(ctx, raw)
+	assert.NoError(t, err)
+
+	sig, err := NewSignatureDataFromRS(ctx, digest, r, s, keypair.Address)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, sig)
+}
+
+func TestNewSignatureDataFromRSWrongAddress(t *testing.T) {
+	ctx := context.Background()
+	keypair := testKeyPair(t)
+	digest := testDigest("wrong")
+	expected, err := keypair.SignDirect(digest)
+	assert.NoError(t, err)
+
+	_, err = NewSignatureDataFromRS(ctx, digest, expected.R, expected.S, *ethtypes.MustNewAddress("0x1f185718734552d08278aa70f804580bab5fd2b4"))
+	assert.Regexp(t, "FF22178", err)
+}
+
+func TestNewSignatureDataFromRSOutOfRange(t *testing.T) {
+	ctx := context.Background()
+	keypair := testKeyPair(t)
+	_, err := NewSignatureDataFromRS(ctx, testDigest("range"), big.NewInt(0), big.NewInt(1), keypair.Address)
+	assert.Regexp(t, "FF22177", err)
+	_, err = NewSignatureDataFromRS(ctx, testDigest("range"), big.NewInt(1), curveOrder, keypair.Address)
+	assert.Regexp(t, "FF22177", err)
+}
+
+func TestParseECDSASignatureErrors(t *testing.T) {
+	ctx := context.Background()
+	_, _, err := ParseECDSASignature(ctx, []byte{0x30, 0x01})
+	assert.Regexp(t, "FF22177", err)
+
+	der, err := asn1.Marshal(derSignature{R: big.NewInt(1), S: big.NewInt(1)})
+	assert.NoError(t, err)
+	_, _, err = ParseECDSASignature(ctx, append(der, 0x00))
+	assert.Regexp(t, "FF22177.*trailing data", err)
+}
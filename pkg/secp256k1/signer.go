@@ -88,6 +88,9 @@ func (s *SignatureData) Recover(message []byte, chainID int64) (a *ethtypes.Addr
 // Recover obtains the original signer
 func (s *SignatureData) RecoverDirect(message []byte, chainID int64) (a *ethtypes.Address0xHex, err error) {
 
+	if s.R.Sign() <= 0 || s.R.Cmp(curveOrder) >= 0 || s.S.Sign() <= 0 || s.S.Cmp(curveOrder) >= 0 {
+		return nil, fmt.Errorf("r or s out of range in signature")
+	}
 	signatureBytes := make([]byte, 65)
 	signatureBytes[0], err = s.getVNormalized(chainID)
 	if err != nil {
@@ -19,6 +19,7 @@ package secp256k1
 import (
 	"bytes"
 	"encoding/hex"
+	"math/big"
 	"strconv"
 	"testing"
 
@@ -84,3 +85,21 @@ func TestSignFailNil(t *testing.T) {
 	assert.Regexp(t, "nil signer", err)
 
 }
+
+func TestRecoverDirectRSOutOfRange(t *testing.T) {
+
+	keypair := testKeyPair(t)
+	sig, err := keypair.Sign(addEthMessagePrefix([]byte(sampleMessage)))
+	assert.NoError(t, err)
+
+	for _, rs := range []struct{ r, s *big.Int }{
+		{big.NewInt(0), sig.S},
+		{sig.R, big.NewInt(0)},
+		{curveOrder, sig.S},
+		{sig.R, new(big.Int).Lsh(big.NewInt(1), 264)},
+	} {
+		_, err := (&SignatureData{V: sig.V, R: rs.r, S: rs.s}).RecoverDirect(addEthMessagePrefix([]byte(sampleMessage)), 0)
+		assert.Regexp(t, "r or s out of range", err)
+	}
+
+}